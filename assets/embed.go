@@ -164,6 +164,13 @@ var (
 	//go:embed configs/quests/*.toml
 	QuestCfgFS embed.FS
 
+	//go:embed configs/scrolls.toml
+	Scrolls_toml []byte
+
+	// World maps
+	//go:embed maps/shandalar.json
+	ShandalarMap_json []byte
+
 	////////////////////////
 	// Duel screen sprites
 	////////////////////////
@@ -0,0 +1,148 @@
+// Command cardimages downloads card images into the game's on-disk image
+// cache ahead of time, for a saved game's collection, deck files, the rogues'
+// decks or the whole card database, so later runs can play offline.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/benprew/s30/game/domain"
+	"github.com/benprew/s30/game/save"
+)
+
+func main() {
+	cacheDir := flag.String("cache", "", "card image cache directory (default: the game's, beside the saves)")
+	cacheMB := flag.Int64("cache-mb", domain.DefaultCardImageCacheBytes>>20, "disk space for cached card images in MB")
+	mirror := flag.String("mirror", "", "fetch card images from this http(s) URL or directory instead of Scryfall")
+	all := flag.Bool("all", false, "fetch every card in the card database")
+	rogues := flag.Bool("rogues", false, "fetch every card in the rogues' decks")
+	query := flag.String("query", "", "only fetch cards matching this card search, e.g. \"set:LEA r:rare\"")
+	workers := flag.Int("workers", 4, "images to download at once")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: cardimages [flags] [save.json | deck.dck | deck.txt]...\n\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	cards, err := collectCards(flag.Args(), *all, *rogues)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *query != "" {
+		q, err := domain.ParseCardQuery(*query)
+		if err != nil {
+			log.Fatalf("bad -query: %v", err)
+		}
+		cards = q.Filter(cards)
+	}
+	if len(cards) == 0 {
+		log.Fatal("no cards to fetch: name a save or deck file, or use -all or -rogues")
+	}
+
+	dir := *cacheDir
+	if dir == "" {
+		if dir, err = save.CardImageDir(); err != nil {
+			log.Fatalf("find card image cache: %v", err)
+		}
+	}
+	if err := domain.SetCardImageCacheDir(dir, *cacheMB<<20); err != nil {
+		log.Fatal(err)
+	}
+	domain.SetCardImageMirror(*mirror)
+
+	fmt.Fprintf(os.Stderr, "Fetching %d card images into %s\n", len(cards), dir)
+	result, err := domain.PrefetchCardImages(cards, *workers, func(done, total int) {
+		if done%50 == 0 || done == total {
+			fmt.Fprintf(os.Stderr, "%d/%d\n", done, total)
+		}
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	stats := domain.CardImageCacheStats()
+	fmt.Printf("%d downloaded, %d already cached, %d failed; cache holds %d images in %.1f MB (%d evicted)\n",
+		result.Downloaded, result.Cached, result.Failed, stats.DiskFiles, float64(stats.DiskBytes)/(1<<20), stats.Evictions)
+	if result.Failed > 0 {
+		os.Exit(1)
+	}
+}
+
+// collectCards returns the cards in the named save and deck files, plus every
+// card or every rogue's card when asked, each printing once and sorted by ID.
+func collectCards(paths []string, all, rogues bool) ([]*domain.Card, error) {
+	seen := make(map[*domain.Card]bool)
+	add := func(c *domain.Card) {
+		if c != nil {
+			seen[c] = true
+		}
+	}
+	if all {
+		for _, c := range domain.CARDS {
+			add(c)
+		}
+	}
+	if rogues {
+		for _, r := range domain.Rogues {
+			for c := range r.CardCollection {
+				add(c)
+			}
+		}
+	}
+	for _, path := range paths {
+		cards, err := cardsFromFile(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range cards {
+			add(c)
+		}
+	}
+
+	cards := make([]*domain.Card, 0, len(seen))
+	for c := range seen {
+		cards = append(cards, c)
+	}
+	sort.Slice(cards, func(i, j int) bool { return cards[i].CardID() < cards[j].CardID() })
+	return cards, nil
+}
+
+// cardsFromFile returns the cards a saved game's player owns, or the cards in
+// a deck file's deck and sideboard.
+func cardsFromFile(path string) ([]*domain.Card, error) {
+	var cards []*domain.Card
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		level, err := save.LoadGame(path)
+		if err != nil {
+			return nil, err
+		}
+		if level.Player == nil {
+			return nil, fmt.Errorf("%s has no player", path)
+		}
+		for c := range level.Player.CardCollection {
+			cards = append(cards, c)
+		}
+		return cards, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	dl, err := domain.ParseDeckList(f)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", path, err)
+	}
+	for _, deck := range []domain.Deck{dl.Main, dl.Sideboard} {
+		for c := range deck {
+			cards = append(cards, c)
+		}
+	}
+	return cards, nil
+}
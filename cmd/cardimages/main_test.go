@@ -0,0 +1,45 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/benprew/s30/game/domain"
+)
+
+func TestCollectCardsReadsDeckFilesOnce(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "burn.dck")
+	deck := "[Main]\n20 Mountain\n4 Lightning Bolt\n[Sideboard]\n2 Lightning Bolt\n3 Red Elemental Blast\n"
+	if err := os.WriteFile(path, []byte(deck), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cards, err := collectCards([]string{path, path}, false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := make(map[string]int)
+	for _, c := range cards {
+		names[c.CardName]++
+	}
+	if len(cards) != 3 || names["Mountain"] != 1 || names["Lightning Bolt"] != 1 || names["Red Elemental Blast"] != 1 {
+		t.Errorf("collected %v", names)
+	}
+}
+
+func TestCollectCardsAll(t *testing.T) {
+	cards, err := collectCards(nil, true, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cards) != len(domain.CARDS) {
+		t.Errorf("collected %d cards, want all %d", len(cards), len(domain.CARDS))
+	}
+}
+
+func TestCollectCardsMissingFile(t *testing.T) {
+	if _, err := collectCards([]string{filepath.Join(t.TempDir(), "missing.dck")}, false, false); err == nil {
+		t.Error("expected an error for a missing deck file")
+	}
+}
@@ -0,0 +1,58 @@
+// Command cardsearch lists the cards in the game's card database that match a
+// search, using the same query language as the deck editor and card shops.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/benprew/s30/game/domain"
+)
+
+func main() {
+	all := flag.Bool("all", false, "list every printing instead of one line per card name")
+	count := flag.Bool("count", false, "print only the number of matching cards")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: cardsearch [flags] query...\n\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	query, err := domain.ParseCardQuery(strings.Join(flag.Args(), " "))
+	if err != nil {
+		log.Fatalf("bad query: %v", err)
+	}
+	cards := query.Filter(domain.CARDS)
+	if !*all {
+		cards = uniqueNames(cards)
+	}
+	if *count {
+		fmt.Println(len(cards))
+		return
+	}
+	writeCards(os.Stdout, cards)
+}
+
+// uniqueNames keeps the first printing of each card. cards is sorted by name,
+// as domain.CARDS is.
+func uniqueNames(cards []*domain.Card) []*domain.Card {
+	var out []*domain.Card
+	for _, c := range cards {
+		if len(out) == 0 || out[len(out)-1].CardName != c.CardName {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// writeCards writes one tab-separated line per card: name, mana cost, type
+// line, set and rarity.
+func writeCards(w io.Writer, cards []*domain.Card) {
+	for _, c := range cards {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", c.CardName, c.ManaCost, c.TypeLine, strings.ToUpper(c.SetID), c.Rarity)
+	}
+}
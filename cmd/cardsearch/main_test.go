@@ -0,0 +1,26 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/benprew/s30/game/domain"
+)
+
+func TestUniqueNamesKeepsOnePrintingPerCard(t *testing.T) {
+	bolts := domain.FindAllCardsByName("Lightning Bolt")
+	cards := append(append([]*domain.Card{}, bolts...), domain.FindCardByName("Mountain"))
+
+	got := uniqueNames(cards)
+	if len(got) != 2 || got[0] != bolts[0] || got[1].CardName != "Mountain" {
+		t.Errorf("uniqueNames kept %d cards", len(got))
+	}
+}
+
+func TestWriteCards(t *testing.T) {
+	var buf bytes.Buffer
+	writeCards(&buf, []*domain.Card{domain.FindCardByName("Dragon Whelp")})
+	if want := "Dragon Whelp\t{2}{R}{R}\tCreature — Dragon\t2ED\tuncommon\n"; buf.String() != want {
+		t.Errorf("writeCards = %q, want %q", buf.String(), want)
+	}
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/benprew/s30/game/domain"
+)
+
+// rogueStartingLife is used for .dck decks, which carry no life total.
+const rogueStartingLife = 20
+
+// simDeck is one side of a simulated matchup.
+type simDeck struct {
+	Name string
+	Life int
+	Deck domain.Deck
+}
+
+// expandDeckSpecs turns a comma-separated list of rogue names and .dck paths
+// into decks. The keyword "all" expands to every rogue.
+func expandDeckSpecs(specs string) ([]simDeck, error) {
+	var decks []simDeck
+	for _, spec := range strings.Split(specs, ",") {
+		spec = strings.TrimSpace(spec)
+		if spec == "" {
+			continue
+		}
+		if spec == "all" {
+			for _, name := range rogueNames() {
+				d, err := loadDeck(name)
+				if err != nil {
+					return nil, err
+				}
+				decks = append(decks, d)
+			}
+			continue
+		}
+		d, err := loadDeck(spec)
+		if err != nil {
+			return nil, err
+		}
+		decks = append(decks, d)
+	}
+	if len(decks) == 0 {
+		return nil, fmt.Errorf("no decks in %q", specs)
+	}
+	return decks, nil
+}
+
+func rogueNames() []string {
+	names := make([]string, 0, len(domain.Rogues))
+	for name := range domain.Rogues {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// loadDeck resolves spec as a .dck file if it names one, otherwise as a rogue.
+func loadDeck(spec string) (simDeck, error) {
+	if strings.EqualFold(filepath.Ext(spec), ".dck") {
+		f, err := os.Open(spec)
+		if err != nil {
+			return simDeck{}, fmt.Errorf("open deck: %w", err)
+		}
+		defer f.Close()
+		dl, err := domain.ParseDeckList(f)
+		if err != nil {
+			return simDeck{}, fmt.Errorf("parse %s: %w", spec, err)
+		}
+		if len(dl.Unknown) > 0 {
+			return simDeck{}, fmt.Errorf("%s: unknown cards %s", spec, strings.Join(dl.Unknown, ", "))
+		}
+		name := dl.Name
+		if name == "" {
+			name = strings.TrimSuffix(filepath.Base(spec), filepath.Ext(spec))
+		}
+		return simDeck{Name: name, Life: rogueStartingLife, Deck: dl.Main}, nil
+	}
+
+	rogue, ok := domain.Rogues[spec]
+	if !ok {
+		return simDeck{}, fmt.Errorf("unknown rogue %q", spec)
+	}
+	return simDeck{Name: rogue.Name, Life: rogue.Life, Deck: rogue.GetActiveDeck()}, nil
+}
+
+// legalDecks returns the decks that are legal in format, writing why each of
+// the others was skipped to w.
+func legalDecks(decks []simDeck, format *domain.Format, w io.Writer) []simDeck {
+	var legal []simDeck
+	for _, d := range decks {
+		violations := format.Validate(d.Deck)
+		if len(violations) == 0 {
+			legal = append(legal, d)
+			continue
+		}
+		fmt.Fprintf(w, "Skipping %s, not %s legal:\n", d.Name, format.Name)
+		for _, v := range violations {
+			fmt.Fprintf(w, "  %s\n", v)
+		}
+	}
+	return legal
+}
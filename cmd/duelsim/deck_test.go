@@ -0,0 +1,83 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/benprew/s30/game/domain"
+)
+
+func TestLoadDeckReadsDckFiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "burn.dck")
+	src := "NAME:Red Deck Wins\n4 [2ED:162] Lightning Bolt\n16 Mountain\n[sideboard]\n2 Shatter\n"
+	if err := os.WriteFile(path, []byte(src), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	d, err := loadDeck(path)
+	if err != nil {
+		t.Fatalf("loadDeck: %v", err)
+	}
+	if d.Name != "Red Deck Wins" || d.Life != rogueStartingLife {
+		t.Errorf("Name, Life = %q, %d", d.Name, d.Life)
+	}
+	total := 0
+	for _, n := range d.Deck {
+		total += n
+	}
+	if total != 20 {
+		t.Errorf("deck has %d cards, want 20 (sideboard excluded)", total)
+	}
+}
+
+func TestLoadDeckRejectsUnknownCards(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "odd.dck")
+	if err := os.WriteFile(path, []byte("4 Not A Real Card\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadDeck(path); err == nil {
+		t.Fatal("expected an error for an unknown card")
+	}
+}
+
+func TestExpandDeckSpecsAll(t *testing.T) {
+	decks, err := expandDeckSpecs("all")
+	if err != nil {
+		t.Fatalf("expandDeckSpecs: %v", err)
+	}
+	if len(decks) != len(domain.Rogues) {
+		t.Fatalf("got %d decks, want %d", len(decks), len(domain.Rogues))
+	}
+	for _, d := range decks {
+		if len(d.Deck) == 0 {
+			t.Errorf("rogue %s has an empty deck", d.Name)
+		}
+	}
+}
+
+func TestExpandDeckSpecsUnknownRogue(t *testing.T) {
+	if _, err := expandDeckSpecs("Nobody In Particular"); err == nil {
+		t.Fatal("expected an error for an unknown rogue")
+	}
+}
+
+func TestLegalDecksSkipsIllegalDecks(t *testing.T) {
+	mountain := domain.FindCardByName("Mountain")
+	bolt := domain.FindCardByName("Lightning Bolt")
+	decks := []simDeck{
+		{Name: "Legal", Deck: domain.Deck{mountain: 56, bolt: 4}},
+		{Name: "Five Bolts", Deck: domain.Deck{mountain: 55, bolt: 5}},
+	}
+
+	var out strings.Builder
+	legal := legalDecks(decks, domain.FormatOldSchool, &out)
+	if len(legal) != 1 || legal[0].Name != "Legal" {
+		t.Fatalf("legal decks = %v", legal)
+	}
+	want := "Skipping Five Bolts, not Old School 93/94 legal:\n  5 copies of Lightning Bolt, 4 allowed\n"
+	if out.String() != want {
+		t.Errorf("output = %q, want %q", out.String(), want)
+	}
+}
@@ -0,0 +1,202 @@
+// Command duelsim plays AI-vs-AI duels between rogue decks without opening a
+// window and reports win rates, game length, mulligans and card play counts.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"math/rand"
+	"os"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	_ "github.com/benprew/mage-go/cards"
+	mage "github.com/benprew/mage-go/pkg/mage"
+	"github.com/benprew/s30/game/domain"
+	"github.com/benprew/s30/logging"
+)
+
+type job struct {
+	matchup int
+	game    int
+}
+
+type jobResult struct {
+	matchup int
+	result  gameResult
+}
+
+func main() {
+	deckA := flag.String("a", "", "comma-separated rogue names or .dck files for seat A, or \"all\" (default: random rogue)")
+	deckB := flag.String("b", "", "comma-separated rogue names or .dck files for seat B, or \"all\" (default: random rogue)")
+	games := flag.Int("games", 100, "games to play per matchup")
+	parallel := flag.Int("parallel", runtime.NumCPU(), "games to run at once")
+	maxTurns := flag.Int("max-turns", 60, "call a game a draw after this many turns (0 disables)")
+	timeout := flag.Duration("timeout", 2*time.Minute, "call a game a draw after this much wall time")
+	format := flag.String("format", "csv", "output format: csv or json")
+	out := flag.String("out", "", "write the report to this file instead of stdout")
+	cardsOut := flag.String("cards-out", "", "with -format csv, also write per-card play counts to this file")
+	cardsQuery := flag.String("cards-query", "", "only write per-card play counts for cards matching this search, e.g. \"t:creature cmc<=2\"")
+	duelLog := flag.Bool("duel-log", false, "enable verbose duel logging")
+	legal := flag.String("legal", "", "skip decks that aren't legal in this deck format: "+strings.Join(domain.FormatIDs(), ", "))
+	flag.Parse()
+
+	if *games < 1 {
+		log.Fatal("-games must be at least 1")
+	}
+	if *parallel < 1 {
+		log.Fatal("-parallel must be at least 1")
+	}
+	if *format != "csv" && *format != "json" {
+		log.Fatalf("unknown -format %q (want csv or json)", *format)
+	}
+	var deckFormat *domain.Format
+	if *legal != "" {
+		f, ok := domain.FormatByID(*legal)
+		if !ok {
+			log.Fatalf("unknown -legal format %q (want one of %s)", *legal, strings.Join(domain.FormatIDs(), ", "))
+		}
+		deckFormat = f
+	}
+	var cardQuery domain.CardQuery
+	if *cardsQuery != "" {
+		q, err := domain.ParseCardQuery(*cardsQuery)
+		if err != nil {
+			log.Fatalf("bad -cards-query: %v", err)
+		}
+		cardQuery = q
+	}
+
+	if *duelLog {
+		logging.Enable(logging.Duel)
+	}
+	mage.DebugPriority = logging.Enabled(logging.Duel)
+
+	as, err := expandDeckSpecs(orRandomRogue(*deckA))
+	if err != nil {
+		log.Fatal(err)
+	}
+	bs, err := expandDeckSpecs(orRandomRogue(*deckB))
+	if err != nil {
+		log.Fatal(err)
+	}
+	if deckFormat != nil {
+		as = legalDecks(as, deckFormat, os.Stderr)
+		bs = legalDecks(bs, deckFormat, os.Stderr)
+		if len(as) == 0 || len(bs) == 0 {
+			log.Fatalf("no %s legal decks left on one side", deckFormat.Name)
+		}
+	}
+
+	var pairs [][2]simDeck
+	var matchups []*matchup
+	for _, a := range as {
+		for _, b := range bs {
+			pairs = append(pairs, [2]simDeck{a, b})
+			matchups = append(matchups, newMatchup(a.Name, b.Name))
+		}
+	}
+
+	start := time.Now()
+	results := run(pairs, *games, *parallel, *maxTurns, *timeout)
+	for r := range results {
+		matchups[r.matchup].add(r.result)
+	}
+	total := len(pairs) * *games
+	fmt.Fprintf(os.Stderr, "Played %d games in %s\n", total, time.Since(start).Round(time.Second))
+
+	if err := writeReport(*out, func(w io.Writer) error {
+		if *format == "json" {
+			return writeJSON(w, matchups)
+		}
+		return writeSummaryCSV(w, matchups)
+	}); err != nil {
+		log.Fatal(err)
+	}
+	if *cardsOut != "" && *format == "csv" {
+		if err := writeReport(*cardsOut, func(w io.Writer) error {
+			return writeCardsCSV(w, matchups, cardQuery)
+		}); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+// run plays every matchup's games across parallel workers. Seats alternate
+// who goes first so neither deck keeps the tempo advantage.
+func run(pairs [][2]simDeck, games, parallel, maxTurns int, timeout time.Duration) <-chan jobResult {
+	jobs := make(chan job)
+	results := make(chan jobResult)
+
+	go func() {
+		defer close(jobs)
+		for m := range pairs {
+			for g := range games {
+				jobs <- job{matchup: m, game: g}
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for range parallel {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				res, err := playJob(pairs[j.matchup], j.game%2, maxTurns, timeout)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "%s vs %s game %d: %v\n",
+						pairs[j.matchup][0].Name, pairs[j.matchup][1].Name, j.game+1, err)
+					continue
+				}
+				results <- jobResult{matchup: j.matchup, result: res}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+	return results
+}
+
+// playJob plays one game, turning an engine panic into an error so one bad
+// card doesn't end a long batch.
+func playJob(decks [2]simDeck, first, maxTurns int, timeout time.Duration) (res gameResult, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("engine panic: %v", r)
+		}
+	}()
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return playGame(ctx, decks, first, maxTurns), nil
+}
+
+func orRandomRogue(spec string) string {
+	if spec != "" {
+		return spec
+	}
+	names := rogueNames()
+	return names[rand.Intn(len(names))]
+}
+
+func writeReport(path string, write func(io.Writer) error) error {
+	if path == "" {
+		return write(os.Stdout)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("create report: %w", err)
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+
+	"github.com/benprew/s30/game/domain"
+)
+
+// deckStats is one deck's side of a matchup.
+type deckStats struct {
+	Name        string         `json:"name"`
+	Wins        int            `json:"wins"`
+	WinRate     float64        `json:"win_rate"`
+	WinsOnPlay  int            `json:"wins_on_play"`
+	GamesOnPlay int            `json:"games_on_play"`
+	Mulligans   int            `json:"mulligans"`
+	CardPlays   map[string]int `json:"card_plays"`
+}
+
+// matchup aggregates every game played between two decks.
+type matchup struct {
+	A        deckStats `json:"a"`
+	B        deckStats `json:"b"`
+	Games    int       `json:"games"`
+	Draws    int       `json:"draws"`
+	AvgTurns float64   `json:"avg_turns"`
+
+	totalTurns int
+}
+
+func newMatchup(a, b string) *matchup {
+	return &matchup{
+		A: deckStats{Name: a, CardPlays: make(map[string]int)},
+		B: deckStats{Name: b, CardPlays: make(map[string]int)},
+	}
+}
+
+func (m *matchup) side(i int) *deckStats {
+	if i == 0 {
+		return &m.A
+	}
+	return &m.B
+}
+
+// add folds one game into the totals and refreshes the derived rates.
+func (m *matchup) add(r gameResult) {
+	m.Games++
+	m.totalTurns += r.Turns
+	m.side(r.OnPlay).GamesOnPlay++
+	if r.Winner < 0 {
+		m.Draws++
+	} else {
+		winner := m.side(r.Winner)
+		winner.Wins++
+		if r.Winner == r.OnPlay {
+			winner.WinsOnPlay++
+		}
+	}
+	for i := range 2 {
+		s := m.side(i)
+		s.Mulligans += r.Mulligans[i]
+		for name, n := range r.Plays[i] {
+			s.CardPlays[name] += n
+		}
+	}
+
+	m.AvgTurns = float64(m.totalTurns) / float64(m.Games)
+	m.A.WinRate = float64(m.A.Wins) / float64(m.Games)
+	m.B.WinRate = float64(m.B.Wins) / float64(m.Games)
+}
+
+func writeJSON(w io.Writer, matchups []*matchup) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(matchups)
+}
+
+// writeSummaryCSV writes one row per matchup.
+func writeSummaryCSV(w io.Writer, matchups []*matchup) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{
+		"deck_a", "deck_b", "games", "wins_a", "wins_b", "draws",
+		"win_rate_a", "win_rate_b", "wins_on_play_a", "wins_on_play_b",
+		"avg_turns", "mulligans_a", "mulligans_b",
+	})
+	for _, m := range matchups {
+		cw.Write([]string{
+			m.A.Name, m.B.Name, strconv.Itoa(m.Games),
+			strconv.Itoa(m.A.Wins), strconv.Itoa(m.B.Wins), strconv.Itoa(m.Draws),
+			formatFloat(m.A.WinRate), formatFloat(m.B.WinRate),
+			strconv.Itoa(m.A.WinsOnPlay), strconv.Itoa(m.B.WinsOnPlay),
+			formatFloat(m.AvgTurns),
+			strconv.Itoa(m.A.Mulligans), strconv.Itoa(m.B.Mulligans),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// writeCardsCSV writes one row per card each deck played in each matchup,
+// sorted by card name. A non-nil query keeps only the cards it matches.
+func writeCardsCSV(w io.Writer, matchups []*matchup, query domain.CardQuery) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"deck", "opponent", "card", "plays", "plays_per_game"})
+	for _, m := range matchups {
+		for i := range 2 {
+			s, opp := m.side(i), m.side(1-i)
+			for _, name := range slices.Sorted(maps.Keys(s.CardPlays)) {
+				if query != nil {
+					if c := domain.FindCardByName(name); c == nil || !query(c) {
+						continue
+					}
+				}
+				n := s.CardPlays[name]
+				cw.Write([]string{
+					s.Name, opp.Name, name, strconv.Itoa(n),
+					formatFloat(float64(n) / float64(m.Games)),
+				})
+			}
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func formatFloat(f float64) string {
+	return fmt.Sprintf("%.3f", f)
+}
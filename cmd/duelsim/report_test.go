@@ -0,0 +1,126 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/benprew/s30/game/domain"
+)
+
+func TestMatchupAddTalliesResults(t *testing.T) {
+	m := newMatchup("Goblin Warlord", "Ape Lord")
+	m.add(gameResult{Winner: 0, Turns: 10, OnPlay: 0, Mulligans: [2]int{1, 0},
+		Plays: [2]map[string]int{{"Mountain": 3, "Goblin King": 1}, {"Forest": 4}}})
+	m.add(gameResult{Winner: 1, Turns: 20, OnPlay: 1,
+		Plays: [2]map[string]int{{"Mountain": 2}, {"Forest": 3}}})
+	m.add(gameResult{Winner: -1, Turns: 60, OnPlay: 0, Mulligans: [2]int{0, 2}})
+
+	if m.Games != 3 || m.Draws != 1 {
+		t.Fatalf("Games, Draws = %d, %d; want 3, 1", m.Games, m.Draws)
+	}
+	if m.A.Wins != 1 || m.B.Wins != 1 {
+		t.Fatalf("wins = %d/%d, want 1/1", m.A.Wins, m.B.Wins)
+	}
+	if m.A.WinsOnPlay != 1 || m.B.WinsOnPlay != 1 {
+		t.Errorf("wins on play = %d/%d, want 1/1", m.A.WinsOnPlay, m.B.WinsOnPlay)
+	}
+	if m.A.GamesOnPlay != 2 || m.B.GamesOnPlay != 1 {
+		t.Errorf("games on play = %d/%d, want 2/1", m.A.GamesOnPlay, m.B.GamesOnPlay)
+	}
+	if m.AvgTurns != 30 {
+		t.Errorf("AvgTurns = %v, want 30", m.AvgTurns)
+	}
+	if m.A.Mulligans != 1 || m.B.Mulligans != 2 {
+		t.Errorf("mulligans = %d/%d, want 1/2", m.A.Mulligans, m.B.Mulligans)
+	}
+	if m.A.CardPlays["Mountain"] != 5 || m.B.CardPlays["Forest"] != 7 {
+		t.Errorf("card plays = %v / %v", m.A.CardPlays, m.B.CardPlays)
+	}
+	if got := m.A.WinRate; got < 0.333 || got > 0.334 {
+		t.Errorf("A.WinRate = %v, want 1/3", got)
+	}
+}
+
+func TestWriteSummaryCSV(t *testing.T) {
+	m := newMatchup("Goblin Warlord", "Ape Lord")
+	m.add(gameResult{Winner: 0, Turns: 12, OnPlay: 0})
+	m.add(gameResult{Winner: 0, Turns: 8, OnPlay: 1})
+
+	var buf bytes.Buffer
+	if err := writeSummaryCSV(&buf, []*matchup{m}); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want header + 1 row:\n%s", len(lines), buf.String())
+	}
+	want := "Goblin Warlord,Ape Lord,2,2,0,0,1.000,0.000,1,0,10.000,0,0"
+	if lines[1] != want {
+		t.Errorf("row = %q, want %q", lines[1], want)
+	}
+}
+
+func TestWriteCardsCSVSortsCards(t *testing.T) {
+	m := newMatchup("Goblin Warlord", "Ape Lord")
+	m.add(gameResult{Winner: 0, Turns: 10,
+		Plays: [2]map[string]int{{"Mountain": 4, "Lightning Bolt": 2}, {"Forest": 1}}})
+
+	var buf bytes.Buffer
+	if err := writeCardsCSV(&buf, []*matchup{m}, nil); err != nil {
+		t.Fatal(err)
+	}
+	want := "deck,opponent,card,plays,plays_per_game\n" +
+		"Goblin Warlord,Ape Lord,Lightning Bolt,2,2.000\n" +
+		"Goblin Warlord,Ape Lord,Mountain,4,4.000\n" +
+		"Ape Lord,Goblin Warlord,Forest,1,1.000\n"
+	if buf.String() != want {
+		t.Errorf("cards CSV =\n%s\nwant\n%s", buf.String(), want)
+	}
+}
+
+func TestWriteCardsCSVKeepsQueriedCards(t *testing.T) {
+	m := newMatchup("Goblin Warlord", "Ape Lord")
+	m.add(gameResult{Winner: 0, Turns: 10,
+		Plays: [2]map[string]int{{"Mountain": 4, "Lightning Bolt": 2}, {"Forest": 1}}})
+	query, err := domain.ParseCardQuery("-t:land")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := writeCardsCSV(&buf, []*matchup{m}, query); err != nil {
+		t.Fatal(err)
+	}
+	want := "deck,opponent,card,plays,plays_per_game\n" +
+		"Goblin Warlord,Ape Lord,Lightning Bolt,2,2.000\n"
+	if buf.String() != want {
+		t.Errorf("cards CSV =\n%s\nwant\n%s", buf.String(), want)
+	}
+}
+
+func TestWriteJSONRoundTrips(t *testing.T) {
+	m := newMatchup("Goblin Warlord", "Ape Lord")
+	m.add(gameResult{Winner: 1, Turns: 9, OnPlay: 1, Plays: [2]map[string]int{nil, {"Forest": 2}}})
+
+	var buf bytes.Buffer
+	if err := writeJSON(&buf, []*matchup{m}); err != nil {
+		t.Fatal(err)
+	}
+	var got []matchup
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if len(got) != 1 || got[0].B.Wins != 1 || got[0].B.CardPlays["Forest"] != 2 || got[0].AvgTurns != 9 {
+		t.Errorf("decoded %+v", got)
+	}
+}
+
+func TestKeepHand(t *testing.T) {
+	for lands, want := range []bool{false, false, true, true, true, true, false, false} {
+		if got := keepHand(lands); got != want {
+			t.Errorf("keepHand(%d) = %v, want %v", lands, got, want)
+		}
+	}
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	mage "github.com/benprew/mage-go/pkg/mage"
+	"github.com/benprew/mage-go/pkg/mage/core"
+	"github.com/benprew/mage-go/pkg/mage/interactive"
+	"github.com/benprew/mage-go/pkg/mage/interactive/ai"
+	"github.com/benprew/mage-go/pkg/mage/interactive/ai/heuristic"
+	"github.com/benprew/s30/game/domain"
+)
+
+// maxMulligans matches the duel screen's AI, which keeps whatever it draws
+// after two mulligans.
+const maxMulligans = 2
+
+// gameResult is the outcome of one simulated duel. Seat 0 is always the "a"
+// deck of the matchup, whichever seat went first.
+type gameResult struct {
+	Winner    int // 0 or 1, or -1 for a draw (turn limit or timeout)
+	Turns     int
+	OnPlay    int
+	Mulligans [2]int
+	Plays     [2]map[string]int
+}
+
+// playCounter wraps an AI strategy to count the cards it plays, and stops the
+// game once it runs past the turn limit.
+type playCounter struct {
+	ai.AIStrategy
+	maxTurns int
+	stop     context.CancelFunc
+
+	mu    sync.Mutex
+	plays map[string]int
+}
+
+func (c *playCounter) PriorityAction(p mage.Player, g *mage.Game, landsPlayed int, mainPhase bool) interactive.PriorityAction {
+	if c.maxTurns > 0 && g.CurrentTurn() > c.maxTurns {
+		c.stop()
+		return interactive.PriorityAction{Type: interactive.ActionPass}
+	}
+	action := c.AIStrategy.PriorityAction(p, g, landsPlayed, mainPhase)
+	if action.Type == interactive.ActionCastSpell || action.Type == interactive.ActionPlayLand {
+		c.record(cardName(p, action))
+	}
+	return action
+}
+
+func (c *playCounter) record(name string) {
+	if name == "" {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.plays[name]++
+}
+
+func (c *playCounter) snapshot() map[string]int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	plays := make(map[string]int, len(c.plays))
+	for name, n := range c.plays {
+		plays[name] = n
+	}
+	return plays
+}
+
+// cardName resolves the card an action plays from the player's hand, falling
+// back to the name the strategy reported.
+func cardName(p mage.Player, action interactive.PriorityAction) string {
+	for _, c := range p.Hand() {
+		if c.ID() == action.CardID {
+			return c.Name()
+		}
+	}
+	return action.CardName
+}
+
+// playGame runs one duel between decks[0] and decks[1] to completion, with
+// decks[first] taking the first turn.
+func playGame(ctx context.Context, decks [2]simDeck, first, maxTurns int) gameResult {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var players [2]*ai.AIPlayer
+	var counters [2]*playCounter
+	for i, d := range decks {
+		counters[i] = &playCounter{
+			AIStrategy: heuristic.NewAdaptive(),
+			maxTurns:   maxTurns,
+			stop:       cancel,
+			plays:      make(map[string]int),
+		}
+		// Seat names must differ for Winner() to tell a mirror match apart.
+		players[i] = ai.NewAIPlayer(fmt.Sprintf("%s (%c)", d.Name, 'A'+i), counters[i])
+		players[i].SetLife(d.Life)
+		addDeckToLibrary(players[i], d.Deck)
+		players[i].ShuffleLibrary()
+	}
+
+	g := mage.NewGame(players[first], players[1-first])
+
+	res := gameResult{Winner: -1, OnPlay: first}
+	for i, p := range players {
+		for range domain.DefaultOpeningHandSize {
+			p.DrawCard()
+		}
+		res.Mulligans[i] = mulligan(p)
+	}
+
+	interactive.RunGameLoopContext(ctx, g, 0, 0)
+
+	res.Turns = g.CurrentTurn()
+	if g.IsGameOver() {
+		for i, p := range players {
+			if g.Winner() == p.Name() {
+				res.Winner = i
+			}
+		}
+	}
+	for i, c := range counters {
+		res.Plays[i] = c.snapshot()
+	}
+	return res
+}
+
+func addDeckToLibrary(player mage.Player, deck domain.Deck) {
+	for card, count := range deck {
+		for range count {
+			c, err := mage.CreateCard(card.CardName)
+			if err != nil {
+				continue
+			}
+			player.AddToLibrary(c)
+		}
+	}
+}
+
+// mulligan applies the duel screen's AI mulligan rule (London): keep 2-5
+// lands, otherwise redraw up to maxMulligans times, then bottom one card per
+// mulligan. It returns the number of mulligans taken.
+func mulligan(p mage.Player) int {
+	mulls := 0
+	for mulls < maxMulligans && !keepHand(countLands(p.Hand())) {
+		for _, c := range p.Hand() {
+			if _, ok := p.RemoveFromHand(c.ID()); ok {
+				p.AddToLibrary(c)
+			}
+		}
+		p.ShuffleLibrary()
+		for range domain.DefaultOpeningHandSize {
+			p.DrawCard()
+		}
+		mulls++
+	}
+
+	lib := p.Library()
+	for range mulls {
+		hand := p.Hand()
+		if len(hand) == 0 {
+			break
+		}
+		worst := hand[len(hand)-1]
+		for _, c := range hand {
+			if c.HasType(core.TypeLand) {
+				worst = c
+				break
+			}
+		}
+		if c, ok := p.RemoveFromHand(worst.ID()); ok {
+			lib = append(lib, c)
+		}
+	}
+	p.SetLibrary(lib)
+	return mulls
+}
+
+func keepHand(lands int) bool {
+	return lands >= 2 && lands <= 5
+}
+
+func countLands(hand []mage.Card) int {
+	lands := 0
+	for _, c := range hand {
+		if c.HasType(core.TypeLand) {
+			lands++
+		}
+	}
+	return lands
+}
@@ -0,0 +1,75 @@
+// Command worldmap writes a world as a map file: a generated world, the world
+// of a saved game, or a map file laid out again with its roads filled in. The
+// game starts new games on map files with -map.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/benprew/s30/game/domain"
+	"github.com/benprew/s30/game/save"
+	"github.com/benprew/s30/game/world"
+)
+
+func main() {
+	seed := flag.String("seed", "", "world seed to generate, a number or any text (default: random)")
+	savePath := flag.String("save", "", "export the world of this saved game instead")
+	mapPath := flag.String("map", "", "lay out this map file and export it with its roads filled in")
+	name := flag.String("name", "", "name to give the exported map")
+	out := flag.String("o", "", "file to write the map to (default: standard output)")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: worldmap [flags]\n\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	level, err := loadLevel(*seed, *savePath, *mapPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	m := world.ExportMap(level)
+	if *name != "" {
+		m.Name = *name
+	}
+	data, err := m.Marshal()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *out == "" {
+		os.Stdout.Write(data)
+		return
+	}
+	if err := os.WriteFile(*out, data, 0644); err != nil {
+		log.Fatal(err)
+	}
+	w, h := m.Size()
+	fmt.Fprintf(os.Stderr, "Wrote %s: %dx%d tiles, %d cities, %d castles, %d dungeons, %d roads\n",
+		*out, w, h, len(m.Cities), len(m.Castles), len(m.Dungeons), len(m.Roads))
+}
+
+// loadLevel returns the world to export: the saved game's or the map file's
+// when one is named, or else the world generated from seed.
+func loadLevel(seed, savePath, mapPath string) (*world.Level, error) {
+	switch {
+	case savePath != "" && mapPath != "":
+		return nil, fmt.Errorf("use -save or -map, not both")
+	case savePath != "":
+		return save.LoadGame(savePath)
+	}
+
+	worldSeed := world.NewSeed()
+	if seed != "" {
+		worldSeed = world.ParseSeed(seed)
+	}
+	if mapPath != "" {
+		m, err := world.LoadMapFile(mapPath)
+		if err != nil {
+			return nil, err
+		}
+		return world.NewLevelFromMap(&domain.Player{}, m, worldSeed)
+	}
+	return world.NewLevelWithSeed(&domain.Player{}, worldSeed)
+}
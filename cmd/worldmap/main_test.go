@@ -0,0 +1,42 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/benprew/s30/game/world"
+)
+
+func TestLoadLevelFillsInMapRoads(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "isle.json")
+	isle := `{"name": "Isle", "terrain": [
+		"~~~~~~~~",
+		"~~....~~",
+		"~~....~~",
+		"~~....~~",
+		"~~....~~",
+		"~~....~~",
+		"~~....~~",
+		"~~~~~~~~"],
+		"cities": [{"name": "North", "x": 3, "y": 1, "tier": "town"}, {"name": "South", "x": 3, "y": 6, "tier": "hamlet"}]}`
+	if err := os.WriteFile(path, []byte(isle), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	level, err := loadLevel("7", "", path)
+	if err != nil {
+		t.Fatalf("loadLevel: %v", err)
+	}
+	m := world.ExportMap(level)
+	if m.Name != "Isle" || len(m.Cities) != 2 {
+		t.Fatalf("exported map = %+v", m)
+	}
+	if len(m.Roads) != 1 {
+		t.Fatalf("roads = %v, want the two cities joined", m.Roads)
+	}
+
+	if _, err := loadLevel("", "game.json", path); err == nil {
+		t.Error("expected -save and -map together to fail")
+	}
+}
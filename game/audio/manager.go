@@ -9,6 +9,7 @@ import (
 	"sync"
 
 	"github.com/benprew/s30/assets"
+	"github.com/benprew/s30/logging"
 	"github.com/hajimehoshi/ebiten/v2/audio"
 	"github.com/hajimehoshi/ebiten/v2/audio/vorbis"
 )
@@ -21,6 +22,8 @@ const (
 
 var sampleRate = targetSampleRate(webAudio)
 
+var logger = logging.Logger(logging.Audio)
+
 func targetSampleRate(web bool) int {
 	if web {
 		return webSampleRate
@@ -358,19 +361,19 @@ func NewAudioManager() *AudioManager {
 func decodeOgg(path string) []byte {
 	data, err := assets.AudioFS.ReadFile(path)
 	if err != nil {
-		fmt.Printf("audio: failed to load %s: %v\n", path, err)
+		logger.Error("failed to load sound", "path", path, "err", err)
 		return nil
 	}
 
 	stream, err := vorbis.DecodeWithSampleRate(sampleRate, bytes.NewReader(data))
 	if err != nil {
-		fmt.Printf("audio: failed to decode %s: %v\n", path, err)
+		logger.Error("failed to decode sound", "path", path, "err", err)
 		return nil
 	}
 
 	decoded, err := io.ReadAll(stream)
 	if err != nil {
-		fmt.Printf("audio: failed to read decoded %s: %v\n", path, err)
+		logger.Error("failed to read decoded sound", "path", path, "err", err)
 		return nil
 	}
 
@@ -610,19 +613,19 @@ func (am *AudioManager) startBGM(bgm BGM) {
 
 	data, err := assets.AudioFS.ReadFile(path)
 	if err != nil {
-		fmt.Printf("audio: failed to load BGM %s: %v\n", path, err)
+		logger.Error("failed to load BGM", "path", path, "err", err)
 		return
 	}
 
 	stream, err := vorbis.DecodeWithSampleRate(sampleRate, bytes.NewReader(data))
 	if err != nil {
-		fmt.Printf("audio: failed to decode BGM %s: %v\n", path, err)
+		logger.Error("failed to decode BGM", "path", path, "err", err)
 		return
 	}
 
 	player, err := am.context.NewPlayer(stream)
 	if err != nil {
-		fmt.Printf("audio: failed to create BGM player: %v\n", err)
+		logger.Error("failed to create BGM player", "err", err)
 		return
 	}
 
@@ -655,6 +658,16 @@ func (am *AudioManager) SetVolume(bgm, sfx float64) {
 	}
 }
 
+// Volume returns the BGM and SFX volumes (0.0 to 1.0).
+func (am *AudioManager) Volume() (bgm, sfx float64) {
+	return am.bgmVolume, am.sfxVolume
+}
+
+// Muted reports whether audio is muted.
+func (am *AudioManager) Muted() bool {
+	return am.muted
+}
+
 // Mute mutes all audio.
 func (am *AudioManager) Mute() {
 	am.muted = true
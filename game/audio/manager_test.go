@@ -181,6 +181,19 @@ func TestSetVolume(t *testing.T) {
 	}
 }
 
+func TestVolumeAndMutedGetters(t *testing.T) {
+	am := newTestAudioManager()
+
+	am.SetVolume(0.4, 0.9)
+	am.Mute()
+	if bgm, sfx := am.Volume(); bgm != 0.4 || sfx != 0.9 {
+		t.Errorf("Volume() = %f, %f; want 0.4, 0.9", bgm, sfx)
+	}
+	if !am.Muted() {
+		t.Error("expected Muted() after Mute()")
+	}
+}
+
 func TestSetVolumeClamped(t *testing.T) {
 	am := newTestAudioManager()
 
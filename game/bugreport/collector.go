@@ -7,6 +7,7 @@ import (
 
 	"github.com/benprew/s30/game/save"
 	"github.com/benprew/s30/game/world"
+	"github.com/benprew/s30/logging"
 	"github.com/hajimehoshi/ebiten/v2"
 )
 
@@ -51,16 +52,11 @@ func CollectReport(level *world.Level, activeScreenName string, activeScreen any
 		UserNotes:    userNotes,
 		Environment:  CollectEnvironment(activeScreenName),
 		ActiveScreen: activeScreenName,
+		EngineLogs:   logging.Recent(),
 	}
 
 	if level != nil {
-		report.WorldState = &save.SaveData{
-			Name:    level.SaveName(),
-			GameID:  level.GameID,
-			Version: 1,
-			SavedAt: now,
-			World:   level,
-		}
+		report.WorldState = save.NewSaveData(level, now)
 	}
 
 	if reporter, ok := activeScreen.(DuelReporter); ok && reporter != nil {
@@ -90,16 +86,11 @@ func CollectCrashReport(level *world.Level, activeScreenName string, activeScree
 		StackTrace:   string(stack),
 		Environment:  CollectEnvironment(activeScreenName),
 		ActiveScreen: activeScreenName,
+		EngineLogs:   logging.Recent(),
 	}
 
 	if level != nil {
-		report.WorldState = &save.SaveData{
-			Name:    level.SaveName(),
-			GameID:  level.GameID,
-			Version: 1,
-			SavedAt: now,
-			World:   level,
-		}
+		report.WorldState = save.NewSaveData(level, now)
 	}
 
 	if reporter, ok := activeScreen.(DuelReporter); ok && reporter != nil {
@@ -12,6 +12,7 @@ func HandleCrash(level *world.Level, screenName string, screen any, panicVal any
 	report := CollectCrashReport(level, screenName, screen, panicVal, stack)
 	submitter := NewDefaultSubmitter()
 
+	logger.Error("crashed", "screen", screenName, "panic", panicVal)
 	result, err := submitter.Submit(report)
 
 	fmt.Fprintf(os.Stderr, "\n=======================================================\n")
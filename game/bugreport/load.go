@@ -0,0 +1,137 @@
+package bugreport
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/benprew/s30/game/save"
+)
+
+// ReportInfo describes a report file on disk, for listing without loading it.
+type ReportInfo struct {
+	ID        string
+	Path      string
+	Timestamp time.Time
+	IsCrash   bool
+	Title     string
+	HasDuel   bool
+}
+
+// ParseReport decodes a report written by LocalFileSubmitter. Its world state
+// is upgraded like a save file, so reports from older versions still load.
+func ParseReport(data []byte) (*BugReport, error) {
+	var report BugReport
+	// The world state is decoded on its own so it goes through the save
+	// migrations.
+	decoded := struct {
+		*BugReport
+		WorldState json.RawMessage `json:"world_state"`
+	}{BugReport: &report}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return nil, fmt.Errorf("failed to parse report: %w", err)
+	}
+	if len(decoded.WorldState) > 0 && string(decoded.WorldState) != "null" {
+		sd, err := save.ParseSaveData(decoded.WorldState)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse report world state: %w", err)
+		}
+		report.WorldState = sd
+	}
+	return &report, nil
+}
+
+// ReadReport loads the report file at path.
+func ReadReport(path string) (*BugReport, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read report %s: %w", path, err)
+	}
+	report, err := ParseReport(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return report, nil
+}
+
+// ListReports returns the reports saved in dirs, newest first. With no dirs
+// it lists the default bug report and crash directories. Missing directories
+// and files that aren't reports are skipped.
+func ListReports(dirs ...string) ([]ReportInfo, error) {
+	if len(dirs) == 0 {
+		for _, isCrash := range []bool{false, true} {
+			dir, err := DefaultBugReportDir(isCrash)
+			if err != nil {
+				return nil, fmt.Errorf("could not determine report directory: %w", err)
+			}
+			dirs = append(dirs, dir)
+		}
+	}
+
+	var reports []ReportInfo
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list reports in %s: %w", dir, err)
+		}
+		for _, entry := range entries {
+			if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+				continue
+			}
+			path := filepath.Join(dir, entry.Name())
+			info, err := readReportInfo(path)
+			if err != nil {
+				continue
+			}
+			reports = append(reports, info)
+		}
+	}
+	sort.Slice(reports, func(i, j int) bool {
+		return reports[i].Timestamp.After(reports[j].Timestamp)
+	})
+	return reports, nil
+}
+
+func readReportInfo(path string) (ReportInfo, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ReportInfo{}, err
+	}
+	var header struct {
+		ID           string          `json:"id"`
+		Timestamp    time.Time       `json:"timestamp"`
+		UserNotes    string          `json:"user_notes"`
+		IsCrash      bool            `json:"is_crash"`
+		CrashMessage string          `json:"crash_message"`
+		ActiveScreen string          `json:"active_screen"`
+		WorldState   json.RawMessage `json:"world_state"`
+		DuelState    json.RawMessage `json:"duel_state"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return ReportInfo{}, err
+	}
+	if header.ID == "" || len(header.WorldState) == 0 {
+		return ReportInfo{}, fmt.Errorf("%s has no world to restore", path)
+	}
+	title := (&BugReport{
+		UserNotes:    header.UserNotes,
+		IsCrash:      header.IsCrash,
+		CrashMessage: header.CrashMessage,
+		ActiveScreen: header.ActiveScreen,
+	}).IssueTitle()
+	return ReportInfo{
+		ID:        header.ID,
+		Path:      path,
+		Timestamp: header.Timestamp,
+		IsCrash:   header.IsCrash,
+		Title:     title,
+		HasDuel:   len(header.DuelState) > 0 && string(header.DuelState) != "null",
+	}, nil
+}
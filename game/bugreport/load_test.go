@@ -0,0 +1,67 @@
+package bugreport
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseReportMigratesWorldState(t *testing.T) {
+	world, err := os.ReadFile(filepath.Join("..", "save", "testdata", "save_v1.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := fmt.Sprintf(`{"id":"bug_1","timestamp":"2026-10-16T12:00:00Z","active_screen":"Duel",
+		"world_state":%s,"duel_state":{"opponent_name":"Sea Troll","human_deck":["Mountain"]}}`, world)
+
+	report, err := ParseReport([]byte(data))
+	if err != nil {
+		t.Fatalf("ParseReport: %v", err)
+	}
+	if report.ID != "bug_1" || report.DuelState == nil || report.DuelState.OpponentName != "Sea Troll" {
+		t.Errorf("report = %+v", report)
+	}
+	if report.WorldState == nil || report.WorldState.World == nil {
+		t.Fatal("world state was not restored")
+	}
+	if got := report.WorldState.World.Player.MoveSpeed; got != 10.0/6.0 {
+		t.Errorf("player MoveSpeed = %v, want the migrated %v", got, 10.0/6.0)
+	}
+
+	if _, err := ParseReport([]byte(`{"id":"bug_2","world_state":{"version":99}}`)); err == nil {
+		t.Error("expected a report from a newer game to fail")
+	}
+}
+
+func TestListReports(t *testing.T) {
+	bugs, crashes := t.TempDir(), filepath.Join(t.TempDir(), "missing")
+	write := func(name, contents string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(bugs, name), []byte(contents), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("old.json", `{"id":"old","timestamp":"2026-01-01T00:00:00Z","user_notes":"cards vanish","world_state":{}}`)
+	write("new.json", `{"id":"new","timestamp":"2026-02-01T00:00:00Z","is_crash":true,"crash_message":"boom",
+		"active_screen":"Duel","world_state":{},"duel_state":{}}`)
+	write("start.json", `{"id":"start","timestamp":"2026-03-01T00:00:00Z"}`)
+	write("notes.txt", "not a report")
+
+	reports, err := ListReports(bugs, crashes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reports) != 2 || reports[0].ID != "new" || reports[1].ID != "old" {
+		t.Fatalf("reports = %+v, want new then old", reports)
+	}
+	if !reports[0].HasDuel || reports[1].HasDuel {
+		t.Error("expected only the crash to have a duel")
+	}
+	if reports[0].Title != "[Crash] boom on Duel" || reports[1].Title != "[Bug] cards vanish" {
+		t.Errorf("titles = %q, %q", reports[0].Title, reports[1].Title)
+	}
+	if reports[1].Path != filepath.Join(bugs, "old.json") {
+		t.Errorf("path = %q", reports[1].Path)
+	}
+}
@@ -0,0 +1,314 @@
+package bugreport
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/benprew/s30/game/save"
+)
+
+const (
+	// outboxBaseBackoff is how long a queued report waits after its first
+	// failed attempt; each further failure doubles the wait.
+	outboxBaseBackoff = 5 * time.Minute
+	outboxMaxBackoff  = 24 * time.Hour
+)
+
+// QueuedReport is a report waiting in the outbox for the worker to be
+// reachable.
+type QueuedReport struct {
+	Signature string    `json:"signature"`
+	Title     string    `json:"title"`
+	QueuedAt  time.Time `json:"queued_at"`
+	// Occurrences counts how many times the report was filed while it was
+	// queued, so a crash that repeats offline is only sent once.
+	Occurrences int             `json:"occurrences"`
+	Attempts    int             `json:"attempts"`
+	NextAttempt time.Time       `json:"next_attempt"`
+	LastError   string          `json:"last_error,omitempty"`
+	Report      json.RawMessage `json:"report"`
+}
+
+// OutboxResult summarizes a pass over the outbox.
+type OutboxResult struct {
+	Sent int
+	// Failed counts reports the worker still couldn't take; they stay queued.
+	Failed int
+	// Waiting counts reports skipped because their backoff hasn't passed.
+	Waiting int
+	// Dropped counts reports the worker rejected outright, which are removed
+	// rather than retried.
+	Dropped   int
+	IssueURLs []string
+}
+
+// The outbox is shared by every Outbox: queued reports are sent at launch and
+// from the bug report screen while new reports may be queued. sendMu lets one
+// Send run at a time, so no report is posted twice. outboxMu guards the
+// outbox files and is never held while a report is posted, so queuing and
+// listing reports don't wait on the network.
+var (
+	sendMu   sync.Mutex
+	outboxMu sync.Mutex
+)
+
+// Outbox keeps reports the worker couldn't be reached for, on disk or in
+// browser storage, and sends them on a later launch. Reports are kept one per
+// Signature.
+type Outbox struct {
+	Worker *WorkerSubmitter
+	now    func() time.Time
+}
+
+// NewOutbox creates an Outbox that sends through worker.
+func NewOutbox(worker *WorkerSubmitter) *Outbox {
+	return &Outbox{Worker: worker, now: time.Now}
+}
+
+// Signature identifies a report for deduplication. Crashes are identified by
+// a hash of their stack trace, ignoring the goroutine numbers, arguments and
+// offsets that change from run to run, so the same crash is only queued once.
+// Every other report is its own signature.
+func (r *BugReport) Signature() string {
+	if !r.IsCrash {
+		return r.ID
+	}
+	frames := stackFrames(r.StackTrace)
+	if len(frames) == 0 {
+		frames = []string{r.CrashMessage}
+	}
+	sum := sha256.Sum256([]byte(strings.Join(frames, "\n")))
+	return "crash_" + hex.EncodeToString(sum[:8])
+}
+
+// stackFrames reduces a goroutine stack trace to its function names and
+// source lines.
+func stackFrames(stack string) []string {
+	var frames []string
+	for line := range strings.SplitSeq(stack, "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "", strings.HasPrefix(line, "goroutine "):
+			continue
+		case strings.HasPrefix(line, "created by "):
+			line, _, _ = strings.Cut(line, " in goroutine ")
+		case strings.Contains(line, ".go:"):
+			line, _, _ = strings.Cut(line, " +0x")
+		default:
+			if i := strings.LastIndex(line, "("); i > 0 {
+				line = line[:i]
+			}
+		}
+		frames = append(frames, line)
+	}
+	return frames
+}
+
+// Queue adds report to the outbox after a failed submission. If a report
+// with the same signature is already queued it is counted rather than
+// queued twice.
+func (o *Outbox) Queue(report *BugReport, cause error) error {
+	outboxMu.Lock()
+	defer outboxMu.Unlock()
+	filename := outboxFileName(report.Signature())
+	if queued, err := readQueuedReport(filename); err == nil {
+		queued.Occurrences++
+		return writeQueuedReport(queued)
+	}
+
+	data, err := json.Marshal(report)
+	if err != nil {
+		return fmt.Errorf("failed to serialize report: %w", err)
+	}
+	now := o.now()
+	queued := &QueuedReport{
+		Signature:   report.Signature(),
+		Title:       report.IssueTitle(),
+		QueuedAt:    now,
+		Occurrences: 1,
+		Attempts:    1,
+		NextAttempt: now.Add(outboxBackoff(1)),
+		Report:      data,
+	}
+	if cause != nil {
+		queued.LastError = cause.Error()
+	}
+	return writeQueuedReport(queued)
+}
+
+// Pending returns the queued reports, oldest first. Unreadable entries are
+// skipped.
+func (o *Outbox) Pending() ([]*QueuedReport, error) {
+	outboxMu.Lock()
+	defer outboxMu.Unlock()
+	names, err := save.ListOutboxFiles()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list outbox: %w", err)
+	}
+	var pending []*QueuedReport
+	for _, name := range names {
+		queued, err := readQueuedReport(name)
+		if err != nil {
+			continue
+		}
+		pending = append(pending, queued)
+	}
+	sort.Slice(pending, func(i, j int) bool {
+		return pending[i].QueuedAt.Before(pending[j].QueuedAt)
+	})
+	return pending, nil
+}
+
+// Send tries each queued report whose backoff has passed, or every queued
+// report when force is set. Sent reports leave the outbox; reports that fail
+// again wait twice as long before the next try. A Send started while another
+// is running waits for it, then finds the reports it sent gone.
+func (o *Outbox) Send(force bool) (OutboxResult, error) {
+	var result OutboxResult
+	if o.Worker == nil || o.Worker.WorkerURL == "" {
+		return result, fmt.Errorf("worker URL not configured")
+	}
+	sendMu.Lock()
+	defer sendMu.Unlock()
+	pending, err := o.Pending()
+	if err != nil {
+		return result, err
+	}
+
+	for _, queued := range pending {
+		if !force && o.now().Before(queued.NextAttempt) {
+			result.Waiting++
+			continue
+		}
+		filename := outboxFileName(queued.Signature)
+		report, err := ParseReport(queued.Report)
+		if err != nil {
+			logger.Warn("dropping unreadable queued report", "signature", queued.Signature, "err", err)
+			result.Dropped++
+			_ = removeQueuedReport(filename)
+			continue
+		}
+
+		res, err := o.Worker.send(report, queued.Occurrences)
+		switch {
+		case err == nil:
+			result.Sent++
+			if res.IssueURL != "" {
+				result.IssueURLs = append(result.IssueURLs, res.IssueURL)
+			}
+			if err := o.markSent(queued); err != nil {
+				return result, err
+			}
+		case !retryable(err):
+			logger.Warn("worker rejected queued report", "signature", queued.Signature, "err", err)
+			result.Dropped++
+			if err := removeQueuedReport(filename); err != nil {
+				return result, err
+			}
+		default:
+			result.Failed++
+			if err := o.retryLater(queued, err); err != nil {
+				return result, err
+			}
+		}
+	}
+	return result, nil
+}
+
+// retryLater puts queued back in the outbox after another failed attempt,
+// keeping any occurrences counted while it was being sent.
+func (o *Outbox) retryLater(queued *QueuedReport, cause error) error {
+	outboxMu.Lock()
+	defer outboxMu.Unlock()
+	if current, err := readQueuedReport(outboxFileName(queued.Signature)); err == nil {
+		queued.Occurrences = current.Occurrences
+	}
+	queued.Attempts++
+	queued.NextAttempt = o.now().Add(outboxBackoff(queued.Attempts))
+	queued.LastError = cause.Error()
+	return writeQueuedReport(queued)
+}
+
+// markSent takes queued out of the outbox now that it has been sent. If it was
+// filed again while it was being sent, it stays queued with just the new
+// occurrences, to be sent on the next pass.
+func (o *Outbox) markSent(queued *QueuedReport) error {
+	outboxMu.Lock()
+	defer outboxMu.Unlock()
+	filename := outboxFileName(queued.Signature)
+	current, err := readQueuedReport(filename)
+	if err != nil || current.Occurrences <= queued.Occurrences {
+		return save.RemoveOutboxFile(filename)
+	}
+	current.Occurrences -= queued.Occurrences
+	current.Attempts = 0
+	current.NextAttempt = o.now()
+	current.LastError = ""
+	return writeQueuedReport(current)
+}
+
+func removeQueuedReport(filename string) error {
+	outboxMu.Lock()
+	defer outboxMu.Unlock()
+	return save.RemoveOutboxFile(filename)
+}
+
+// outboxBackoff returns how long to wait after the given number of failed
+// attempts.
+func outboxBackoff(attempts int) time.Duration {
+	wait := outboxBaseBackoff
+	for range attempts - 1 {
+		wait *= 2
+		if wait >= outboxMaxBackoff {
+			return outboxMaxBackoff
+		}
+	}
+	return wait
+}
+
+// retryable reports whether a failed submission is worth trying again: the
+// worker couldn't be reached or was having trouble, rather than refusing the
+// report.
+func retryable(err error) bool {
+	var statusErr *workerStatusError
+	if !errors.As(err, &statusErr) {
+		return true
+	}
+	code := statusErr.StatusCode
+	return code >= 500 || code == http.StatusRequestTimeout || code == http.StatusTooManyRequests
+}
+
+func outboxFileName(signature string) string {
+	return signature + ".json"
+}
+
+func readQueuedReport(filename string) (*QueuedReport, error) {
+	data, err := save.ReadOutboxFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	var queued QueuedReport
+	if err := json.Unmarshal(data, &queued); err != nil {
+		return nil, fmt.Errorf("failed to parse queued report %s: %w", filename, err)
+	}
+	return &queued, nil
+}
+
+func writeQueuedReport(queued *QueuedReport) error {
+	data, err := json.Marshal(queued)
+	if err != nil {
+		return fmt.Errorf("failed to serialize queued report: %w", err)
+	}
+	if err := save.WriteOutboxFile(outboxFileName(queued.Signature), data); err != nil {
+		return fmt.Errorf("failed to queue report: %w", err)
+	}
+	return nil
+}
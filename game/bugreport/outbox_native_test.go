@@ -0,0 +1,258 @@
+//go:build !js
+
+package bugreport
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/benprew/s30/game/save"
+)
+
+// fakeWorker stands in for the issues worker, answering with status until
+// it is changed.
+type fakeWorker struct {
+	mu     sync.Mutex
+	status int
+	delay  time.Duration // how long each answer takes
+	bodies []string
+	// onRequest, if set, runs while a report is being posted.
+	onRequest func()
+}
+
+func (w *fakeWorker) setStatus(status int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.status = status
+}
+
+func (w *fakeWorker) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	time.Sleep(w.delay)
+	if w.onRequest != nil {
+		w.onRequest()
+	}
+	if w.status != http.StatusOK {
+		http.Error(rw, "unavailable", w.status)
+		return
+	}
+	var payload struct {
+		Body string `json:"body"`
+	}
+	_ = json.NewDecoder(r.Body).Decode(&payload)
+	w.bodies = append(w.bodies, payload.Body)
+	_ = json.NewEncoder(rw).Encode(SubmitResult{Success: true, IssueURL: "https://github.com/benprew/s30/issues/7"})
+}
+
+func newOutboxTest(t *testing.T, status int) (*fakeWorker, *CompositeSubmitter, *time.Time) {
+	t.Helper()
+	save.SetSaveDir(filepath.Join(t.TempDir(), "saves"))
+	t.Cleanup(func() { save.SetSaveDir("") })
+
+	worker := &fakeWorker{status: status}
+	server := httptest.NewServer(worker)
+	t.Cleanup(server.Close)
+
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	submitter := NewWorkerSubmitter(server.URL)
+	outbox := NewOutbox(submitter)
+	outbox.now = func() time.Time { return now }
+	return worker, &CompositeSubmitter{
+		Worker: submitter,
+		Local:  NewLocalFileSubmitter(t.TempDir(), func(string) error { return nil }),
+		Outbox: outbox,
+	}, &now
+}
+
+func crashReport(id, goroutine, arg string) *BugReport {
+	return &BugReport{
+		ID:           id,
+		Timestamp:    time.Now(),
+		IsCrash:      true,
+		CrashMessage: "index out of range [" + arg + "] with length 3",
+		StackTrace: "goroutine " + goroutine + " [running]:\n" +
+			"github.com/benprew/s30/game/world.(*Level).UpdateWorld(0xc000" + arg + ")\n" +
+			"\t/src/s30/game/world/level.go:120 +0x1d4\n" +
+			"created by main.main in goroutine 1\n" +
+			"\t/src/s30/main.go:40 +0x88",
+		ActiveScreen: "World",
+	}
+}
+
+func TestSignatureIgnoresRunToRunDetails(t *testing.T) {
+	a, b := crashReport("crash_a", "1", "5"), crashReport("crash_b", "17", "9")
+	if a.Signature() != b.Signature() {
+		t.Errorf("signatures differ for the same crash: %s, %s", a.Signature(), b.Signature())
+	}
+	other := crashReport("crash_c", "1", "5")
+	other.StackTrace = strings.Replace(other.StackTrace, "level.go:120", "level.go:121", 1)
+	if other.Signature() == a.Signature() {
+		t.Error("expected a crash on another line to have another signature")
+	}
+	bug := &BugReport{ID: "bug_1"}
+	if bug.Signature() != "bug_1" {
+		t.Errorf("bug signature = %q, want its ID", bug.Signature())
+	}
+}
+
+func TestCompositeSubmitterQueuesAndRetriesWhenOffline(t *testing.T) {
+	worker, submitter, now := newOutboxTest(t, http.StatusServiceUnavailable)
+
+	for _, report := range []*BugReport{crashReport("crash_a", "1", "5"), crashReport("crash_b", "8", "6")} {
+		result, err := submitter.Submit(report)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !result.Queued || result.LocalFilePath == "" {
+			t.Errorf("result = %+v, want saved locally and queued", result)
+		}
+	}
+	pending, err := submitter.Outbox.Pending()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pending) != 1 || pending[0].Occurrences != 2 {
+		t.Fatalf("pending = %+v, want one crash seen twice", pending)
+	}
+
+	worker.setStatus(http.StatusOK)
+	result, err := submitter.SendQueued(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Waiting != 1 || result.Sent != 0 {
+		t.Errorf("result before the backoff = %+v, want it waiting", result)
+	}
+
+	*now = now.Add(outboxBaseBackoff)
+	result, err = submitter.SendQueued(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Sent != 1 || len(result.IssueURLs) != 1 {
+		t.Errorf("result = %+v, want the crash sent", result)
+	}
+	if len(worker.bodies) != 1 || !strings.Contains(worker.bodies[0], "Reported 2 times while offline") {
+		t.Errorf("worker received %q", worker.bodies)
+	}
+	if pending, _ := submitter.Outbox.Pending(); len(pending) != 0 {
+		t.Errorf("%d reports still queued after sending", len(pending))
+	}
+}
+
+func TestConcurrentSendsPostAQueuedReportOnce(t *testing.T) {
+	worker, submitter, _ := newOutboxTest(t, http.StatusServiceUnavailable)
+	if _, err := submitter.Submit(&BugReport{ID: "bug_1", UserNotes: "cards vanish"}); err != nil {
+		t.Fatal(err)
+	}
+	worker.mu.Lock()
+	worker.status = http.StatusOK
+	worker.delay = 50 * time.Millisecond
+	worker.mu.Unlock()
+
+	// The game retries the outbox at launch while the bug report screen may
+	// be retrying it too.
+	var wg sync.WaitGroup
+	results := make([]OutboxResult, 2)
+	for i := range results {
+		wg.Go(func() {
+			var err error
+			if results[i], err = submitter.SendQueued(true); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+	wg.Wait()
+
+	if sent := results[0].Sent + results[1].Sent; sent != 1 {
+		t.Errorf("results = %+v, want the report sent once", results)
+	}
+	if len(worker.bodies) != 1 {
+		t.Errorf("worker received %d reports, want 1", len(worker.bodies))
+	}
+}
+
+func TestOutboxKeepsOccurrencesFiledDuringASend(t *testing.T) {
+	worker, submitter, _ := newOutboxTest(t, http.StatusServiceUnavailable)
+	if _, err := submitter.Submit(crashReport("crash_a", "1", "5")); err != nil {
+		t.Fatal(err)
+	}
+	// The same crash happens again while the queued one is being posted.
+	worker.mu.Lock()
+	worker.status = http.StatusOK
+	worker.onRequest = func() {
+		if err := submitter.Outbox.Queue(crashReport("crash_b", "8", "6"), nil); err != nil {
+			t.Error(err)
+		}
+	}
+	worker.mu.Unlock()
+
+	result, err := submitter.SendQueued(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Sent != 1 {
+		t.Errorf("result = %+v, want the crash sent", result)
+	}
+	pending, err := submitter.Outbox.Pending()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pending) != 1 || pending[0].Occurrences != 1 {
+		t.Fatalf("pending = %+v, want the crash queued again for the one new occurrence", pending)
+	}
+
+	worker.mu.Lock()
+	worker.onRequest = nil
+	worker.mu.Unlock()
+	if result, err := submitter.SendQueued(false); err != nil || result.Sent != 1 {
+		t.Errorf("second pass = %+v, %v; want the new occurrence sent", result, err)
+	}
+	if pending, _ := submitter.Outbox.Pending(); len(pending) != 0 {
+		t.Errorf("%d reports still queued after sending", len(pending))
+	}
+}
+
+func TestOutboxBacksOffAndDropsRejectedReports(t *testing.T) {
+	worker, submitter, now := newOutboxTest(t, http.StatusServiceUnavailable)
+	if _, err := submitter.Submit(&BugReport{ID: "bug_1", UserNotes: "cards vanish"}); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := submitter.SendQueued(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Failed != 1 {
+		t.Errorf("result = %+v, want a failed retry", result)
+	}
+	pending, _ := submitter.Outbox.Pending()
+	if len(pending) != 1 || pending[0].Attempts != 2 || !pending[0].NextAttempt.Equal(now.Add(2*outboxBaseBackoff)) {
+		t.Fatalf("pending = %+v, want a second attempt and a doubled wait", pending)
+	}
+	if outboxBackoff(20) != outboxMaxBackoff {
+		t.Errorf("backoff after 20 attempts = %v, want the %v cap", outboxBackoff(20), outboxMaxBackoff)
+	}
+
+	worker.setStatus(http.StatusBadRequest)
+	result, err = submitter.SendQueued(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Dropped != 1 {
+		t.Errorf("result = %+v, want the rejected report dropped", result)
+	}
+	if pending, _ := submitter.Outbox.Pending(); len(pending) != 0 {
+		t.Errorf("%d reports still queued after the worker rejected them", len(pending))
+	}
+	if res, _ := submitter.Submit(&BugReport{ID: "bug_2"}); res.Queued {
+		t.Error("expected a report the worker rejects not to be queued")
+	}
+}
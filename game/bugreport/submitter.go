@@ -8,8 +8,12 @@ import (
 	"os"
 	"path/filepath"
 	"time"
+
+	"github.com/benprew/s30/logging"
 )
 
+var logger = logging.Logger(logging.Game)
+
 // EnvWorkerURL is the environment variable used to configure the worker endpoint.
 const EnvWorkerURL = "S30_BUG_WORKER_URL"
 
@@ -23,6 +27,9 @@ type SubmitResult struct {
 	IssueNumber   int    `json:"issue_number,omitempty"`
 	LocalFilePath string `json:"local_file_path,omitempty"`
 	Message       string `json:"message"`
+	// Queued is set when the worker couldn't be reached and the report was
+	// put in the outbox to send later.
+	Queued bool `json:"queued,omitempty"`
 }
 
 // Submitter delivers bug and crash reports.
@@ -44,14 +51,33 @@ func NewWorkerSubmitter(workerURL string) *WorkerSubmitter {
 	}
 }
 
+// workerStatusError is a response from the worker outside the 2xx range.
+type workerStatusError struct {
+	StatusCode int
+}
+
+func (e *workerStatusError) Error() string {
+	return fmt.Sprintf("worker returned HTTP status %d", e.StatusCode)
+}
+
 func (s *WorkerSubmitter) Submit(report *BugReport) (*SubmitResult, error) {
+	return s.send(report, 1)
+}
+
+// send files report, noting in the issue body when it was filed more than
+// once while queued.
+func (s *WorkerSubmitter) send(report *BugReport, occurrences int) (*SubmitResult, error) {
 	if s.WorkerURL == "" {
 		return nil, fmt.Errorf("worker URL not configured")
 	}
 
+	body := report.ToMarkdown()
+	if occurrences > 1 {
+		body = fmt.Sprintf("> Reported %d times while offline.\n\n%s", occurrences, body)
+	}
 	payload := map[string]any{
 		"title":    report.IssueTitle(),
-		"body":     report.ToMarkdown(),
+		"body":     body,
 		"is_crash": report.IsCrash,
 		"report":   report,
 	}
@@ -75,7 +101,7 @@ func (s *WorkerSubmitter) Submit(report *BugReport) (*SubmitResult, error) {
 	defer resp.Body.Close()
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, fmt.Errorf("worker returned HTTP status %d", resp.StatusCode)
+		return nil, &workerStatusError{StatusCode: resp.StatusCode}
 	}
 
 	var result SubmitResult
@@ -152,9 +178,11 @@ func (s *LocalFileSubmitter) Submit(report *BugReport) (*SubmitResult, error) {
 }
 
 // CompositeSubmitter saves locally and sends to the worker if configured.
+// Reports the worker couldn't take are queued in Outbox, when set.
 type CompositeSubmitter struct {
 	Worker *WorkerSubmitter
 	Local  *LocalFileSubmitter
+	Outbox *Outbox
 }
 
 // NewDefaultSubmitter creates the standard submitter with configured worker and local fallback.
@@ -165,13 +193,16 @@ func NewDefaultSubmitter() *CompositeSubmitter {
 	}
 
 	var worker *WorkerSubmitter
+	var outbox *Outbox
 	if workerURL != "" {
 		worker = NewWorkerSubmitter(workerURL)
+		outbox = NewOutbox(worker)
 	}
 
 	return &CompositeSubmitter{
 		Worker: worker,
 		Local:  NewLocalFileSubmitter("", nil),
+		Outbox: outbox,
 	}
 }
 
@@ -188,9 +219,25 @@ func (c *CompositeSubmitter) Submit(report *BugReport) (*SubmitResult, error) {
 	workerRes, workerErr := c.Worker.Submit(report)
 	if workerErr != nil {
 		localRes.Message = fmt.Sprintf("Saved locally to %s (worker error: %v)", localRes.LocalFilePath, workerErr)
+		if c.Outbox != nil && retryable(workerErr) {
+			if err := c.Outbox.Queue(report, workerErr); err != nil {
+				logger.Warn("failed to queue report", "report", report.ID, "err", err)
+			} else {
+				localRes.Queued = true
+				localRes.Message = fmt.Sprintf("Saved locally to %s and queued to send later (worker error: %v)", localRes.LocalFilePath, workerErr)
+			}
+		}
 		return localRes, nil
 	}
 
 	workerRes.LocalFilePath = localRes.LocalFilePath
 	return workerRes, nil
 }
+
+// SendQueued sends the reports waiting in the outbox; see Outbox.Send.
+func (c *CompositeSubmitter) SendQueued(force bool) (OutboxResult, error) {
+	if c.Outbox == nil {
+		return OutboxResult{}, fmt.Errorf("worker URL not configured")
+	}
+	return c.Outbox.Send(force)
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/benprew/mage-go/pkg/mage/interactive"
+	"github.com/benprew/s30/game/replay"
 	"github.com/benprew/s30/game/save"
 )
 
@@ -40,6 +41,9 @@ type DuelReportState struct {
 	AIDeck         []string               `json:"ai_deck,omitempty"`
 	History        []string               `json:"history,omitempty"`
 	EngineLogs     []string               `json:"engine_logs,omitempty"`
+	// Replay records the duel so far; it can be saved as a replay file and
+	// played back with the -replay flag.
+	Replay *replay.Recording `json:"replay,omitempty"`
 }
 
 // BugReport represents a complete bug or crash report.
@@ -54,6 +58,8 @@ type BugReport struct {
 	ActiveScreen string           `json:"active_screen"`
 	WorldState   *save.SaveData   `json:"world_state,omitempty"`
 	DuelState    *DuelReportState `json:"duel_state,omitempty"`
+	// EngineLogs are the game's most recent log lines, oldest first.
+	EngineLogs []string `json:"engine_logs,omitempty"`
 }
 
 // ToJSON serializes the BugReport into formatted JSON.
@@ -113,6 +119,9 @@ func (r *BugReport) ToMarkdown() string {
 	if r.Environment.FPS > 0 {
 		fmt.Fprintf(&b, "- **FPS / TPS:** %.1f / %.1f\n", r.Environment.FPS, r.Environment.TPS)
 	}
+	if r.WorldState != nil && r.WorldState.World != nil {
+		fmt.Fprintf(&b, "- **World Seed:** %d\n", r.WorldState.World.Seed)
+	}
 	fmt.Fprintf(&b, "- **Timestamp:** %s\n\n", r.Timestamp.Format(time.RFC3339))
 
 	if r.DuelState != nil {
@@ -133,7 +142,19 @@ func (r *BugReport) ToMarkdown() string {
 		}
 	}
 
-	jsonData, err := r.ToJSON()
+	if len(r.EngineLogs) > 0 {
+		b.WriteString("<details><summary><b>Recent Log (click to expand)</b></summary>\n\n```\n")
+		for _, line := range r.EngineLogs {
+			b.WriteString(line)
+			b.WriteString("\n")
+		}
+		b.WriteString("```\n</details>\n\n")
+	}
+
+	// The log lines are already above, so the JSON leaves them out.
+	dump := *r
+	dump.EngineLogs = nil
+	jsonData, err := dump.ToJSON()
 	if err == nil {
 		b.WriteString("<details><summary><b>Full State JSON (click to expand)</b></summary>\n\n```json\n")
 		b.WriteString(string(jsonData))
@@ -5,6 +5,8 @@ import (
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/benprew/s30/logging"
 )
 
 func TestBugReportSerialization(t *testing.T) {
@@ -128,3 +130,26 @@ func TestCrashReportMarkdownFormatting(t *testing.T) {
 		t.Errorf("Crash title = %q, want prefix [Crash]", title)
 	}
 }
+
+func TestReportAttachesRecentLogs(t *testing.T) {
+	logging.Logger(logging.Duel).Warn("mana pool emptied early", "player", "You")
+
+	report := CollectReport(nil, "Duel", nil, "lost my mana")
+	if len(report.EngineLogs) == 0 {
+		t.Fatal("expected the report to carry the recent log lines")
+	}
+	last := report.EngineLogs[len(report.EngineLogs)-1]
+	if !strings.Contains(last, "WARN duel: mana pool emptied early player=You") {
+		t.Errorf("last log line = %q", last)
+	}
+	md := report.ToMarkdown()
+	if !strings.Contains(md, "Recent Log") || !strings.Contains(md, "mana pool emptied early") {
+		t.Errorf("Markdown missing the recent log: %s", md)
+	}
+	if n := strings.Count(md, "mana pool emptied early"); n != 1 {
+		t.Errorf("Markdown has the log line %d times, want it once", n)
+	}
+	if data, _ := report.ToJSON(); !strings.Contains(string(data), "mana pool emptied early") {
+		t.Error("expected the JSON report to keep the recent log")
+	}
+}
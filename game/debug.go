@@ -1,10 +1,14 @@
 package game
 
 import (
+	"cmp"
 	"fmt"
 
 	"github.com/benprew/mage-go/pkg/mage/interactive"
+	"github.com/benprew/s30/game/bugreport"
 	"github.com/benprew/s30/game/domain"
+	"github.com/benprew/s30/game/save"
+	duelscreen "github.com/benprew/s30/game/screens/duel"
 	"github.com/benprew/s30/game/world"
 )
 
@@ -15,6 +19,43 @@ var debugBurnDeck = map[string]int{
 
 func applyRuntimeOptions(options Options) {
 	interactive.RevealOpponentHand = options.ShowOpponentHand
+	duelscreen.RecordReplays = options.RecordDuels
+}
+
+// setupCardImageCache keeps downloaded card images on disk beside the saves
+// and points card image fetches at the chosen mirror. Without a cache
+// directory, images are downloaded afresh each run.
+func setupCardImageCache(options Options) {
+	domain.SetCardImageMirror(options.CardImageMirror)
+	if options.CardImageCacheBytes < 0 {
+		return
+	}
+	dir, err := save.CardImageDir()
+	if err != nil {
+		logger.Warn("no card image cache", "err", err)
+		return
+	}
+	limit := cmp.Or(options.CardImageCacheBytes, domain.DefaultCardImageCacheBytes)
+	if err := domain.SetCardImageCacheDir(dir, limit); err != nil {
+		logger.Warn("no card image cache", "err", err)
+	}
+}
+
+// sendQueuedReports retries the bug reports queued while the worker was
+// unreachable, once each report's backoff has passed.
+func sendQueuedReports() {
+	submitter := bugreport.NewDefaultSubmitter()
+	if submitter.Outbox == nil {
+		return
+	}
+	result, err := submitter.SendQueued(false)
+	if err != nil {
+		logger.Warn("failed to send queued bug reports", "err", err)
+		return
+	}
+	if result.Sent > 0 {
+		logger.Info("sent queued bug reports", "count", result.Sent)
+	}
 }
 
 func applyDebugOptions(level *world.Level, options Options) error {
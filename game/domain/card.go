@@ -265,6 +265,7 @@ func (card *Card) CardImage(view CardView) (*ebiten.Image, error) {
 
 	if cached, ok := cardImages.Load(card.cardID); ok {
 		fullImg = cached.(*ebiten.Image)
+		cardImageCounters.memoryHits.Add(1)
 	} else {
 		if _, alreadyFetching := fetchingSet.LoadOrStore(card.cardID, true); !alreadyFetching {
 			go fetchAndCacheCardImage(card)
@@ -282,32 +283,6 @@ func (card *Card) CardImage(view CardView) (*ebiten.Image, error) {
 	return fullImg, nil
 }
 
-func (c *Card) SalePrice(city *City) int {
-	basePercentage := 0.5
-	switch city.Tier {
-	case TierTown:
-		basePercentage = 0.6
-	case TierCapital:
-		basePercentage = 0.75
-	}
-
-	hasColorMatch := false
-	for _, colorStr := range c.ColorIdentity {
-		if colorMask, ok := colorStringToMask[colorStr]; ok {
-			if city.AmuletColor&colorMask != 0 {
-				hasColorMatch = true
-				break
-			}
-		}
-	}
-
-	if hasColorMatch {
-		basePercentage += 0.1
-	}
-
-	return int(float64(c.Price) * basePercentage)
-}
-
 func sanitizeFilename(name string) string {
 	name = strings.ToLower(name)
 
@@ -0,0 +1,422 @@
+package domain
+
+import (
+	"bytes"
+	"container/list"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultCardImageCacheBytes is the disk cache size the game uses unless told
+// otherwise: room for every card in the database several times over.
+const DefaultCardImageCacheBytes = 256 << 20
+
+// maxCardImageBytes caps a single downloaded image, so a misconfigured mirror
+// can't fill memory.
+const maxCardImageBytes = 8 << 20
+
+// CardImageStats counts the card image caches' contents and traffic.
+type CardImageStats struct {
+	Cards               int // full-card images held in memory
+	LabeledPlaceholders int // generated placeholders held in memory
+	MemoryHits          int64
+	DiskHits            int64
+	Downloads           int64 // images fetched from the mirror or Scryfall
+	DownloadErrors      int64
+	DiskFiles           int
+	DiskBytes           int64
+	Evictions           int64 // disk files removed to stay under the size limit
+}
+
+var cardImageCounters struct {
+	memoryHits, diskHits, downloads, downloadErrors, evictions atomic.Int64
+}
+
+// CardImageCacheStats reports the card image caches' contents and hit counts.
+// Profiling harnesses use these alongside heap and RSS samples.
+func CardImageCacheStats() CardImageStats {
+	var s CardImageStats
+	cardImages.Range(func(_, _ any) bool {
+		s.Cards++
+		return true
+	})
+	labeledBlankCards.Range(func(_, _ any) bool {
+		s.LabeledPlaceholders++
+		return true
+	})
+	s.MemoryHits = cardImageCounters.memoryHits.Load()
+	s.DiskHits = cardImageCounters.diskHits.Load()
+	s.Downloads = cardImageCounters.downloads.Load()
+	s.DownloadErrors = cardImageCounters.downloadErrors.Load()
+	s.Evictions = cardImageCounters.evictions.Load()
+	s.DiskFiles, s.DiskBytes = imageDisk.usage()
+	return s
+}
+
+// ResetCardImageCacheStats zeroes the hit counters.
+func ResetCardImageCacheStats() {
+	cardImageCounters.memoryHits.Store(0)
+	cardImageCounters.diskHits.Store(0)
+	cardImageCounters.downloads.Store(0)
+	cardImageCounters.downloadErrors.Store(0)
+	cardImageCounters.evictions.Store(0)
+}
+
+// cardImageDisk keeps downloaded card images in a directory, one file per card
+// ID, and removes the least recently used files when they pass maxBytes. The
+// files are named as a directory mirror expects, so one player's cache can be
+// another's mirror.
+type cardImageDisk struct {
+	mu       sync.Mutex
+	dir      string // "" disables the disk cache
+	maxBytes int64
+	size     int64
+	lru      *list.List               // of *diskEntry, most recently used first
+	entries  map[string]*list.Element // by card ID
+}
+
+type diskEntry struct {
+	id   string
+	file string
+	size int64
+}
+
+var imageDisk cardImageDisk
+
+// SetCardImageCacheDir keeps downloaded card images in dir, using at most
+// maxBytes of disk. Files already in dir are kept, oldest first in line for
+// eviction. An empty dir turns the disk cache off.
+func SetCardImageCacheDir(dir string, maxBytes int64) error {
+	return imageDisk.open(dir, maxBytes)
+}
+
+// CardImageCacheDir returns the disk cache directory, or "" when it is off.
+func CardImageCacheDir() string {
+	imageDisk.mu.Lock()
+	defer imageDisk.mu.Unlock()
+	return imageDisk.dir
+}
+
+func (d *cardImageDisk) open(dir string, maxBytes int64) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.dir, d.maxBytes, d.size = "", maxBytes, 0
+	d.lru, d.entries = list.New(), make(map[string]*list.Element)
+	if dir == "" {
+		return nil
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("create card image cache: %w", err)
+	}
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("read card image cache: %w", err)
+	}
+
+	type found struct {
+		entry   *diskEntry
+		modTime time.Time
+	}
+	var existing []found
+	for _, f := range files {
+		if strings.HasPrefix(f.Name(), ".download-") {
+			os.Remove(filepath.Join(dir, f.Name())) // left by a crash mid-write
+			continue
+		}
+		ext := filepath.Ext(f.Name())
+		if f.IsDir() || (ext != ".jpg" && ext != ".png") {
+			continue
+		}
+		id := strings.TrimSuffix(f.Name(), ext)
+		info, err := f.Info()
+		if err != nil {
+			continue
+		}
+		existing = append(existing, found{&diskEntry{id: id, file: f.Name(), size: info.Size()}, info.ModTime()})
+	}
+	// Newest first, so the oldest files are evicted first.
+	slices.SortFunc(existing, func(a, b found) int { return b.modTime.Compare(a.modTime) })
+	d.dir = dir
+	for _, f := range existing {
+		if _, dup := d.entries[f.entry.id]; dup {
+			continue
+		}
+		d.entries[f.entry.id] = d.lru.PushBack(f.entry)
+		d.size += f.entry.size
+	}
+	d.evict()
+	return nil
+}
+
+// get returns the cached image data for id and marks it recently used.
+func (d *cardImageDisk) get(id string) ([]byte, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.dir == "" {
+		return nil, false
+	}
+	el, ok := d.entries[id]
+	if !ok {
+		return nil, false
+	}
+	e := el.Value.(*diskEntry)
+	path := filepath.Join(d.dir, e.file)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		d.remove(el)
+		return nil, false
+	}
+	d.lru.MoveToFront(el)
+	now := time.Now()
+	_ = os.Chtimes(path, now, now) // so the order survives a restart
+	return data, true
+}
+
+// put stores data as id's image, replacing any older copy, then evicts the
+// least recently used images past the size limit.
+func (d *cardImageDisk) put(id string, data []byte) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.dir == "" {
+		return nil
+	}
+	file := id + cardImageExt(data)
+	tmp, err := os.CreateTemp(d.dir, ".download-*")
+	if err != nil {
+		return fmt.Errorf("cache card image %s: %w", id, err)
+	}
+	_, writeErr := tmp.Write(data)
+	closeErr := tmp.Close()
+	if err := errors.Join(writeErr, closeErr); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("cache card image %s: %w", id, err)
+	}
+	if el, ok := d.entries[id]; ok {
+		d.remove(el)
+	}
+	if err := os.Rename(tmp.Name(), filepath.Join(d.dir, file)); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("cache card image %s: %w", id, err)
+	}
+	d.entries[id] = d.lru.PushFront(&diskEntry{id: id, file: file, size: int64(len(data))})
+	d.size += int64(len(data))
+	d.evict()
+	return nil
+}
+
+// has reports whether id's image is on disk.
+func (d *cardImageDisk) has(id string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	_, ok := d.entries[id]
+	return ok
+}
+
+func (d *cardImageDisk) usage() (files int, bytes int64) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return len(d.entries), d.size
+}
+
+// evict removes least recently used files until the cache fits. The caller
+// holds d.mu.
+func (d *cardImageDisk) evict() {
+	for d.maxBytes > 0 && d.size > d.maxBytes && d.lru.Len() > 0 {
+		d.remove(d.lru.Back())
+		cardImageCounters.evictions.Add(1)
+	}
+}
+
+// remove drops an entry and its file. The caller holds d.mu.
+func (d *cardImageDisk) remove(el *list.Element) {
+	e := d.lru.Remove(el).(*diskEntry)
+	delete(d.entries, e.id)
+	d.size -= e.size
+	if err := os.Remove(filepath.Join(d.dir, e.file)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		logger.Warn("failed to remove cached card image", "file", e.file, "err", err)
+	}
+}
+
+func cardImageExt(data []byte) string {
+	if http.DetectContentType(data) == "image/png" {
+		return ".png"
+	}
+	return ".jpg"
+}
+
+var cardImageMirror struct {
+	sync.RWMutex
+	base string
+}
+
+// mirrorImageExts are the file extensions a mirror may keep a card's image
+// under, in the order they are tried.
+var mirrorImageExts = []string{".jpg", ".png", ".jpeg"}
+
+// errCardImageNotFound is returned for an image a mirror doesn't have.
+var errCardImageNotFound = errors.New("card image not found")
+
+// SetCardImageMirror fetches card images from base instead of Scryfall. base
+// is either an http(s) URL or a directory (optionally as a file:// URL), such
+// as another player's card image cache, of <card ID>.jpg or .png files. An
+// empty base restores Scryfall.
+func SetCardImageMirror(base string) {
+	cardImageMirror.Lock()
+	defer cardImageMirror.Unlock()
+	cardImageMirror.base = strings.TrimSuffix(base, "/")
+}
+
+// downloadCardImage returns card's image data from the mirror, or from
+// Scryfall when there is no mirror.
+func downloadCardImage(card *Card) ([]byte, error) {
+	cardImageMirror.RLock()
+	base := cardImageMirror.base
+	cardImageMirror.RUnlock()
+
+	switch {
+	case base == "":
+		if card.BorderCropURL == "" {
+			return nil, fmt.Errorf("no image URL")
+		}
+		return httpGetCardImage(card.BorderCropURL)
+	case strings.HasPrefix(base, "http://") || strings.HasPrefix(base, "https://"):
+		for _, ext := range mirrorImageExts {
+			data, err := httpGetCardImage(base + "/" + url.PathEscape(card.cardID) + ext)
+			if !errors.Is(err, errCardImageNotFound) {
+				return data, err
+			}
+		}
+		return nil, fmt.Errorf("not in mirror %s", base)
+	}
+
+	dir := strings.TrimPrefix(base, "file://")
+	for _, ext := range mirrorImageExts {
+		data, err := os.ReadFile(filepath.Join(dir, card.cardID+ext))
+		if err == nil {
+			return data, nil
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+	}
+	return nil, fmt.Errorf("not in mirror %s", dir)
+}
+
+func httpGetCardImage(u string) ([]byte, error) {
+	resp, err := http.Get(u)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("HTTP %d: %w", resp.StatusCode, errCardImageNotFound)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxCardImageBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxCardImageBytes {
+		return nil, fmt.Errorf("image over %d bytes", maxCardImageBytes)
+	}
+	return data, nil
+}
+
+// loadCardImageData returns card's image data, from the disk cache if it is
+// there and otherwise downloaded and added to the disk cache.
+func loadCardImageData(card *Card) ([]byte, error) {
+	if data, ok := imageDisk.get(card.cardID); ok {
+		if _, _, err := image.DecodeConfig(bytes.NewReader(data)); err == nil {
+			cardImageCounters.diskHits.Add(1)
+			return data, nil
+		}
+		logger.Warn("discarding unreadable cached card image", "card", card.CardName)
+	}
+
+	data, err := downloadCardImage(card)
+	if err != nil {
+		cardImageCounters.downloadErrors.Add(1)
+		return nil, err
+	}
+	if _, _, err := image.DecodeConfig(bytes.NewReader(data)); err != nil {
+		cardImageCounters.downloadErrors.Add(1)
+		return nil, fmt.Errorf("decode: %w", err)
+	}
+	cardImageCounters.downloads.Add(1)
+	if err := imageDisk.put(card.cardID, data); err != nil {
+		logger.Warn("failed to cache card image", "card", card.CardName, "err", err)
+	}
+	return data, nil
+}
+
+// PrefetchResult counts what PrefetchCardImages did.
+type PrefetchResult struct {
+	Cached, Downloaded, Failed int
+}
+
+// PrefetchCardImages makes sure every card's image is in the disk cache,
+// downloading the missing ones with workers at a time. It only fills the disk
+// cache, so it needs one (see SetCardImageCacheDir) and doesn't need a window.
+// progress, if not nil, is called after each card.
+func PrefetchCardImages(cards []*Card, workers int, progress func(done, total int)) (PrefetchResult, error) {
+	if CardImageCacheDir() == "" {
+		return PrefetchResult{}, fmt.Errorf("no card image cache directory set")
+	}
+	var unique []*Card
+	seen := make(map[string]bool, len(cards))
+	for _, c := range cards {
+		if c != nil && !seen[c.cardID] {
+			seen[c.cardID] = true
+			unique = append(unique, c)
+		}
+	}
+
+	var mu sync.Mutex
+	var result PrefetchResult
+	done := 0
+	ch := make(chan *Card)
+	var wg sync.WaitGroup
+	for range max(1, min(workers, len(unique))) {
+		wg.Go(func() {
+			for card := range ch {
+				cached := imageDisk.has(card.cardID)
+				_, err := loadCardImageData(card)
+
+				mu.Lock()
+				switch {
+				case err != nil:
+					result.Failed++
+					logger.Warn("failed to fetch card image", "card", card.CardName, "err", err)
+				case cached:
+					result.Cached++
+				default:
+					result.Downloaded++
+				}
+				done++
+				if progress != nil {
+					progress(done, len(unique))
+				}
+				mu.Unlock()
+			}
+		})
+	}
+	for _, card := range unique {
+		ch <- card
+	}
+	close(ch)
+	wg.Wait()
+	return result, nil
+}
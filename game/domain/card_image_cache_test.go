@@ -0,0 +1,221 @@
+package domain
+
+import (
+	"bytes"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync/atomic"
+	"testing"
+)
+
+// useCardImageCache points the disk cache at a fresh directory for one test.
+func useCardImageCache(t *testing.T, maxBytes int64) string {
+	t.Helper()
+	dir := t.TempDir()
+	if err := SetCardImageCacheDir(dir, maxBytes); err != nil {
+		t.Fatal(err)
+	}
+	cardImages.Clear()
+	ResetCardImageCacheStats()
+	t.Cleanup(func() {
+		SetCardImageCacheDir("", 0)
+		SetCardImageMirror("")
+		cardImages.Clear()
+		ResetCardImageCacheStats()
+	})
+	return dir
+}
+
+func pngCardImage(t *testing.T) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, solidCardImage(color.RGBA{R: 0x80, A: 0xff})); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func countingImageServer(t *testing.T, requests *atomic.Int32) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "image/jpeg")
+		if err := jpeg.Encode(w, solidCardImage(color.RGBA{G: 0xff, A: 0xff}), nil); err != nil {
+			t.Errorf("encode response: %v", err)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestCardImageDiskCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	data := pngCardImage(t)
+	dir := useCardImageCache(t, int64(3*len(data)))
+
+	for _, id := range []string{"tst-a", "tst-b", "tst-c"} {
+		if err := imageDisk.put(id, data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, ok := imageDisk.get("tst-a"); !ok {
+		t.Fatal("expected tst-a on disk")
+	}
+	if err := imageDisk.put("tst-d", data); err != nil {
+		t.Fatal(err)
+	}
+
+	for id, want := range map[string]bool{"tst-a": true, "tst-b": false, "tst-c": true, "tst-d": true} {
+		if got := imageDisk.has(id); got != want {
+			t.Errorf("has(%s) = %v, want %v", id, got, want)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "tst-b.png")); !os.IsNotExist(err) {
+		t.Errorf("expected the evicted file to be removed, stat err %v", err)
+	}
+	stats := CardImageCacheStats()
+	if stats.Evictions != 1 || stats.DiskFiles != 3 || stats.DiskBytes != int64(3*len(data)) {
+		t.Errorf("stats = %+v", stats)
+	}
+
+	// Reopening the directory, as the next run does, keeps what was cached.
+	if err := SetCardImageCacheDir(dir, int64(3*len(data))); err != nil {
+		t.Fatal(err)
+	}
+	if !imageDisk.has("tst-a") || imageDisk.has("tst-b") {
+		t.Error("expected the cache to be reloaded from disk")
+	}
+}
+
+func TestFetchAndCacheCardImageReadsDiskBeforeNetwork(t *testing.T) {
+	useCardImageCache(t, DefaultCardImageCacheBytes)
+	var requests atomic.Int32
+	server := countingImageServer(t, &requests)
+	card := &Card{CardName: "Disk Card", BorderCropURL: server.URL, cardID: "tst-disk"}
+
+	fetchAndCacheCardImage(card)
+	cardImages.Clear() // as if the game restarted
+	fetchAndCacheCardImage(card)
+
+	if requests.Load() != 1 {
+		t.Errorf("HTTP requests = %d, want 1", requests.Load())
+	}
+	if _, ok := cardImages.Load(card.cardID); !ok {
+		t.Fatal("card image was not cached in memory")
+	}
+	stats := CardImageCacheStats()
+	if stats.Downloads != 1 || stats.DiskHits != 1 || stats.DiskFiles != 1 {
+		t.Errorf("stats = %+v, want 1 download, 1 disk hit and 1 file", stats)
+	}
+}
+
+func TestCardImageMirrors(t *testing.T) {
+	useCardImageCache(t, DefaultCardImageCacheBytes)
+
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		if err := jpeg.Encode(w, solidCardImage(color.RGBA{B: 0xff, A: 0xff}), nil); err != nil {
+			t.Errorf("encode response: %v", err)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	SetCardImageMirror(server.URL + "/")
+	if _, err := downloadCardImage(&Card{CardName: "Http Card", cardID: "tst-http"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) != 1 || paths[0] != "/tst-http.jpg" {
+		t.Errorf("mirror requests = %q, want /tst-http.jpg", paths)
+	}
+
+	mirror := t.TempDir()
+	if err := os.WriteFile(filepath.Join(mirror, "tst-dir.png"), pngCardImage(t), 0644); err != nil {
+		t.Fatal(err)
+	}
+	SetCardImageMirror("file://" + mirror)
+	if _, err := downloadCardImage(&Card{CardName: "Dir Card", cardID: "tst-dir"}); err != nil {
+		t.Errorf("directory mirror: %v", err)
+	}
+	if _, err := downloadCardImage(&Card{CardName: "Missing", cardID: "tst-missing"}); err == nil {
+		t.Error("expected a card missing from the mirror to fail")
+	}
+}
+
+func TestHTTPCardImageMirrorServesPNGs(t *testing.T) {
+	useCardImageCache(t, DefaultCardImageCacheBytes)
+
+	pngData := pngCardImage(t)
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		if r.URL.Path != "/tst-png.png" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(pngData)
+	}))
+	t.Cleanup(server.Close)
+
+	SetCardImageMirror(server.URL)
+	data, err := downloadCardImage(&Card{CardName: "Png Card", cardID: "tst-png"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, pngData) {
+		t.Error("downloaded image isn't the mirror's PNG")
+	}
+	if want := []string{"/tst-png.jpg", "/tst-png.png"}; !slices.Equal(paths, want) {
+		t.Errorf("mirror requests = %q, want %q", paths, want)
+	}
+
+	paths = nil
+	if _, err := downloadCardImage(&Card{CardName: "Missing", cardID: "tst-missing"}); err == nil {
+		t.Error("expected a card missing from the mirror to fail")
+	}
+	if len(paths) != len(mirrorImageExts) {
+		t.Errorf("mirror requests = %q, want one per extension", paths)
+	}
+}
+
+func TestPrefetchCardImages(t *testing.T) {
+	if _, err := PrefetchCardImages(nil, 2, nil); err == nil {
+		t.Fatal("expected prefetching without a cache directory to fail")
+	}
+	useCardImageCache(t, DefaultCardImageCacheBytes)
+	var requests atomic.Int32
+	server := countingImageServer(t, &requests)
+	cards := []*Card{
+		{CardName: "One", BorderCropURL: server.URL, cardID: "tst-1"},
+		{CardName: "Two", BorderCropURL: server.URL, cardID: "tst-2"},
+		{CardName: "Broken", cardID: "tst-broken"},
+	}
+	cards = append(cards, cards[0])
+
+	var calls atomic.Int32
+	result, err := PrefetchCardImages(cards, 2, func(done, total int) {
+		calls.Add(1)
+		if total != 3 {
+			t.Errorf("progress total = %d, want 3", total)
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result != (PrefetchResult{Downloaded: 2, Failed: 1}) || calls.Load() != 3 {
+		t.Errorf("first prefetch = %+v with %d progress calls", result, calls.Load())
+	}
+
+	result, _ = PrefetchCardImages(cards[:2], 2, nil)
+	if result != (PrefetchResult{Cached: 2}) || requests.Load() != 2 {
+		t.Errorf("second prefetch = %+v after %d requests, want both cached", result, requests.Load())
+	}
+	if _, ok := cardImages.Load("tst-1"); ok {
+		t.Error("prefetching should fill only the disk cache")
+	}
+}
@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"image"
 	"image/color"
-	"net/http"
 	"path"
 	"strings"
 	"sync"
@@ -28,20 +27,6 @@ var labeledBlankCards sync.Map
 var blankCardOnce sync.Once
 var blankCardImage *ebiten.Image
 
-// CardImageCacheStats reports retained full-card and generated placeholder
-// images. Profiling harnesses use these counts alongside heap and RSS samples.
-func CardImageCacheStats() (cards, labeledPlaceholders int) {
-	cardImages.Range(func(_, _ any) bool {
-		cards++
-		return true
-	})
-	labeledBlankCards.Range(func(_, _ any) bool {
-		labeledPlaceholders++
-		return true
-	})
-	return cards, labeledPlaceholders
-}
-
 func blankCard() *ebiten.Image {
 	blankCardOnce.Do(func() {
 		img, _, err := image.Decode(bytes.NewReader(assets.CardBlank_png))
@@ -135,17 +120,17 @@ func loadCardImagesFromArchive(data []byte) (int, error) {
 
 		entry, err := file.Open()
 		if err != nil {
-			fmt.Printf("WARN: Failed to open embedded card image %s: %v\n", file.Name, err)
+			logger.Warn("failed to open embedded card image", "file", file.Name, "err", err)
 			continue
 		}
 		img, _, decodeErr := image.Decode(entry)
 		closeErr := entry.Close()
 		if decodeErr != nil {
-			fmt.Printf("WARN: Failed to decode embedded card image %s: %v\n", file.Name, decodeErr)
+			logger.Warn("failed to decode embedded card image", "file", file.Name, "err", decodeErr)
 			continue
 		}
 		if closeErr != nil {
-			fmt.Printf("WARN: Failed to close embedded card image %s: %v\n", file.Name, closeErr)
+			logger.Warn("failed to close embedded card image", "file", file.Name, "err", closeErr)
 			continue
 		}
 
@@ -165,31 +150,20 @@ func LoadEmbeddedCardImages() (int, error) {
 	return loadCardImagesFromArchive(assets.CardImagesZip)
 }
 
+// fetchAndCacheCardImage loads card's image from the disk cache, or else the
+// mirror or Scryfall, into the in-memory cache.
 func fetchAndCacheCardImage(card *Card) {
 	id := card.cardID
-	if card.BorderCropURL == "" {
-		fmt.Printf("WARN: No BorderCropURL for card: %s\n", card.CardName)
-		cardImages.Store(id, blankCard())
-		return
-	}
-
-	resp, err := http.Get(card.BorderCropURL)
+	data, err := loadCardImageData(card)
 	if err != nil {
-		fmt.Printf("WARN: Failed to fetch card image for %s: %v\n", card.CardName, err)
-		cardImages.Store(id, blankCard())
-		return
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		fmt.Printf("WARN: HTTP %d fetching card image for %s\n", resp.StatusCode, card.CardName)
+		logger.Warn("failed to fetch card image", "card", card.CardName, "err", err)
 		cardImages.Store(id, blankCard())
 		return
 	}
 
-	img, _, err := image.Decode(resp.Body)
+	img, _, err := image.Decode(bytes.NewReader(data))
 	if err != nil {
-		fmt.Printf("WARN: Failed to decode card image for %s: %v\n", card.CardName, err)
+		logger.Warn("failed to decode card image", "card", card.CardName, "err", err)
 		cardImages.Store(id, blankCard())
 		return
 	}
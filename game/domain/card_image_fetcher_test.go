@@ -30,8 +30,7 @@ func TestLoadCardImagesFromArchiveCachesEveryValidImage(t *testing.T) {
 	if loaded != 2 {
 		t.Fatalf("loadCardImagesFromArchive() loaded = %d, want 2", loaded)
 	}
-	cards, _ := CardImageCacheStats()
-	if cards != 2 {
+	if cards := CardImageCacheStats().Cards; cards != 2 {
 		t.Fatalf("CardImageCacheStats() cards = %d, want 2", cards)
 	}
 	for _, id := range []string{"tst-1-first-card", "tst-2-second-card"} {
@@ -10,9 +10,12 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/benprew/s30/logging"
 	"github.com/klauspost/compress/zstd"
 )
 
+var logger = logging.Logger(logging.MTG)
+
 type CardJSON struct {
 	CardName          string
 	ManaCost          string
@@ -76,7 +79,7 @@ func LoadCardDatabase(reader io.Reader) []*Card {
 		return cards[i].CardName < cards[j].CardName
 	})
 
-	fmt.Printf("Loaded %d cards\n", len(cards))
+	logger.Debug("loaded card database", "cards", len(cards))
 
 	return cards
 }
@@ -84,7 +87,7 @@ func LoadCardDatabase(reader io.Reader) []*Card {
 func LoadParsedAbilities(data []byte) map[string][]ParsedAbility {
 	var parsedCards []ParsedCardJSON
 	if err := json.Unmarshal(data, &parsedCards); err != nil {
-		log.Printf("Error unmarshalling parsed cards: %v", err)
+		logger.Error("failed to unmarshal parsed cards", "err", err)
 		return nil
 	}
 
@@ -95,7 +98,7 @@ func LoadParsedAbilities(data []byte) map[string][]ParsedAbility {
 		}
 	}
 
-	fmt.Printf("Loaded %d parsed card abilities\n", len(result))
+	logger.Debug("loaded parsed card abilities", "cards", len(result))
 	return result
 }
 
@@ -107,7 +110,7 @@ func ApplyParsedAbilities(cards []*Card, parsedAbilities map[string][]ParsedAbil
 			matched++
 		}
 	}
-	fmt.Printf("Applied parsed abilities to %d cards\n", matched)
+	logger.Debug("applied parsed abilities", "cards", matched)
 }
 
 var subtypeToMana = map[string]string{
@@ -154,7 +157,7 @@ func ApplyLandManaAbilities(cards []*Card) {
 			added++
 		}
 	}
-	fmt.Printf("Added land mana abilities to %d cards\n", added)
+	logger.Debug("added land mana abilities", "cards", added)
 }
 
 func decompress(input io.Reader) (io.Reader, error) {
@@ -0,0 +1,461 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// A CardQuery reports whether a card matches a search.
+type CardQuery func(*Card) bool
+
+// ParseCardQuery parses a Scryfall-style search such as
+//
+//	c:rg t:creature cmc<=3 o:"first strike" r:rare pow>=3 set:LEA
+//
+// Terms are ANDed together. "or" between terms matches either side, a leading
+// "-" negates a term, and parentheses group terms. A term without a key
+// matches card names. The keys are:
+//
+//	name, n            name contains
+//	c, color           colors: c:rg has red and green, c=rg is exactly
+//	                   red-green, c<=rg is within red-green, c:c is colorless
+//	                   and c:m is multicolored
+//	id, identity       color identity, as for color
+//	t, type            type line contains
+//	o, oracle          rules text contains
+//	k, kw, keyword     has the keyword
+//	m, mana            mana cost has the symbols, as m:{R}{R} or m:rr
+//	cmc, mv            mana value
+//	pow, power         creature power
+//	tou, toughness     creature toughness
+//	r, rarity          common, uncommon, rare or mythic, or their initials
+//	s, set, e          set code
+//
+// Numbers and rarities compare with :, =, !=, <, <=, > and >=. An empty query
+// matches every card.
+func ParseCardQuery(query string) (CardQuery, error) {
+	tokens, err := lexCardQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return func(*Card) bool { return true }, nil
+	}
+	p := &queryParser{tokens: tokens}
+	q, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %q", p.tokens[p.pos].text)
+	}
+	return q, nil
+}
+
+// Filter returns the cards that match q, in order.
+func (q CardQuery) Filter(cards []*Card) []*Card {
+	var out []*Card
+	for _, c := range cards {
+		if q(c) {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+type queryToken struct {
+	text   string
+	quoted bool // the whole token was in quotes, so it is never an operator
+}
+
+// lexCardQuery splits a query into terms, "(", ")" and "-". Quotes keep
+// spaces inside a term and are dropped from its text.
+func lexCardQuery(query string) ([]queryToken, error) {
+	var tokens []queryToken
+	rs := []rune(query)
+	for i := 0; i < len(rs); {
+		switch r := rs[i]; {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(' || r == ')':
+			tokens = append(tokens, queryToken{text: string(r)})
+			i++
+		case r == '-' && i+1 < len(rs) && !unicode.IsSpace(rs[i+1]):
+			tokens = append(tokens, queryToken{text: "-"})
+			i++
+		default:
+			var sb strings.Builder
+			quoted, inQuote := r == '"', false
+			for ; i < len(rs); i++ {
+				r := rs[i]
+				if r == '"' {
+					inQuote = !inQuote
+					continue
+				}
+				if !inQuote && (unicode.IsSpace(r) || r == '(' || r == ')') {
+					break
+				}
+				sb.WriteRune(r)
+			}
+			if inQuote {
+				return nil, fmt.Errorf("unclosed quote in %q", query)
+			}
+			tokens = append(tokens, queryToken{text: sb.String(), quoted: quoted})
+		}
+	}
+	return tokens, nil
+}
+
+type queryParser struct {
+	tokens []queryToken
+	pos    int
+}
+
+func (p *queryParser) peek() (queryToken, bool) {
+	if p.pos >= len(p.tokens) {
+		return queryToken{}, false
+	}
+	return p.tokens[p.pos], true
+}
+
+func isOr(t queryToken) bool { return !t.quoted && strings.EqualFold(t.text, "or") }
+
+func (p *queryParser) parseOr() (CardQuery, error) {
+	q, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t, ok := p.peek()
+		if !ok || !isOr(t) {
+			return q, nil
+		}
+		p.pos++
+		rhs, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		lhs := q
+		q = func(c *Card) bool { return lhs(c) || rhs(c) }
+	}
+}
+
+func (p *queryParser) parseAnd() (CardQuery, error) {
+	var terms []CardQuery
+	for {
+		t, ok := p.peek()
+		if !ok || isOr(t) || (t.text == ")" && !t.quoted) {
+			break
+		}
+		q, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, q)
+	}
+	if len(terms) == 0 {
+		return nil, fmt.Errorf("expected a search term")
+	}
+	return func(c *Card) bool {
+		for _, q := range terms {
+			if !q(c) {
+				return false
+			}
+		}
+		return true
+	}, nil
+}
+
+func (p *queryParser) parseUnary() (CardQuery, error) {
+	t, _ := p.peek()
+	p.pos++
+	if t.quoted {
+		return parseQueryTerm(t.text, true)
+	}
+	switch t.text {
+	case "-":
+		q, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return func(c *Card) bool { return !q(c) }, nil
+	case "(":
+		q, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if t, ok := p.peek(); !ok || t.text != ")" {
+			return nil, fmt.Errorf("missing )")
+		}
+		p.pos++
+		return q, nil
+	}
+	return parseQueryTerm(t.text, false)
+}
+
+var queryTermPattern = regexp.MustCompile(`^([a-zA-Z]+)(:|!=|<=|>=|=|<|>)(.*)$`)
+
+// parseQueryTerm parses one key-operator-value term, or a bare name.
+func parseQueryTerm(term string, quoted bool) (CardQuery, error) {
+	m := queryTermPattern.FindStringSubmatch(term)
+	if quoted || m == nil {
+		return textQuery(":", term, func(c *Card) []string { return []string{c.CardName} })
+	}
+	key, op, value := strings.ToLower(m[1]), m[2], m[3]
+	if value == "" {
+		return nil, fmt.Errorf("%s%s needs a value", m[1], op)
+	}
+
+	switch key {
+	case "name", "n":
+		return textQuery(op, value, func(c *Card) []string { return []string{c.CardName} })
+	case "t", "type":
+		return textQuery(op, value, func(c *Card) []string { return append([]string{c.TypeLine}, c.Subtypes...) })
+	case "o", "oracle":
+		return textQuery(op, value, func(c *Card) []string { return []string{c.Text} })
+	case "k", "kw", "keyword":
+		return keywordQuery(op, value)
+	case "c", "color":
+		return colorQuery(op, value, func(c *Card) []string { return c.Colors })
+	case "id", "identity":
+		return colorQuery(op, value, func(c *Card) []string { return c.ColorIdentity })
+	case "m", "mana":
+		return manaQuery(op, value)
+	case "cmc", "mv", "manavalue":
+		return numberQuery(op, value, func(c *Card) (int, bool) { return c.ManaValue(), true })
+	case "pow", "power":
+		return numberQuery(op, value, func(c *Card) (int, bool) { return c.Power, c.CardType == CardTypeCreature && c.Power >= 0 })
+	case "tou", "toughness":
+		return numberQuery(op, value, func(c *Card) (int, bool) { return c.Toughness, c.CardType == CardTypeCreature && c.Toughness >= 0 })
+	case "r", "rarity":
+		return rarityQuery(op, value)
+	case "s", "set", "e", "edition":
+		return setQuery(op, value)
+	}
+	return nil, fmt.Errorf("unknown search key %q", m[1])
+}
+
+// textQuery matches when any of a card's fields contains value, ignoring case.
+// != matches cards where none does.
+func textQuery(op, value string, fields func(*Card) []string) (CardQuery, error) {
+	value = strings.ToLower(value)
+	contains := func(c *Card) bool {
+		return slices.ContainsFunc(fields(c), func(f string) bool {
+			return strings.Contains(strings.ToLower(f), value)
+		})
+	}
+	switch op {
+	case ":", "=":
+		return contains, nil
+	case "!=":
+		return func(c *Card) bool { return !contains(c) }, nil
+	}
+	return nil, fmt.Errorf("text can't use %s", op)
+}
+
+func keywordQuery(op, value string) (CardQuery, error) {
+	has := func(c *Card) bool {
+		return slices.ContainsFunc(c.Keywords, func(k string) bool { return strings.EqualFold(k, value) })
+	}
+	switch op {
+	case ":", "=":
+		return has, nil
+	case "!=":
+		return func(c *Card) bool { return !has(c) }, nil
+	}
+	return nil, fmt.Errorf("keyword can't use %s", op)
+}
+
+// colorQuery compares a card's colors with a set of colors as Scryfall does:
+// : and >= mean at least those colors.
+func colorQuery(op, value string, colors func(*Card) []string) (CardQuery, error) {
+	mask := func(c *Card) ColorMask {
+		var m ColorMask
+		for _, s := range colors(c) {
+			m |= colorStringToMask[s]
+		}
+		return m
+	}
+	switch strings.ToLower(value) {
+	case "c", "colorless":
+		return setCompareQuery(op, mask, ColorColorless)
+	case "m", "multi", "multicolor":
+		multi := func(c *Card) bool {
+			m := mask(c)
+			return m&(m-1) != 0
+		}
+		switch op {
+		case ":", "=":
+			return multi, nil
+		case "!=":
+			return func(c *Card) bool { return !multi(c) }, nil
+		}
+		return nil, fmt.Errorf("multicolor can't use %s", op)
+	}
+
+	var want ColorMask
+	if m := ColorNameToMask(strings.ToLower(value)); m != ColorColorless {
+		want = m
+	} else {
+		for _, r := range strings.ToLower(value) {
+			m := ColorNameToMask(string(r))
+			if m == ColorColorless {
+				return nil, fmt.Errorf("unknown color %q", value)
+			}
+			want |= m
+		}
+	}
+	return setCompareQuery(op, mask, want)
+}
+
+// setCompareQuery compares a card's color mask with want as sets.
+func setCompareQuery(op string, mask func(*Card) ColorMask, want ColorMask) (CardQuery, error) {
+	if want == ColorColorless && (op == ":" || op == ">=") {
+		op = "="
+	}
+	var cmp func(have ColorMask) bool
+	switch op {
+	case ":", ">=":
+		cmp = func(have ColorMask) bool { return have&want == want }
+	case ">":
+		cmp = func(have ColorMask) bool { return have&want == want && have != want }
+	case "<=":
+		cmp = func(have ColorMask) bool { return have&^want == 0 }
+	case "<":
+		cmp = func(have ColorMask) bool { return have&^want == 0 && have != want }
+	case "=":
+		cmp = func(have ColorMask) bool { return have == want }
+	case "!=":
+		cmp = func(have ColorMask) bool { return have != want }
+	}
+	return func(c *Card) bool { return cmp(mask(c)) }, nil
+}
+
+// manaQuery matches cards whose mana cost has at least the given symbols, or
+// for = exactly them.
+func manaQuery(op, value string) (CardQuery, error) {
+	want := manaSymbols(value)
+	if len(want) == 0 {
+		return nil, fmt.Errorf("no mana symbols in %q", value)
+	}
+	covers := func(c *Card) bool {
+		have := manaSymbols(c.ManaCost)
+		for sym, n := range want {
+			if have[sym] < n {
+				return false
+			}
+		}
+		return true
+	}
+	switch op {
+	case ":", ">=":
+		return covers, nil
+	case "=":
+		return func(c *Card) bool { return manaSymbols(c.ManaCost).equal(want) }, nil
+	case "!=":
+		return func(c *Card) bool { return !manaSymbols(c.ManaCost).equal(want) }, nil
+	}
+	return nil, fmt.Errorf("mana can't use %s", op)
+}
+
+type manaCount map[string]int
+
+func (m manaCount) equal(o manaCount) bool {
+	if len(m) != len(o) {
+		return false
+	}
+	for k, n := range m {
+		if o[k] != n {
+			return false
+		}
+	}
+	return true
+}
+
+// manaSymbols counts the symbols in a cost written as {2}{R}{R} or 2rr.
+func manaSymbols(cost string) manaCount {
+	counts := make(manaCount)
+	if strings.Contains(cost, "{") {
+		for _, m := range manaCostToken.FindAllStringSubmatch(cost, -1) {
+			counts[strings.ToUpper(m[1])]++
+		}
+		return counts
+	}
+	rs := []rune(strings.ToUpper(cost))
+	for i := 0; i < len(rs); i++ {
+		if unicode.IsDigit(rs[i]) {
+			j := i
+			for j < len(rs) && unicode.IsDigit(rs[j]) {
+				j++
+			}
+			counts[string(rs[i:j])]++
+			i = j - 1
+			continue
+		}
+		counts[string(rs[i])]++
+	}
+	return counts
+}
+
+// numberQuery compares a number from the card with value. Cards without the
+// number, like a non-creature's power, never match.
+func numberQuery(op, value string, number func(*Card) (int, bool)) (CardQuery, error) {
+	want, err := strconv.Atoi(value)
+	if err != nil {
+		return nil, fmt.Errorf("%q is not a number", value)
+	}
+	return func(c *Card) bool {
+		n, ok := number(c)
+		return ok && compareQuery(op, n, want)
+	}, nil
+}
+
+var rarityRanks = map[string]int{
+	"common": 0, "c": 0,
+	"uncommon": 1, "u": 1,
+	"rare": 2, "r": 2,
+	"mythic": 3, "m": 3,
+}
+
+func rarityQuery(op, value string) (CardQuery, error) {
+	want, ok := rarityRanks[strings.ToLower(value)]
+	if !ok {
+		return nil, fmt.Errorf("unknown rarity %q", value)
+	}
+	return func(c *Card) bool {
+		have, ok := rarityRanks[strings.ToLower(c.Rarity)]
+		return ok && compareQuery(op, have, want)
+	}, nil
+}
+
+func setQuery(op, value string) (CardQuery, error) {
+	in := func(c *Card) bool { return strings.EqualFold(c.SetID, value) }
+	switch op {
+	case ":", "=":
+		return in, nil
+	case "!=":
+		return func(c *Card) bool { return !in(c) }, nil
+	}
+	return nil, fmt.Errorf("set can't use %s", op)
+}
+
+func compareQuery(op string, have, want int) bool {
+	switch op {
+	case "<":
+		return have < want
+	case "<=":
+		return have <= want
+	case ">":
+		return have > want
+	case ">=":
+		return have >= want
+	case "!=":
+		return have != want
+	default: // ":" and "="
+		return have == want
+	}
+}
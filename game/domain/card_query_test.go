@@ -0,0 +1,87 @@
+package domain
+
+import (
+	"slices"
+	"testing"
+)
+
+func queryPool(t *testing.T) []*Card {
+	t.Helper()
+	var pool []*Card
+	for _, name := range []string{"Serra Angel", "Lightning Bolt", "Dragon Whelp", "Juggernaut", "Shivan Dragon", "Black Knight"} {
+		c := FindCardByName(name)
+		if c == nil {
+			t.Fatalf("card %q not found", name)
+		}
+		pool = append(pool, c)
+	}
+	gold := &Card{
+		CardName: "Test Centaur", ManaCost: "{R}{G}", Colors: []string{"R", "G"}, ColorIdentity: []string{"R", "G"},
+		CardType: CardTypeCreature, TypeLine: "Creature — Centaur", Power: 3, Toughness: 3, Rarity: "rare",
+		CardSet: CardSet{SetID: "leg"},
+	}
+	return append(pool, gold)
+}
+
+func TestParseCardQuery(t *testing.T) {
+	pool := queryPool(t)
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"", []string{"Serra Angel", "Lightning Bolt", "Dragon Whelp", "Juggernaut", "Shivan Dragon", "Black Knight", "Test Centaur"}},
+		{"dragon", []string{"Dragon Whelp", "Shivan Dragon"}},
+		{"c:r", []string{"Lightning Bolt", "Dragon Whelp", "Shivan Dragon", "Test Centaur"}},
+		{"c=r", []string{"Lightning Bolt", "Dragon Whelp", "Shivan Dragon"}},
+		{"c:rg", []string{"Test Centaur"}},
+		{"c:m", []string{"Test Centaur"}},
+		{"c:c", []string{"Juggernaut"}},
+		{"c<=rg t:creature", []string{"Dragon Whelp", "Juggernaut", "Shivan Dragon", "Test Centaur"}},
+		{"t:creature cmc<=3", []string{"Black Knight", "Test Centaur"}},
+		{`o:"first strike"`, []string{"Black Knight"}},
+		{"k:flying -c:w", []string{"Dragon Whelp", "Shivan Dragon"}},
+		{"r:rare", []string{"Shivan Dragon", "Test Centaur"}},
+		{"r>=u pow>=4", []string{"Serra Angel", "Juggernaut", "Shivan Dragon"}},
+		{"tou<3", []string{"Black Knight"}},
+		{"set:LEG", []string{"Test Centaur"}},
+		{"m:rr", []string{"Dragon Whelp", "Shivan Dragon"}},
+		{"m={2}{R}{R}", []string{"Dragon Whelp"}},
+		{"t:angel or t:knight", []string{"Serra Angel", "Black Knight"}},
+		{"c:r (t:instant or pow>4)", []string{"Lightning Bolt", "Shivan Dragon"}},
+		{"-(c:r or c:w)", []string{"Juggernaut", "Black Knight"}},
+		{`"serra angel"`, []string{"Serra Angel"}},
+	}
+	for _, tt := range tests {
+		q, err := ParseCardQuery(tt.query)
+		if err != nil {
+			t.Errorf("ParseCardQuery(%q): %v", tt.query, err)
+			continue
+		}
+		var got []string
+		for _, c := range q.Filter(pool) {
+			got = append(got, c.CardName)
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%q matched %q, want %q", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestParseCardQueryErrors(t *testing.T) {
+	for _, query := range []string{
+		"foo:bar",
+		"c:purple",
+		"cmc>=three",
+		"r:legendary",
+		`o:"first strike`,
+		"(t:creature",
+		"t:creature or",
+		"t<creature",
+		"pow>=",
+		")",
+	} {
+		if _, err := ParseCardQuery(query); err == nil {
+			t.Errorf("ParseCardQuery(%q) succeeded, want an error", query)
+		}
+	}
+}
@@ -2,7 +2,6 @@ package domain
 
 import (
 	"fmt"
-	"log"
 	"math/rand"
 
 	"github.com/BurntSushi/toml"
@@ -92,7 +91,7 @@ func loadCardTiers() map[CardTier][]*Card {
 			continue
 		}
 		seen[card.CardName] = true
-		log.Printf("card_tiers: %q is in the card database but not in any tier", card.CardName)
+		logger.Warn("card is in the card database but not in any tier", "card", card.CardName)
 	}
 	return out
 }
@@ -9,3 +9,11 @@ type Castle struct {
 	MapTile   image.Point
 	Defeated  bool
 }
+
+// Siege is a castle wizard's attack on a city, carried by the minion sent to
+// take it. The city falls on EndsDay unless the player beats the minion first.
+type Siege struct {
+	City    image.Point
+	Color   ColorMask
+	EndsDay int
+}
@@ -1,7 +1,6 @@
 package domain
 
 import (
-	"fmt"
 	_ "image/png"
 	"math"
 
@@ -203,7 +202,7 @@ func (c *Character) GetNumDecks() int {
 func getEmbeddedFile(filename string) []byte {
 	data, err := assets.CharacterFS.ReadFile("art/screens/world/characters/" + filename)
 	if err != nil {
-		fmt.Printf("Error loading sprite file %s: %v\n", filename, err)
+		logger.Error("failed to load sprite file", "file", filename, "err", err)
 		return nil
 	}
 	return data
@@ -15,6 +15,7 @@ const (
 	BoonEnemyDeckInfo
 	BoonWorldMagicLocation
 	BoonBonusCard
+	BoonDungeonClue
 )
 
 func (b BoonType) IsQuest() bool {
@@ -64,6 +65,14 @@ type City struct {
 	WisemanBoon        BoonType
 	BoonGranted        bool
 	ProposedQuest      *Quest
+	// ConqueredBy is the color of the castle wizard whose minion holds the
+	// city, or zero while it is free. Occupier names that minion, whom the
+	// player must duel to liberate the city.
+	ConqueredBy ColorMask
+	Occupier    string
+	// Market tracks the card shop's restocking and prices. It is nil until
+	// the player first trades in the city.
+	Market *Market `json:",omitempty"`
 }
 
 // ==============================================================================
@@ -98,11 +107,20 @@ type City struct {
 //
 //   Note: Beth Moursund says that there should be a difference between the
 //   buying price and the selling price, but I've never seen a difference.
+//
+// Here each city runs a Market (market.go) with a spread between buying and
+// selling, and SellCards refuses to sell fewer than one card.
 
 func (c *City) FoodCost() int {
 	return int(c.Tier) * 10
 }
 
+// IsConquered reports whether one of Arzakon's minions holds the city. A
+// conquered city's shops and Wiseman are closed until it is liberated.
+func (c *City) IsConquered() bool {
+	return c.ConqueredBy != 0
+}
+
 func (c *City) HasWorldMagic() bool {
 	return c.AssignedWorldMagic != nil
 }
@@ -111,10 +129,12 @@ func (c *City) GetWorldMagic() *WorldMagic {
 	return c.AssignedWorldMagic
 }
 
-func MkCards() []*Card {
+// MkCardsWithRand picks a city's stock using rng, so seeded world generation
+// produces the same cards for sale.
+func MkCardsWithRand(rng *rand.Rand) []*Card {
 	cards := make([]*Card, 0, 5)
 	for i := 0; i < 5; i++ {
-		card := CARDS[rand.Intn(len(CARDS))]
+		card := CARDS[rng.Intn(len(CARDS))]
 		if card.VintageRestricted {
 			i--
 			continue
@@ -6,9 +6,10 @@ import (
 )
 
 type CollectionItem struct {
-	Card       *Card
-	Count      int   // total number of cards in the collection
-	DeckCounts []int // count of this card in each deck (index = deck number)
+	Card            *Card
+	Count           int   // total number of cards in the collection
+	DeckCounts      []int // count of this card in each deck (index = deck number)
+	SideboardCounts []int // count of this card in each deck's sideboard (index = deck number)
 }
 
 type CardCollection map[*Card]*CollectionItem
@@ -27,6 +28,17 @@ func (cc CardCollection) GetDeck(deckIndex int) Deck {
 	return deck
 }
 
+// GetSideboard returns the sideboard of deck deckIndex.
+func (cc CardCollection) GetSideboard(deckIndex int) Deck {
+	sideboard := make(Deck)
+	for card, item := range cc {
+		if deckIndex < len(item.SideboardCounts) && item.SideboardCounts[deckIndex] > 0 {
+			sideboard[card] = item.SideboardCounts[deckIndex]
+		}
+	}
+	return sideboard
+}
+
 func (cc CardCollection) AddCardToDeck(card *Card, deckIndex int, count int) {
 	item := cc[card]
 	if item == nil {
@@ -49,18 +61,22 @@ func (cc CardCollection) AddCardToDeck(card *Card, deckIndex int, count int) {
 	item.Count += count
 }
 
+// AddCardToSideboard adds count new copies of card to the collection and puts
+// them in deck deckIndex's sideboard.
+func (cc CardCollection) AddCardToSideboard(card *Card, deckIndex int, count int) {
+	cc.AddCard(card, count)
+	item := cc[card]
+	item.SideboardCounts = growCounts(item.SideboardCounts, deckIndex)
+	item.SideboardCounts[deckIndex] += count
+}
+
 func (cc CardCollection) MoveCardToDeck(card *Card, deckIndex int, count int) error {
 	item := cc[card]
 	if item == nil {
 		return fmt.Errorf("card %s not found in collection", card.Name())
 	}
 
-	totalInDecks := 0
-	for _, deckCount := range item.DeckCounts {
-		totalInDecks += deckCount
-	}
-
-	availableCount := item.Count - totalInDecks
+	availableCount := cc.GetAvailableCount(card)
 	if availableCount < count {
 		return fmt.Errorf("insufficient available cards for %s (have %d available, need %d)",
 			card.Name(), availableCount, count)
@@ -123,6 +139,27 @@ func (cc CardCollection) RemoveCardFromDeck(card *Card, deckIndex int, count int
 	return nil
 }
 
+// CopyDeck makes deck to, and its sideboard, a copy of deck from, built from
+// the copies no other deck is using. It returns the entries it couldn't fill,
+// as BuildDeckFromList does.
+func (cc CardCollection) CopyDeck(from, to int) []string {
+	missing := cc.BuildDeckFromList(to, cc.GetDeck(from))
+	return append(missing, cc.BuildSideboardFromList(to, cc.GetSideboard(from))...)
+}
+
+// RemoveDeck deletes deck deckIndex and its sideboard. Its cards stay in the
+// collection, and the decks after it move down one index.
+func (cc CardCollection) RemoveDeck(deckIndex int) {
+	for _, item := range cc {
+		if deckIndex < len(item.DeckCounts) {
+			item.DeckCounts = append(item.DeckCounts[:deckIndex], item.DeckCounts[deckIndex+1:]...)
+		}
+		if deckIndex < len(item.SideboardCounts) {
+			item.SideboardCounts = append(item.SideboardCounts[:deckIndex], item.SideboardCounts[deckIndex+1:]...)
+		}
+	}
+}
+
 func (cc CardCollection) GetTotalCount(card *Card) int {
 	item := cc[card]
 	if item == nil {
@@ -153,11 +190,7 @@ func (cc CardCollection) DecrementCardCount(card *Card) error {
 	item.Count--
 
 	// Update all deck counts to not exceed total count
-	for i := range item.DeckCounts {
-		if item.DeckCounts[i] > item.Count {
-			item.DeckCounts[i] = item.Count
-		}
-	}
+	item.clampCounts()
 
 	// Remove item if no cards left
 	if item.Count <= 0 {
@@ -194,11 +227,7 @@ func (cc CardCollection) RemoveCard(card *Card, count int) error {
 	item.Count -= count
 
 	// Update deck counts to not exceed total count
-	for i := range item.DeckCounts {
-		if item.DeckCounts[i] > item.Count {
-			item.DeckCounts[i] = item.Count
-		}
-	}
+	item.clampCounts()
 
 	// Remove item if no cards left
 	if item.Count <= 0 {
@@ -208,6 +237,32 @@ func (cc CardCollection) RemoveCard(card *Card, count int) error {
 	return nil
 }
 
+// assigned returns how many copies of the card are in decks and sideboards.
+func (item *CollectionItem) assigned() int {
+	n := 0
+	for _, c := range item.DeckCounts {
+		n += c
+	}
+	for _, c := range item.SideboardCounts {
+		n += c
+	}
+	return n
+}
+
+// clampCounts takes copies out of decks and sideboards until they hold no more
+// than the copies owned, emptying sideboards before decks and later decks
+// before earlier ones.
+func (item *CollectionItem) clampCounts() {
+	excess := item.assigned() - item.Count
+	for _, counts := range [][]int{item.SideboardCounts, item.DeckCounts} {
+		for i := len(counts) - 1; i >= 0 && excess > 0; i-- {
+			n := min(counts[i], excess)
+			counts[i] -= n
+			excess -= n
+		}
+	}
+}
+
 func (cc CardCollection) GetAllCards() []*Card {
 	cards := make([]*Card, 0, len(cc))
 	for card := range cc {
@@ -225,20 +280,22 @@ func (cc CardCollection) NumCards() int {
 }
 
 type collectionItemJSON struct {
-	CardID     string `json:"card_id"`
-	CardName   string `json:"card_name"`
-	Count      int    `json:"count"`
-	DeckCounts []int  `json:"deck_counts"`
+	CardID          string `json:"card_id"`
+	CardName        string `json:"card_name"`
+	Count           int    `json:"count"`
+	DeckCounts      []int  `json:"deck_counts"`
+	SideboardCounts []int  `json:"sideboard_counts,omitempty"`
 }
 
 func (cc CardCollection) MarshalJSON() ([]byte, error) {
 	items := make([]collectionItemJSON, 0, len(cc))
 	for card, item := range cc {
 		items = append(items, collectionItemJSON{
-			CardID:     card.cardID,
-			CardName:   card.CardName,
-			Count:      item.Count,
-			DeckCounts: item.DeckCounts,
+			CardID:          card.cardID,
+			CardName:        card.CardName,
+			Count:           item.Count,
+			DeckCounts:      item.DeckCounts,
+			SideboardCounts: item.SideboardCounts,
 		})
 	}
 	return json.Marshal(items)
@@ -273,12 +330,17 @@ func (cc *CardCollection) UnmarshalJSON(data []byte) error {
 				}
 				existing.DeckCounts[i] += n
 			}
+			for i, n := range item.SideboardCounts {
+				existing.SideboardCounts = growCounts(existing.SideboardCounts, i)
+				existing.SideboardCounts[i] += n
+			}
 			continue
 		}
 		(*cc)[card] = &CollectionItem{
-			Card:       card,
-			Count:      item.Count,
-			DeckCounts: item.DeckCounts,
+			Card:            card,
+			Count:           item.Count,
+			DeckCounts:      item.DeckCounts,
+			SideboardCounts: item.SideboardCounts,
 		}
 	}
 	return nil
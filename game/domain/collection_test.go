@@ -122,3 +122,25 @@ func TestCardCollectionMarshalJSONMultipleCards(t *testing.T) {
 		t.Errorf("Expected giant deck 1 count 1, got %d", cc2.GetDeckCount(giant, 1))
 	}
 }
+
+func TestDecrementCardCountKeepsCopiesInOneDeckEach(t *testing.T) {
+	bolt := FindCardByName("Lightning Bolt")
+	cc := NewCardCollection()
+	cc.AddCardToDeck(bolt, 0, 2)
+	cc.AddCardToDeck(bolt, 1, 1)
+	cc.AddCardToSideboard(bolt, 0, 1)
+
+	// Selling a copy in use takes it from the sideboard before the decks.
+	if err := cc.DecrementCardCount(bolt); err != nil {
+		t.Fatal(err)
+	}
+	if cc.GetSideboardCount(bolt, 0) != 0 || cc.GetDeckCount(bolt, 0) != 2 || cc.GetDeckCount(bolt, 1) != 1 {
+		t.Errorf("after one sale: deck 0 %d, deck 1 %d, sideboard %d", cc.GetDeckCount(bolt, 0), cc.GetDeckCount(bolt, 1), cc.GetSideboardCount(bolt, 0))
+	}
+	if err := cc.DecrementCardCount(bolt); err != nil {
+		t.Fatal(err)
+	}
+	if cc.GetDeckCount(bolt, 0) != 2 || cc.GetDeckCount(bolt, 1) != 0 {
+		t.Errorf("after two sales: deck 0 %d, deck 1 %d; want 2, 0", cc.GetDeckCount(bolt, 0), cc.GetDeckCount(bolt, 1))
+	}
+}
@@ -48,3 +48,21 @@ func (d Deck) ValidAnteCards(exclusions ...AnteExclusion) []*Card {
 	}
 	return cards
 }
+
+// Colors returns every color of the deck's cards.
+func (d Deck) Colors() ColorMask {
+	var m ColorMask
+	for card := range d {
+		m |= card.ColorMask()
+	}
+	return m
+}
+
+// Size returns the number of cards in the deck.
+func (d Deck) Size() int {
+	n := 0
+	for _, count := range d {
+		n += count
+	}
+	return n
+}
@@ -0,0 +1,318 @@
+package domain
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// DeckFormat is a deck file format the codec reads and writes.
+type DeckFormat int
+
+const (
+	// DeckFormatText is a plain decklist: "4 Lightning Bolt" per line, with a
+	// "Sideboard" line before any sideboard cards.
+	DeckFormatText DeckFormat = iota
+	// DeckFormatDck is Forge's .dck format: a "NAME:" header and
+	// "count [SET:num] Name" lines, with sideboard cards under "[sideboard]".
+	DeckFormatDck
+)
+
+// DeckFormatForPath picks the format for a file from its extension.
+func DeckFormatForPath(path string) DeckFormat {
+	if strings.EqualFold(filepath.Ext(path), ".dck") {
+		return DeckFormatDck
+	}
+	return DeckFormatText
+}
+
+// Ext returns the file extension written for a format.
+func (f DeckFormat) Ext() string {
+	if f == DeckFormatDck {
+		return ".dck"
+	}
+	return ".txt"
+}
+
+// DeckList is a deck read from or written to a deck file.
+type DeckList struct {
+	Name      string
+	Main      Deck
+	Sideboard Deck
+	// Unknown lists entries naming cards that aren't in the card database,
+	// as "count name", in file order.
+	Unknown []string
+}
+
+// NewDeckList returns an empty deck list.
+func NewDeckList(name string) *DeckList {
+	return &DeckList{Name: name, Main: make(Deck), Sideboard: make(Deck)}
+}
+
+var (
+	// [2ED:162] or [2ED] before the card name
+	dckSetPrefix = regexp.MustCompile(`^\[([^\]:]+)(?::([^\]]+))?\]\s*(.+)$`)
+	// Lightning Bolt (2ED) 162, as exported by Arena
+	arenaSetSuffix = regexp.MustCompile(`^(.+?)\s+\(([A-Za-z0-9]+)\)(?:\s+(\S+))?$`)
+)
+
+// ParseDeckList reads a Forge .dck file or a plain-text decklist; the two are
+// told apart line by line, so either format may be passed. Cards missing from
+// the card database are collected in Unknown rather than failing the parse.
+func ParseDeckList(r io.Reader) (*DeckList, error) {
+	dl := NewDeckList("")
+	section := "main"
+	scanner := bufio.NewScanner(r)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if lineNo == 1 {
+			line = strings.TrimPrefix(line, "\ufeff")
+		}
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "//") {
+			continue
+		}
+		if name, ok := strings.CutPrefix(line, "NAME:"); ok {
+			dl.Name = strings.TrimSpace(name)
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") && !strings.Contains(line, ":") {
+			section = strings.ToLower(strings.Trim(line, "[]"))
+			continue
+		}
+		switch strings.ToLower(strings.TrimSuffix(line, ":")) {
+		case "deck", "main", "mainboard":
+			section = "main"
+			continue
+		case "sideboard":
+			section = "sideboard"
+			continue
+		}
+
+		deck := dl.Main
+		switch section {
+		case "main":
+		case "sideboard":
+			deck = dl.Sideboard
+		case "metadata":
+			if name, ok := strings.CutPrefix(line, "Name="); ok {
+				dl.Name = strings.TrimSpace(name)
+			}
+			continue
+		default:
+			// Forge sections the game has no use for, e.g. [Avatar].
+			continue
+		}
+		if rest, ok := strings.CutPrefix(line, "SB:"); ok {
+			deck = dl.Sideboard
+			line = strings.TrimSpace(rest)
+		}
+
+		count, name, setID, collectorNo, err := parseDeckLine(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNo, err)
+		}
+		card := findPrinting(name, setID, collectorNo)
+		if card == nil {
+			dl.Unknown = append(dl.Unknown, fmt.Sprintf("%d %s", count, name))
+			continue
+		}
+		deck[card] += count
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read deck: %w", err)
+	}
+	return dl, nil
+}
+
+// parseDeckLine splits "4 Lightning Bolt", "4x Lightning Bolt",
+// "4 [2ED:162] Lightning Bolt", "4 Lightning Bolt|2ED" and
+// "4 Lightning Bolt (2ED) 162" into their parts.
+func parseDeckLine(line string) (count int, name, setID, collectorNo string, err error) {
+	countStr, rest, ok := strings.Cut(line, " ")
+	if !ok {
+		return 0, "", "", "", fmt.Errorf("expected \"<count> <card>\", got %q", line)
+	}
+	count, err = strconv.Atoi(strings.TrimSuffix(strings.ToLower(countStr), "x"))
+	if err != nil || count < 1 {
+		return 0, "", "", "", fmt.Errorf("invalid card count %q", countStr)
+	}
+	rest = strings.TrimSpace(rest)
+
+	if m := dckSetPrefix.FindStringSubmatch(rest); m != nil {
+		setID, collectorNo, rest = m[1], m[2], m[3]
+	}
+	if before, after, ok := strings.Cut(rest, "|"); ok {
+		rest = before
+		setID, collectorNo, _ = strings.Cut(after, "|")
+	} else if m := arenaSetSuffix.FindStringSubmatch(rest); m != nil {
+		rest, setID, collectorNo = m[1], m[2], m[3]
+	}
+	return count, strings.TrimSpace(rest), setID, collectorNo, nil
+}
+
+// findPrinting returns the printing of name from the given set and collector
+// number, falling back to any printing from that set and then to any
+// printing at all.
+func findPrinting(name, setID, collectorNo string) *Card {
+	printings := FindAllCardsByName(name)
+	if len(printings) == 0 {
+		return nil
+	}
+	if setID != "" {
+		var inSet *Card
+		for _, c := range printings {
+			if !strings.EqualFold(c.SetID, setID) {
+				continue
+			}
+			if collectorNo == "" || c.CollectorNo == collectorNo {
+				return c
+			}
+			if inSet == nil {
+				inSet = c
+			}
+		}
+		if inSet != nil {
+			return inSet
+		}
+	}
+	return printings[0]
+}
+
+// Encode writes the deck list in the given format. Cards are sorted by name so
+// exported decks diff cleanly under version control.
+func (dl *DeckList) Encode(w io.Writer, format DeckFormat) error {
+	bw := bufio.NewWriter(w)
+	if format == DeckFormatDck {
+		fmt.Fprintf(bw, "NAME:%s\n", dl.Name)
+		writeDckCards(bw, dl.Main)
+		if len(dl.Sideboard) > 0 {
+			fmt.Fprintln(bw, "[sideboard]")
+			writeDckCards(bw, dl.Sideboard)
+		}
+	} else {
+		writeTextCards(bw, dl.Main)
+		if len(dl.Sideboard) > 0 {
+			fmt.Fprintln(bw)
+			fmt.Fprintln(bw, "Sideboard")
+			writeTextCards(bw, dl.Sideboard)
+		}
+	}
+	return bw.Flush()
+}
+
+func writeDckCards(w io.Writer, deck Deck) {
+	for _, card := range sortedDeckCards(deck) {
+		fmt.Fprintf(w, "%d [%s:%s] %s\n", deck[card], strings.ToUpper(card.SetID), card.CollectorNo, card.CardName)
+	}
+}
+
+// writeTextCards writes one line per card name, folding printings together
+// since the text format has nowhere to keep them.
+func writeTextCards(w io.Writer, deck Deck) {
+	counts := make(map[string]int)
+	var names []string
+	for _, card := range sortedDeckCards(deck) {
+		if counts[card.CardName] == 0 {
+			names = append(names, card.CardName)
+		}
+		counts[card.CardName] += deck[card]
+	}
+	for _, name := range names {
+		fmt.Fprintf(w, "%d %s\n", counts[name], name)
+	}
+}
+
+func sortedDeckCards(deck Deck) []*Card {
+	cards := make([]*Card, 0, len(deck))
+	for card, n := range deck {
+		if n > 0 {
+			cards = append(cards, card)
+		}
+	}
+	slices.SortFunc(cards, func(a, b *Card) int {
+		if c := strings.Compare(a.CardName, b.CardName); c != 0 {
+			return c
+		}
+		return strings.Compare(a.CardID(), b.CardID())
+	})
+	return cards
+}
+
+// BuildDeckFromList replaces deck deckIndex with the cards in want, using only
+// copies the player owns and hasn't put in another deck. The deck's sideboard
+// is emptied first, so a sideboard built after the deck gets what is left. A
+// printing the player lacks is filled from other printings of the same card.
+// It returns the entries it couldn't fill, as "count name".
+func (cc CardCollection) BuildDeckFromList(deckIndex int, want Deck) []string {
+	for _, item := range cc {
+		if deckIndex < len(item.DeckCounts) {
+			item.DeckCounts[deckIndex] = 0
+		}
+		if deckIndex < len(item.SideboardCounts) {
+			item.SideboardCounts[deckIndex] = 0
+		}
+	}
+	return cc.fillFromList(want, func(c *Card, n int) error { return cc.MoveCardToDeck(c, deckIndex, n) })
+}
+
+// BuildSideboardFromList replaces the sideboard of deck deckIndex with the
+// cards in want, as BuildDeckFromList does for the deck itself.
+func (cc CardCollection) BuildSideboardFromList(deckIndex int, want Deck) []string {
+	for _, item := range cc {
+		if deckIndex < len(item.SideboardCounts) {
+			item.SideboardCounts[deckIndex] = 0
+		}
+	}
+	return cc.fillFromList(want, func(c *Card, n int) error { return cc.MoveCardToSideboard(c, deckIndex, n) })
+}
+
+// fillFromList moves the owned, unassigned copies of each card in want with
+// move and returns the entries it couldn't fill.
+func (cc CardCollection) fillFromList(want Deck, move func(card *Card, count int) error) []string {
+	byName := make(map[string][]*Card)
+	for card := range cc {
+		byName[card.CardName] = append(byName[card.CardName], card)
+	}
+
+	var missing []string
+	for _, card := range sortedDeckCards(want) {
+		need := want[card]
+		// Try the exact printing first, then the others in a stable order.
+		candidates := []*Card{card}
+		others := byName[card.CardName]
+		slices.SortFunc(others, func(a, b *Card) int { return strings.Compare(a.CardID(), b.CardID()) })
+		for _, c := range others {
+			if c != card {
+				candidates = append(candidates, c)
+			}
+		}
+		for _, c := range candidates {
+			if need == 0 {
+				break
+			}
+			n := min(need, cc.GetAvailableCount(c))
+			if n > 0 && move(c, n) == nil {
+				need -= n
+			}
+		}
+		if need > 0 {
+			missing = append(missing, fmt.Sprintf("%d %s", need, card.CardName))
+		}
+	}
+	return missing
+}
+
+// GetAvailableCount returns how many copies of card are owned but in no deck
+// or sideboard.
+func (cc CardCollection) GetAvailableCount(card *Card) int {
+	item := cc[card]
+	if item == nil {
+		return 0
+	}
+	return max(item.Count-item.assigned(), 0)
+}
@@ -0,0 +1,211 @@
+package domain
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestParseDeckListDck(t *testing.T) {
+	src := `NAME:Burn
+4 [4ED:208] Lightning Bolt
+16 [2ED] Mountain
+2 [XXX:1] Not A Real Card
+[sideboard]
+2 Lightning Bolt
+`
+	dl, err := ParseDeckList(strings.NewReader(src))
+	if err != nil {
+		t.Fatalf("ParseDeckList: %v", err)
+	}
+	if dl.Name != "Burn" {
+		t.Errorf("Name = %q, want Burn", dl.Name)
+	}
+	bolt := findPrinting("Lightning Bolt", "4ed", "208")
+	if bolt == nil || bolt.SetID != "4ed" {
+		t.Fatalf("4ED Lightning Bolt not found: %+v", bolt)
+	}
+	if got := dl.Main[bolt]; got != 4 {
+		t.Errorf("main 4ED Lightning Bolt = %d, want 4", got)
+	}
+	mountains := 0
+	for card, n := range dl.Main {
+		if card.CardName == "Mountain" {
+			mountains += n
+			if card.SetID != "2ed" {
+				t.Errorf("Mountain printing = %s, want 2ed", card.SetID)
+			}
+		}
+	}
+	if mountains != 16 {
+		t.Errorf("Mountains = %d, want 16", mountains)
+	}
+	if len(dl.Sideboard) != 1 {
+		t.Errorf("sideboard = %v, want one Lightning Bolt entry", dl.Sideboard)
+	}
+	if len(dl.Unknown) != 1 || dl.Unknown[0] != "2 Not A Real Card" {
+		t.Errorf("Unknown = %q, want [\"2 Not A Real Card\"]", dl.Unknown)
+	}
+}
+
+func TestParseDeckListText(t *testing.T) {
+	src := `// Red
+4x Lightning Bolt
+4 Lightning Bolt (2ED) 162
+12 Mountain|4ED
+
+Sideboard
+SB: 1 Mountain
+`
+	dl, err := ParseDeckList(strings.NewReader(src))
+	if err != nil {
+		t.Fatalf("ParseDeckList: %v", err)
+	}
+	total := 0
+	for _, n := range dl.Main {
+		total += n
+	}
+	if total != 20 {
+		t.Errorf("main deck has %d cards, want 20", total)
+	}
+	if got := dl.Main[findPrinting("Lightning Bolt", "2ed", "162")]; got < 4 {
+		t.Errorf("2ED Lightning Bolt = %d, want at least 4", got)
+	}
+	if len(dl.Sideboard) != 1 {
+		t.Errorf("sideboard = %v, want one Mountain entry", dl.Sideboard)
+	}
+}
+
+func TestParseDeckListRejectsBadCounts(t *testing.T) {
+	for _, src := range []string{"Lightning Bolt\n", "four Lightning Bolt\n", "0 Lightning Bolt\n"} {
+		if _, err := ParseDeckList(strings.NewReader(src)); err == nil {
+			t.Errorf("ParseDeckList(%q) succeeded, want an error", src)
+		}
+	}
+}
+
+func TestParseDeckListReadsBundledDck(t *testing.T) {
+	f, err := os.Open("../../Cunning.dck")
+	if err != nil {
+		t.Fatalf("open Cunning.dck: %v", err)
+	}
+	defer f.Close()
+
+	dl, err := ParseDeckList(f)
+	if err != nil {
+		t.Fatalf("ParseDeckList: %v", err)
+	}
+	if dl.Name != "Speed vs Cunning - Cunning" {
+		t.Errorf("Name = %q", dl.Name)
+	}
+	cards := 0
+	for _, n := range dl.Main {
+		cards += n
+	}
+	for _, entry := range dl.Unknown {
+		var n int
+		var name string
+		if _, err := fmt.Sscanf(entry, "%d %s", &n, &name); err == nil {
+			cards += n
+		}
+	}
+	if cards != 60 {
+		t.Errorf("read %d cards (known and unknown), want 60", cards)
+	}
+}
+
+func TestDeckListEncodeRoundTrips(t *testing.T) {
+	dl := NewDeckList("Burn")
+	dl.Main[findPrinting("Lightning Bolt", "2ed", "162")] = 2
+	dl.Main[findPrinting("Lightning Bolt", "4ed", "208")] = 2
+	dl.Main[findPrinting("Mountain", "", "")] = 16
+	dl.Sideboard[findPrinting("Lightning Bolt", "4ed", "208")] = 1
+
+	for _, format := range []DeckFormat{DeckFormatDck, DeckFormatText} {
+		var buf bytes.Buffer
+		if err := dl.Encode(&buf, format); err != nil {
+			t.Fatalf("Encode: %v", err)
+		}
+		got, err := ParseDeckList(&buf)
+		if err != nil {
+			t.Fatalf("format %d: ParseDeckList: %v\n%s", format, err, buf.String())
+		}
+		if countByName(got.Main)["Lightning Bolt"] != 4 || countByName(got.Main)["Mountain"] != 16 {
+			t.Errorf("format %d: main = %v", format, countByName(got.Main))
+		}
+		if countByName(got.Sideboard)["Lightning Bolt"] != 1 {
+			t.Errorf("format %d: sideboard = %v", format, countByName(got.Sideboard))
+		}
+		if format == DeckFormatDck {
+			if got.Name != "Burn" {
+				t.Errorf("dck Name = %q, want Burn", got.Name)
+			}
+			if len(got.Main) != 3 {
+				t.Errorf("dck kept %d printings, want 3", len(got.Main))
+			}
+		}
+	}
+}
+
+func TestDeckListEncodeText(t *testing.T) {
+	dl := NewDeckList("")
+	dl.Main[findPrinting("Mountain", "", "")] = 16
+	dl.Main[findPrinting("Lightning Bolt", "2ed", "162")] = 2
+	dl.Main[findPrinting("Lightning Bolt", "4ed", "208")] = 2
+
+	var buf bytes.Buffer
+	if err := dl.Encode(&buf, DeckFormatText); err != nil {
+		t.Fatal(err)
+	}
+	if want := "4 Lightning Bolt\n16 Mountain\n"; buf.String() != want {
+		t.Errorf("Encode = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestBuildDeckFromListUsesOwnedCopies(t *testing.T) {
+	bolt2ed := findPrinting("Lightning Bolt", "2ed", "162")
+	bolt4ed := findPrinting("Lightning Bolt", "4ed", "208")
+	mountain := findPrinting("Mountain", "", "")
+	giant := FindCardByName("Hill Giant")
+
+	cc := NewCardCollection()
+	cc.AddCard(bolt2ed, 1)
+	cc.AddCard(bolt4ed, 2)
+	cc.AddCard(mountain, 10)
+	cc.AddCardToDeck(giant, 0, 2)
+	// Two Mountains are already committed to another deck, and two are in the
+	// deck's sideboard, which is emptied first.
+	if err := cc.MoveCardToDeck(mountain, 1, 2); err != nil {
+		t.Fatal(err)
+	}
+	if err := cc.MoveCardToSideboard(mountain, 0, 2); err != nil {
+		t.Fatal(err)
+	}
+
+	want := Deck{bolt4ed: 4, mountain: 10}
+	missing := cc.BuildDeckFromList(0, want)
+
+	if got := cc.GetDeckCount(giant, 0); got != 0 {
+		t.Errorf("Hill Giant still in deck %d times", got)
+	}
+	if got := cc.GetDeckCount(bolt4ed, 0) + cc.GetDeckCount(bolt2ed, 0); got != 3 {
+		t.Errorf("deck has %d Lightning Bolts, want 3", got)
+	}
+	if got := cc.GetDeckCount(mountain, 0); got != 8 {
+		t.Errorf("deck has %d Mountains, want 8", got)
+	}
+	wantMissing := []string{"1 Lightning Bolt", "2 Mountain"}
+	if strings.Join(missing, ",") != strings.Join(wantMissing, ",") {
+		t.Errorf("missing = %q, want %q", missing, wantMissing)
+	}
+}
+
+func countByName(d Deck) map[string]int {
+	counts := make(map[string]int)
+	for card, n := range d {
+		counts[card.CardName] += n
+	}
+	return counts
+}
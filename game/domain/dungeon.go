@@ -3,6 +3,7 @@ package domain
 import (
 	"fmt"
 	"image"
+	"strings"
 )
 
 type DungeonTileType int
@@ -98,6 +99,66 @@ type CardRestriction struct {
 	ForbiddenType  *CardType
 }
 
+// Forbids reports whether c can't be played in the restricted dungeon. A nil
+// restriction forbids nothing.
+func (r *CardRestriction) Forbids(c *Card) bool {
+	if r == nil || c == nil {
+		return false
+	}
+	if r.ForbiddenColor != nil && c.ColorMask()&*r.ForbiddenColor != 0 {
+		return true
+	}
+	return r.ForbiddenType != nil && c.CardType == *r.ForbiddenType
+}
+
+// String describes the restriction for the dungeon entrance and the duel
+// banner, e.g. "No Red cards" or "No Instants".
+func (r *CardRestriction) String() string {
+	if r == nil {
+		return ""
+	}
+	var parts []string
+	for _, name := range r.forbidden() {
+		parts = append(parts, "No "+name)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// forbidden names what r forbids, e.g. "Red cards" or "Instants".
+func (r *CardRestriction) forbidden() []string {
+	if r == nil {
+		return nil
+	}
+	var names []string
+	if r.ForbiddenColor != nil {
+		names = append(names, fmt.Sprintf("%s cards", ColorMaskToString(*r.ForbiddenColor)))
+	}
+	if r.ForbiddenType != nil {
+		plural := string(*r.ForbiddenType) + "s"
+		if *r.ForbiddenType == CardTypeSorcery {
+			plural = "Sorceries"
+		}
+		names = append(names, plural)
+	}
+	return names
+}
+
+// Filter returns deck without the cards r forbids, along with the cards it
+// took out. The deck isn't filled back up to the minimum size: a player
+// whose deck breaks the restriction duels with fewer cards.
+func (r *CardRestriction) Filter(deck Deck) (allowed, removed Deck) {
+	allowed = make(Deck, len(deck))
+	removed = make(Deck)
+	for card, count := range deck {
+		if r.Forbids(card) {
+			removed[card] = count
+		} else {
+			allowed[card] = count
+		}
+	}
+	return allowed, removed
+}
+
 type DungeonClue struct {
 	Type     ClueType
 	Text     string
@@ -148,6 +209,12 @@ func (d *Dungeon) IsCastle() bool {
 	return d != nil && d.Theme == DungeonThemeCastle
 }
 
+// DuelDeck is the deck p brings to duels in this dungeon: their usual duel
+// deck less the cards the dungeon forbids, which are returned as removed.
+func (d *Dungeon) DuelDeck(p *Player) (deck, removed Deck) {
+	return d.CardRestriction.Filter(p.GetDuelDeck())
+}
+
 func (d *Dungeon) Width() int {
 	if len(d.Grid) == 0 {
 		return 0
@@ -211,17 +278,22 @@ func (st *DungeonState) ApplyDice(tile *DungeonTile, p *Player) string {
 	}
 	effect := tile.Dice
 	desc := DescribeDiceEffect(effect)
-	if effect.LifeMod != 0 {
-		p.BonusDuelLife += effect.LifeMod
-	}
-	if effect.Card != nil {
-		p.BonusDuelCards = append(p.BonusDuelCards, effect.Card)
-	}
+	applyDiceEffect(effect, p)
 	tile.Dice = nil
 	tile.Type = DungeonTileEmpty
 	return desc
 }
 
+// applyDiceEffect queues e's life change and card grant for p's next duel.
+func applyDiceEffect(e *DiceEffect, p *Player) {
+	if e.LifeMod != 0 {
+		p.BonusDuelLife += e.LifeMod
+	}
+	if e.Card != nil {
+		p.BonusDuelCards = append(p.BonusDuelCards, e.Card)
+	}
+}
+
 // DescribeDiceEffect renders a dice effect as a short sentence for the dungeon
 // overlay and the in-duel notice.
 func DescribeDiceEffect(e *DiceEffect) string {
@@ -274,11 +346,3 @@ func (st *DungeonState) DefeatEnemy(tile *DungeonTile) {
 	tile.Enemy = nil
 	tile.Type = DungeonTileEmpty
 }
-
-type PlayerClues struct {
-	RevealedClues map[string][]DungeonClue
-}
-
-func NewPlayerClues() *PlayerClues {
-	return &PlayerClues{RevealedClues: make(map[string][]DungeonClue)}
-}
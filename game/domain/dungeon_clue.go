@@ -0,0 +1,150 @@
+package domain
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// WriteClues fills in any of d's clues that have no text yet. The population
+// and effect clues are read off the dungeon itself; the location clue needs
+// the world map, so the caller passes it in as location, which may be "" when
+// it isn't known yet.
+func (d *Dungeon) WriteClues(location string) {
+	for i := range d.Clues {
+		c := &d.Clues[i]
+		c.Type = ClueType(i)
+		if c.Text != "" {
+			continue
+		}
+		switch c.Type {
+		case ClueLocation:
+			c.Text = location
+		case CluePopulation:
+			c.Text = d.PopulationClue()
+		case ClueEffect:
+			c.Text = d.EffectClue()
+		}
+	}
+}
+
+// PopulationClue describes who guards d: how many foes wait inside, the most
+// common of them, and the master of a castle.
+func (d *Dungeon) PopulationClue() string {
+	counts := make(map[string]int)
+	total := 0
+	boss := ""
+	for y := range d.Grid {
+		for x := range d.Grid[y] {
+			t := &d.Grid[y][x]
+			if t.Type != DungeonTileEnemy {
+				continue
+			}
+			if t.Boss && t.Enemy != nil {
+				boss = t.Enemy.Name
+				continue
+			}
+			total++
+			if t.Enemy != nil {
+				counts[t.Enemy.Name]++
+			}
+		}
+	}
+
+	var text string
+	common := mostCommon(counts)
+	switch {
+	case total == 0:
+		text = fmt.Sprintf("No one stands guard in %s.", d.Name)
+	case total == 1 && common != "":
+		text = fmt.Sprintf("A lone %s guards %s.", common, d.Name)
+	case common != "":
+		text = fmt.Sprintf("%d foes guard %s, the %s among them.", total, d.Name, common)
+	default:
+		text = fmt.Sprintf("%d foes guard %s.", total, d.Name)
+	}
+	if boss != "" {
+		text += fmt.Sprintf(" %s waits in its depths.", boss)
+	}
+	return text
+}
+
+// mostCommon returns the name counted most often, breaking ties
+// alphabetically so a dungeon's clue doesn't change between loads.
+func mostCommon(counts map[string]int) string {
+	names := make([]string, 0, len(counts))
+	for name := range counts {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	best := ""
+	for _, name := range names {
+		if best == "" || counts[name] > counts[best] {
+			best = name
+		}
+	}
+	return best
+}
+
+// EffectClue describes the enchantment in play in d's duels and the cards it
+// forbids.
+func (d *Dungeon) EffectClue() string {
+	forbidden := strings.Join(d.CardRestriction.forbidden(), " and ")
+	switch {
+	case d.Enchantment != nil && forbidden != "":
+		return fmt.Sprintf("Every duel in %s begins with %s in play, and %s can't be played there.", d.Name, d.Enchantment.CardName, forbidden)
+	case d.Enchantment != nil:
+		return fmt.Sprintf("Every duel in %s begins with %s in play.", d.Name, d.Enchantment.CardName)
+	case forbidden != "":
+		return fmt.Sprintf("%s can't be played in %s.", forbidden, d.Name)
+	}
+	return fmt.Sprintf("No strange magic lingers in %s.", d.Name)
+}
+
+// PlayerClues is the player's clue journal: every dungeon clue they've
+// found, by dungeon name, in the order they found them.
+type PlayerClues struct {
+	RevealedClues map[string][]DungeonClue
+}
+
+func NewPlayerClues() *PlayerClues {
+	return &PlayerClues{RevealedClues: make(map[string][]DungeonClue)}
+}
+
+// Record adds clue to the journal under dungeon. A clue already in the journal
+// isn't added again. Reports whether the clue was new.
+func (pc *PlayerClues) Record(dungeon string, clue DungeonClue) bool {
+	if pc.RevealedClues == nil {
+		pc.RevealedClues = make(map[string][]DungeonClue)
+	}
+	for _, c := range pc.RevealedClues[dungeon] {
+		if c.Type == clue.Type && c.Text == clue.Text {
+			return false
+		}
+	}
+	clue.Revealed = true
+	pc.RevealedClues[dungeon] = append(pc.RevealedClues[dungeon], clue)
+	return true
+}
+
+// Dungeons returns the names of the dungeons with clues in the journal,
+// sorted.
+func (pc *PlayerClues) Dungeons() []string {
+	if pc == nil {
+		return nil
+	}
+	names := make([]string, 0, len(pc.RevealedClues))
+	for name := range pc.RevealedClues {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// RecordClue adds a clue to p's journal, starting the journal if p has none.
+func (p *Player) RecordClue(dungeon string, clue DungeonClue) bool {
+	if p.Clues == nil {
+		p.Clues = NewPlayerClues()
+	}
+	return p.Clues.Record(dungeon, clue)
+}
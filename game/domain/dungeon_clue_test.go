@@ -0,0 +1,88 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerateDungeonWritesPopulationAndEffectClues(t *testing.T) {
+	goblin := &Character{Name: "Goblin Lord"}
+	d := GenerateDungeon(DungeonGenOptions{
+		Name:        "Ember Spire",
+		Difficulty:  DungeonDifficultyMedium,
+		EnemyPool:   []*Character{goblin},
+		Enchantment: &Card{CardName: "Meekstone"},
+		Seed:        3,
+	})
+
+	if d.Clues[ClueLocation].Text != "" {
+		t.Errorf("location clue %q written before the dungeon was placed", d.Clues[ClueLocation].Text)
+	}
+	pop := d.Clues[CluePopulation]
+	if pop.Type != CluePopulation || !strings.Contains(pop.Text, "Goblin Lord") {
+		t.Errorf("population clue = %+v, want it to name the Goblin Lord", pop)
+	}
+	effect := d.Clues[ClueEffect]
+	if effect.Type != ClueEffect || !strings.Contains(effect.Text, "Meekstone") {
+		t.Errorf("effect clue = %+v, want it to name the Meekstone", effect)
+	}
+	for _, c := range d.Clues {
+		if c.Revealed {
+			t.Errorf("clue %+v revealed at generation", c)
+		}
+	}
+}
+
+func TestPopulationClueCountsEnemiesAndTheBoss(t *testing.T) {
+	d := &Dungeon{Name: "Gloom Bastion", Grid: makeWalls(3, 3)}
+	d.Grid[1][0] = DungeonTile{Type: DungeonTileEnemy, Enemy: &Character{Name: "Orc"}}
+	d.Grid[1][1] = DungeonTile{Type: DungeonTileEnemy, Enemy: &Character{Name: "Troll"}}
+	d.Grid[1][2] = DungeonTile{Type: DungeonTileEnemy, Enemy: &Character{Name: "Orc"}}
+	d.Grid[2][1] = DungeonTile{Type: DungeonTileEnemy, Enemy: &Character{Name: "Lich"}, Boss: true}
+
+	want := "3 foes guard Gloom Bastion, the Orc among them. Lich waits in its depths."
+	if got := d.PopulationClue(); got != want {
+		t.Errorf("PopulationClue = %q, want %q", got, want)
+	}
+}
+
+func TestEffectClueDescribesEnchantmentAndRestriction(t *testing.T) {
+	red := ColorRed
+	instant := CardTypeInstant
+	cases := []struct {
+		d    Dungeon
+		want string
+	}{
+		{Dungeon{Name: "Vault"}, "No strange magic lingers in Vault."},
+		{Dungeon{Name: "Vault", Enchantment: &Card{CardName: "Winter Orb"}}, "Every duel in Vault begins with Winter Orb in play."},
+		{Dungeon{Name: "Vault", CardRestriction: &CardRestriction{ForbiddenType: &instant}}, "Instants can't be played in Vault."},
+		{
+			Dungeon{Name: "Vault", Enchantment: &Card{CardName: "Meekstone"}, CardRestriction: &CardRestriction{ForbiddenColor: &red}},
+			"Every duel in Vault begins with Meekstone in play, and Red cards can't be played there.",
+		},
+	}
+	for _, c := range cases {
+		if got := c.d.EffectClue(); got != c.want {
+			t.Errorf("EffectClue = %q, want %q", got, c.want)
+		}
+	}
+}
+
+func TestRecordClueKeepsEachClueOnce(t *testing.T) {
+	p := &Player{}
+	clue := DungeonClue{Type: CluePopulation, Text: "3 foes guard Vault."}
+	if !p.RecordClue("Vault", clue) {
+		t.Fatal("first clue not recorded")
+	}
+	if p.RecordClue("Vault", clue) {
+		t.Error("the same clue was recorded twice")
+	}
+	p.RecordClue("Crypt", DungeonClue{Type: ClueLocation, Text: "Crypt lies close by Tenby."})
+
+	if got := p.Clues.Dungeons(); len(got) != 2 || got[0] != "Crypt" || got[1] != "Vault" {
+		t.Errorf("Dungeons = %v, want [Crypt Vault]", got)
+	}
+	if got := p.Clues.RevealedClues["Vault"]; len(got) != 1 || !got[0].Revealed {
+		t.Errorf("Vault clues = %+v, want one revealed clue", got)
+	}
+}
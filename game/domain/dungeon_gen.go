@@ -23,9 +23,9 @@ type DungeonGenOptions struct {
 }
 
 // GenerateDungeon builds a Dungeon with a carved hallway grid and event tiles
-// placed across enemies, treasures, dice, and scrolls. The MapTile field is
-// left at the zero value; callers are expected to assign it during world
-// placement.
+// placed across enemies, treasures, dice, and scrolls, and writes its
+// population and effect clues. The MapTile field and location clue are left
+// empty; callers are expected to fill them in during world placement.
 func GenerateDungeon(opts DungeonGenOptions) *Dungeon {
 	const size = 17
 	opts.Difficulty = opts.Difficulty.normalized()
@@ -131,9 +131,10 @@ func GenerateDungeon(opts DungeonGenOptions) *Dungeon {
 
 	placeOnEmpty(difficulty.scrolls, func(t *DungeonTile, r *rand.Rand) {
 		t.Type = DungeonTileScroll
-		t.Scroll = &ScrollQuestion{}
+		t.Scroll = randomScrollQuestion(r)
 	})
 
+	d.WriteClues("")
 	return d
 }
 
@@ -158,11 +159,79 @@ func randomIntInclusive(rng *rand.Rand, minValue, maxValue int) int {
 	return minValue + rng.Intn(maxValue-minValue+1)
 }
 
+// dungeonEnchantments are the color-aligned enchantments a dungeon can hold in
+// play for every duel inside it. Each one affects both duelists alike,
+// whoever controls it: nothing with an activated ability or an effect on
+// "you" alone, since the dungeon enemy controls the only copy.
+var dungeonEnchantments = map[ColorMask][]string{
+	ColorWhite: {"Crusade", "Karma"},
+	ColorBlue:  {"Energy Flux"},
+	ColorBlack: {"Gloom", "Bad Moon"},
+	ColorRed:   {"Manabarbs", "Magnetic Mountain", "Mana Flare", "Power Surge"},
+	ColorGreen: {"Titania's Song", "Living Lands"},
+}
+
+// dungeonArtifacts can stand in for any color's enchantment. They're
+// non-creature artifacts without an upkeep, so neither duelist has to pay to
+// keep them around.
+var dungeonArtifacts = []string{"Meekstone", "Howling Mine", "Winter Orb", "Ankh of Mishra", "Dingus Egg"}
+
+// RollDungeonEnchantment picks the enchantment in play at the start of every
+// duel in a dungeon of the given color. One dungeon in four gets a colorless
+// artifact instead. Returns nil if no candidate is in the card database.
+func RollDungeonEnchantment(color ColorMask, rng *rand.Rand) *Card {
+	names := dungeonEnchantments[color]
+	if len(names) == 0 || rng.Intn(4) == 0 {
+		names = dungeonArtifacts
+	}
+	var cards []*Card
+	for _, name := range names {
+		if card := FindCardByName(name); card != nil {
+			cards = append(cards, card)
+		}
+	}
+	if len(cards) == 0 {
+		return nil
+	}
+	return cards[rng.Intn(len(cards))]
+}
+
+// restrictableTypes are the card types a dungeon can forbid.
+var restrictableTypes = []CardType{CardTypeInstant, CardTypeSorcery, CardTypeArtifact}
+
+// RollCardRestriction picks the cards forbidden in a dungeon. Easy dungeons
+// never restrict cards, medium ones do half the time and hard ones always
+// do. A restriction forbids either a color other than the dungeon's own or
+// one card type.
+func RollCardRestriction(color ColorMask, difficulty DungeonDifficulty, rng *rand.Rand) *CardRestriction {
+	switch difficulty.normalized() {
+	case DungeonDifficultyEasy:
+		return nil
+	case DungeonDifficultyMedium:
+		if rng.Intn(2) == 0 {
+			return nil
+		}
+	}
+	if rng.Intn(2) == 0 {
+		cardType := restrictableTypes[rng.Intn(len(restrictableTypes))]
+		return &CardRestriction{ForbiddenType: &cardType}
+	}
+	var others []ColorMask
+	for _, c := range GetAllAmuletColors() {
+		if c != color {
+			others = append(others, c)
+		}
+	}
+	forbidden := others[rng.Intn(len(others))]
+	return &CardRestriction{ForbiddenColor: &forbidden}
+}
+
 func CastleDungeonGenOptions(name string, color ColorMask, finalEnemy *Character, diceCardPool []*Card, seed int64) DungeonGenOptions {
 	return DungeonGenOptions{
 		Name: name, Difficulty: DungeonDifficultyHard, Color: color, Theme: DungeonThemeCastle,
 		NumGoldChests: 2, EnemyPool: DungeonEnemyPool(color, DungeonDifficultyHard), FinalEnemy: finalEnemy,
 		DiceCardPool: diceCardPool, Seed: seed,
+		Enchantment: RollDungeonEnchantment(color, rand.New(rand.NewSource(seed))),
 	}
 }
 
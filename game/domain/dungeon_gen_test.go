@@ -3,6 +3,8 @@ package domain
 import (
 	"image"
 	"math/rand"
+	"regexp"
+	"slices"
 	"testing"
 )
 
@@ -215,3 +217,135 @@ func findDeadEndsIncludingEvents(g [][]DungeonTile) []image.Point {
 	}
 	return findDeadEnds(copyGrid)
 }
+
+func TestRollDungeonEnchantmentMatchesColorOrIsAnArtifact(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for _, color := range GetAllAmuletColors() {
+		artifacts := 0
+		for range 200 {
+			card := RollDungeonEnchantment(color, rng)
+			if card == nil {
+				t.Fatalf("%s dungeon rolled no enchantment", ColorMaskToString(color))
+			}
+			switch card.CardType {
+			case CardTypeArtifact:
+				artifacts++
+			case CardTypeEnchantment:
+				if card.ColorMask() != color {
+					t.Errorf("%s dungeon rolled off-color %s", ColorMaskToString(color), card.CardName)
+				}
+			default:
+				t.Errorf("%s dungeon rolled %s, a %s", ColorMaskToString(color), card.CardName, card.CardType)
+			}
+		}
+		if artifacts == 0 || artifacts > 100 {
+			t.Errorf("%s dungeon rolled %d artifacts out of 200, want about a quarter", ColorMaskToString(color), artifacts)
+		}
+	}
+}
+
+// oneSidedRules matches rules text that favours a card's controller: an
+// activated ability, or an effect on "you" or what "you" control.
+var oneSidedRules = regexp.MustCompile(`(?m)^(\{[^}]+\})+:|\byour?\b`)
+
+// grantedAbility matches a quoted ability a card hands out to other
+// permanents, as Energy Flux does to every artifact.
+var grantedAbility = regexp.MustCompile(`"[^"]*"`)
+
+func TestDungeonEnchantmentsAffectBothPlayers(t *testing.T) {
+	names := slices.Clone(dungeonArtifacts)
+	for _, colorNames := range dungeonEnchantments {
+		names = append(names, colorNames...)
+	}
+	for _, name := range names {
+		card := FindCardByName(name)
+		if card == nil {
+			t.Errorf("dungeon card %s is not in the card database", name)
+			continue
+		}
+		if rule := oneSidedRules.FindString(grantedAbility.ReplaceAllString(card.Text, "")); rule != "" {
+			t.Errorf("dungeon card %s only helps its controller (%q): %q", name, rule, card.Text)
+		}
+	}
+}
+
+func TestRollCardRestrictionScalesWithDifficulty(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	counts := map[DungeonDifficulty]int{}
+	for _, d := range []DungeonDifficulty{DungeonDifficultyEasy, DungeonDifficultyMedium, DungeonDifficultyHard} {
+		for range 200 {
+			r := RollCardRestriction(ColorRed, d, rng)
+			if r == nil {
+				continue
+			}
+			counts[d]++
+			if r.ForbiddenColor != nil && *r.ForbiddenColor == ColorRed {
+				t.Errorf("red dungeon forbade its own color")
+			}
+			if (r.ForbiddenColor == nil) == (r.ForbiddenType == nil) {
+				t.Errorf("restriction %+v should forbid exactly one color or type", r)
+			}
+		}
+	}
+	if counts[DungeonDifficultyEasy] != 0 {
+		t.Errorf("easy dungeons restricted cards %d times", counts[DungeonDifficultyEasy])
+	}
+	if n := counts[DungeonDifficultyMedium]; n == 0 || n == 200 {
+		t.Errorf("medium dungeons restricted cards %d times out of 200, want some", n)
+	}
+	if counts[DungeonDifficultyHard] != 200 {
+		t.Errorf("hard dungeons restricted cards %d times out of 200, want every time", counts[DungeonDifficultyHard])
+	}
+}
+
+func TestCardRestrictionFilterDropsForbiddenCards(t *testing.T) {
+	red := ColorRed
+	instant := CardTypeInstant
+	bolt := &Card{CardName: "Lightning Bolt", CardType: CardTypeInstant, Colors: []string{"R"}}
+	growth := &Card{CardName: "Giant Growth", CardType: CardTypeInstant, Colors: []string{"G"}}
+	bears := &Card{CardName: "Grizzly Bears", CardType: CardTypeCreature, Colors: []string{"G"}}
+	deck := Deck{bolt: 4, growth: 2, bears: 3}
+
+	tests := []struct {
+		name        string
+		restriction *CardRestriction
+		wantAllowed Deck
+	}{
+		{"none", nil, deck},
+		{"color", &CardRestriction{ForbiddenColor: &red}, Deck{growth: 2, bears: 3}},
+		{"type", &CardRestriction{ForbiddenType: &instant}, Deck{bears: 3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			allowed, removed := tt.restriction.Filter(deck)
+			if len(allowed) != len(tt.wantAllowed) {
+				t.Fatalf("allowed = %v, want %v", allowed, tt.wantAllowed)
+			}
+			for card, count := range tt.wantAllowed {
+				if allowed[card] != count {
+					t.Errorf("%s: allowed %d, want %d", card.CardName, allowed[card], count)
+				}
+			}
+			for card, count := range deck {
+				if allowed[card]+removed[card] != count {
+					t.Errorf("%s: %d allowed + %d removed, want %d", card.CardName, allowed[card], removed[card], count)
+				}
+			}
+		})
+	}
+}
+
+func TestCardRestrictionString(t *testing.T) {
+	blue := ColorBlue
+	sorcery := CardTypeSorcery
+	if got := (&CardRestriction{ForbiddenColor: &blue}).String(); got != "No Blue cards" {
+		t.Errorf("color restriction = %q", got)
+	}
+	if got := (&CardRestriction{ForbiddenType: &sorcery}).String(); got != "No Sorceries" {
+		t.Errorf("type restriction = %q", got)
+	}
+	var none *CardRestriction
+	if got := none.String(); got != "" {
+		t.Errorf("nil restriction = %q, want empty", got)
+	}
+}
@@ -13,6 +13,9 @@ import (
 const (
 	EnemyChaseDistance  = 150.0
 	EnemyRandomDistance = 200.0
+	// EnemyGiveUpDistance is how long the way to the player may grow, in
+	// pixels walked, before a chasing enemy gives up: about seven tiles.
+	EnemyGiveUpDistance = 1500.0
 	EnemyMoveBuffer     = 10
 	enemyWaitChance     = 0.02
 )
@@ -27,6 +30,11 @@ type Enemy struct {
 	Character *Character
 	CharacterInstance
 	Engaged bool
+	// Pursuing is set while the enemy chases the player, which it keeps on
+	// doing around water even once the player is out of EnemyChaseDistance.
+	Pursuing bool
+	// Siege is set on a castle wizard's minion sent to capture a city.
+	Siege *Siege
 
 	waitingTicks      int
 	maxWaitTicks      int
@@ -63,6 +71,24 @@ func (e *Enemy) Update(pLoc image.Point) error {
 	return nil
 }
 
+// MoveToward moves the enemy straight toward p, stopping once it is within
+// EnemyMoveBuffer of it. The world steers enemies along paths with it.
+func (e *Enemy) MoveToward(p image.Point) {
+	e.CharacterInstance.Update(e.dirBitsToward(p.X, p.Y))
+}
+
+// Chasing reports whether target is close enough for the enemy to make for
+// it rather than wander.
+func (e *Enemy) Chasing(target image.Point) bool {
+	return math.Hypot(float64(target.X-e.X), float64(target.Y-e.Y)) <= EnemyChaseDistance
+}
+
+// Redirect has a wandering enemy pick a new direction on its next update,
+// for when the way it was heading is blocked.
+func (e *Enemy) Redirect() {
+	e.randomDirTicks = e.maxRandomDirTicks
+}
+
 func (e *Enemy) Name() string {
 	return e.Character.Name
 }
@@ -82,21 +108,7 @@ func (e *Enemy) move(playerX, playerY int) int {
 
 	// If close, move directly toward player
 	if distToPlayer <= EnemyChaseDistance {
-		dirbits := 0
-		buffer := EnemyMoveBuffer
-		if playerX > e.X+buffer {
-			dirbits |= DirRight
-		}
-		if playerX < e.X-buffer {
-			dirbits |= DirLeft
-		}
-		if playerY > e.Y+buffer {
-			dirbits |= DirDown
-		}
-		if playerY < e.Y-buffer {
-			dirbits |= DirUp
-		}
-		return dirbits
+		return e.dirBitsToward(playerX, playerY)
 	}
 
 	// Check if enemy should start waiting
@@ -124,22 +136,10 @@ func (e *Enemy) move(playerX, playerY int) int {
 		e.maxRandomDirTicks = randomEnemyDirectionTicks()
 
 		dirbits := 0
-		buffer := 10
 
 		// Add randomness to movement
 		if rand.Float64() < 0.7 {
-			if playerX > e.X+buffer {
-				dirbits |= DirRight
-			}
-			if playerX < e.X-buffer {
-				dirbits |= DirLeft
-			}
-			if playerY > e.Y+buffer {
-				dirbits |= DirDown
-			}
-			if playerY < e.Y-buffer {
-				dirbits |= DirUp
-			}
+			dirbits = e.dirBitsToward(playerX, playerY)
 		}
 
 		// Add random perpendicular movement
@@ -167,6 +167,25 @@ func (e *Enemy) move(playerX, playerY int) int {
 	return e.randomDirBits
 }
 
+// dirBitsToward returns the direction bits that head from the enemy toward
+// x, y, leaving out any axis already within EnemyMoveBuffer of it.
+func (e *Enemy) dirBitsToward(x, y int) int {
+	dirbits := 0
+	if x > e.X+EnemyMoveBuffer {
+		dirbits |= DirRight
+	}
+	if x < e.X-EnemyMoveBuffer {
+		dirbits |= DirLeft
+	}
+	if y > e.Y+EnemyMoveBuffer {
+		dirbits |= DirDown
+	}
+	if y < e.Y-EnemyMoveBuffer {
+		dirbits |= DirUp
+	}
+	return dirbits
+}
+
 func randomEnemyWaitTicks() int {
 	duration := 2*time.Second + time.Duration(rand.Intn(60))*100*time.Millisecond
 	return timing.Ticks(duration)
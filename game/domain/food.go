@@ -0,0 +1,66 @@
+package domain
+
+// FoodPerDay is how much food the player eats each time a day passes on the
+// road.
+const FoodPerDay = 1
+
+// LowFoodDays is how many days of food remain when the world frame starts
+// warning the player.
+const LowFoodDays = 5
+
+// StarvationLifeLoss is the life lost for each day the player goes without
+// food. Starvation never takes the player's last life point.
+const StarvationLifeLoss = 1
+
+// StarvingSpeedFactor slows the player while they have no food.
+const StarvingSpeedFactor = 0.5
+
+// travel adds dist to the distance walked today and ends the day once the
+// player has covered TravelDistancePerDay.
+func (p *Player) travel(dist float64) {
+	p.TimeAccumulator += dist
+	if p.TimeAccumulator >= TravelDistancePerDay {
+		p.TimeAccumulator -= TravelDistancePerDay
+		p.endDay()
+	}
+}
+
+// endDay advances the calendar by one day: quest deadlines count down and the
+// player eats.
+func (p *Player) endDay() {
+	p.Days++
+	for _, q := range p.ActiveQuests {
+		q.DaysRemaining--
+	}
+	p.ExpireQuests()
+	p.eat()
+}
+
+// eat consumes a day's food, or costs life when there is none left.
+func (p *Player) eat() {
+	if p.Food >= FoodPerDay {
+		p.Food -= FoodPerDay
+		return
+	}
+	p.Food = 0
+	p.Life = max(p.Life-StarvationLifeLoss, 1)
+}
+
+// IsStarving reports whether the player has run out of food.
+func (p *Player) IsStarving() bool {
+	return p.Food < FoodPerDay
+}
+
+// IsLowOnFood reports whether the player has only a few days of food left.
+func (p *Player) IsLowOnFood() bool {
+	return p.Food < LowFoodDays*FoodPerDay
+}
+
+// TravelSpeedFactor returns the multiplier hunger applies to the player's
+// movement speed.
+func (p *Player) TravelSpeedFactor() float64 {
+	if p.IsStarving() {
+		return StarvingSpeedFactor
+	}
+	return 1
+}
@@ -0,0 +1,85 @@
+package domain
+
+import "testing"
+
+func TestTravelEatsFoodEachDay(t *testing.T) {
+	p := &Player{Character: Character{Life: 10}, Food: 3}
+
+	p.travel(TravelDistancePerDay - 1)
+	if p.Days != 0 || p.Food != 3 {
+		t.Fatalf("before a full day: Days = %d, Food = %d; want 0, 3", p.Days, p.Food)
+	}
+
+	p.travel(1)
+	if p.Days != 1 || p.Food != 3-FoodPerDay {
+		t.Fatalf("after a full day: Days = %d, Food = %d; want 1, %d", p.Days, p.Food, 3-FoodPerDay)
+	}
+	if p.TimeAccumulator != 0 {
+		t.Fatalf("TimeAccumulator = %v, want 0", p.TimeAccumulator)
+	}
+	if p.Life != 10 {
+		t.Fatalf("Life = %d while fed, want 10", p.Life)
+	}
+}
+
+func TestStarvationCostsLifeButNotTheLastPoint(t *testing.T) {
+	p := &Player{Character: Character{Life: 3}}
+
+	p.endDay()
+	if p.Life != 3-StarvationLifeLoss {
+		t.Fatalf("Life = %d after a day starving, want %d", p.Life, 3-StarvationLifeLoss)
+	}
+	for range 5 {
+		p.endDay()
+	}
+	if p.Life != 1 {
+		t.Fatalf("Life = %d after a week starving, want 1", p.Life)
+	}
+	if p.Food != 0 {
+		t.Fatalf("Food = %d, want 0", p.Food)
+	}
+}
+
+func TestLastRationIsEatenBeforeStarving(t *testing.T) {
+	p := &Player{Character: Character{Life: 10}, Food: FoodPerDay}
+
+	if p.IsStarving() {
+		t.Fatal("IsStarving() = true with a ration left")
+	}
+	p.endDay()
+	if p.Life != 10 {
+		t.Fatalf("Life = %d after eating the last ration, want 10", p.Life)
+	}
+	if !p.IsStarving() {
+		t.Fatal("IsStarving() = false after the last ration")
+	}
+}
+
+func TestTravelSpeedFactorSlowsStarvingPlayer(t *testing.T) {
+	fed := &Player{Food: 10}
+	if got := fed.TravelSpeedFactor(); got != 1 {
+		t.Fatalf("fed TravelSpeedFactor() = %v, want 1", got)
+	}
+	starving := &Player{}
+	if got := starving.TravelSpeedFactor(); got != StarvingSpeedFactor {
+		t.Fatalf("starving TravelSpeedFactor() = %v, want %v", got, StarvingSpeedFactor)
+	}
+}
+
+func TestIsLowOnFood(t *testing.T) {
+	cases := []struct {
+		food int
+		want bool
+	}{
+		{0, true},
+		{LowFoodDays*FoodPerDay - 1, true},
+		{LowFoodDays * FoodPerDay, false},
+		{50, false},
+	}
+	for _, c := range cases {
+		p := &Player{Food: c.food}
+		if got := p.IsLowOnFood(); got != c.want {
+			t.Errorf("Food %d: IsLowOnFood() = %v, want %v", c.food, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,198 @@
+package domain
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// A Format is a set of deck construction rules. Validate checks a deck against
+// them and returns every rule the deck breaks, so the deck editor, quests and
+// command line tools can all explain why a deck isn't legal.
+type Format struct {
+	ID   string
+	Name string
+	// MinSize is the fewest cards a deck may have.
+	MinSize int
+	// MaxCopies is the most copies of a card, other than a basic land, a deck
+	// may have. Zero means any number.
+	MaxCopies int
+	// RestrictVintage limits every Vintage-restricted card to one copy.
+	RestrictVintage bool
+	// Restricted names the other cards limited to one copy.
+	Restricted map[string]bool
+	// Banned names the cards a deck may not have.
+	Banned map[string]bool
+	// Sets lists the set codes a card must have been printed in. Nil allows
+	// every set.
+	Sets map[string]bool
+	// TieredCardsLegal allows every card in card_tiers.toml, which lists the
+	// format's card pool, whatever its printing in the card database.
+	TieredCardsLegal bool
+}
+
+var (
+	// FormatOldSchool is Old School 93/94: cards from the sets printed in
+	// 1993 and 1994, four of each, with the era's restricted list and no ante
+	// or dexterity cards.
+	FormatOldSchool = &Format{
+		ID:              "oldschool",
+		Name:            "Old School 93/94",
+		MinSize:         60,
+		MaxCopies:       4,
+		RestrictVintage: true,
+		Restricted: setOf(
+			"Braingeyser", "Mana Drain", "Mind Twist", "Mirror Universe", "Recall", "Regrowth",
+		),
+		Banned: setOf(
+			"Chaos Orb", "Falling Star", "Shahrazad",
+			"Bronze Tablet", "Contract from Below", "Darkpact", "Demonic Attorney",
+			"Jeweled Bird", "Rebirth", "Tempest Efreet", "Amulet of Quoz",
+		),
+		Sets:             setOf("lea", "leb", "2ed", "ced", "cei", "arn", "atq", "leg", "drk", "fem", "sum"),
+		TieredCardsLegal: true,
+	}
+	// FormatShandalar is the original game's rule: any cards, as many as you
+	// own, in a deck of at least 40.
+	FormatShandalar = &Format{
+		ID:      "shandalar",
+		Name:    "Shandalar classic",
+		MinSize: 40,
+	}
+	// FormatAnything checks nothing at all.
+	FormatAnything = &Format{
+		ID:   "anything",
+		Name: "Anything goes",
+	}
+)
+
+// Formats lists every format, in the order the deck editor offers them.
+var Formats = []*Format{FormatShandalar, FormatOldSchool, FormatAnything}
+
+// FormatByID finds a format by its ID or name, ignoring case.
+func FormatByID(id string) (*Format, bool) {
+	for _, f := range Formats {
+		if strings.EqualFold(f.ID, id) || strings.EqualFold(f.Name, id) {
+			return f, true
+		}
+	}
+	return nil, false
+}
+
+// FormatIDs returns the ID of every format.
+func FormatIDs() []string {
+	ids := make([]string, len(Formats))
+	for i, f := range Formats {
+		ids[i] = f.ID
+	}
+	return ids
+}
+
+// ViolationKind is the rule a deck breaks.
+type ViolationKind int
+
+const (
+	ViolationDeckSize ViolationKind = iota
+	ViolationBanned
+	ViolationNotInSets
+	ViolationRestricted
+	ViolationTooManyCopies
+)
+
+// FormatViolation is one rule a deck breaks. Deck-wide rules leave Card empty.
+type FormatViolation struct {
+	Kind  ViolationKind
+	Card  string
+	Count int // copies of Card in the deck, or the deck's size
+	Limit int // copies allowed, or the minimum deck size
+}
+
+func (v FormatViolation) String() string {
+	switch v.Kind {
+	case ViolationDeckSize:
+		return fmt.Sprintf("Deck has %d cards, needs at least %d", v.Count, v.Limit)
+	case ViolationBanned:
+		return v.Card + " is banned"
+	case ViolationNotInSets:
+		return v.Card + " is not from a legal set"
+	case ViolationRestricted:
+		return fmt.Sprintf("%s is restricted: %d copies, 1 allowed", v.Card, v.Count)
+	case ViolationTooManyCopies:
+		return fmt.Sprintf("%d copies of %s, %d allowed", v.Count, v.Card, v.Limit)
+	default:
+		return v.Card
+	}
+}
+
+// Validate returns every rule deck breaks, deck size first and then by card
+// name. Printings of a card count together.
+func (f *Format) Validate(deck Deck) []FormatViolation {
+	var violations []FormatViolation
+	if size := deck.Size(); size < f.MinSize {
+		violations = append(violations, FormatViolation{Kind: ViolationDeckSize, Count: size, Limit: f.MinSize})
+	}
+
+	counts := make(map[string]int)
+	restricted := make(map[string]bool)
+	for card, n := range deck {
+		counts[card.CardName] += n
+		if f.RestrictVintage && card.VintageRestricted {
+			restricted[card.CardName] = true
+		}
+	}
+	var cards []FormatViolation
+	for name, n := range counts {
+		_, basic := basicLands[name]
+		switch {
+		case f.Banned[name]:
+			cards = append(cards, FormatViolation{Kind: ViolationBanned, Card: name, Count: n})
+		case !basic && !f.inSets(name):
+			cards = append(cards, FormatViolation{Kind: ViolationNotInSets, Card: name, Count: n})
+		}
+		switch {
+		case (restricted[name] || f.Restricted[name]) && n > 1:
+			cards = append(cards, FormatViolation{Kind: ViolationRestricted, Card: name, Count: n, Limit: 1})
+		case !basic && f.MaxCopies > 0 && n > f.MaxCopies:
+			cards = append(cards, FormatViolation{Kind: ViolationTooManyCopies, Card: name, Count: n, Limit: f.MaxCopies})
+		}
+	}
+	slices.SortFunc(cards, func(a, b FormatViolation) int {
+		return cmp.Or(strings.Compare(a.Card, b.Card), cmp.Compare(a.Kind, b.Kind))
+	})
+	return append(violations, cards...)
+}
+
+// IsLegal reports whether deck breaks none of the format's rules.
+func (f *Format) IsLegal(deck Deck) bool {
+	return len(f.Validate(deck)) == 0
+}
+
+// inSets reports whether the card called name was printed in one of the
+// format's sets.
+func (f *Format) inSets(name string) bool {
+	if f.Sets == nil {
+		return true
+	}
+	for _, c := range FindAllCardsByName(name) {
+		if f.Sets[c.SetID] {
+			return true
+		}
+	}
+	if f.TieredCardsLegal {
+		for _, cards := range CardsByTier {
+			if slices.ContainsFunc(cards, func(c *Card) bool { return c.CardName == name }) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func setOf(names ...string) map[string]bool {
+	m := make(map[string]bool, len(names))
+	for _, n := range names {
+		m[n] = true
+	}
+	return m
+}
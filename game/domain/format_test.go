@@ -0,0 +1,101 @@
+package domain
+
+import (
+	"slices"
+	"testing"
+)
+
+func violationStrings(vs []FormatViolation) []string {
+	out := make([]string, len(vs))
+	for i, v := range vs {
+		out[i] = v.String()
+	}
+	return out
+}
+
+func TestFormatOldSchoolValidate(t *testing.T) {
+	deck := Deck{
+		FindCardByName("Mountain"):            20,
+		FindCardByName("Lightning Bolt"):      5,
+		FindCardByName("Black Lotus"):         2,
+		FindCardByName("Contract from Below"): 1,
+		FindCardByName("Giant Badger"):        1,
+		FindCardByName("Hill Giant"):          4,
+	}
+
+	got := violationStrings(FormatOldSchool.Validate(deck))
+	want := []string{
+		"Deck has 33 cards, needs at least 60",
+		"Black Lotus is restricted: 2 copies, 1 allowed",
+		"Contract from Below is banned",
+		"Giant Badger is not from a legal set",
+		"5 copies of Lightning Bolt, 4 allowed",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("violations =\n%q\nwant\n%q", got, want)
+	}
+	if FormatOldSchool.IsLegal(deck) {
+		t.Error("expected the deck to be illegal")
+	}
+}
+
+func TestFormatCountsPrintingsTogether(t *testing.T) {
+	bolts := FindAllCardsByName("Lightning Bolt")
+	if len(bolts) < 2 {
+		t.Skip("need two printings of Lightning Bolt")
+	}
+	deck := Deck{FindCardByName("Mountain"): 55, bolts[0]: 3, bolts[1]: 2}
+
+	got := FormatOldSchool.Validate(deck)
+	if len(got) != 1 || got[0].Kind != ViolationTooManyCopies || got[0].Count != 5 {
+		t.Errorf("violations = %q, want 5 Lightning Bolts over the limit", violationStrings(got))
+	}
+}
+
+func TestFormatOldSchoolAllowsRestrictedSingletonsAndBasics(t *testing.T) {
+	deck := Deck{
+		FindCardByName("Mountain"):       50,
+		FindCardByName("Black Lotus"):    1,
+		FindCardByName("Lightning Bolt"): 4,
+		FindCardByName("Hill Giant"):     4,
+		FindCardByName("Braingeyser"):    1,
+	}
+	if vs := FormatOldSchool.Validate(deck); len(vs) != 0 {
+		t.Errorf("expected a legal deck, got %q", violationStrings(vs))
+	}
+}
+
+func TestFormatOldSchoolNeedsSixtyCards(t *testing.T) {
+	deck := Deck{FindCardByName("Mountain"): 36, FindCardByName("Lightning Bolt"): 4}
+
+	got := FormatOldSchool.Validate(deck)
+	if len(got) != 1 || got[0].Kind != ViolationDeckSize {
+		t.Errorf("violations = %q, want only the deck size", violationStrings(got))
+	}
+	if !FormatOldSchool.Banned["Amulet of Quoz"] {
+		t.Error("expected Amulet of Quoz, an ante card from Fallen Empires, to be banned")
+	}
+}
+
+func TestFormatShandalarAndAnythingGoes(t *testing.T) {
+	deck := Deck{FindCardByName("Lightning Bolt"): 12, FindCardByName("Giant Badger"): 1}
+
+	got := FormatShandalar.Validate(deck)
+	if len(got) != 1 || got[0].Kind != ViolationDeckSize {
+		t.Errorf("Shandalar violations = %q, want only the deck size", violationStrings(got))
+	}
+	if !FormatAnything.IsLegal(deck) {
+		t.Errorf("anything goes rejected the deck: %q", violationStrings(FormatAnything.Validate(deck)))
+	}
+}
+
+func TestFormatByID(t *testing.T) {
+	for _, id := range []string{"oldschool", "OldSchool", "Old School 93/94"} {
+		if f, ok := FormatByID(id); !ok || f != FormatOldSchool {
+			t.Errorf("FormatByID(%q) = %v, %v", id, f, ok)
+		}
+	}
+	if _, ok := FormatByID("standard"); ok {
+		t.Error("expected no standard format")
+	}
+}
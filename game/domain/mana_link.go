@@ -0,0 +1,69 @@
+package domain
+
+import (
+	"image"
+	"slices"
+)
+
+// Mana links bind cities to the player. A delivery quest (or any quest paying
+// a mana link) links the city where it is redeemed. Each linked city lends its
+// mana to the player's duels as starting life, more if the duel deck plays the
+// city's color, and a linked city resists Arzakon's minions.
+
+const (
+	// ManaLinkLife is the starting life each linked city adds to a duel.
+	ManaLinkLife = 1
+	// ManaLinkDeckColorLife is the extra starting life a linked city adds
+	// when the duel deck plays the city's color.
+	ManaLinkDeckColorLife = 1
+	// MaxManaLinkLife caps the starting life mana links add to a duel.
+	MaxManaLinkLife = 10
+)
+
+// ManaLink is a city the player has linked.
+type ManaLink struct {
+	City  image.Point // the city's tile
+	Color ColorMask   // the city's color, which its mana lends to duels
+}
+
+// LinkCity links city to the player. It returns false when there is no city
+// or it is already linked.
+func (p *Player) LinkCity(city *City) bool {
+	if city == nil || city.IsManaLinked {
+		return false
+	}
+	city.IsManaLinked = true
+	p.ManaLinks = append(p.ManaLinks, ManaLink{City: image.Pt(city.X, city.Y), Color: city.AmuletColor})
+	return true
+}
+
+// UnlinkCity breaks the player's link to city.
+func (p *Player) UnlinkCity(city *City) {
+	city.IsManaLinked = false
+	tile := image.Pt(city.X, city.Y)
+	p.ManaLinks = slices.DeleteFunc(p.ManaLinks, func(l ManaLink) bool { return l.City == tile })
+}
+
+// ManaLinkCounts returns how many linked cities the player has of each color.
+func (p *Player) ManaLinkCounts() map[ColorMask]int {
+	counts := make(map[ColorMask]int)
+	for _, l := range p.ManaLinks {
+		counts[l.Color]++
+	}
+	return counts
+}
+
+// ManaLinkLife returns the starting life the player's linked cities add to a
+// duel played with a deck of deckColors: ManaLinkLife for every linked city
+// plus ManaLinkDeckColorLife for each one of a color the deck plays, up to
+// MaxManaLinkLife.
+func (p *Player) ManaLinkLife(deckColors ColorMask) int {
+	life := 0
+	for color, n := range p.ManaLinkCounts() {
+		life += n * ManaLinkLife
+		if color != ColorColorless && deckColors&color != 0 {
+			life += n * ManaLinkDeckColorLife
+		}
+	}
+	return min(life, MaxManaLinkLife)
+}
@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"image"
+	"testing"
+)
+
+func TestLinkCityOnlyOnce(t *testing.T) {
+	p := &Player{}
+	city := &City{Name: "Kiel", X: 3, Y: 7, AmuletColor: ColorBlue}
+	if !p.LinkCity(city) {
+		t.Fatal("LinkCity refused a free city")
+	}
+	if p.LinkCity(city) {
+		t.Error("LinkCity linked the same city twice")
+	}
+	if p.LinkCity(nil) {
+		t.Error("LinkCity linked a nil city")
+	}
+	want := ManaLink{City: image.Pt(3, 7), Color: ColorBlue}
+	if len(p.ManaLinks) != 1 || p.ManaLinks[0] != want {
+		t.Fatalf("ManaLinks = %+v, want [%+v]", p.ManaLinks, want)
+	}
+
+	p.UnlinkCity(city)
+	if city.IsManaLinked || len(p.ManaLinks) != 0 {
+		t.Errorf("after UnlinkCity: linked = %v, links = %+v", city.IsManaLinked, p.ManaLinks)
+	}
+}
+
+func TestRepeatedManaLinkGrantsLife(t *testing.T) {
+	p := &Player{Character: Character{Life: 10}}
+	city := &City{Name: "Kiel", AmuletColor: ColorRed}
+	GrantQuestReward(p, QuestReward{ManaLinks: 2}, city)
+	if len(p.ManaLinks) != 1 || p.Life != 11 {
+		t.Errorf("links = %d, life = %d; want 1 link and 11 life", len(p.ManaLinks), p.Life)
+	}
+}
+
+func TestManaLinkLife(t *testing.T) {
+	p := &Player{ManaLinks: []ManaLink{
+		{City: image.Pt(0, 0), Color: ColorRed},
+		{City: image.Pt(1, 0), Color: ColorRed},
+		{City: image.Pt(2, 0), Color: ColorGreen},
+	}}
+	tests := []struct {
+		deck ColorMask
+		want int
+	}{
+		{ColorBlue, 3},
+		{ColorRed, 5},
+		{ColorRed | ColorGreen, 6},
+	}
+	for _, tt := range tests {
+		if got := p.ManaLinkLife(tt.deck); got != tt.want {
+			t.Errorf("ManaLinkLife(%b) = %d, want %d", tt.deck, got, tt.want)
+		}
+	}
+
+	for i := range 20 {
+		p.ManaLinks = append(p.ManaLinks, ManaLink{City: image.Pt(i, 1), Color: ColorRed})
+	}
+	if got := p.ManaLinkLife(ColorRed); got != MaxManaLinkLife {
+		t.Errorf("ManaLinkLife with 23 links = %d, want the cap %d", got, MaxManaLinkLife)
+	}
+}
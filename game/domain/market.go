@@ -0,0 +1,191 @@
+package domain
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// MarketRestockDays is how many days a city's card shop takes to refill its
+// stock.
+const MarketRestockDays = 7
+
+const (
+	// marketColorBias is the percent chance each card in stock is drawn from
+	// the city's amulet color rather than from every card.
+	marketColorBias = 50
+	// marketPriceStep is how far, in percent, each unit of pressure moves a
+	// card's price.
+	marketPriceStep = 10
+	// maxMarketMarkup caps how far demand raises the buying price, and
+	// maxMarketGlut how far a glut lowers the selling price, in percent.
+	maxMarketMarkup = 100
+	maxMarketGlut   = 50
+)
+
+// Market is what a city's card shop remembers between visits: when it was
+// last brought up to date and restocked, and how the player's trading has
+// moved its prices.
+type Market struct {
+	Day        int
+	RestockDay int
+	// Pressure is keyed by card name. Each purchase adds one and each sale
+	// takes one away, and it eases back toward zero by one a day. Demand
+	// raises what the city asks for a card and a glut lowers what it pays,
+	// never the other way round, so no city buys a card for more than
+	// another sells it.
+	Pressure map[string]int `json:",omitempty"`
+}
+
+// MarketStockSize is how many cards a city of the given tier puts up for sale
+// when it restocks.
+func MarketStockSize(tier CityTier) int {
+	switch tier {
+	case TierHamlet:
+		return 4
+	case TierCapital:
+		return 6
+	default:
+		return 5
+	}
+}
+
+// MarketStockWithRand picks a restock for a city of the given tier using
+// rng. About half the cards share the city's amulet color.
+func MarketStockWithRand(rng *rand.Rand, tier CityTier, color ColorMask) []*Card {
+	var colored []*Card
+	if color != ColorColorless {
+		for _, card := range CARDS {
+			if !card.VintageRestricted && card.ColorMask()&color != 0 {
+				colored = append(colored, card)
+			}
+		}
+	}
+
+	n := MarketStockSize(tier)
+	cards := make([]*Card, 0, n)
+	for len(cards) < n {
+		if len(colored) > 0 && rng.Intn(100) < marketColorBias {
+			cards = append(cards, colored[rng.Intn(len(colored))])
+			continue
+		}
+		card := CARDS[rng.Intn(len(CARDS))]
+		if card.VintageRestricted {
+			continue
+		}
+		cards = append(cards, card)
+	}
+	return cards
+}
+
+// OpenMarket brings the city's card shop up to day, the player's Days, before
+// the player trades there.
+func (c *City) OpenMarket(day int) {
+	c.OpenMarketWithRand(day, rand.New(rand.NewSource(rand.Int63())))
+}
+
+// OpenMarketWithRand is OpenMarket drawing any restock from rng. Prices ease
+// back for each day since the last visit and the stock is replaced once
+// MarketRestockDays have passed since the last restock. A city's first
+// market keeps the stock it was generated with.
+func (c *City) OpenMarketWithRand(day int, rng *rand.Rand) {
+	if c.Market == nil {
+		c.Market = &Market{Day: day, RestockDay: day}
+		if len(c.CardsForSale) == 0 {
+			c.CardsForSale = MarketStockWithRand(rng, c.Tier, c.AmuletColor)
+		}
+		return
+	}
+
+	m := c.Market
+	if elapsed := day - m.Day; elapsed > 0 {
+		for name, p := range m.Pressure {
+			switch {
+			case p > elapsed:
+				m.Pressure[name] = p - elapsed
+			case p < -elapsed:
+				m.Pressure[name] = p + elapsed
+			default:
+				delete(m.Pressure, name)
+			}
+		}
+		m.Day = day
+	}
+	if day-m.RestockDay >= MarketRestockDays {
+		c.CardsForSale = MarketStockWithRand(rng, c.Tier, c.AmuletColor)
+		m.RestockDay = day
+	}
+}
+
+func (c *City) pressure(card *Card) int {
+	if c.Market == nil {
+		return 0
+	}
+	return c.Market.Pressure[card.Name()]
+}
+
+func (c *City) addPressure(card *Card, delta int) {
+	if c.Market == nil {
+		c.Market = &Market{}
+	}
+	if c.Market.Pressure == nil {
+		c.Market.Pressure = make(map[string]int)
+	}
+	c.Market.Pressure[card.Name()] += delta
+	if c.Market.Pressure[card.Name()] == 0 {
+		delete(c.Market.Pressure, card.Name())
+	}
+}
+
+// BuyPrice is what the city asks for card before any world magic discount:
+// its full price, marked up by the demand the player's purchases have built.
+func (c *City) BuyPrice(card *Card) int {
+	markup := min(max(c.pressure(card), 0)*marketPriceStep, maxMarketMarkup)
+	return (card.Price*(100+markup) + 99) / 100
+}
+
+// sellRate is the percent of its price a city pays for card. Towns pay more
+// than hamlets and capitals more than towns, and a card sharing the city's
+// amulet color fetches 10% more. The best rate stays below what Haggler's
+// Coin leaves of the buying price, so selling never pays for buying.
+func (c *City) sellRate(card *Card) int {
+	rate := 50
+	switch c.Tier {
+	case TierTown:
+		rate = 60
+	case TierCapital:
+		rate = 65
+	}
+	for _, colorStr := range card.ColorIdentity {
+		if c.AmuletColor&colorStringToMask[colorStr] != 0 {
+			return rate + 10
+		}
+	}
+	return rate
+}
+
+// SellPrice is what the city pays for one copy of card, less the glut the
+// player's sales have built.
+func (c *City) SellPrice(card *Card) int {
+	glut := min(max(-c.pressure(card), 0)*marketPriceStep, maxMarketGlut)
+	return card.Price * c.sellRate(card) * (100 - glut) / 10000
+}
+
+// RecordPurchase raises the city's demand for card after the player buys it.
+func (c *City) RecordPurchase(card *Card) {
+	c.addPressure(card, 1)
+}
+
+// SellCards sells count copies of card to the city and returns the gold they
+// fetch. Each copy floods the market a little more, so it fetches less than
+// the one before. The caller takes the cards from the player.
+func (c *City) SellCards(card *Card, count int) (int, error) {
+	if count <= 0 {
+		return 0, fmt.Errorf("cannot sell %d copies of %s", count, card.Name())
+	}
+	gold := 0
+	for range count {
+		gold += c.SellPrice(card)
+		c.addPressure(card, -1)
+	}
+	return gold, nil
+}
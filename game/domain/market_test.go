@@ -0,0 +1,152 @@
+package domain
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func marketCity(tier CityTier, color ColorMask) *City {
+	return &City{Tier: tier, AmuletColor: color}
+}
+
+func TestMarketStockSizedByTierAndBiasedToAmuletColor(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for _, tier := range []CityTier{TierHamlet, TierTown, TierCapital} {
+		if got := len(MarketStockWithRand(rng, tier, ColorRed)); got != MarketStockSize(tier) {
+			t.Errorf("%s stock = %d cards, want %d", tier, got, MarketStockSize(tier))
+		}
+	}
+	if MarketStockSize(TierHamlet) >= MarketStockSize(TierCapital) {
+		t.Error("expected capitals to stock more cards than hamlets")
+	}
+
+	red, total := 0, 0
+	for range 200 {
+		for _, card := range MarketStockWithRand(rng, TierTown, ColorRed) {
+			if card.VintageRestricted {
+				t.Fatalf("restricted card %s for sale", card.Name())
+			}
+			if card.ColorMask()&ColorRed != 0 {
+				red++
+			}
+			total++
+		}
+	}
+	if red*2 < total {
+		t.Errorf("%d of %d cards were red, want at least half", red, total)
+	}
+}
+
+func TestMarketRestocksAndPricesRecover(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	city := marketCity(TierTown, ColorBlue)
+	city.OpenMarketWithRand(10, rng)
+	if len(city.CardsForSale) != MarketStockSize(TierTown) {
+		t.Fatalf("first visit stocked %d cards", len(city.CardsForSale))
+	}
+
+	bolt := FindCardByName("Lightning Bolt")
+	for range 3 {
+		city.RecordPurchase(bolt)
+	}
+	city.CardsForSale = nil // the player bought everything
+
+	city.OpenMarketWithRand(10+MarketRestockDays-1, rng)
+	if len(city.CardsForSale) != 0 {
+		t.Error("expected the shop to stay empty until it restocks")
+	}
+	if got := city.Market.Pressure[bolt.Name()]; got != 0 {
+		t.Errorf("pressure after %d days = %d, want 0", MarketRestockDays-1, got)
+	}
+	if city.BuyPrice(bolt) != bolt.Price {
+		t.Errorf("BuyPrice = %d after demand eased, want %d", city.BuyPrice(bolt), bolt.Price)
+	}
+
+	city.OpenMarketWithRand(10+MarketRestockDays, rng)
+	if len(city.CardsForSale) != MarketStockSize(TierTown) {
+		t.Errorf("restock has %d cards, want %d", len(city.CardsForSale), MarketStockSize(TierTown))
+	}
+}
+
+func TestMarketPricesMoveWithTrade(t *testing.T) {
+	city := marketCity(TierCapital, ColorRed)
+	bolt := FindCardByName("Lightning Bolt")
+
+	before := city.BuyPrice(bolt)
+	city.RecordPurchase(bolt)
+	if after := city.BuyPrice(bolt); after <= before {
+		t.Errorf("BuyPrice after a purchase = %d, want more than %d", after, before)
+	}
+
+	for range 20 {
+		city.RecordPurchase(bolt)
+	}
+	if got := city.BuyPrice(bolt); got > 2*bolt.Price {
+		t.Errorf("BuyPrice = %d, want at most double the price %d", got, bolt.Price)
+	}
+
+	city = marketCity(TierCapital, ColorRed)
+	last := city.SellPrice(bolt)
+	for i := range 10 {
+		gold, err := city.SellCards(bolt, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if gold != last || (i < 5 && city.SellPrice(bolt) >= last) {
+			t.Fatalf("sale %d fetched %d, then %d, after %d", i, gold, city.SellPrice(bolt), last)
+		}
+		last = city.SellPrice(bolt)
+	}
+	if floor := bolt.Price * city.sellRate(bolt) / 200; last < floor {
+		t.Errorf("SellPrice fell to %d, want at least %d", last, floor)
+	}
+}
+
+// The FAQ's Negative Card Bug: sell -50 of a card where it's cheap and then
+// sell the cards that appear where it's dear.
+func TestSellCardsRejectsNonPositiveCounts(t *testing.T) {
+	city := marketCity(TierHamlet, ColorGreen)
+	card := FindCardByName("Llanowar Elves")
+	for _, count := range []int{-50, -1, 0} {
+		gold, err := city.SellCards(card, count)
+		if err == nil || gold != 0 {
+			t.Errorf("SellCards(%d) = %d, %v, want an error", count, gold, err)
+		}
+	}
+	if city.Market != nil {
+		t.Errorf("refused sales moved the market: %+v", city.Market)
+	}
+}
+
+// Whatever the tier, color, world magic or trading, a card bought in one
+// city never sells for more in another.
+func TestMarketHasNoArbitrage(t *testing.T) {
+	haggler := &Player{WorldMagics: []*WorldMagic{{Name: WorldMagicHagglersCoin}}}
+	var cities []*City
+	for _, tier := range []CityTier{TierHamlet, TierTown, TierCapital} {
+		for _, color := range GetAllAmuletColors() {
+			cities = append(cities, marketCity(tier, color))
+		}
+	}
+	glutted := marketCity(TierHamlet, ColorWhite)
+
+	for _, card := range CARDS {
+		glutted.addPressure(card, -3)
+		cheapest := haggler.TownPrice(glutted.BuyPrice(card))
+		for _, city := range cities {
+			cheapest = min(cheapest, haggler.TownPrice(city.BuyPrice(card)))
+		}
+		for _, city := range cities {
+			if sell := city.SellPrice(card); sell > cheapest {
+				t.Fatalf("%s: %s sells for %d but can be bought for %d", card.Name(), city.Tier, sell, cheapest)
+			}
+		}
+
+		city := cities[len(cities)-1]
+		gold, _ := city.SellCards(card, 3)
+		if bought := 3 * haggler.TownPrice(city.BuyPrice(card)); gold > bought {
+			t.Fatalf("%s: selling 3 fetched %d, buying them back costs %d", card.Name(), gold, bought)
+		}
+		delete(city.Market.Pressure, card.Name())
+	}
+}
@@ -24,6 +24,8 @@ type Player struct {
 	Amulets         map[ColorMask]int
 	WorldMagics     []*WorldMagic
 	ActiveDeck      int
+	DeckNames       []string // names of the player's decks, by index; "" for the default name
+	DeckFormat      string   // ID of the format the deck editor checks decks against
 	ActiveQuests    []*Quest // active quests (legacy delivery/defeat + deck-changing), up to MaxActiveQuests
 	Days            int
 	TimeAccumulator float64
@@ -31,6 +33,9 @@ type Player struct {
 	BonusDuelLife   int
 	BonusDuelCards  []*Card // One-time bonus cards that start in play in the next duel
 	DungeonState    *DungeonState
+	VisitedCities   []image.Point // tiles of cities entered, for Leap of Fate
+	Clues           *PlayerClues  // dungeon clues found so far, for the clue journal
+	ManaLinks       []ManaLink    // linked cities, in the order they were linked
 }
 
 const TravelDistancePerDay = 5000.0
@@ -151,15 +156,7 @@ func (p *Player) Update(screenW, screenH, levelW, levelH int) error {
 	if p.X != oldX || p.Y != oldY {
 		// Player moved
 		dist := math.Sqrt(math.Pow(float64(p.X-oldX), 2) + math.Pow(float64(p.Y-oldY), 2))
-		p.TimeAccumulator += dist
-		if p.TimeAccumulator >= TravelDistancePerDay {
-			p.TimeAccumulator -= TravelDistancePerDay
-			p.Days++
-			for _, q := range p.ActiveQuests {
-				q.DaysRemaining--
-			}
-			p.ExpireQuests()
-		}
+		p.travel(dist)
 	}
 
 	if p.X < screenW/2 {
@@ -323,7 +320,7 @@ func (p *Player) RedeemFulfilledQuests(city *City) []DeckQuestReward {
 			q.IsCompleted = true
 		}
 		if q.IsFulfilled() {
-			cards := GrantQuestReward(p, q.Reward)
+			cards := GrantQuestReward(p, q.Reward, city)
 			rewards = append(rewards, DeckQuestReward{Quest: q, Reward: q.Reward, Cards: cards})
 			continue
 		}
@@ -380,7 +377,7 @@ func (p *Player) RemoveAmulet(color ColorMask) error {
 }
 
 func (p *Player) HasWorldMagic(magic *WorldMagic) bool {
-	return slices.Contains(p.WorldMagics, magic)
+	return magic != nil && p.HasWorldMagicNamed(magic.Name)
 }
 
 func (p *Player) AddWorldMagic(magic *WorldMagic) {
@@ -392,3 +389,11 @@ func (p *Player) AddWorldMagic(magic *WorldMagic) {
 func (p *Player) GetWorldMagics() []*WorldMagic {
 	return p.WorldMagics
 }
+
+// VisitCity records that the player has entered city.
+func (p *Player) VisitCity(city *City) {
+	tile := image.Pt(city.X, city.Y)
+	if !slices.Contains(p.VisitedCities, tile) {
+		p.VisitedCities = append(p.VisitedCities, tile)
+	}
+}
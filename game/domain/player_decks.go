@@ -0,0 +1,113 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// The player keeps several named decks built from one collection. Each owned
+// copy of a card is in at most one deck or sideboard at a time. Decks are numbered from 0, and
+// ActiveDeck is the one the player duels with.
+
+const (
+	// MaxDecks caps how many decks the player can keep.
+	MaxDecks = 8
+	// MaxDeckNameLen caps the length of a deck name.
+	MaxDeckNameLen = 24
+)
+
+// NumDecks returns how many decks the player has, always at least one.
+func (p *Player) NumDecks() int {
+	n := max(p.GetNumDecks(), len(p.DeckNames), p.ActiveDeck+1)
+	for _, item := range p.CardCollection {
+		n = max(n, len(item.SideboardCounts))
+	}
+	return n
+}
+
+// DeckName returns the name of deck i, or "Deck i+1" when it has none.
+func (p *Player) DeckName(i int) string {
+	if i < len(p.DeckNames) && p.DeckNames[i] != "" {
+		return p.DeckNames[i]
+	}
+	return fmt.Sprintf("Deck %d", i+1)
+}
+
+// RenameDeck names deck i. An empty name restores the default one.
+func (p *Player) RenameDeck(i int, name string) error {
+	if i < 0 || i >= p.NumDecks() {
+		return fmt.Errorf("deck %d does not exist", i)
+	}
+	p.setDeckName(i, name)
+	return nil
+}
+
+func (p *Player) setDeckName(i int, name string) {
+	name = strings.TrimSpace(name)
+	if r := []rune(name); len(r) > MaxDeckNameLen {
+		name = string(r[:MaxDeckNameLen])
+	}
+	for len(p.DeckNames) <= i {
+		p.DeckNames = append(p.DeckNames, "")
+	}
+	p.DeckNames[i] = name
+}
+
+// GetActiveDeck returns the deck the player duels with.
+func (p *Player) GetActiveDeck() Deck {
+	return p.CardCollection.GetDeck(p.ActiveDeck)
+}
+
+// SetActiveDeck makes deck i the one the player duels with.
+func (p *Player) SetActiveDeck(i int) error {
+	if i < 0 || i >= p.NumDecks() {
+		return fmt.Errorf("deck %d does not exist", i)
+	}
+	p.ActiveDeck = i
+	return nil
+}
+
+// NewDeck adds an empty deck called name and returns its index.
+func (p *Player) NewDeck(name string) (int, error) {
+	i := p.NumDecks()
+	if i >= MaxDecks {
+		return 0, fmt.Errorf("no room for more than %d decks", MaxDecks)
+	}
+	p.setDeckName(i, name)
+	return i, nil
+}
+
+// DuplicateDeck adds a copy of deck i, and its sideboard, and returns the
+// copy's index. The copy only gets the spare copies of each card, so it also
+// returns the entries that didn't fit, as "count name".
+func (p *Player) DuplicateDeck(i int) (int, []string, error) {
+	if i < 0 || i >= p.NumDecks() {
+		return 0, nil, fmt.Errorf("deck %d does not exist", i)
+	}
+	copied, err := p.NewDeck(p.DeckName(i) + " copy")
+	if err != nil {
+		return 0, nil, err
+	}
+	return copied, p.CardCollection.CopyDeck(i, copied), nil
+}
+
+// DeleteDeck removes deck i, leaving its cards in the collection. The player's
+// only deck can't be deleted. Deleting the active deck makes the deck that
+// takes its place active, or the one before it when it was the last.
+func (p *Player) DeleteDeck(i int) error {
+	n := p.NumDecks()
+	if i < 0 || i >= n {
+		return fmt.Errorf("deck %d does not exist", i)
+	}
+	if n == 1 {
+		return fmt.Errorf("can't delete your only deck")
+	}
+	p.CardCollection.RemoveDeck(i)
+	if i < len(p.DeckNames) {
+		p.DeckNames = append(p.DeckNames[:i], p.DeckNames[i+1:]...)
+	}
+	if p.ActiveDeck > i || p.ActiveDeck == n-1 {
+		p.ActiveDeck--
+	}
+	return nil
+}
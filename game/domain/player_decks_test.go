@@ -0,0 +1,127 @@
+package domain
+
+import "testing"
+
+func deckPlayer() (*Player, *Card, *Card) {
+	bolt := FindCardByName("Lightning Bolt")
+	mountain := FindCardByName("Mountain")
+	cc := NewCardCollection()
+	cc.AddCardToDeck(mountain, 0, 10)
+	cc.AddCardToDeck(bolt, 0, 4)
+	return &Player{Character: Character{CardCollection: cc}}, bolt, mountain
+}
+
+func TestPlayerDecksNewRenameAndSwitch(t *testing.T) {
+	p, bolt, _ := deckPlayer()
+
+	if got := p.NumDecks(); got != 1 {
+		t.Fatalf("NumDecks() = %d, want 1", got)
+	}
+	if got := p.DeckName(0); got != "Deck 1" {
+		t.Errorf("DeckName(0) = %q, want the default name", got)
+	}
+
+	i, err := p.NewDeck("Quest deck")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if i != 1 || p.NumDecks() != 2 || p.DeckName(1) != "Quest deck" {
+		t.Fatalf("NewDeck made deck %d %q of %d", i, p.DeckName(i), p.NumDecks())
+	}
+	if err := p.SetActiveDeck(1); err != nil {
+		t.Fatal(err)
+	}
+	if len(p.GetActiveDeck()) != 0 {
+		t.Errorf("expected the new deck to be empty, got %v", p.GetActiveDeck())
+	}
+
+	// Each copy is in one deck at a time: deck 0 holds all four bolts.
+	if err := p.CardCollection.MoveCardToDeck(bolt, 1, 1); err == nil {
+		t.Error("expected deck 1 not to get a bolt deck 0 is using")
+	}
+	p.CardCollection.AddCard(bolt, 1)
+	if err := p.CardCollection.MoveCardToDeck(bolt, 1, 1); err != nil {
+		t.Fatalf("expected deck 1 to get the spare bolt: %v", err)
+	}
+
+	if err := p.RenameDeck(0, "  Main  "); err != nil {
+		t.Fatal(err)
+	}
+	if got := p.DeckName(0); got != "Main" {
+		t.Errorf("DeckName(0) = %q, want Main", got)
+	}
+	if err := p.SetActiveDeck(2); err == nil {
+		t.Error("expected an error switching to a missing deck")
+	}
+}
+
+func TestPlayerDuplicateDeck(t *testing.T) {
+	p, bolt, mountain := deckPlayer()
+	blast := FindCardByName("Red Elemental Blast")
+	p.CardCollection.AddCardToSideboard(blast, 0, 2)
+	// Enough spare copies for the copy except one bolt.
+	p.CardCollection.AddCard(mountain, 10)
+	p.CardCollection.AddCard(bolt, 3)
+	p.CardCollection.AddCard(blast, 2)
+	if err := p.RenameDeck(0, "Red"); err != nil {
+		t.Fatal(err)
+	}
+
+	i, missing, err := p.DuplicateDeck(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.DeckName(i) != "Red copy" {
+		t.Errorf("copy is named %q", p.DeckName(i))
+	}
+	cc := p.CardCollection
+	if cc.GetDeckCount(bolt, i) != 3 || cc.GetDeckCount(mountain, i) != 10 || cc.GetSideboardCount(blast, i) != 2 {
+		t.Errorf("copy is %v with sideboard %v", cc.GetDeck(i), cc.GetSideboard(i))
+	}
+	if cc.GetDeckCount(bolt, 0) != 4 {
+		t.Errorf("duplicating a deck took bolts from the original: %d left", cc.GetDeckCount(bolt, 0))
+	}
+	if len(missing) != 1 || missing[0] != "1 Lightning Bolt" {
+		t.Errorf("missing = %q, want the bolt that didn't fit", missing)
+	}
+	if cc.GetTotalCount(bolt) != 7 {
+		t.Errorf("duplicating a deck added cards to the collection: %d bolts", cc.GetTotalCount(bolt))
+	}
+}
+
+func TestPlayerDeleteDeck(t *testing.T) {
+	p, bolt, _ := deckPlayer()
+	if err := p.DeleteDeck(0); err == nil {
+		t.Fatal("expected the only deck not to be deleted")
+	}
+
+	second, _ := p.NewDeck("Second")
+	third, _ := p.NewDeck("Third")
+	p.CardCollection.AddCard(bolt, 2)
+	if err := p.CardCollection.MoveCardToDeck(bolt, third, 2); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.SetActiveDeck(third); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := p.DeleteDeck(second); err != nil {
+		t.Fatal(err)
+	}
+	if p.NumDecks() != 2 || p.ActiveDeck != 1 || p.DeckName(1) != "Third" {
+		t.Fatalf("after deleting deck 1: %d decks, active %d %q", p.NumDecks(), p.ActiveDeck, p.DeckName(p.ActiveDeck))
+	}
+	if got := p.CardCollection.GetDeckCount(bolt, 1); got != 2 {
+		t.Errorf("Third deck has %d bolts, want 2", got)
+	}
+
+	if err := p.DeleteDeck(1); err != nil {
+		t.Fatal(err)
+	}
+	if p.NumDecks() != 1 || p.ActiveDeck != 0 {
+		t.Errorf("after deleting the active last deck: %d decks, active %d", p.NumDecks(), p.ActiveDeck)
+	}
+	if got := p.CardCollection.GetTotalCount(bolt); got != 6 {
+		t.Errorf("deleting decks lost cards: %d bolts", got)
+	}
+}
@@ -84,3 +84,88 @@ func TestWorldMagicDefinitions(t *testing.T) {
 		}
 	}
 }
+
+func TestWorldMagicLookupSurvivesSaveRoundTrip(t *testing.T) {
+	player := Player{}
+	player.AddWorldMagic(FindWorldMagic(WorldMagicQuickening))
+
+	// A loaded save holds a copy, not the pointer from AllWorldMagics.
+	player.WorldMagics[0] = &WorldMagic{Name: WorldMagicQuickening}
+	if !player.HasWorldMagic(FindWorldMagic(WorldMagicQuickening)) {
+		t.Error("HasWorldMagic should match by name after a save round trip")
+	}
+}
+
+func TestWorldMagicEffects(t *testing.T) {
+	with := func(name string) *Player {
+		p := &Player{Character: Character{Life: 10}}
+		if name != "" {
+			p.AddWorldMagic(FindWorldMagic(name))
+		}
+		return p
+	}
+	city := &City{Tier: TierCapital}
+
+	tests := []struct {
+		name string
+		got  int
+		want int
+	}{
+		{"town price", with("").TownPrice(100), 100},
+		{"haggler's coin price", with(WorldMagicHagglersCoin).TownPrice(100), 80},
+		{"food price", with("").FoodPrice(city), city.FoodCost()},
+		{"haggler's coin food", with(WorldMagicHagglersCoin).FoodPrice(city), city.FoodCost() * 80 / 100},
+		{"fruit of sustenance food", with(WorldMagicFruitOfSustenance).FoodPrice(city), 0},
+		{"duel life", with("").DuelStartingLife(), 10},
+		{"sword of resistance life", with(WorldMagicSwordOfResistance).DuelStartingLife(), 10 + SwordOfResistanceLife},
+		{"opponent life", with("").OpponentStartingLife(8), 8},
+		{"staff of thunder opponent life", with(WorldMagicStaffOfThunder).OpponentStartingLife(8), 8 - StaffOfThunderDamage},
+		{"staff of thunder leaves 1 life", with(WorldMagicStaffOfThunder).OpponentStartingLife(1), 1},
+		{"opening hand", with("").OpeningHandSize(), 7},
+		{"conjurer's will opening hand", with(WorldMagicConjurersWill).OpeningHandSize(), 8},
+		{"mulligan penalty", with("").MulliganPenalty(2), 2},
+		{"sleight of hand mulligan penalty", with(WorldMagicSleightOfHand).MulliganPenalty(2), 1},
+		{"sleight of hand free mulligan", with(WorldMagicSleightOfHand).MulliganPenalty(1), 0},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s = %d, want %d", tt.name, tt.got, tt.want)
+		}
+	}
+
+	if with("").KeepsAnteOnLoss() || !with(WorldMagicRingOfTheGuardian).KeepsAnteOnLoss() {
+		t.Error("only Ring of the Guardian should keep the ante on a loss")
+	}
+	if with("").WorldMoveSpeedFactor() != 1 || with(WorldMagicQuickening).WorldMoveSpeedFactor() != QuickeningSpeedFactor {
+		t.Error("only Quickening should change world movement speed")
+	}
+}
+
+func TestLearnedCardRequiresTome(t *testing.T) {
+	spell := &Card{CardName: "Giant Growth", CardType: CardTypeInstant}
+	deck := Deck{spell: 2, &Card{CardName: "Forest", CardType: CardTypeLand}: 10}
+
+	if c := (&Player{}).LearnedCard(deck); c != nil {
+		t.Errorf("LearnedCard without the Tome = %v, want nil", c.CardName)
+	}
+	p := &Player{}
+	p.AddWorldMagic(FindWorldMagic(WorldMagicTomeOfEnlightenment))
+	if c := p.LearnedCard(deck); c != spell {
+		t.Errorf("LearnedCard = %v, want the deck's only spell", c)
+	}
+	if c := p.LearnedCard(Deck{}); c != nil {
+		t.Errorf("LearnedCard from an empty deck = %v, want nil", c.CardName)
+	}
+}
+
+func TestVisitCityRecordsEachCityOnce(t *testing.T) {
+	p := &Player{}
+	a := &City{Name: "A", X: 1, Y: 2}
+	b := &City{Name: "B", X: 3, Y: 4}
+	p.VisitCity(a)
+	p.VisitCity(b)
+	p.VisitCity(a)
+	if len(p.VisitedCities) != 2 {
+		t.Fatalf("VisitedCities = %v, want 2 entries", p.VisitedCities)
+	}
+}
@@ -23,7 +23,7 @@ type QuestReward struct {
 	CardColor   ColorMask // color bundle for Cards (ColorColorless => any)
 	Amulets     int
 	AmuletColor ColorMask
-	ManaLinks   int // each grants a permanent +1 life
+	ManaLinks   int // each links the city the reward is redeemed in
 }
 
 // IsEmpty reports whether the reward grants nothing.
@@ -68,6 +68,8 @@ const (
 	ConstraintColorLight
 	// ConstraintNoAttacking requires winning without declaring an attacker.
 	ConstraintNoAttacking
+	// ConstraintFormat requires the deck to be legal in Format.
+	ConstraintFormat
 )
 
 type Quest struct {
@@ -95,6 +97,7 @@ type Quest struct {
 	Target      int        // action-tracker count target
 	Progress    int        // accumulated action-tracker progress
 	ConstraintN int        // for ConstraintFatDeck / ConstraintLowCurve
+	Format      string     // for ConstraintFormat: the format's ID
 
 	Reward QuestReward
 }
@@ -37,6 +37,7 @@ type questDefRaw struct {
 	Target       int    `toml:"target"`
 	Constraint   string `toml:"constraint"`
 	N            int    `toml:"n"`
+	Format       string `toml:"format"`
 }
 
 type questDefFile struct {
@@ -102,6 +103,7 @@ func parseQuestDef(raw *questDefRaw) (*Quest, error) {
 		DeadlineDays: raw.DeadlineDays,
 		Target:       raw.Target,
 		ConstraintN:  raw.N,
+		Format:       raw.Format,
 		Color:        ParseColorMask(raw.Color),
 		CardTypes:    parseCardTypes(raw.CardType),
 	}
@@ -130,6 +132,11 @@ func parseQuestDef(raw *questDefRaw) (*Quest, error) {
 			return nil, err
 		}
 		def.Constraint = constraint
+		if constraint == ConstraintFormat {
+			if _, ok := FormatByID(raw.Format); !ok {
+				return nil, fmt.Errorf("quest %q has unknown format %q", raw.ID, raw.Format)
+			}
+		}
 	default:
 		return nil, fmt.Errorf("quest %q has unknown type %q", raw.ID, raw.Type)
 	}
@@ -171,6 +178,8 @@ func parseConstraint(s string) (QuestConstraint, error) {
 		return ConstraintColorLight, nil
 	case "no_attacking":
 		return ConstraintNoAttacking, nil
+	case "format":
+		return ConstraintFormat, nil
 	default:
 		return ConstraintNone, fmt.Errorf("unknown constraint %q", s)
 	}
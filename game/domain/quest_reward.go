@@ -26,10 +26,15 @@ type DeckQuestReward struct {
 
 // GrantQuestReward applies a reward to the player and returns any cards added
 // (so the reward screen can display them). Every quest type funnels its payout
-// through here.
-func GrantQuestReward(p *Player, r QuestReward) []*Card {
+// through here. Mana links link city, the city the reward is redeemed in; a
+// link that finds the city already linked grants a permanent +1 life instead.
+func GrantQuestReward(p *Player, r QuestReward, city *City) []*Card {
 	p.Gold += r.Gold
-	p.Life += r.ManaLinks
+	for range r.ManaLinks {
+		if !p.LinkCity(city) {
+			p.Life++
+		}
+	}
 	for range r.Amulets {
 		p.AddAmulet(NewAmulet(r.AmuletColor))
 	}
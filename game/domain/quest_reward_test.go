@@ -72,14 +72,18 @@ func TestGrantQuestReward(t *testing.T) {
 	p := &Player{Character: Character{CardCollection: NewCardCollection(), Life: 10}, Gold: 100, Amulets: make(map[ColorMask]int)}
 	r := QuestReward{Gold: 250, Cards: 2, CardColor: ColorRed, Amulets: 1, AmuletColor: ColorBlue, ManaLinks: 1}
 
+	city := &City{Name: "Kiel", X: 3, Y: 7, AmuletColor: ColorGreen}
 	before := p.NumCards()
-	cards := GrantQuestReward(p, r)
+	cards := GrantQuestReward(p, r, city)
 
 	if p.Gold != 350 {
 		t.Errorf("gold = %d, want 350", p.Gold)
 	}
-	if p.Life != 11 {
-		t.Errorf("life = %d, want 11 (one mana link)", p.Life)
+	if !city.IsManaLinked || len(p.ManaLinks) != 1 || p.ManaLinks[0].Color != ColorGreen {
+		t.Errorf("city linked = %v, links = %+v; want Kiel linked green", city.IsManaLinked, p.ManaLinks)
+	}
+	if p.Life != 10 {
+		t.Errorf("life = %d, want 10 (the mana link went to the city)", p.Life)
 	}
 	if p.Amulets[ColorBlue] != 1 {
 		t.Errorf("blue amulets = %d, want 1", p.Amulets[ColorBlue])
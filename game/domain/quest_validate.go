@@ -25,6 +25,8 @@ func ValidateDeckConstraint(q *Quest, deck Deck) (bool, string) {
 		return validateLowCurve(q, deck)
 	case ConstraintColorLight:
 		return validateColorLight(q, deck)
+	case ConstraintFormat:
+		return validateFormat(q, deck)
 	case ConstraintNoAttacking, ConstraintNone:
 		return true, ""
 	default:
@@ -80,3 +82,15 @@ func validateColorLight(q *Quest, deck Deck) (bool, string) {
 	}
 	return true, ""
 }
+
+func validateFormat(q *Quest, deck Deck) (bool, string) {
+	f, ok := FormatByID(q.Format)
+	if !ok {
+		return true, ""
+	}
+	violations := f.Validate(deck)
+	if len(violations) == 0 {
+		return true, ""
+	}
+	return false, "Deck must be " + f.Name + " legal: " + violations[0].String()
+}
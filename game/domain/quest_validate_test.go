@@ -81,3 +81,16 @@ func TestValidateNoAttackingIsDeckAgnostic(t *testing.T) {
 		t.Error("no-attacking is not a deck check; should always pass deck validation")
 	}
 }
+
+func TestValidateFormat(t *testing.T) {
+	q := &Quest{Constraint: ConstraintFormat, Format: FormatOldSchool.ID}
+	legal := Deck{FindCardByName("Mountain"): 56, FindCardByName("Lightning Bolt"): 4}
+	if ok, reason := ValidateDeckConstraint(q, legal); !ok {
+		t.Errorf("legal Old School deck should pass, got %q", reason)
+	}
+	legal[FindCardByName("Lightning Bolt")] = 5
+	ok, reason := ValidateDeckConstraint(q, legal)
+	if ok || reason != "Deck must be Old School 93/94 legal: 5 copies of Lightning Bolt, 4 allowed" {
+		t.Errorf("five bolts should fail Old School, got %v %q", ok, reason)
+	}
+}
@@ -21,33 +21,33 @@ func (c *Character) LoadImages() error {
 	if c.Visage == nil {
 		data, err := assets.RogueVisageFS.ReadFile("art/rogues/" + c.VisageFn)
 		if err != nil {
-			fmt.Printf("ERROR: Loading Visage: %v", err)
+			logger.Error("failed to load visage", "rogue", c.Name, "err", err)
 		}
 		img, err := imageutil.LoadImage(data)
 		if err != nil {
-			fmt.Printf("ERROR: Loading Visage: %v", err)
+			logger.Error("failed to load visage", "rogue", c.Name, "err", err)
 		}
 		c.Visage = img
 	}
 	if c.WalkingSprite == nil {
 		data, err := assets.RogueSpriteFS.ReadFile("art/screens/world/characters/" + c.WalkingSpriteFn)
 		if err != nil {
-			fmt.Printf("ERROR: Loading Walking for %s: %v", c.Name, err)
+			logger.Error("failed to load walking sprite", "rogue", c.Name, "err", err)
 		}
 		spr, err := imageutil.LoadSpriteSheet(5, 8, data)
 		if err != nil {
-			fmt.Printf("ERROR: Loading Walking for %s: %v", c.Name, err)
+			logger.Error("failed to load walking sprite", "rogue", c.Name, "err", err)
 		}
 		c.WalkingSprite = spr
 	}
 	if c.ShadowSprite == nil {
 		data, err := assets.RogueSpriteFS.ReadFile("art/screens/world/characters/" + c.WalkingShadowSpriteFn)
 		if err != nil {
-			fmt.Printf("ERROR: Loading Shadow: %v", err)
+			logger.Error("failed to load shadow sprite", "rogue", c.Name, "err", err)
 		}
 		spr, err := imageutil.LoadSpriteSheet(5, 8, data)
 		if err != nil {
-			fmt.Printf("ERROR: Loading Shadow: %v", err)
+			logger.Error("failed to load shadow sprite", "rogue", c.Name, "err", err)
 		}
 		c.ShadowSprite = spr
 	}
@@ -181,7 +181,7 @@ func loadRogues() map[string]*Character {
 			r.CardCollection.AddCardToDeck(card, 0, count)
 		}
 
-		// Add sideboard cards to collection only (not to any deck)
+		// Add sideboard cards to the collection and deck 0's sideboard
 		for _, entry := range r.SideboardRaw {
 			if len(entry) != 2 {
 				panic(fmt.Errorf("invalid sideboard entry format: %v", entry))
@@ -195,7 +195,7 @@ func loadRogues() map[string]*Character {
 			if card == nil {
 				panic(fmt.Sprintf("Unable to find card: %s\n", name))
 			}
-			r.CardCollection.AddCard(card, count)
+			r.CardCollection.AddCardToSideboard(card, 0, count)
 		}
 
 		// Analyze collection colors and set color fields
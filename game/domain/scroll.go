@@ -0,0 +1,122 @@
+package domain
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/benprew/s30/assets"
+)
+
+// scrollQuestionRaw mirrors one [[question]] in assets/configs/scrolls.toml.
+type scrollQuestionRaw struct {
+	Question string   `toml:"question"`
+	Answer   string   `toml:"answer"`
+	Wrong    []string `toml:"wrong"`
+}
+
+type scrollQuestionFile struct {
+	Questions []scrollQuestionRaw `toml:"question"`
+}
+
+// ScrollQuestions is the bank of trivia riddles dungeon scrolls draw from.
+var ScrollQuestions = loadScrollQuestions()
+
+func loadScrollQuestions() []scrollQuestionRaw {
+	var file scrollQuestionFile
+	if _, err := toml.Decode(string(assets.Scrolls_toml), &file); err != nil {
+		panic(fmt.Errorf("error decoding scrolls.toml: %w", err))
+	}
+	for i, q := range file.Questions {
+		if q.Question == "" || q.Answer == "" || len(q.Wrong) == 0 {
+			panic(fmt.Errorf("scrolls.toml question %d needs a question, an answer and wrong answers", i+1))
+		}
+	}
+	return file.Questions
+}
+
+// RandomScrollQuestion draws a riddle from the bank, with its choices in a
+// random order.
+func RandomScrollQuestion() *ScrollQuestion {
+	return randomScrollQuestion(rand.New(rand.NewSource(time.Now().UnixNano())))
+}
+
+func randomScrollQuestion(rng *rand.Rand) *ScrollQuestion {
+	if len(ScrollQuestions) == 0 {
+		return &ScrollQuestion{}
+	}
+	raw := ScrollQuestions[rng.Intn(len(ScrollQuestions))]
+	q := &ScrollQuestion{Question: raw.Question, Choices: append([]string{raw.Answer}, raw.Wrong...)}
+	rng.Shuffle(len(q.Choices), func(i, j int) {
+		q.Choices[i], q.Choices[j] = q.Choices[j], q.Choices[i]
+	})
+	for i, c := range q.Choices {
+		if c == raw.Answer {
+			q.Answer = i
+		}
+	}
+	return q
+}
+
+// ScrollRewardLife and ScrollPenaltyLife bound the life a scroll's riddle
+// adds to or takes from the next duel.
+const (
+	ScrollRewardLife  = 3
+	ScrollPenaltyLife = 2
+)
+
+// ScrollOutcome is what answering a scroll did to the player.
+type ScrollOutcome struct {
+	Correct bool
+	// Answer is the right answer, shown to a player who got it wrong.
+	Answer string
+	// Effect is the dice effect the answer caused, already applied. It is nil
+	// when the reward is a clue.
+	Effect *DiceEffect
+	// Clue is set when a right answer earns a clue to another dungeon. The
+	// caller, who knows where the other dungeons are, reveals it.
+	Clue bool
+}
+
+// AnswerScroll resolves the player's answer to the riddle on a scroll tile
+// and clears the tile. A right answer earns a clue to another dungeon (when
+// canClue says one is left to find), bonus life or a card from the player's
+// deck in play for the next duel. A wrong answer costs life in the next duel.
+// ok is false if tile isn't an unanswered scroll.
+func (st *DungeonState) AnswerScroll(tile *DungeonTile, choice int, p *Player, canClue bool) (out ScrollOutcome, ok bool) {
+	return st.answerScrollWithRand(tile, choice, p, canClue, rand.New(rand.NewSource(time.Now().UnixNano())))
+}
+
+func (st *DungeonState) answerScrollWithRand(tile *DungeonTile, choice int, p *Player, canClue bool, rng *rand.Rand) (ScrollOutcome, bool) {
+	if tile == nil || tile.Type != DungeonTileScroll || tile.Scroll == nil {
+		return ScrollOutcome{}, false
+	}
+	q := tile.Scroll
+	out := ScrollOutcome{Correct: choice == q.Answer}
+	if q.Answer >= 0 && q.Answer < len(q.Choices) {
+		out.Answer = q.Choices[q.Answer]
+	}
+	tile.Scroll = nil
+	tile.Type = DungeonTileEmpty
+
+	if !out.Correct {
+		out.Effect = &DiceEffect{Type: DiceDisadvantage, LifeMod: -(1 + rng.Intn(ScrollPenaltyLife))}
+		applyDiceEffect(out.Effect, p)
+		return out, true
+	}
+	switch roll := rng.Intn(3); {
+	case roll == 0 && canClue:
+		out.Clue = true
+		return out, true
+	case roll == 1:
+		if card := randomDiceCard(rng, p.GetActiveDeck().NonLandCards()); card != nil {
+			out.Effect = &DiceEffect{Type: DiceAdvantage, Card: card}
+		}
+	}
+	if out.Effect == nil {
+		out.Effect = &DiceEffect{Type: DiceAdvantage, LifeMod: 1 + rng.Intn(ScrollRewardLife)}
+	}
+	applyDiceEffect(out.Effect, p)
+	return out, true
+}
@@ -0,0 +1,138 @@
+package domain
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestScrollQuestionsAreWellFormed(t *testing.T) {
+	if len(ScrollQuestions) < 20 {
+		t.Fatalf("only %d scroll questions, want a bank of at least 20", len(ScrollQuestions))
+	}
+	seen := make(map[string]bool)
+	for _, q := range ScrollQuestions {
+		if seen[q.Question] {
+			t.Errorf("duplicate question %q", q.Question)
+		}
+		seen[q.Question] = true
+		if slices.Contains(q.Wrong, q.Answer) {
+			t.Errorf("%q lists its answer %q as wrong", q.Question, q.Answer)
+		}
+		if len(q.Wrong) > 3 {
+			t.Errorf("%q has %d wrong answers, the scroll has room for 3", q.Question, len(q.Wrong))
+		}
+	}
+}
+
+func TestRandomScrollQuestionPointsAtTheAnswer(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	positions := make(map[int]bool)
+	for range 100 {
+		q := randomScrollQuestion(rng)
+		i := slices.IndexFunc(ScrollQuestions, func(raw scrollQuestionRaw) bool { return raw.Question == q.Question })
+		if i < 0 {
+			t.Fatalf("question %q isn't in the bank", q.Question)
+		}
+		if got, want := q.Choices[q.Answer], ScrollQuestions[i].Answer; got != want {
+			t.Fatalf("%q: Answer points at %q, want %q", q.Question, got, want)
+		}
+		positions[q.Answer] = true
+	}
+	if len(positions) < 2 {
+		t.Errorf("the answer was always choice %v, want it shuffled", positions)
+	}
+}
+
+func scrollTile() *DungeonTile {
+	return &DungeonTile{
+		Type:   DungeonTileScroll,
+		Scroll: &ScrollQuestion{Question: "What color is Serra Angel?", Choices: []string{"Blue", "White"}, Answer: 1},
+	}
+}
+
+func TestAnswerScrollWrongCostsLife(t *testing.T) {
+	st := &DungeonState{}
+	p := &Player{}
+	tile := scrollTile()
+
+	out, ok := st.answerScrollWithRand(tile, 0, p, true, rand.New(rand.NewSource(1)))
+	if !ok || out.Correct {
+		t.Fatalf("wrong answer = %+v, %v", out, ok)
+	}
+	if out.Answer != "White" {
+		t.Errorf("Answer = %q, want the right answer", out.Answer)
+	}
+	if p.BonusDuelLife >= 0 || p.BonusDuelLife < -ScrollPenaltyLife {
+		t.Errorf("BonusDuelLife = %d, want a penalty of 1 to %d", p.BonusDuelLife, ScrollPenaltyLife)
+	}
+	if tile.Type != DungeonTileEmpty || tile.Scroll != nil {
+		t.Errorf("scroll tile not cleared: %+v", tile)
+	}
+	if _, ok := st.answerScrollWithRand(tile, 1, p, true, rand.New(rand.NewSource(1))); ok {
+		t.Error("answered a scroll twice")
+	}
+}
+
+func TestAnswerScrollRightPaysOut(t *testing.T) {
+	kinds := make(map[string]bool)
+	for seed := range int64(50) {
+		p := &Player{Character: Character{CardCollection: NewCardCollection()}}
+		p.CardCollection.AddCardToDeck(FindCardByName("Grizzly Bears"), 0, 1)
+		out, ok := (&DungeonState{}).answerScrollWithRand(scrollTile(), 1, p, true, rand.New(rand.NewSource(seed)))
+		if !ok || !out.Correct {
+			t.Fatalf("seed %d: right answer = %+v, %v", seed, out, ok)
+		}
+		switch {
+		case out.Clue:
+			kinds["clue"] = true
+			if out.Effect != nil || p.BonusDuelLife != 0 {
+				t.Errorf("seed %d: clue reward also applied %+v", seed, out.Effect)
+			}
+		case out.Effect != nil && out.Effect.Card != nil:
+			kinds["card"] = true
+			if len(p.BonusDuelCards) != 1 {
+				t.Errorf("seed %d: card reward not queued: %v", seed, p.BonusDuelCards)
+			}
+		case out.Effect != nil && out.Effect.LifeMod > 0:
+			kinds["life"] = true
+			if p.BonusDuelLife != out.Effect.LifeMod || p.BonusDuelLife > ScrollRewardLife {
+				t.Errorf("seed %d: BonusDuelLife = %d for %+v", seed, p.BonusDuelLife, out.Effect)
+			}
+		default:
+			t.Errorf("seed %d: right answer earned nothing: %+v", seed, out)
+		}
+	}
+	if len(kinds) != 3 {
+		t.Errorf("rewards seen = %v, want clues, cards and life", kinds)
+	}
+}
+
+func TestAnswerScrollWithoutCluesLeftNeverGivesAClue(t *testing.T) {
+	for seed := range int64(50) {
+		p := &Player{Character: Character{CardCollection: NewCardCollection()}}
+		out, _ := (&DungeonState{}).answerScrollWithRand(scrollTile(), 1, p, false, rand.New(rand.NewSource(seed)))
+		if out.Clue || out.Effect == nil {
+			t.Fatalf("seed %d: got %+v, want a life or card reward", seed, out)
+		}
+	}
+}
+
+func TestGenerateDungeonWritesScrollRiddles(t *testing.T) {
+	d := GenerateDungeon(DungeonGenOptions{Name: "Test", Seed: 3})
+	scrolls := 0
+	for _, row := range d.Grid {
+		for _, tile := range row {
+			if tile.Type != DungeonTileScroll {
+				continue
+			}
+			scrolls++
+			if tile.Scroll == nil || tile.Scroll.Question == "" || len(tile.Scroll.Choices) < 2 {
+				t.Errorf("scroll without a riddle: %+v", tile.Scroll)
+			}
+		}
+	}
+	if scrolls == 0 {
+		t.Fatal("dungeon has no scrolls")
+	}
+}
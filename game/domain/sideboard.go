@@ -0,0 +1,161 @@
+package domain
+
+import (
+	"cmp"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Every deck has a sideboard: owned cards set aside for that deck, which
+// don't count toward it but can be swapped in between games. Rogues keep
+// their TOML sideboard on deck 0 and swap in hate cards against the player's
+// colors before a duel.
+
+// GetSideboardCount returns how many copies of card are in deck deckIndex's
+// sideboard.
+func (cc CardCollection) GetSideboardCount(card *Card, deckIndex int) int {
+	item := cc[card]
+	if item == nil || deckIndex >= len(item.SideboardCounts) {
+		return 0
+	}
+	return item.SideboardCounts[deckIndex]
+}
+
+// MoveCardToSideboard puts count copies of card that are in no deck or
+// sideboard into deck deckIndex's sideboard.
+func (cc CardCollection) MoveCardToSideboard(card *Card, deckIndex int, count int) error {
+	item := cc[card]
+	if item == nil {
+		return fmt.Errorf("card %s not found in collection", card.Name())
+	}
+	if available := cc.GetAvailableCount(card); available < count {
+		return fmt.Errorf("insufficient available cards for %s (have %d available, need %d)",
+			card.Name(), available, count)
+	}
+	item.SideboardCounts = growCounts(item.SideboardCounts, deckIndex)
+	item.SideboardCounts[deckIndex] += count
+	return nil
+}
+
+// MoveCardFromSideboard takes count copies of card out of deck deckIndex's
+// sideboard, back to the collection.
+func (cc CardCollection) MoveCardFromSideboard(card *Card, deckIndex int, count int) error {
+	if have := cc.GetSideboardCount(card, deckIndex); have < count {
+		return fmt.Errorf("insufficient cards in sideboard %d for card %s (have %d, need %d)",
+			deckIndex, card.Name(), have, count)
+	}
+	cc[card].SideboardCounts[deckIndex] -= count
+	return nil
+}
+
+// SideboardIn moves count copies of card from deck deckIndex's sideboard into
+// the deck.
+func (cc CardCollection) SideboardIn(card *Card, deckIndex int, count int) error {
+	if err := cc.MoveCardFromSideboard(card, deckIndex, count); err != nil {
+		return err
+	}
+	return cc.MoveCardToDeck(card, deckIndex, count)
+}
+
+// SideboardOut moves count copies of card from deck deckIndex into its
+// sideboard.
+func (cc CardCollection) SideboardOut(card *Card, deckIndex int, count int) error {
+	if err := cc.MoveCardFromDeck(card, deckIndex, count); err != nil {
+		return err
+	}
+	return cc.MoveCardToSideboard(card, deckIndex, count)
+}
+
+// growCounts extends counts so index is in range.
+func growCounts(counts []int, index int) []int {
+	if index < len(counts) {
+		return counts
+	}
+	grown := make([]int, index+1)
+	copy(grown, counts)
+	return grown
+}
+
+var (
+	hateColorWord = regexp.MustCompile(`(?i)\b(white|blue|black|red|green)\b`)
+	hateLandType  = regexp.MustCompile(`(?i)\b(plains|islands?|swamps?|mountains?|forests?)(walk)?\b`)
+)
+
+var hateLandColors = map[string]ColorMask{
+	"plains":   ColorWhite,
+	"island":   ColorBlue,
+	"swamp":    ColorBlack,
+	"mountain": ColorRed,
+	"forest":   ColorGreen,
+}
+
+// HateColors returns the colors a card is aimed at: those its rules text
+// names, directly ("Counter target blue spell") or through their basic land
+// type ("Each Swamp deals 1 damage", "Swampwalk").
+func (c *Card) HateColors() ColorMask {
+	text := strings.ReplaceAll(c.Text, c.CardName, "")
+	var m ColorMask
+	for _, word := range hateColorWord.FindAllString(text, -1) {
+		m |= ColorNameToMask(strings.ToLower(word))
+	}
+	for _, word := range hateLandType.FindAllString(text, -1) {
+		for land, color := range hateLandColors {
+			if strings.HasPrefix(strings.ToLower(word), land) {
+				m |= color
+			}
+		}
+	}
+	return m
+}
+
+// SideboardAgainst returns deck with each sideboard card aimed at one of
+// opponentColors swapped in for one of the deck's cheapest spells. Lands and
+// the keep cards, such as an ante, are never swapped out.
+func SideboardAgainst(deck, sideboard Deck, opponentColors ColorMask, keep ...*Card) Deck {
+	var hate []*Card
+	for _, card := range sortedDeckCards(sideboard) {
+		if card.HateColors()&opponentColors != 0 {
+			for range sideboard[card] {
+				hate = append(hate, card)
+			}
+		}
+	}
+
+	result := make(Deck, len(deck))
+	var out []*Card
+	for card, n := range deck {
+		result[card] = n
+		if card.CardType == CardTypeLand {
+			continue
+		}
+		spare := n
+		for _, k := range keep {
+			if k != nil && k.CardName == card.CardName {
+				spare--
+			}
+		}
+		for range spare {
+			out = append(out, card)
+		}
+	}
+	slices.SortStableFunc(out, func(a, b *Card) int {
+		return cmp.Or(cmp.Compare(a.Price, b.Price), strings.Compare(a.CardName, b.CardName))
+	})
+
+	for i := range min(len(hate), len(out)) {
+		result[out[i]]--
+		if result[out[i]] == 0 {
+			delete(result, out[i])
+		}
+		result[hate[i]]++
+	}
+	return result
+}
+
+// DuelDeckAgainst returns the character's deck for a duel against a deck of
+// opponentColors, sideboarded with SideboardAgainst.
+func (c *Character) DuelDeckAgainst(opponentColors ColorMask, keep ...*Card) Deck {
+	return SideboardAgainst(c.GetActiveDeck(), c.CardCollection.GetSideboard(0), opponentColors, keep...)
+}
@@ -0,0 +1,148 @@
+package domain
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestSideboardCardsAreSetAsideFromTheDeck(t *testing.T) {
+	bolt := FindCardByName("Lightning Bolt")
+	cc := NewCardCollection()
+	cc.AddCard(bolt, 4)
+	if err := cc.MoveCardToDeck(bolt, 0, 2); err != nil {
+		t.Fatal(err)
+	}
+	if err := cc.MoveCardToSideboard(bolt, 0, 2); err != nil {
+		t.Fatal(err)
+	}
+	if err := cc.MoveCardToDeck(bolt, 0, 1); err == nil {
+		t.Error("MoveCardToDeck took a copy that is in the sideboard")
+	}
+	if got := cc.GetSideboard(0)[bolt]; got != 2 {
+		t.Errorf("sideboard bolts = %d, want 2", got)
+	}
+
+	if err := cc.SideboardIn(bolt, 0, 1); err != nil {
+		t.Fatal(err)
+	}
+	if cc.GetDeckCount(bolt, 0) != 3 || cc.GetSideboardCount(bolt, 0) != 1 {
+		t.Errorf("after SideboardIn: deck %d, sideboard %d; want 3, 1", cc.GetDeckCount(bolt, 0), cc.GetSideboardCount(bolt, 0))
+	}
+	if err := cc.SideboardOut(bolt, 0, 3); err != nil {
+		t.Fatal(err)
+	}
+	if cc.GetDeckCount(bolt, 0) != 0 || cc.GetSideboardCount(bolt, 0) != 4 {
+		t.Errorf("after SideboardOut: deck %d, sideboard %d; want 0, 4", cc.GetDeckCount(bolt, 0), cc.GetSideboardCount(bolt, 0))
+	}
+	if err := cc.MoveCardFromSideboard(bolt, 0, 5); err == nil {
+		t.Error("MoveCardFromSideboard took more copies than the sideboard holds")
+	}
+
+	if err := cc.RemoveCard(bolt, 3); err != nil {
+		t.Fatal(err)
+	}
+	if got := cc.GetSideboardCount(bolt, 0); got != 1 {
+		t.Errorf("sideboard after selling down to 1 copy = %d, want 1", got)
+	}
+}
+
+func TestSideboardSurvivesJSONRoundTrip(t *testing.T) {
+	bolt := FindCardByName("Lightning Bolt")
+	cc := NewCardCollection()
+	cc.AddCardToDeck(bolt, 0, 2)
+	cc.AddCardToSideboard(bolt, 1, 1)
+
+	data, err := json.Marshal(cc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got CardCollection
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.GetDeckCount(bolt, 0) != 2 || got.GetSideboardCount(bolt, 1) != 1 || got.GetTotalCount(bolt) != 3 {
+		t.Errorf("after round trip: deck %d, sideboard %d, total %d", got.GetDeckCount(bolt, 0), got.GetSideboardCount(bolt, 1), got.GetTotalCount(bolt))
+	}
+}
+
+func TestBuildSideboardFromList(t *testing.T) {
+	bolt := FindCardByName("Lightning Bolt")
+	mountain := FindCardByName("Mountain")
+	cc := NewCardCollection()
+	cc.AddCard(bolt, 3)
+	cc.AddCard(mountain, 10)
+
+	cc.BuildDeckFromList(0, Deck{bolt: 2, mountain: 10})
+	missing := cc.BuildSideboardFromList(0, Deck{bolt: 2})
+	if cc.GetSideboardCount(bolt, 0) != 1 || len(missing) != 1 || missing[0] != "1 Lightning Bolt" {
+		t.Errorf("sideboard bolts = %d, missing %v; want 1 and [1 Lightning Bolt]", cc.GetSideboardCount(bolt, 0), missing)
+	}
+}
+
+func TestHateColors(t *testing.T) {
+	tests := []struct {
+		name string
+		want ColorMask
+	}{
+		{"Red Elemental Blast", ColorBlue},
+		{"Karma", ColorBlack},
+		{"Tsunami", ColorBlue},
+		{"Circle of Protection: Red", ColorRed},
+		{"Swords to Plowshares", 0},
+		{"Terror", 0},
+	}
+	for _, tt := range tests {
+		card := FindCardByName(tt.name)
+		if card == nil {
+			t.Fatalf("no card %q", tt.name)
+		}
+		if got := card.HateColors(); got != tt.want {
+			t.Errorf("%s.HateColors() = %b, want %b", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSideboardAgainstSwapsHateForTheCheapestSpells(t *testing.T) {
+	reb := FindCardByName("Red Elemental Blast")
+	karma := FindCardByName("Karma")
+	bolt := FindCardByName("Lightning Bolt")
+	shivan := FindCardByName("Shivan Dragon")
+	mountain := FindCardByName("Mountain")
+	cheap, dear := bolt, shivan
+	if cheap.Price > dear.Price {
+		cheap, dear = dear, cheap
+	}
+	deck := Deck{mountain: 10, cheap: 2, dear: 2}
+	sideboard := Deck{reb: 2, karma: 1}
+
+	got := SideboardAgainst(deck, sideboard, ColorBlue)
+	if got[reb] != 2 || got[karma] != 0 || got[cheap] != 0 || got[dear] != 2 || got[mountain] != 10 {
+		t.Errorf("against blue = %v", got)
+	}
+	if deck[cheap] != 2 {
+		t.Error("SideboardAgainst changed the deck it was given")
+	}
+
+	got = SideboardAgainst(deck, sideboard, ColorBlue, cheap)
+	if got[cheap] != 1 || got[dear] != 1 {
+		t.Errorf("against blue keeping %s = %v", cheap.Name(), got)
+	}
+
+	if got := SideboardAgainst(deck, sideboard, ColorGreen); len(got) != len(deck) || got[cheap] != 2 {
+		t.Errorf("against green = %v, want the deck unchanged", got)
+	}
+}
+
+func TestRoguesKeepTheirSideboards(t *testing.T) {
+	for name, rogue := range Rogues {
+		want := 0
+		for _, entry := range rogue.SideboardRaw {
+			if len(entry) == 2 && entry[0] != "0" {
+				want++
+			}
+		}
+		if got := len(rogue.CardCollection.GetSideboard(0)); want > 0 && got == 0 {
+			t.Errorf("%s has an empty sideboard, want %d cards", name, want)
+		}
+	}
+}
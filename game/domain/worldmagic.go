@@ -1,5 +1,24 @@
 package domain
 
+import "math/rand"
+
+// World magic names. Effects are looked up by name because saved games
+// deserialize each WorldMagic into a fresh pointer.
+const (
+	WorldMagicSwordOfResistance   = "Sword of Resistance"
+	WorldMagicQuickening          = "Quickening"
+	WorldMagicLeapOfFate          = "Leap of Fate"
+	WorldMagicRingOfTheGuardian   = "Ring of the Guardian"
+	WorldMagicHagglersCoin        = "Haggler's Coin"
+	WorldMagicTomeOfEnlightenment = "Tome of Enlightenment"
+	WorldMagicSleightOfHand       = "Sleight of Hand"
+	WorldMagicStaffOfThunder      = "Staff of Thunder"
+	WorldMagicConjurersWill       = "Conjurer's Will"
+	WorldMagicDwarvenPick         = "Dwarven Pick"
+	WorldMagicAmuletOfSwampwalk   = "Amulet of Swampwalk"
+	WorldMagicFruitOfSustenance   = "Fruit of Sustenance"
+)
+
 type WorldMagic struct {
 	Name        string
 	Cost        int
@@ -8,63 +27,177 @@ type WorldMagic struct {
 
 var AllWorldMagics = []*WorldMagic{
 	{
-		Name:        "Sword of Resistance",
+		Name:        WorldMagicSwordOfResistance,
 		Cost:        400,
-		Description: "Grants magical resistance against enemy attacks",
+		Description: "You begin every duel with 3 extra life",
 	},
 	{
-		Name:        "Quickening",
+		Name:        WorldMagicQuickening,
 		Cost:        300,
-		Description: "Increases movement speed and reaction time",
+		Description: "You travel a quarter faster across the land",
 	},
 	{
-		Name:        "Leap of Fate",
+		Name:        WorldMagicLeapOfFate,
 		Cost:        300,
-		Description: "Allows teleportation to distant locations",
+		Description: "Teleport from any town to a town you have visited",
 	},
 	{
-		Name:        "Ring of the Guardian",
+		Name:        WorldMagicRingOfTheGuardian,
 		Cost:        500,
-		Description: "Provides powerful protective barriers",
+		Description: "You keep your ante card when you lose a duel",
 	},
 	{
-		Name:        "Haggler's Coin",
+		Name:        WorldMagicHagglersCoin,
 		Cost:        250,
-		Description: "Reduces costs of all purchases",
+		Description: "Everything you buy in towns costs a fifth less",
 	},
 	{
-		Name:        "Tome of Enlightenment",
+		Name:        WorldMagicTomeOfEnlightenment,
 		Cost:        300,
-		Description: "Enhances learning and spell effectiveness",
+		Description: "Each duel you win teaches you a spell from your opponent's deck",
 	},
 	{
-		Name:        "Sleight of Hand",
+		Name:        WorldMagicSleightOfHand,
 		Cost:        300,
-		Description: "Improves dexterity and stealth abilities",
+		Description: "Your first mulligan in each duel is free",
 	},
 	{
-		Name:        "Staff of Thunder",
+		Name:        WorldMagicStaffOfThunder,
 		Cost:        100,
-		Description: "Channels elemental lightning magic",
+		Description: "Your opponents begin every duel with 2 less life",
 	},
 	{
-		Name:        "Conjurer's Will",
+		Name:        WorldMagicConjurersWill,
 		Cost:        300,
-		Description: "Strengthens magical focus and willpower",
+		Description: "You draw an extra card in your opening hand",
 	},
 	{
-		Name:        "Dwarven Pick",
+		Name:        WorldMagicDwarvenPick,
 		Cost:        125,
-		Description: "Enhances mining and resource gathering",
+		Description: "You cross mountains without slowing down",
 	},
 	{
-		Name:        "Amulet of Swampwalk",
+		Name:        WorldMagicAmuletOfSwampwalk,
 		Cost:        125,
-		Description: "Allows safe passage through marshlands",
+		Description: "You cross marshes without slowing down",
 	},
 	{
-		Name:        "Fruit of Sustenance",
+		Name:        WorldMagicFruitOfSustenance,
 		Cost:        50,
-		Description: "Provides eternal nourishment and vitality",
+		Description: "Towns feed you for free",
 	},
 }
+
+const (
+	// QuickeningSpeedFactor scales overworld movement for Quickening.
+	QuickeningSpeedFactor = 1.25
+	// SwordOfResistanceLife is the extra starting life from Sword of Resistance.
+	SwordOfResistanceLife = 3
+	// StaffOfThunderDamage is the life opponents lose to Staff of Thunder before
+	// a duel starts.
+	StaffOfThunderDamage = 2
+	// HagglersCoinDiscount is the percentage taken off town prices.
+	HagglersCoinDiscount = 20
+	// DefaultOpeningHandSize is the number of cards drawn at the start of a duel.
+	DefaultOpeningHandSize = 7
+)
+
+// FindWorldMagic returns the world magic with the given name, or nil.
+func FindWorldMagic(name string) *WorldMagic {
+	for _, m := range AllWorldMagics {
+		if m.Name == name {
+			return m
+		}
+	}
+	return nil
+}
+
+// HasWorldMagicNamed reports whether the player owns the named world magic.
+func (p *Player) HasWorldMagicNamed(name string) bool {
+	for _, m := range p.WorldMagics {
+		if m != nil && m.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// TownPrice applies Haggler's Coin to a price asked in town.
+func (p *Player) TownPrice(price int) int {
+	if !p.HasWorldMagicNamed(WorldMagicHagglersCoin) {
+		return price
+	}
+	return price * (100 - HagglersCoinDiscount) / 100
+}
+
+// FoodPrice returns what the player pays for a city's food ration.
+func (p *Player) FoodPrice(city *City) int {
+	if p.HasWorldMagicNamed(WorldMagicFruitOfSustenance) {
+		return 0
+	}
+	return p.TownPrice(city.FoodCost())
+}
+
+// WorldMoveSpeedFactor returns the overworld movement multiplier from world
+// magics, not counting terrain.
+func (p *Player) WorldMoveSpeedFactor() float64 {
+	if p.HasWorldMagicNamed(WorldMagicQuickening) {
+		return QuickeningSpeedFactor
+	}
+	return 1
+}
+
+// DuelStartingLife returns the player's life at the start of the next duel.
+func (p *Player) DuelStartingLife() int {
+	life := p.Life + p.BonusDuelLife
+	if p.HasWorldMagicNamed(WorldMagicSwordOfResistance) {
+		life += SwordOfResistanceLife
+	}
+	return life
+}
+
+// OpponentStartingLife applies Staff of Thunder to an opponent's starting
+// life. Opponents always start with at least 1 life.
+func (p *Player) OpponentStartingLife(life int) int {
+	if p.HasWorldMagicNamed(WorldMagicStaffOfThunder) {
+		life -= StaffOfThunderDamage
+	}
+	return max(1, life)
+}
+
+// OpeningHandSize returns how many cards the player draws for an opening hand.
+func (p *Player) OpeningHandSize() int {
+	if p.HasWorldMagicNamed(WorldMagicConjurersWill) {
+		return DefaultOpeningHandSize + 1
+	}
+	return DefaultOpeningHandSize
+}
+
+// MulliganPenalty returns how many cards go to the bottom of the library after
+// mulligans times mulligans.
+func (p *Player) MulliganPenalty(mulligans int) int {
+	if p.HasWorldMagicNamed(WorldMagicSleightOfHand) {
+		mulligans--
+	}
+	return max(0, mulligans)
+}
+
+// KeepsAnteOnLoss reports whether losing a duel leaves the ante card with the
+// player.
+func (p *Player) KeepsAnteOnLoss() bool {
+	return p.HasWorldMagicNamed(WorldMagicRingOfTheGuardian)
+}
+
+// LearnedCard picks the spell Tome of Enlightenment teaches the player from an
+// opponent's deck after a win. It returns nil without the Tome or when the
+// deck has no spells.
+func (p *Player) LearnedCard(opponentDeck Deck) *Card {
+	if !p.HasWorldMagicNamed(WorldMagicTomeOfEnlightenment) {
+		return nil
+	}
+	spells := opponentDeck.NonLandCards()
+	if len(spells) == 0 {
+		return nil
+	}
+	return spells[rand.Intn(len(spells))]
+}
@@ -1,6 +1,7 @@
 package game
 
 import (
+	"bytes"
 	"fmt"
 	"math"
 	"time"
@@ -9,8 +10,10 @@ import (
 	"github.com/benprew/s30/game/bugreport"
 	"github.com/benprew/s30/game/domain"
 	"github.com/benprew/s30/game/minimap"
+	"github.com/benprew/s30/game/replay"
 	"github.com/benprew/s30/game/save"
 	"github.com/benprew/s30/game/screens"
+	"github.com/benprew/s30/game/settings"
 	"github.com/benprew/s30/game/ui"
 	"github.com/benprew/s30/game/ui/screenui"
 	"github.com/benprew/s30/game/world"
@@ -21,6 +24,8 @@ import (
 	"runtime/debug"
 )
 
+var logger = logging.Logger(logging.Game)
+
 type Game struct {
 	ScreenW, ScreenH     int
 	camScale             float64
@@ -34,6 +39,7 @@ type Game struct {
 	Difficulty           domain.Difficulty
 	audio                *gameaudio.AudioManager
 	options              Options
+	settings             settings.Settings
 }
 
 type lifecycleScreen interface {
@@ -44,6 +50,23 @@ type lifecycleScreen interface {
 type Options struct {
 	Debug            bool
 	ShowOpponentHand bool
+	// Verbose sets log levels like the -v flag, over the settings file's.
+	Verbose string
+	// RecordDuels saves a replay of every finished duel.
+	RecordDuels bool
+	// ReplayFile, if set, opens straight into playback of that duel replay.
+	ReplayFile string
+	// ReportFile, if set, restores the game a bug report was filed from.
+	ReportFile string
+	// MapFile, if set, lays out new games' worlds from this map file instead
+	// of generating them or using the map picked on the start screen.
+	MapFile string
+	// CardImageMirror, if set, is an http(s) URL or directory card images
+	// are fetched from instead of Scryfall.
+	CardImageMirror string
+	// CardImageCacheBytes caps the disk cache of card images. Zero uses
+	// domain.DefaultCardImageCacheBytes and a negative value turns it off.
+	CardImageCacheBytes int64
 }
 
 func (g *Game) CurrentScreen() screenui.Screen {
@@ -92,12 +115,14 @@ func NewGame() (*Game, error) {
 // NewGameWithOptions creates a game with the requested runtime options.
 func NewGameWithOptions(options Options) (*Game, error) {
 	applyRuntimeOptions(options)
+	setupCardImageCache(options)
+	go sendQueuedReports()
 	loadedCardImages, err := domain.LoadEmbeddedCardImages()
 	if err != nil {
 		return nil, fmt.Errorf("failed to load embedded card images: %w", err)
 	}
 	if loadedCardImages > 0 {
-		fmt.Printf("Loaded %d embedded card images\n", loadedCardImages)
+		logger.Info("loaded embedded card images", "count", loadedCardImages)
 	}
 
 	scale := 1.0
@@ -121,14 +146,77 @@ func NewGameWithOptions(options Options) (*Game, error) {
 		Difficulty: domain.DifficultyEasy,
 		audio:      am,
 		options:    options,
+		settings:   loadSettings(),
+	}
+	g.screenMap[screenui.SettingsScr] = screens.NewSettingsScreen(g.Settings, g.changeSettings)
+	g.applySettings()
+	if options.ReplayFile != "" {
+		if err := g.openReplay(options.ReplayFile); err != nil {
+			return nil, err
+		}
+	} else if options.ReportFile != "" {
+		name, err := g.openReport(options.ReportFile)
+		if err != nil {
+			return nil, err
+		}
+		g.currentScreen = name
+		g.prevScreen = screenui.WorldScr
+		g.updateBGM(name)
+	} else {
+		am.PlayBGM(gameaudio.BGMTitle)
 	}
-	am.PlayBGM(gameaudio.BGMTitle)
 
-	ebiten.SetWindowSize(g.ScreenW, g.ScreenH)
 	ebiten.SetWindowClosingHandled(true)
 	return g, nil
 }
 
+// openReplay starts the game on playback of a saved duel replay.
+func (g *Game) openReplay(path string) error {
+	data, err := save.ReadReplayFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read replay: %w", err)
+	}
+	rec, err := replay.Decode(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to load replay %s: %w", path, err)
+	}
+	scr, err := screens.NewReplayScreen(rec)
+	if err != nil {
+		return fmt.Errorf("failed to start replay: %w", err)
+	}
+	g.screenMap[screenui.DuelScr] = scr
+	g.currentScreen = screenui.DuelScr
+	return nil
+}
+
+// openReport restores the game a bug report was filed from: its world and, if
+// it was filed during a duel, that duel. It returns the screen to show.
+func (g *Game) openReport(path string) (screenui.ScreenName, error) {
+	report, err := bugreport.ReadReport(path)
+	if err != nil {
+		return screenui.StartScr, err
+	}
+	if report.WorldState == nil || report.WorldState.World == nil {
+		return screenui.StartScr, fmt.Errorf("report %s has no world to restore", path)
+	}
+	level := report.WorldState.World
+	if err := level.RebuildSprites(); err != nil {
+		return screenui.StartScr, fmt.Errorf("failed to rebuild sprites: %w", err)
+	}
+	if err := g.initWorld(level); err != nil {
+		return screenui.StartScr, err
+	}
+	if report.DuelState == nil {
+		return screenui.WorldScr, nil
+	}
+	scr, err := screens.NewReportDuelScreen(level.Player, level, report.DuelState)
+	if err != nil {
+		return screenui.StartScr, fmt.Errorf("failed to restore the reported duel: %w", err)
+	}
+	g.screenMap[screenui.DuelScr] = scr
+	return screenui.DuelScr, nil
+}
+
 func (g *Game) initWorld(level *world.Level) error {
 	if err := applyDebugOptions(level, g.options); err != nil {
 		return fmt.Errorf("failed to apply debug options: %w", err)
@@ -145,6 +233,7 @@ func (g *Game) initWorld(level *world.Level) error {
 	g.screenMap[screenui.WorldScr] = screens.NewLevelScreen(level)
 	g.screenMap[screenui.MiniMapScr] = m
 	g.screenMap[screenui.QuestScrollScr] = screens.NewQuestScrollScreen(level.Player)
+	g.screenMap[screenui.ClueJournalScr] = screens.NewClueJournalScreen(level)
 	g.screenMap[screenui.DuelAnteScr] = screens.NewDuelAnteScreen()
 
 	go domain.PreloadCardImages(domain.CollectPriorityCards(level.Player))
@@ -153,35 +242,59 @@ func (g *Game) initWorld(level *world.Level) error {
 	return nil
 }
 
-func (g *Game) handleStartTransition() error {
+// handleStartTransition builds the world the start screen asked for and
+// returns the screen to enter it on.
+func (g *Game) handleStartTransition() (screenui.ScreenName, error) {
 	startScr := g.screenMap[screenui.StartScr].(*screens.StartScreen)
+	if startScr.SelectedReport != "" {
+		return g.openReport(startScr.SelectedReport)
+	}
 	if startScr.SelectedSave != "" {
 		level, err := save.LoadGame(startScr.SelectedSave)
 		if err != nil {
-			return fmt.Errorf("failed to load save: %w", err)
+			return screenui.StartScr, fmt.Errorf("failed to load save: %w", err)
 		}
 		if err := level.RebuildSprites(); err != nil {
-			return fmt.Errorf("failed to rebuild sprites: %w", err)
+			return screenui.StartScr, fmt.Errorf("failed to rebuild sprites: %w", err)
 		}
-		return g.initWorld(level)
+		return screenui.WorldScr, g.initWorld(level)
 	}
 
 	startTime := time.Now()
 	g.Difficulty = startScr.SelectedDifficulty
 	player, err := domain.NewPlayer("Player", nil, false, g.Difficulty, startScr.SelectedColor)
 	if err != nil {
-		return fmt.Errorf("failed to load player sprite: %s", err)
+		return screenui.StartScr, fmt.Errorf("failed to load player sprite: %s", err)
 	}
-	level, err := world.NewLevel(player)
+	level, err := g.newWorld(player, startScr.SelectedMap, startScr.SelectedSeed)
 	if err != nil {
-		return fmt.Errorf("failed to create new level: %s", err)
+		return screenui.StartScr, fmt.Errorf("failed to create new level: %s", err)
 	}
 	level.SetIdentity(world.NewGameID(), startScr.SelectedDifficulty, startScr.SelectedColor)
 	if err := g.initWorld(level); err != nil {
-		return err
+		return screenui.StartScr, err
 	}
-	fmt.Printf("New game creation time: %s\n", time.Since(startTime))
-	return nil
+	logger.Info("created new game", "took", time.Since(startTime))
+	return screenui.WorldScr, nil
+}
+
+// newWorld builds a new game's world from the -map file, else from the
+// bundled map picked on the start screen, else generated from seed.
+func (g *Game) newWorld(player *domain.Player, mapName string, seed int64) (*world.Level, error) {
+	var m *world.MapFile
+	var err error
+	switch {
+	case g.options.MapFile != "":
+		m, err = world.LoadMapFile(g.options.MapFile)
+	case mapName == world.ShandalarMapName:
+		m, err = world.ShandalarMap()
+	default:
+		return world.NewLevelWithSeed(player, seed)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return world.NewLevelFromMap(player, m, seed)
 }
 
 func (g *Game) Update() error {
@@ -199,7 +312,7 @@ func (g *Game) Update() error {
 	if ebiten.IsWindowBeingClosed() {
 		if g.player != nil {
 			if err := g.SaveGame(); err != nil {
-				fmt.Printf("Error auto-saving: %v\n", err)
+				logger.Error("failed to auto-save", "err", err)
 			}
 		}
 		return ebiten.Termination
@@ -221,14 +334,16 @@ func (g *Game) Update() error {
 
 	if inpututil.IsKeyJustPressed(ebiten.KeyM) {
 		g.audio.ToggleMute()
+		g.settings.Muted = g.audio.Muted()
+		g.saveSettings()
 	}
 
 	if g.player != nil {
 		if inpututil.IsKeyJustPressed(ebiten.KeyF5) {
 			if err := g.SaveGame(); err != nil {
-				fmt.Printf("Error saving game: %v\n", err)
+				logger.Error("failed to save game", "err", err)
 			} else {
-				fmt.Println("Game saved!")
+				logger.Info("game saved")
 			}
 		}
 
@@ -254,9 +369,11 @@ func (g *Game) Update() error {
 
 	// Entering the world from the start screen builds the world first.
 	if prevScreen == screenui.StartScr && name == screenui.WorldScr {
-		if transitionErr := g.handleStartTransition(); transitionErr != nil {
+		entry, transitionErr := g.handleStartTransition()
+		if transitionErr != nil {
 			return transitionErr
 		}
+		name = entry
 	}
 
 	// If the screen returned a new instance, register it.
@@ -282,9 +399,8 @@ func (g *Game) Update() error {
 	if name == screenui.WorldScr {
 		lvl := g.Level()
 		if lvl.RandomEncounterPending() {
-			if _, terrainType, ok := lvl.TakeRandomEncounter(); ok {
-				landName := world.TerrainToLandName(terrainType)
-				g.screenMap[screenui.RandomEncounterScr] = screens.NewRandomEncounterScreen(g.player, landName, terrainType)
+			if re, ok := lvl.TakeRandomEncounter(); ok {
+				g.screenMap[screenui.RandomEncounterScr] = screens.NewRandomEncounterScreen(lvl, re)
 				name = screenui.RandomEncounterScr
 			}
 		}
@@ -381,7 +497,8 @@ func (g *Game) updateBGM(screen screenui.ScreenName) {
 	}
 
 	switch screen {
-	case screenui.CityScr, screenui.BuyCardsScr, screenui.EditDeckScr, screenui.WisemanScr:
+	case screenui.CityScr, screenui.BuyCardsScr, screenui.EditDeckScr, screenui.WisemanScr,
+		screenui.WorldMagicScr, screenui.LeapOfFateScr:
 		if !gameaudio.IsWorldBGM(g.audio.CurrentBGM()) {
 			g.audio.PlayBGM(g.randomCityBGM())
 		}
@@ -441,7 +558,7 @@ func (g *Game) SaveGame() error {
 	if err != nil {
 		return fmt.Errorf("failed to save game: %w", err)
 	}
-	fmt.Printf("Game saved to: %s\n", savePath)
+	logger.Info("game saved", "path", savePath)
 	return nil
 }
 
@@ -470,7 +587,9 @@ func LoadSavedGame(savePath string) (*Game, error) {
 		mousePanY:  math.MinInt32,
 		screenMap:  make(map[screenui.ScreenName]screenui.Screen),
 		audio:      am,
+		settings:   loadSettings(),
 	}
+	g.screenMap[screenui.SettingsScr] = screens.NewSettingsScreen(g.Settings, g.changeSettings)
 
 	if err := g.initWorld(level); err != nil {
 		return nil, err
@@ -478,7 +597,7 @@ func LoadSavedGame(savePath string) (*Game, error) {
 
 	g.currentScreen = screenui.WorldScr
 	g.prevScreen = screenui.WorldScr
-	ebiten.SetWindowSize(g.ScreenW, g.ScreenH)
+	g.applySettings()
 
 	return g, nil
 }
@@ -1,7 +1,6 @@
 package game
 
 import (
-	"fmt"
 	"image/color"
 	"strings"
 	"sync/atomic"
@@ -37,7 +36,7 @@ func NewLoadingGame() *LoadingGame {
 		}
 		lg.game = g
 		lg.ready.Store(true)
-		fmt.Println("Game initialization complete")
+		logger.Info("game initialization complete")
 	}()
 	return lg
 }
@@ -16,6 +16,7 @@ import (
 	"github.com/benprew/s30/game/world"
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/text/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
 )
 
 type MiniMap struct {
@@ -33,6 +34,7 @@ const (
 	doneButtonID    = "Done"
 	blinkPeriod     = timing.UpdatesPerSecond
 	blinkVisible    = 7 * timing.UpdatesPerSecond / 10
+	manaLinkRadius  = 5
 )
 
 func NewMiniMap(l *world.Level) *MiniMap {
@@ -167,7 +169,13 @@ func (m *MiniMap) Draw(screen *ebiten.Image, W, H int, scale float64) {
 				cOpts := &ebiten.DrawImageOptions{}
 				cOpts.GeoM.Concat(opts.GeoM)
 				cOpts.GeoM.Translate(0, -13)
-				screen.DrawImage(city, cOpts)
+				if col.City.IsConquered() {
+					// A conquered city flies its conqueror's castle.
+					cOpts.ColorScale.ScaleWithColor(colorTint(col.City.ConqueredBy))
+					screen.DrawImage(castle, cOpts)
+				} else {
+					screen.DrawImage(city, cOpts)
+				}
 			}
 			if col.IsCastle && col.Castle != nil {
 				cOpts := &ebiten.DrawImageOptions{}
@@ -188,6 +196,8 @@ func (m *MiniMap) Draw(screen *ebiten.Image, W, H int, scale float64) {
 		}
 	}
 
+	m.drawManaLinks(screen, width, height)
+
 	// draw minimap image overlays (castles & cities)
 	for i, row := range m.level.Tiles {
 		offset := 0
@@ -199,7 +209,7 @@ func (m *MiniMap) Draw(screen *ebiten.Image, W, H int, scale float64) {
 		opts.GeoM.Concat(options.GeoM)
 		opts.GeoM.Translate(50, 100)
 		opts.GeoM.Translate(float64(offset), float64(height*i)/2)
-		for _, col := range row {
+		for j, col := range row {
 			opts.GeoM.Translate(float64(width), 0)
 
 			if col.IsCity() && col.City.Name != "" {
@@ -208,7 +218,13 @@ func (m *MiniMap) Draw(screen *ebiten.Image, W, H int, scale float64) {
 				cityText.LineSpacing = float64(m.fontFace.Size)
 				textWidth, _ := cityText.Measure()
 				cityText.X = -int(textWidth / 2)
-				if m.isQuestTarget(col.City.Name) && m.blinkCounter%blinkPeriod < blinkVisible {
+				blinkOn := m.blinkCounter%blinkPeriod < blinkVisible
+				switch {
+				case col.City.IsConquered():
+					cityText.Color = colorTint(col.City.ConqueredBy)
+				case m.level.IsBesieged(image.Point{X: j, Y: i}) && blinkOn:
+					cityText.Color = color.RGBA{R: 230, G: 60, B: 50, A: 255}
+				case m.isQuestTarget(col.City.Name) && blinkOn:
 					cityText.Color = color.RGBA{R: 255, G: 215, B: 0, A: 255}
 				}
 				cityText.Draw(screen, opts, 1.0)
@@ -225,6 +241,82 @@ func (m *MiniMap) Draw(screen *ebiten.Image, W, H int, scale float64) {
 	}
 }
 
+// colorTint is the color the map marks a conquered city or a mana link of
+// color c with.
+func colorTint(c domain.ColorMask) color.RGBA {
+	switch c {
+	case domain.ColorWhite:
+		return color.RGBA{R: 250, G: 240, B: 190, A: 255}
+	case domain.ColorBlue:
+		return color.RGBA{R: 110, G: 150, B: 255, A: 255}
+	case domain.ColorBlack:
+		return color.RGBA{R: 150, G: 110, B: 170, A: 255}
+	case domain.ColorRed:
+		return color.RGBA{R: 255, G: 100, B: 80, A: 255}
+	case domain.ColorGreen:
+		return color.RGBA{R: 100, G: 220, B: 110, A: 255}
+	default:
+		return color.RGBA{R: 160, G: 160, B: 160, A: 255}
+	}
+}
+
+// drawManaLinks draws the player's mana link network: a line from each linked
+// city to its nearest linked neighbor and a dot of the city's color on each.
+func (m *MiniMap) drawManaLinks(screen *ebiten.Image, width, height int) {
+	links := m.level.Player.ManaLinks
+	lineColor := color.RGBA{R: 200, G: 230, B: 255, A: 200}
+	for _, edge := range manaLinkNetwork(links) {
+		x0, y0 := miniMapTileCenter(edge[0], width, height)
+		x1, y1 := miniMapTileCenter(edge[1], width, height)
+		vector.StrokeLine(screen, x0, y0, x1, y1, 2, lineColor, true)
+	}
+	for _, l := range links {
+		x, y := miniMapTileCenter(l.City, width, height)
+		vector.FillCircle(screen, x, y, manaLinkRadius, colorTint(l.Color), true)
+		vector.StrokeCircle(screen, x, y, manaLinkRadius, 1, color.Black, true)
+	}
+}
+
+// miniMapTileCenter returns where the middle of tile p is drawn on the map.
+// Odd rows are shifted half a tile right, as the level is laid out.
+func miniMapTileCenter(p image.Point, width, height int) (float32, float32) {
+	x := 50 + width*(p.X+1) + width/2
+	if p.Y%2 == 1 {
+		x += width / 2
+	}
+	y := 100 + float32(height*p.Y)/2 + float32(height)/2
+	return float32(x), y
+}
+
+// manaLinkNetwork joins each linked city to its nearest linked neighbor,
+// listing every pair of tiles once.
+func manaLinkNetwork(links []domain.ManaLink) [][2]image.Point {
+	var edges [][2]image.Point
+	seen := make(map[[2]image.Point]bool)
+	for i, a := range links {
+		nearest, best := -1, 0
+		for j, b := range links {
+			d := a.City.Sub(b.City)
+			dist := d.X*d.X + d.Y*d.Y
+			if j != i && (nearest < 0 || dist < best) {
+				nearest, best = j, dist
+			}
+		}
+		if nearest < 0 {
+			continue
+		}
+		edge := [2]image.Point{a.City, links[nearest].City}
+		if nearest < i {
+			edge = [2]image.Point{links[nearest].City, a.City}
+		}
+		if !seen[edge] {
+			seen[edge] = true
+			edges = append(edges, edge)
+		}
+	}
+	return edges
+}
+
 func (m *MiniMap) isQuestTarget(cityName string) bool {
 	for _, q := range m.level.Player.ActiveQuests {
 		if q.Type == domain.QuestTypeDelivery && q.TargetCity != nil && q.TargetCity.Name == cityName {
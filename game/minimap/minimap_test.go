@@ -4,6 +4,7 @@ import (
 	"image"
 	"testing"
 
+	"github.com/benprew/s30/game/domain"
 	"github.com/benprew/s30/game/ui/elements"
 )
 
@@ -43,3 +44,28 @@ func TestMiniMapTextIsPreScaledAndCentered(t *testing.T) {
 		}
 	}
 }
+
+func TestManaLinkNetworkJoinsNearestNeighbors(t *testing.T) {
+	links := []domain.ManaLink{
+		{City: image.Pt(0, 0)},
+		{City: image.Pt(2, 0)},
+		{City: image.Pt(20, 20)},
+		{City: image.Pt(21, 22)},
+	}
+	got := manaLinkNetwork(links)
+	want := [][2]image.Point{
+		{image.Pt(0, 0), image.Pt(2, 0)},
+		{image.Pt(20, 20), image.Pt(21, 22)},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("network = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("edge %d = %v, want %v", i, got[i], want[i])
+		}
+	}
+	if edges := manaLinkNetwork(links[:1]); len(edges) != 0 {
+		t.Errorf("a single link has edges %v", edges)
+	}
+}
@@ -0,0 +1,155 @@
+package replay
+
+import (
+	"fmt"
+	"sync"
+
+	mage "github.com/benprew/mage-go/pkg/mage"
+	"github.com/benprew/mage-go/pkg/mage/interactive"
+	"github.com/benprew/mage-go/pkg/mage/interactive/ai"
+	"github.com/google/uuid"
+)
+
+// Playback hands a recording's events back out, one seat at a time. The game
+// loop decides whose turn it is to act, so each seat only needs its own
+// events in order. Its methods are safe to call from the UI and game loop
+// goroutines.
+type Playback struct {
+	rec *Recording
+
+	mu        sync.Mutex
+	next      [2]int
+	played    int
+	diverged  string
+	seatIndex [2][]int
+}
+
+// NewPlayback prepares rec for playback from the start.
+func NewPlayback(rec *Recording) *Playback {
+	p := &Playback{rec: rec}
+	for i, e := range rec.Events {
+		if e.Seat == HumanSeat || e.Seat == AISeat {
+			p.seatIndex[e.Seat] = append(p.seatIndex[e.Seat], i)
+		}
+	}
+	return p
+}
+
+// Recording returns the recording being played.
+func (p *Playback) Recording() *Recording { return p.rec }
+
+// Peek returns seat's next event without consuming it.
+func (p *Playback) Peek(seat int) (Event, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.peek(seat)
+}
+
+func (p *Playback) peek(seat int) (Event, bool) {
+	if p.next[seat] >= len(p.seatIndex[seat]) {
+		return Event{}, false
+	}
+	return p.rec.Events[p.seatIndex[seat][p.next[seat]]], true
+}
+
+// Advance consumes seat's next event.
+func (p *Playback) Advance(seat int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.next[seat] < len(p.seatIndex[seat]) {
+		p.next[seat]++
+		p.played++
+	}
+}
+
+// Played returns how many events have been consumed across both seats.
+func (p *Playback) Played() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.played
+}
+
+// Diverge notes that the game stopped matching the recording. Only the first
+// divergence is kept, since everything after it follows from it.
+func (p *Playback) Diverge(format string, args ...any) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.diverged == "" {
+		p.diverged = fmt.Sprintf(format, args...)
+	}
+}
+
+// Divergence describes where the game stopped matching the recording, or
+// returns "" if it hasn't.
+func (p *Playback) Divergence() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.diverged
+}
+
+// CheckResult compares how the replayed game ended with the recording.
+func (p *Playback) CheckResult(winner string, turns int) {
+	want := p.rec.Result
+	if want == nil {
+		return
+	}
+	if want.Winner != winner || want.Turns != turns {
+		p.Diverge("recorded game ended on turn %d won by %q, replay ended on turn %d won by %q",
+			want.Turns, want.Winner, turns, winner)
+	}
+}
+
+// Strategy returns a strategy that plays the AI seat's recorded decisions.
+// fallback answers anything the recording doesn't cover, such as decisions
+// after the recording ran out.
+func (p *Playback) Strategy(fallback ai.AIStrategy) ai.AIStrategy {
+	return &playbackStrategy{AIStrategy: fallback, p: p}
+}
+
+type playbackStrategy struct {
+	ai.AIStrategy
+	p *Playback
+}
+
+// take consumes the AI seat's next event if it is of kind, noting a
+// divergence otherwise.
+func (s *playbackStrategy) take(kind EventKind, g *mage.Game) (Event, bool) {
+	s.p.mu.Lock()
+	defer s.p.mu.Unlock()
+	e, ok := s.p.peek(AISeat)
+	if !ok {
+		if s.p.diverged == "" {
+			s.p.diverged = fmt.Sprintf("opponent asked for %s on turn %d after the recording ended", kind, g.CurrentTurn())
+		}
+		return Event{}, false
+	}
+	if e.Kind != kind || e.Turn != g.CurrentTurn() {
+		if s.p.diverged == "" {
+			s.p.diverged = fmt.Sprintf("opponent asked for %s on turn %d, recording has %s on turn %d",
+				kind, g.CurrentTurn(), e.Kind, e.Turn)
+		}
+		if e.Kind != kind {
+			return Event{}, false
+		}
+	}
+	s.p.next[AISeat]++
+	s.p.played++
+	return e, true
+}
+
+func (s *playbackStrategy) PriorityAction(p mage.Player, g *mage.Game, landsPlayed int, mainPhase bool) interactive.PriorityAction {
+	if e, ok := s.take(KindAction, g); ok && e.Action != nil {
+		return *e.Action
+	}
+	return interactive.PriorityAction{Type: interactive.ActionPass}
+}
+
+func (s *playbackStrategy) Attackers(p mage.Player, g *mage.Game) []uuid.UUID {
+	e, _ := s.take(KindAttackers, g)
+	return e.Attackers
+}
+
+func (s *playbackStrategy) Blockers(p mage.Player, g *mage.Game) []mage.BlockAssignment {
+	e, _ := s.take(KindBlockers, g)
+	return e.Blockers
+}
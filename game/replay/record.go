@@ -0,0 +1,194 @@
+package replay
+
+import (
+	crand "crypto/rand"
+	"math/rand"
+	"slices"
+	"sync"
+	"time"
+
+	mage "github.com/benprew/mage-go/pkg/mage"
+	"github.com/benprew/mage-go/pkg/mage/interactive"
+	"github.com/benprew/mage-go/pkg/mage/interactive/ai"
+	"github.com/google/uuid"
+)
+
+// IDSource makes the IDs mage-go gives players, cards and permanents
+// reproducible from a seed. The uuid package draws from one process-wide
+// reader, so one IDSource is installed at a time; installing another takes
+// over from it.
+type IDSource struct {
+	rng *rand.Rand
+	// isolated counts the Isolate calls running, and is guarded by ids.mu.
+	isolated int
+}
+
+// idReader is the reader the uuid package draws from. It hands out the
+// installed IDSource's numbers, or random ones when none is installed or it
+// is isolated. The lock lets the UI and game loop goroutines make IDs at
+// once, since a rand.Rand isn't safe for concurrent use.
+type idReader struct {
+	mu        sync.Mutex
+	installed *IDSource
+}
+
+var ids = &idReader{}
+
+func (r *idReader) Read(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if s := r.installed; s != nil && s.isolated == 0 {
+		return s.rng.Read(p)
+	}
+	return crand.Read(p)
+}
+
+// NewIDSource returns an ID source seeded with seed.
+func NewIDSource(seed int64) *IDSource {
+	return &IDSource{rng: rand.New(rand.NewSource(seed))}
+}
+
+// Install makes new uuids come from this source.
+func (s *IDSource) Install() {
+	ids.mu.Lock()
+	ids.installed = s
+	ids.mu.Unlock()
+	uuid.SetRand(ids)
+}
+
+// Uninstall puts back random uuids, unless another source has been installed
+// since s was, so a finished duel can't take the IDs from under the next
+// one. It does nothing on a nil IDSource.
+func (s *IDSource) Uninstall() {
+	if s == nil {
+		return
+	}
+	ids.mu.Lock()
+	defer ids.mu.Unlock()
+	if ids.installed == s {
+		ids.installed = nil
+	}
+}
+
+// Isolate runs fn with random uuids, so IDs fn creates don't use up the
+// seeded ones. The AI's look-ahead creates IDs that playback never does;
+// isolating it keeps the engine's IDs identical in both.
+func (s *IDSource) Isolate(fn func()) {
+	if s == nil {
+		fn()
+		return
+	}
+	ids.mu.Lock()
+	s.isolated++
+	ids.mu.Unlock()
+	defer func() {
+		ids.mu.Lock()
+		s.isolated--
+		ids.mu.Unlock()
+	}()
+	fn()
+}
+
+// Recorder collects a duel's recording as it is played. Its methods are safe
+// to call from the UI and game loop goroutines, and on a nil Recorder, which
+// records nothing.
+type Recorder struct {
+	mu  sync.Mutex
+	rec Recording
+}
+
+// NewRecorder starts a recording of a duel whose IDs come from seed.
+func NewRecorder(seed int64) *Recorder {
+	return &Recorder{rec: Recording{Version: Version, Seed: seed, RecordedAt: time.Now()}}
+}
+
+// SetSeat records the setup of one seat.
+func (r *Recorder) SetSeat(i int, seat Seat) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.rec.Seats[i] = seat
+}
+
+// Deal records the hand and library p starts the game with. See Seat.Deal.
+func (r *Recorder) Deal(i int, p mage.Player) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.rec.Seats[i].Deal(p)
+}
+
+// Add appends an event.
+func (r *Recorder) Add(e Event) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.rec.Events = append(r.rec.Events, e)
+}
+
+// Finish records the duel's result. Events added afterwards are ignored.
+func (r *Recorder) Finish(winner string, turns int) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.rec.Result == nil {
+		r.rec.Result = &Result{Winner: winner, Turns: turns}
+	}
+}
+
+// Recording returns a copy of everything recorded so far.
+func (r *Recorder) Recording() *Recording {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	rec := r.rec
+	rec.Events = slices.Clone(r.rec.Events)
+	if r.rec.Result != nil {
+		result := *r.rec.Result
+		rec.Result = &result
+	}
+	return &rec
+}
+
+// Strategy wraps the AI seat's strategy so its decisions are recorded. The
+// wrapped strategy runs with ids isolated.
+func (r *Recorder) Strategy(inner ai.AIStrategy, ids *IDSource) ai.AIStrategy {
+	return &recordingStrategy{AIStrategy: inner, rec: r, ids: ids}
+}
+
+type recordingStrategy struct {
+	ai.AIStrategy
+	rec *Recorder
+	ids *IDSource
+}
+
+func (s *recordingStrategy) PriorityAction(p mage.Player, g *mage.Game, landsPlayed int, mainPhase bool) interactive.PriorityAction {
+	var action interactive.PriorityAction
+	s.ids.Isolate(func() { action = s.AIStrategy.PriorityAction(p, g, landsPlayed, mainPhase) })
+	s.rec.Add(Event{Seat: AISeat, Kind: KindAction, Turn: g.CurrentTurn(), Action: &action})
+	return action
+}
+
+func (s *recordingStrategy) Attackers(p mage.Player, g *mage.Game) []uuid.UUID {
+	var attackers []uuid.UUID
+	s.ids.Isolate(func() { attackers = s.AIStrategy.Attackers(p, g) })
+	s.rec.Add(Event{Seat: AISeat, Kind: KindAttackers, Turn: g.CurrentTurn(), Attackers: slices.Clone(attackers)})
+	return attackers
+}
+
+func (s *recordingStrategy) Blockers(p mage.Player, g *mage.Game) []mage.BlockAssignment {
+	var blockers []mage.BlockAssignment
+	s.ids.Isolate(func() { blockers = s.AIStrategy.Blockers(p, g) })
+	s.rec.Add(Event{Seat: AISeat, Kind: KindBlockers, Turn: g.CurrentTurn(), Blockers: slices.Clone(blockers)})
+	return blockers
+}
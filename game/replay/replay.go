@@ -0,0 +1,127 @@
+// Package replay records duels and plays them back through mage-go.
+//
+// A recording holds the seed the duel's card IDs were drawn from, the cards
+// each seat brought, how their hands and libraries were arranged when the
+// first turn began, and every decision either seat made after that. Feeding
+// those decisions back into a fresh game reproduces the duel as long as the
+// rules engine behaves the same way, which is what makes a recording useful
+// alongside a rules-engine bug report.
+package replay
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	mage "github.com/benprew/mage-go/pkg/mage"
+	"github.com/benprew/mage-go/pkg/mage/interactive"
+	"github.com/google/uuid"
+)
+
+// Version is the recording format version written by Encode.
+const Version = 1
+
+// FileExt is the extension recordings are saved with.
+const FileExt = ".s30replay"
+
+// Seat indexes. The human is always seat 0 and the AI seat 1.
+const (
+	HumanSeat = 0
+	AISeat    = 1
+)
+
+// Recording is a complete, replayable duel.
+type Recording struct {
+	Version    int       `json:"version"`
+	Seed       int64     `json:"seed"`
+	RecordedAt time.Time `json:"recorded_at"`
+	Seats      [2]Seat   `json:"seats"`
+	Events     []Event   `json:"events"`
+	Result     *Result   `json:"result,omitempty"`
+}
+
+// Result is how a recorded duel ended.
+type Result struct {
+	Winner string `json:"winner"`
+	Turns  int    `json:"turns"`
+}
+
+// EventKind says which decision an Event holds.
+type EventKind string
+
+const (
+	KindAction    EventKind = "action"
+	KindChoice    EventKind = "choice"
+	KindAttackers EventKind = "attackers"
+	KindBlockers  EventKind = "blockers"
+)
+
+// Event is one decision made by one seat. The human seat records the
+// PriorityActions and ChoiceResponses it sent; the AI seat records what its
+// strategy returned, along with the turn it was asked on.
+type Event struct {
+	Seat      int                         `json:"seat"`
+	Kind      EventKind                   `json:"kind"`
+	Turn      int                         `json:"turn,omitempty"`
+	Action    *interactive.PriorityAction `json:"action,omitempty"`
+	Choice    *interactive.ChoiceResponse `json:"choice,omitempty"`
+	Attackers []uuid.UUID                 `json:"attackers,omitempty"`
+	Blockers  []mage.BlockAssignment      `json:"blockers,omitempty"`
+}
+
+// Label describes the event for the replay controls and logs.
+func (e Event) Label() string {
+	who := "You"
+	if e.Seat == AISeat {
+		who = "Opponent"
+	}
+	switch e.Kind {
+	case KindAction:
+		if e.Action == nil {
+			break
+		}
+		if e.Action.CardName != "" {
+			return fmt.Sprintf("%s: %s %s", who, e.Action.Type, e.Action.CardName)
+		}
+		return fmt.Sprintf("%s: %s", who, e.Action.Type)
+	case KindAttackers:
+		return fmt.Sprintf("%s: attack with %d", who, len(e.Attackers))
+	case KindBlockers:
+		return fmt.Sprintf("%s: block with %d", who, len(e.Blockers))
+	}
+	return fmt.Sprintf("%s: %s", who, e.Kind)
+}
+
+// Encode writes the recording as gzipped JSON.
+func (r *Recording) Encode(w io.Writer) error {
+	zw := gzip.NewWriter(w)
+	if err := json.NewEncoder(zw).Encode(r); err != nil {
+		return fmt.Errorf("encode replay: %w", err)
+	}
+	return zw.Close()
+}
+
+// Decode reads a recording written by Encode.
+func Decode(r io.Reader) (*Recording, error) {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("read replay: %w", err)
+	}
+	defer zr.Close()
+
+	var rec Recording
+	if err := json.NewDecoder(zr).Decode(&rec); err != nil {
+		return nil, fmt.Errorf("decode replay: %w", err)
+	}
+	if rec.Version != Version {
+		return nil, fmt.Errorf("unsupported replay version: %d", rec.Version)
+	}
+	for i := range rec.Seats {
+		if err := rec.Seats[i].validate(); err != nil {
+			return nil, fmt.Errorf("seat %d: %w", i, err)
+		}
+	}
+	return &rec, nil
+}
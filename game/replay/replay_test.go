@@ -0,0 +1,171 @@
+package replay
+
+import (
+	"bytes"
+	"compress/gzip"
+	"strings"
+	"testing"
+
+	"github.com/benprew/mage-go/pkg/mage/interactive"
+	"github.com/google/uuid"
+)
+
+func testRecording() *Recording {
+	rec := NewRecorder(42)
+	rec.SetSeat(HumanSeat, Seat{Name: "You", Life: 20, Cards: []string{"Mountain", "Lightning Bolt"}, Hand: []int{1}, Library: []int{0}})
+	rec.SetSeat(AISeat, Seat{Name: "Goblin Lord", Life: 15, Cards: []string{"Forest"}, Ante: []int{0}, Library: []int{0}})
+	rec.Add(Event{Seat: HumanSeat, Kind: KindAction, Action: &interactive.PriorityAction{Type: interactive.ActionPlayLand, CardName: "Mountain"}})
+	rec.Add(Event{Seat: AISeat, Kind: KindAttackers, Turn: 2, Attackers: []uuid.UUID{uuid.MustParse("6ba7b810-9dad-11d1-80b4-00c04fd430c8")}})
+	rec.Add(Event{Seat: HumanSeat, Kind: KindChoice, Choice: &interactive.ChoiceResponse{Accepted: true}})
+	rec.Finish("You", 7)
+	return rec.Recording()
+}
+
+func TestRecordingEncodeRoundTrips(t *testing.T) {
+	want := testRecording()
+	var buf bytes.Buffer
+	if err := want.Encode(&buf); err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	got, err := Decode(&buf)
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+
+	if got.Seed != 42 || got.Version != Version {
+		t.Errorf("seed/version = %d/%d", got.Seed, got.Version)
+	}
+	if got.Seats[AISeat].Name != "Goblin Lord" || got.Seats[AISeat].Ante[0] != 0 || got.Seats[HumanSeat].Hand[0] != 1 {
+		t.Errorf("seats = %+v", got.Seats)
+	}
+	if len(got.Events) != 3 {
+		t.Fatalf("%d events, want 3", len(got.Events))
+	}
+	if got.Events[0].Action.Type != interactive.ActionPlayLand || got.Events[0].Action.CardName != "Mountain" {
+		t.Errorf("event 0 = %+v", got.Events[0].Action)
+	}
+	if got.Events[1].Turn != 2 || got.Events[1].Attackers[0] != want.Events[1].Attackers[0] {
+		t.Errorf("event 1 = %+v", got.Events[1])
+	}
+	if !got.Events[2].Choice.Accepted {
+		t.Errorf("event 2 = %+v", got.Events[2].Choice)
+	}
+	if got.Result == nil || *got.Result != (Result{Winner: "You", Turns: 7}) {
+		t.Errorf("Result = %+v", got.Result)
+	}
+}
+
+func TestDecodeRejectsBadRecordings(t *testing.T) {
+	cases := map[string]string{
+		"version": `{"version": 99}`,
+		"index":   `{"version": 1, "seats": [{"cards": ["Mountain"], "hand": [1]}, {}]}`,
+	}
+	for name, js := range cases {
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		zw.Write([]byte(js))
+		zw.Close()
+		if _, err := Decode(&buf); err == nil {
+			t.Errorf("%s: Decode succeeded, want an error", name)
+		}
+	}
+	if _, err := Decode(strings.NewReader("not gzip")); err == nil {
+		t.Error("Decode of plain text succeeded, want an error")
+	}
+}
+
+func TestRecorderCopiesAndIgnoresNil(t *testing.T) {
+	var none *Recorder
+	none.Add(Event{Seat: HumanSeat, Kind: KindAction})
+	none.Finish("You", 1)
+	if none.Recording() != nil {
+		t.Error("nil Recorder returned a recording")
+	}
+
+	r := NewRecorder(1)
+	r.Add(Event{Seat: HumanSeat, Kind: KindAction})
+	snapshot := r.Recording()
+	r.Add(Event{Seat: AISeat, Kind: KindAction})
+	r.Finish("You", 3)
+	r.Finish("Opponent", 4)
+	if len(snapshot.Events) != 1 || snapshot.Result != nil {
+		t.Errorf("snapshot changed after later events: %+v", snapshot)
+	}
+	if got := r.Recording().Result; got.Winner != "You" || got.Turns != 3 {
+		t.Errorf("Result = %+v, want the first one kept", got)
+	}
+}
+
+func TestPlaybackHandsOutEachSeatsEventsInOrder(t *testing.T) {
+	p := NewPlayback(testRecording())
+
+	e, ok := p.Peek(HumanSeat)
+	if !ok || e.Kind != KindAction {
+		t.Fatalf("first human event = %+v, %v", e, ok)
+	}
+	p.Advance(HumanSeat)
+	if e, _ := p.Peek(HumanSeat); e.Kind != KindChoice {
+		t.Errorf("second human event = %s, want choice", e.Kind)
+	}
+	if e, _ := p.Peek(AISeat); e.Kind != KindAttackers {
+		t.Errorf("first AI event = %s, want attackers", e.Kind)
+	}
+	p.Advance(HumanSeat)
+	p.Advance(HumanSeat)
+	if _, ok := p.Peek(HumanSeat); ok {
+		t.Error("human seat still has events after the last one")
+	}
+	if p.Played() != 2 {
+		t.Errorf("Played = %d, want 2", p.Played())
+	}
+}
+
+func TestPlaybackKeepsFirstDivergence(t *testing.T) {
+	p := NewPlayback(testRecording())
+	p.CheckResult("You", 7)
+	if d := p.Divergence(); d != "" {
+		t.Fatalf("matching result reported divergence %q", d)
+	}
+	p.Diverge("first %d", 1)
+	p.CheckResult("Opponent", 9)
+	if d := p.Divergence(); d != "first 1" {
+		t.Errorf("Divergence = %q, want the first one", d)
+	}
+}
+
+func TestIDSourceIsReproducible(t *testing.T) {
+	draw := func(seed int64) []uuid.UUID {
+		ids := NewIDSource(seed)
+		ids.Install()
+		defer ids.Uninstall()
+		first := uuid.New()
+		// IDs made while isolated must not shift the seeded sequence.
+		ids.Isolate(func() { uuid.New() })
+		return []uuid.UUID{first, uuid.New()}
+	}
+	a, b := draw(7), draw(7)
+	if a[0] != b[0] || a[1] != b[1] {
+		t.Errorf("same seed gave %v and %v", a, b)
+	}
+	if c := draw(8); c[0] == a[0] {
+		t.Errorf("different seeds gave the same ID %v", c[0])
+	}
+}
+
+func TestStaleUninstallLeavesTheNewerSource(t *testing.T) {
+	want := NewIDSource(2)
+	want.Install()
+	first := uuid.New()
+	want.Uninstall()
+
+	// A finished duel's loop uninstalls its source after the next duel has
+	// installed one.
+	old, current := NewIDSource(1), NewIDSource(2)
+	old.Install()
+	current.Install()
+	defer current.Uninstall()
+	old.Uninstall()
+	if got := uuid.New(); got != first {
+		t.Errorf("ID after a stale Uninstall = %v, want the seeded %v", got, first)
+	}
+}
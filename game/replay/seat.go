@@ -0,0 +1,106 @@
+package replay
+
+import (
+	"fmt"
+
+	mage "github.com/benprew/mage-go/pkg/mage"
+	"github.com/google/uuid"
+)
+
+// Seat is one player's side of a recorded duel. Cards are listed in the order
+// they were created, and the ante, hand and library refer to them by index so
+// a recording doesn't depend on the IDs mage-go handed out.
+type Seat struct {
+	Name         string   `json:"name"`
+	PrimaryColor string   `json:"primary_color,omitempty"`
+	Life         int      `json:"life"`
+	Cards        []string `json:"cards"`
+	Ante         []int    `json:"ante,omitempty"`
+	// Permanents are cards put onto the battlefield before the first turn,
+	// such as dungeon dice bonuses and a dungeon's enchantment.
+	Permanents []string `json:"permanents,omitempty"`
+	Hand       []int    `json:"hand"`
+	Library    []int    `json:"library"`
+
+	index map[uuid.UUID]int
+}
+
+// AddCard records a card created for this seat's library.
+func (s *Seat) AddCard(c mage.Card) {
+	if s.index == nil {
+		s.index = make(map[uuid.UUID]int)
+	}
+	s.index[c.ID()] = len(s.Cards)
+	s.Cards = append(s.Cards, c.Name())
+}
+
+// AddAnte records that c, already added with AddCard, is this seat's ante.
+func (s *Seat) AddAnte(c mage.Card) {
+	if i, ok := s.index[c.ID()]; ok {
+		s.Ante = append(s.Ante, i)
+	}
+}
+
+// Deal records the order of p's hand and library, top card first. Call it
+// once mulligans are done, just before the game loop starts.
+func (s *Seat) Deal(p mage.Player) {
+	s.Hand = s.indexes(p.Hand())
+	s.Library = s.indexes(p.Library())
+}
+
+func (s *Seat) indexes(cards []mage.Card) []int {
+	out := make([]int, 0, len(cards))
+	for _, c := range cards {
+		if i, ok := s.index[c.ID()]; ok {
+			out = append(out, i)
+		}
+	}
+	return out
+}
+
+// CreateCards creates this seat's cards in recorded order and returns them
+// with the ones marked as ante.
+func (s *Seat) CreateCards() (cards, ante []mage.Card, err error) {
+	cards = make([]mage.Card, len(s.Cards))
+	for i, name := range s.Cards {
+		c, err := mage.CreateCard(name)
+		if err != nil {
+			return nil, nil, fmt.Errorf("create %s: %w", name, err)
+		}
+		cards[i] = c
+	}
+	for _, i := range s.Ante {
+		ante = append(ante, cards[i])
+	}
+	return cards, ante, nil
+}
+
+// Arrange puts p's cards back into the recorded hand and library order. cards
+// must be the slice CreateCards returned for this seat.
+func (s *Seat) Arrange(p mage.Player, cards []mage.Card) {
+	for _, c := range p.Hand() {
+		p.RemoveFromHand(c.ID())
+	}
+	lib := make([]mage.Card, 0, len(s.Hand)+len(s.Library))
+	for _, i := range s.Hand {
+		lib = append(lib, cards[i])
+	}
+	for _, i := range s.Library {
+		lib = append(lib, cards[i])
+	}
+	p.SetLibrary(lib)
+	for range s.Hand {
+		p.DrawCard()
+	}
+}
+
+func (s *Seat) validate() error {
+	for _, group := range [][]int{s.Ante, s.Hand, s.Library} {
+		for _, i := range group {
+			if i < 0 || i >= len(s.Cards) {
+				return fmt.Errorf("card index %d out of range", i)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package game
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	gameaudio "github.com/benprew/s30/game/audio"
+	"github.com/benprew/s30/game/bugreport"
+	"github.com/benprew/s30/game/domain"
+	"github.com/benprew/s30/game/screens"
+	"github.com/benprew/s30/game/ui/screenui"
+	"github.com/benprew/s30/game/world"
+)
+
+func TestOpenReportRestoresWorld(t *testing.T) {
+	player, err := domain.NewPlayer("Reporter", nil, false, domain.DifficultyEasy, domain.ColorBlue)
+	if err != nil {
+		t.Fatal(err)
+	}
+	level, err := world.NewLevelWithSeed(player, 42)
+	if err != nil {
+		t.Fatal(err)
+	}
+	level.SetIdentity("reported_game", domain.DifficultyEasy, domain.ColorBlue)
+	player.Gold = 321
+
+	data, err := bugreport.CollectReport(level, "World", nil, "stuck on a coastline").ToJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "bug.json")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	am := gameaudio.NewAudioManager()
+	am.Mute()
+	g := newTestGame()
+	g.audio = am
+	name, err := g.openReport(path)
+	if err != nil {
+		t.Fatalf("openReport: %v", err)
+	}
+	if name != screenui.WorldScr {
+		t.Errorf("screen = %v, want WorldScr for a report without a duel", name)
+	}
+	restored := g.Level()
+	if restored == nil || restored.GameID != "reported_game" || restored.Seed != 42 {
+		t.Fatalf("restored level = %+v", restored)
+	}
+	if g.player.Gold != 321 {
+		t.Errorf("gold = %d, want 321", g.player.Gold)
+	}
+	if _, ok := g.screenMap[screenui.WorldScr].(*screens.LevelScreen); !ok {
+		t.Error("expected the world screen to show the restored level")
+	}
+
+	empty := filepath.Join(t.TempDir(), "start.json")
+	if err := os.WriteFile(empty, []byte(`{"id":"bug_start"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := g.openReport(empty); err == nil {
+		t.Error("expected a report without a world to fail")
+	}
+}
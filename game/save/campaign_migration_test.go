@@ -0,0 +1,18 @@
+package save
+
+import "testing"
+
+func TestDeserializeSaveFromBeforeTheCampaignHasNoSieges(t *testing.T) {
+	data := []byte(`{"version": 3, "world": {"Player": {"MoveSpeed": 1}, "Enemies": [{"Character": null, "MoveSpeed": 1}]}}`)
+
+	got, err := deserializeSave(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.World.Enemies[0].Siege != nil {
+		t.Errorf("Siege = %+v, want nil", got.World.Enemies[0].Siege)
+	}
+	if got.World.Campaign.NextDispatch != nil {
+		t.Errorf("NextDispatch = %v, want nil until the clock first runs", got.World.Campaign.NextDispatch)
+	}
+}
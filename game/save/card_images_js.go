@@ -0,0 +1,11 @@
+//go:build js
+
+package save
+
+import "errors"
+
+// CardImageDir reports that browsers have no card image cache: the browser's
+// own HTTP cache keeps downloaded images instead.
+func CardImageDir() (string, error) {
+	return "", errors.New("no card image cache in the browser")
+}
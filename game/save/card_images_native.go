@@ -0,0 +1,15 @@
+//go:build !js
+
+package save
+
+import "path/filepath"
+
+// CardImageDir returns the directory downloaded card images are cached in. It
+// sits beside the save directory, so every saved game shares it.
+func CardImageDir() (string, error) {
+	saveDir, err := SaveDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(saveDir), "cards"), nil
+}
@@ -0,0 +1,52 @@
+package save
+
+import "testing"
+
+func TestDeserializeSaveMigratesRevealedCluesIntoTheJournal(t *testing.T) {
+	data := []byte(`{
+		"version": 2,
+		"world": {
+			"Player": {"MoveSpeed": 1},
+			"Dungeons": [
+				{"Name": "Ember Spire", "Clues": [
+					{"Type": 0, "Text": "Ember Spire lies to the east, 6 leagues from here.", "Revealed": true},
+					{"Type": 0, "Text": "", "Revealed": false},
+					{"Type": 0, "Text": "", "Revealed": false}
+				]},
+				{"Name": "Sunken Vault", "Clues": [
+					{"Type": 0, "Text": "", "Revealed": false},
+					{"Type": 0, "Text": "", "Revealed": false},
+					{"Type": 0, "Text": "", "Revealed": false}
+				]}
+			]
+		}
+	}`)
+
+	got, err := deserializeSave(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	journal := got.World.Player.Clues
+	if journal == nil {
+		t.Fatal("no clue journal after migrating a save with a revealed clue")
+	}
+	if names := journal.Dungeons(); len(names) != 1 || names[0] != "Ember Spire" {
+		t.Fatalf("journal dungeons = %v, want [Ember Spire]", names)
+	}
+	clues := journal.RevealedClues["Ember Spire"]
+	if len(clues) != 1 || clues[0].Text != "Ember Spire lies to the east, 6 leagues from here." {
+		t.Errorf("Ember Spire clues = %+v, want the revealed location clue", clues)
+	}
+}
+
+func TestDeserializeSaveWithoutRevealedCluesHasNoJournal(t *testing.T) {
+	data := []byte(`{"version": 2, "world": {"Player": {"MoveSpeed": 1}, "Dungeons": null}}`)
+
+	got, err := deserializeSave(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.World.Player.Clues != nil {
+		t.Errorf("Clues = %+v, want nil", got.World.Player.Clues)
+	}
+}
@@ -0,0 +1,47 @@
+package save
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/benprew/s30/game/domain"
+)
+
+// A save from before decks were named must load with every deck as it was
+// saved, under the default names.
+func TestDeserializeSaveKeepsTheDecksOfOlderSaves(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "save_v3_decks.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := deserializeSave(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := got.World.Player
+	mountain := domain.FindCardByID("4ed-375-mountain")
+	bolt := domain.FindCardByID("2ed-162-lightning-bolt")
+	want := []domain.Deck{
+		{mountain: 2, bolt: 1},
+		{bolt: 1},
+		{mountain: 3},
+	}
+	if n := p.NumDecks(); n != len(want) {
+		t.Fatalf("NumDecks = %d, want %d", n, len(want))
+	}
+	for i, deck := range want {
+		if got := p.CardCollection.GetDeck(i); !maps.Equal(got, deck) {
+			t.Errorf("deck %d = %v, want %v", i, got, deck)
+		}
+		if name := p.DeckName(i); name != fmt.Sprintf("Deck %d", i+1) {
+			t.Errorf("deck %d is named %q, want the default name", i, name)
+		}
+	}
+	if p.ActiveDeck != 2 {
+		t.Errorf("ActiveDeck = %d, want 2", p.ActiveDeck)
+	}
+}
@@ -0,0 +1,85 @@
+//go:build js
+
+package save
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/benprew/s30/game/save/internal/browserstore"
+)
+
+const (
+	webDeckDir       = "localStorage://s30/decks"
+	webDeckKeyPrefix = "s30.deck."
+)
+
+// DeckDir returns the browser-local virtual deck directory.
+func DeckDir() (string, error) {
+	_, err := browserstore.Open()
+	if err != nil {
+		return "", err
+	}
+	return webDeckDir, nil
+}
+
+// ListDeckFiles returns the deck files kept in browser storage, sorted by
+// name.
+func ListDeckFiles() ([]string, error) {
+	storage, err := browserstore.Open()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := storage.Entries(webDeckKeyPrefix)
+	if err != nil {
+		return nil, err
+	}
+	paths := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		paths = append(paths, webDeckDir+"/"+strings.TrimPrefix(entry.Key, webDeckKeyPrefix))
+	}
+	slices.Sort(paths)
+	return paths, nil
+}
+
+// ReadDeckFile returns the contents of a deck file listed by ListDeckFiles.
+func ReadDeckFile(deckPath string) ([]byte, error) {
+	filename, ok := strings.CutPrefix(deckPath, webDeckDir+"/")
+	if !ok {
+		return nil, fmt.Errorf("invalid browser deck path %q", deckPath)
+	}
+	storage, err := browserstore.Open()
+	if err != nil {
+		return nil, err
+	}
+	value, found, err := storage.Get(webDeckKeyPrefix + filename)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("deck file does not exist")
+	}
+	return browserstore.Decode(value)
+}
+
+// WriteDeckFile stores filename in browser storage, replacing any deck of the
+// same name, and returns its virtual path.
+func WriteDeckFile(filename string, data []byte) (string, error) {
+	if filename == "" || path.Base(filename) != filename {
+		return "", fmt.Errorf("invalid deck file name %q", filename)
+	}
+	storage, err := browserstore.Open()
+	if err != nil {
+		return "", err
+	}
+	encoded, err := browserstore.Encode(data)
+	if err != nil {
+		return "", err
+	}
+	if err := storage.Set(webDeckKeyPrefix+filename, encoded); err != nil {
+		return "", fmt.Errorf("write browser deck: %w", err)
+	}
+	return webDeckDir + "/" + filename, nil
+}
@@ -0,0 +1,77 @@
+//go:build !js
+
+package save
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// DeckDir returns the directory deck files are imported from and exported
+// to. It sits beside the save directory so both are easy to find.
+func DeckDir() (string, error) {
+	saveDir, err := SaveDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(saveDir), "decks"), nil
+}
+
+// ListDeckFiles returns the .dck and .txt files in the deck directory, sorted
+// by name.
+func ListDeckFiles() ([]string, error) {
+	deckDir, err := DeckDir()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(deckDir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var paths []string
+	for _, entry := range entries {
+		if entry.IsDir() || !isDeckFile(entry.Name()) {
+			continue
+		}
+		paths = append(paths, filepath.Join(deckDir, entry.Name()))
+	}
+	slices.Sort(paths)
+	return paths, nil
+}
+
+// ReadDeckFile returns the contents of a deck file listed by ListDeckFiles.
+func ReadDeckFile(deckPath string) ([]byte, error) {
+	return os.ReadFile(deckPath)
+}
+
+// WriteDeckFile writes filename into the deck directory, replacing any file
+// of the same name, and returns its path.
+func WriteDeckFile(filename string, data []byte) (string, error) {
+	if filename == "" || filepath.Base(filename) != filename {
+		return "", fmt.Errorf("invalid deck file name %q", filename)
+	}
+	deckDir, err := DeckDir()
+	if err != nil {
+		return "", fmt.Errorf("get deck directory: %w", err)
+	}
+	if err := os.MkdirAll(deckDir, 0755); err != nil {
+		return "", fmt.Errorf("create deck directory: %w", err)
+	}
+	deckPath := filepath.Join(deckDir, filename)
+	if err := os.WriteFile(deckPath, data, 0644); err != nil {
+		return "", fmt.Errorf("write deck file: %w", err)
+	}
+	return deckPath, nil
+}
+
+func isDeckFile(name string) bool {
+	ext := strings.ToLower(filepath.Ext(name))
+	return ext == ".dck" || ext == ".txt"
+}
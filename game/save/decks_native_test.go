@@ -0,0 +1,69 @@
+//go:build !js
+
+package save
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDeckFilesLiveBesideSaves(t *testing.T) {
+	root := t.TempDir()
+	SetSaveDir(filepath.Join(root, "saves"))
+	t.Cleanup(func() { SetSaveDir("") })
+
+	deckDir, err := DeckDir()
+	if err != nil {
+		t.Fatalf("DeckDir: %v", err)
+	}
+	if want := filepath.Join(root, "decks"); deckDir != want {
+		t.Fatalf("DeckDir = %q, want %q", deckDir, want)
+	}
+
+	paths, err := ListDeckFiles()
+	if err != nil || len(paths) != 0 {
+		t.Fatalf("ListDeckFiles before export = %v, %v; want none", paths, err)
+	}
+
+	if _, err := WriteDeckFile("burn.dck", []byte("NAME:Burn\n")); err != nil {
+		t.Fatalf("WriteDeckFile: %v", err)
+	}
+	if _, err := WriteDeckFile("aggro.txt", []byte("4 Lightning Bolt\n")); err != nil {
+		t.Fatalf("WriteDeckFile: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(deckDir, "notes.md"), []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := WriteDeckFile("../escape.dck", nil); err == nil {
+		t.Fatal("WriteDeckFile accepted a path outside the deck directory")
+	}
+
+	paths, err = ListDeckFiles()
+	if err != nil {
+		t.Fatalf("ListDeckFiles: %v", err)
+	}
+	want := []string{filepath.Join(deckDir, "aggro.txt"), filepath.Join(deckDir, "burn.dck")}
+	if len(paths) != 2 || paths[0] != want[0] || paths[1] != want[1] {
+		t.Fatalf("ListDeckFiles = %v, want %v", paths, want)
+	}
+
+	data, err := ReadDeckFile(paths[1])
+	if err != nil || string(data) != "NAME:Burn\n" {
+		t.Fatalf("ReadDeckFile = %q, %v", data, err)
+	}
+}
+
+func TestCardImageDirLivesBesideSaves(t *testing.T) {
+	root := t.TempDir()
+	SetSaveDir(filepath.Join(root, "saves"))
+	t.Cleanup(func() { SetSaveDir("") })
+
+	dir, err := CardImageDir()
+	if err != nil {
+		t.Fatalf("CardImageDir: %v", err)
+	}
+	if want := filepath.Join(root, "cards"); dir != want {
+		t.Errorf("CardImageDir = %q, want %q", dir, want)
+	}
+}
@@ -0,0 +1,23 @@
+package save
+
+import (
+	"testing"
+
+	"github.com/benprew/s30/game/domain"
+)
+
+func TestDeserializeSaveFromBeforeDeckFormatsHasNone(t *testing.T) {
+	data := []byte(`{"version": 7, "world": {"Player": {"MoveSpeed": 1, "ActiveQuests": [{"Type": 3, "Constraint": 1, "Color": 8}]}}}`)
+
+	got, err := deserializeSave(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := got.World.Player
+	if p.DeckFormat != "" {
+		t.Errorf("DeckFormat = %q, want none", p.DeckFormat)
+	}
+	if q := p.ActiveQuests[0]; q.Constraint != domain.ConstraintMonoColor || q.Format != "" {
+		t.Errorf("quest constraint = %d format %q, want mono-color and no format", q.Constraint, q.Format)
+	}
+}
@@ -0,0 +1,11 @@
+//go:build js
+
+package save
+
+import "errors"
+
+// LogDir reports that browsers have no log files: the browser console shows
+// the log instead.
+func LogDir() (string, error) {
+	return "", errors.New("no log files in the browser")
+}
@@ -0,0 +1,15 @@
+//go:build !js
+
+package save
+
+import "path/filepath"
+
+// LogDir returns the directory the game's log files are written to. It sits
+// beside the save directory.
+func LogDir() (string, error) {
+	saveDir, err := SaveDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(saveDir), "logs"), nil
+}
@@ -0,0 +1,15 @@
+package save
+
+import "testing"
+
+func TestDeserializeSaveFromBeforeManaLinksHasNone(t *testing.T) {
+	data := []byte(`{"version": 4, "world": {"Player": {"MoveSpeed": 1}}}`)
+
+	got, err := deserializeSave(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if links := got.World.Player.ManaLinks; len(links) != 0 {
+		t.Errorf("ManaLinks = %+v, want none", links)
+	}
+}
@@ -0,0 +1,16 @@
+package save
+
+import "testing"
+
+func TestDeserializeSaveFromBeforeMapFilesHasNoRoads(t *testing.T) {
+	data := []byte(`{"version": 9, "world": {"Player": {"MoveSpeed": 1}}}`)
+
+	got, err := deserializeSave(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Without roads the cities are joined again when the world is rebuilt.
+	if got.World.Map != "" || got.World.Roads != nil {
+		t.Errorf("Map = %q, Roads = %v, want a generated world without roads", got.World.Map, got.World.Roads)
+	}
+}
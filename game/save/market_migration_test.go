@@ -0,0 +1,21 @@
+package save
+
+import "testing"
+
+func TestDeserializeSaveFromBeforeMarketsHasNone(t *testing.T) {
+	data := []byte(`{
+		"version": 8,
+		"world": {
+			"Tiles": [[{"City": {"Name": "Tenby", "X": 0, "Y": 0}, "TerrainType": 4}]],
+			"Player": {"MoveSpeed": 1}
+		}
+	}`)
+
+	got, err := deserializeSave(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m := got.World.Tiles[0][0].City.Market; m != nil {
+		t.Errorf("Tenby market = %+v, want none before the player trades there", *m)
+	}
+}
@@ -0,0 +1,212 @@
+package save
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// migration upgrades a decoded save from version from to from+1. Migrations
+// work on the raw JSON document so they keep working after the Go structs
+// they were written against have changed.
+type migration struct {
+	from    int
+	name    string
+	migrate func(doc jsonObject) error
+}
+
+// migrations is the upgrade chain, in order. Every change to the saved shape
+// of world.Level, domain.Player, Quest, Dungeon etc. appends a migration,
+// bumps currentSaveVersion and adds a testdata/save_vN.json fixture.
+var migrations = []migration{
+	{from: 1, name: "movement speed in pixels per tick", migrate: migrateMovementSpeed},
+	{from: 2, name: "player clue journal", migrate: migrateClueJournal},
+	{from: 3, name: "Arzakon's campaign", migrate: migrateCampaign},
+	{from: 4, name: "player mana links", migrate: migrateManaLinks},
+	{from: 5, name: "deck sideboards", migrate: migrateSideboards},
+	{from: 6, name: "named decks", migrate: migrateDeckNames},
+	{from: 7, name: "deck formats", migrate: migrateDeckFormats},
+	{from: 8, name: "city markets", migrate: migrateMarkets},
+	{from: 9, name: "map files and roads", migrate: migrateMapAndRoads},
+}
+
+// jsonObject is a decoded JSON object. Numbers are kept as json.Number so
+// values such as the world seed survive the round trip exactly.
+type jsonObject map[string]any
+
+// object returns the nested object at key, or nil if it is missing or not an
+// object.
+func (o jsonObject) object(key string) jsonObject {
+	if v, ok := o[key].(map[string]any); ok {
+		return v
+	}
+	return nil
+}
+
+// objects returns the objects in the array at key, skipping anything else.
+func (o jsonObject) objects(key string) []jsonObject {
+	arr, _ := o[key].([]any)
+	out := make([]jsonObject, 0, len(arr))
+	for _, v := range arr {
+		if m, ok := v.(map[string]any); ok {
+			out = append(out, m)
+		}
+	}
+	return out
+}
+
+// float returns the number at key.
+func (o jsonObject) float(key string) (float64, bool) {
+	n, ok := o[key].(json.Number)
+	if !ok {
+		return 0, false
+	}
+	f, err := n.Float64()
+	return f, err == nil
+}
+
+// migrateSave upgrades raw save JSON to currentSaveVersion.
+func migrateSave(data []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var doc jsonObject
+	if err := dec.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal save data: %w", err)
+	}
+
+	version := 0
+	if n, ok := doc["version"].(json.Number); ok {
+		v, err := n.Int64()
+		if err != nil {
+			return nil, fmt.Errorf("invalid save version %q", n)
+		}
+		version = int(v)
+	}
+	if version < 1 || version > currentSaveVersion {
+		return nil, fmt.Errorf("unsupported save version: %d", version)
+	}
+	if version == currentSaveVersion {
+		return data, nil
+	}
+
+	for _, m := range migrations {
+		if m.from != version {
+			continue
+		}
+		if err := m.migrate(doc); err != nil {
+			return nil, fmt.Errorf("migrate save v%d to v%d (%s): %w", m.from, m.from+1, m.name, err)
+		}
+		version++
+	}
+	if version != currentSaveVersion {
+		return nil, fmt.Errorf("no migration from save version %d", version)
+	}
+	doc["version"] = version
+	return json.Marshal(doc)
+}
+
+const (
+	legacyMovementSpeedMinimum = 5
+	legacyMovementSpeedMaximum = 11
+	legacyMovementSpeedScale   = 6
+)
+
+// migrateMovementSpeed converts movement speeds saved in the older unit,
+// legacyMovementSpeedScale times the current distance per update. Version 1
+// saves were written both before and after that change, so only speeds in
+// the old range are converted.
+func migrateMovementSpeed(doc jsonObject) error {
+	w := doc.object("world")
+	if w == nil {
+		return nil
+	}
+	characters := w.objects("Enemies")
+	if p := w.object("Player"); p != nil {
+		characters = append(characters, p)
+	}
+	for _, c := range characters {
+		speed, ok := c.float("MoveSpeed")
+		if !ok || speed < legacyMovementSpeedMinimum || speed > legacyMovementSpeedMaximum {
+			continue
+		}
+		c["MoveSpeed"] = speed / legacyMovementSpeedScale
+	}
+	return nil
+}
+
+// migrateClueJournal starts the player's clue journal with the dungeon clues
+// they had already found, which older saves only marked on the dungeons.
+func migrateClueJournal(doc jsonObject) error {
+	w := doc.object("world")
+	if w == nil {
+		return nil
+	}
+	p := w.object("Player")
+	if p == nil {
+		return nil
+	}
+	journal := make(map[string]any)
+	for _, d := range w.objects("Dungeons") {
+		name, _ := d["Name"].(string)
+		var found []any
+		for _, c := range d.objects("Clues") {
+			revealed, _ := c["Revealed"].(bool)
+			text, _ := c["Text"].(string)
+			if revealed && text != "" {
+				found = append(found, c)
+			}
+		}
+		if len(found) > 0 {
+			journal[name] = found
+		}
+	}
+	if len(journal) > 0 {
+		p["Clues"] = map[string]any{"RevealedClues": journal}
+	}
+	return nil
+}
+
+// migrateCampaign has nothing to convert. Older saves have no sieges or
+// conquered cities, and the campaign clock starts on the first day played
+// after loading.
+func migrateCampaign(doc jsonObject) error {
+	return nil
+}
+
+// migrateManaLinks has nothing to convert: older saves have no linked cities,
+// so the player's list of links starts empty.
+func migrateManaLinks(doc jsonObject) error {
+	return nil
+}
+
+// migrateSideboards has nothing to convert: older saves have no sideboards,
+// so every deck starts with an empty one.
+func migrateSideboards(doc jsonObject) error {
+	return nil
+}
+
+// migrateDeckNames has nothing to convert: older saves have no deck names, so
+// their decks take the default ones.
+func migrateDeckNames(doc jsonObject) error {
+	return nil
+}
+
+// migrateDeckFormats has nothing to convert. Older saves have no deck format,
+// so the deck editor checks decks against the default one, and none of their
+// quests can ask for a format.
+func migrateDeckFormats(doc jsonObject) error {
+	return nil
+}
+
+// migrateMarkets has nothing to convert: older saves have no city markets, so
+// each city opens one the first time the player trades there.
+func migrateMarkets(doc jsonObject) error {
+	return nil
+}
+
+// migrateMapAndRoads has nothing to convert. Older saves were all generated
+// worlds, which have no map file, and without their roads the cities are
+// joined again when the world is rebuilt.
+func migrateMapAndRoads(doc jsonObject) error {
+	return nil
+}
@@ -0,0 +1,116 @@
+package save
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/benprew/s30/game/domain"
+)
+
+func TestMigrationsCoverEveryVersion(t *testing.T) {
+	if len(migrations) != currentSaveVersion-1 {
+		t.Fatalf("%d migrations for save version %d, want %d", len(migrations), currentSaveVersion, currentSaveVersion-1)
+	}
+	for i, m := range migrations {
+		if m.from != i+1 {
+			t.Errorf("migrations[%d].from = %d, want %d", i, m.from, i+1)
+		}
+	}
+}
+
+// Every save version needs a golden fixture, so each migration is exercised
+// against a save as that version actually wrote it.
+func TestGoldenSaveFixturesLoad(t *testing.T) {
+	for v := 1; v <= currentSaveVersion; v++ {
+		t.Run(fmt.Sprintf("v%d", v), func(t *testing.T) {
+			data, err := os.ReadFile(filepath.Join("testdata", fmt.Sprintf("save_v%d.json", v)))
+			if err != nil {
+				t.Fatalf("missing golden fixture for save version %d: %v", v, err)
+			}
+			got, err := deserializeSave(data)
+			if err != nil {
+				t.Fatalf("deserializeSave: %v", err)
+			}
+			if got.Version != currentSaveVersion {
+				t.Errorf("Version = %d, want %d", got.Version, currentSaveVersion)
+			}
+			if got.GameID != fmt.Sprintf("golden-v%d", v) {
+				t.Errorf("GameID = %q", got.GameID)
+			}
+
+			p := got.World.Player
+			if p.Name != "Ada" || p.Gold != 120 || p.Food != 30 || p.Life != 12 || p.Days != 4 {
+				t.Errorf("player = %s gold=%d food=%d life=%d days=%d", p.Name, p.Gold, p.Food, p.Life, p.Days)
+			}
+			if p.MoveSpeed != 10.0/6.0 {
+				t.Errorf("player MoveSpeed = %v, want %v", p.MoveSpeed, 10.0/6.0)
+			}
+			if got.World.Enemies[0].MoveSpeed != 1 {
+				t.Errorf("enemy MoveSpeed = %v, want 1", got.World.Enemies[0].MoveSpeed)
+			}
+			if n := p.Amulets[domain.ColorRed]; n != 1 {
+				t.Errorf("red amulets = %d, want 1", n)
+			}
+			if n := p.CardCollection.GetDeckCount(domain.FindCardByID("4ed-375-mountain"), 0); n != 2 {
+				t.Errorf("Mountains in deck = %d, want 2", n)
+			}
+		})
+	}
+}
+
+func TestMigrateSaveKeepsLargeNumbersExact(t *testing.T) {
+	data := []byte(`{"version": 1, "world": {"Seed": 9007199254740993, "Player": {"MoveSpeed": 1}}}`)
+
+	got, err := deserializeSave(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.World.Seed != 9007199254740993 {
+		t.Errorf("Seed = %d, want 9007199254740993", got.World.Seed)
+	}
+}
+
+func TestMigrateSaveRejectsUnknownVersions(t *testing.T) {
+	for _, data := range []string{`{}`, `{"version": 0}`, fmt.Sprintf(`{"version": %d}`, currentSaveVersion+1)} {
+		if _, err := migrateSave([]byte(data)); err == nil {
+			t.Errorf("migrateSave(%s) succeeded, want an error", data)
+		}
+	}
+}
+
+func TestCurrentSavesAreNotMigrated(t *testing.T) {
+	// A speed of 10 is in the legacy range, but a current save means it.
+	data := []byte(fmt.Sprintf(`{"version": %d, "world": {"Player": {"MoveSpeed": 10}}}`, currentSaveVersion))
+
+	got, err := deserializeSave(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.World.Player.MoveSpeed != 10 {
+		t.Errorf("MoveSpeed = %v, want 10", got.World.Player.MoveSpeed)
+	}
+}
+
+func TestSerializeSaveWritesCurrentVersion(t *testing.T) {
+	data := []byte(fmt.Sprintf(`{"version": %d, "world": {"GameID": "g", "Player": {}}}`, currentSaveVersion))
+	loaded, err := deserializeSave(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := serializeSave(loaded.World)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var header struct {
+		Version int `json:"version"`
+	}
+	if err := json.Unmarshal(out, &header); err != nil {
+		t.Fatal(err)
+	}
+	if header.Version != currentSaveVersion {
+		t.Errorf("written version = %d, want %d", header.Version, currentSaveVersion)
+	}
+}
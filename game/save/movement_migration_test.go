@@ -2,7 +2,7 @@ package save
 
 import "testing"
 
-func TestDeserializeSaveNormalizesLegacyMovementSpeedWithoutChangingVersion(t *testing.T) {
+func TestDeserializeSaveMigratesLegacyMovementSpeed(t *testing.T) {
 	data := []byte(`{
 		"version": 1,
 		"world": {
@@ -15,8 +15,8 @@ func TestDeserializeSaveNormalizesLegacyMovementSpeedWithoutChangingVersion(t *t
 	if err != nil {
 		t.Fatal(err)
 	}
-	if got.Version != 1 {
-		t.Fatalf("Version = %d, want 1", got.Version)
+	if got.Version != currentSaveVersion {
+		t.Fatalf("Version = %d, want %d", got.Version, currentSaveVersion)
 	}
 	if got.World.Player.MoveSpeed != 10.0/6.0 {
 		t.Errorf("player MoveSpeed = %v, want %v", got.World.Player.MoveSpeed, 10.0/6.0)
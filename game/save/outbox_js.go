@@ -0,0 +1,99 @@
+//go:build js
+
+package save
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/benprew/s30/game/save/internal/browserstore"
+)
+
+const (
+	webOutboxDir       = "localStorage://s30/outbox"
+	webOutboxKeyPrefix = "s30.outbox."
+)
+
+// OutboxDir returns the browser-local virtual outbox directory.
+func OutboxDir() (string, error) {
+	_, err := browserstore.Open()
+	if err != nil {
+		return "", err
+	}
+	return webOutboxDir, nil
+}
+
+// ListOutboxFiles returns the names of the files in the browser outbox,
+// sorted.
+func ListOutboxFiles() ([]string, error) {
+	storage, err := browserstore.Open()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := storage.Entries(webOutboxKeyPrefix)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, strings.TrimPrefix(entry.Key, webOutboxKeyPrefix))
+	}
+	slices.Sort(names)
+	return names, nil
+}
+
+// ReadOutboxFile returns the contents of an outbox file kept in browser
+// storage.
+func ReadOutboxFile(filename string) ([]byte, error) {
+	storage, key, err := openOutboxKey(filename)
+	if err != nil {
+		return nil, err
+	}
+	value, found, err := storage.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("outbox file does not exist")
+	}
+	return browserstore.Decode(value)
+}
+
+// WriteOutboxFile stores filename in the browser outbox, replacing any file
+// of the same name.
+func WriteOutboxFile(filename string, data []byte) error {
+	storage, key, err := openOutboxKey(filename)
+	if err != nil {
+		return err
+	}
+	encoded, err := browserstore.Encode(data)
+	if err != nil {
+		return err
+	}
+	if err := storage.Set(key, encoded); err != nil {
+		return fmt.Errorf("write browser outbox: %w", err)
+	}
+	return nil
+}
+
+// RemoveOutboxFile deletes filename from the browser outbox.
+func RemoveOutboxFile(filename string) error {
+	storage, key, err := openOutboxKey(filename)
+	if err != nil {
+		return err
+	}
+	return storage.Remove(key)
+}
+
+func openOutboxKey(filename string) (browserstore.Store, string, error) {
+	if filename == "" || path.Base(filename) != filename {
+		return browserstore.Store{}, "", fmt.Errorf("invalid outbox file name %q", filename)
+	}
+	storage, err := browserstore.Open()
+	if err != nil {
+		return browserstore.Store{}, "", err
+	}
+	return storage, webOutboxKeyPrefix + filename, nil
+}
@@ -0,0 +1,93 @@
+//go:build !js
+
+package save
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+// OutboxDir returns the directory bug reports wait in until they can be
+// sent. It sits beside the save directory.
+func OutboxDir() (string, error) {
+	saveDir, err := SaveDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(saveDir), "outbox"), nil
+}
+
+// ListOutboxFiles returns the names of the files in the outbox, sorted.
+func ListOutboxFiles() ([]string, error) {
+	outboxDir, err := OutboxDir()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(outboxDir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var names []string
+	for _, entry := range entries {
+		if !entry.IsDir() && filepath.Ext(entry.Name()) == ".json" {
+			names = append(names, entry.Name())
+		}
+	}
+	slices.Sort(names)
+	return names, nil
+}
+
+// ReadOutboxFile returns the contents of an outbox file.
+func ReadOutboxFile(filename string) ([]byte, error) {
+	outboxPath, err := outboxPath(filename)
+	if err != nil {
+		return nil, err
+	}
+	return os.ReadFile(outboxPath)
+}
+
+// WriteOutboxFile writes filename into the outbox, replacing any file of the
+// same name.
+func WriteOutboxFile(filename string, data []byte) error {
+	outboxPath, err := outboxPath(filename)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(outboxPath), 0755); err != nil {
+		return fmt.Errorf("create outbox directory: %w", err)
+	}
+	if err := os.WriteFile(outboxPath, data, 0644); err != nil {
+		return fmt.Errorf("write outbox file: %w", err)
+	}
+	return nil
+}
+
+// RemoveOutboxFile deletes filename from the outbox. Removing a file that
+// isn't there is not an error.
+func RemoveOutboxFile(filename string) error {
+	outboxPath, err := outboxPath(filename)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(outboxPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("remove outbox file: %w", err)
+	}
+	return nil
+}
+
+func outboxPath(filename string) (string, error) {
+	if filename == "" || filepath.Base(filename) != filename {
+		return "", fmt.Errorf("invalid outbox file name %q", filename)
+	}
+	outboxDir, err := OutboxDir()
+	if err != nil {
+		return "", fmt.Errorf("get outbox directory: %w", err)
+	}
+	return filepath.Join(outboxDir, filename), nil
+}
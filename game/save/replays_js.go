@@ -0,0 +1,66 @@
+//go:build js
+
+package save
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/benprew/s30/game/save/internal/browserstore"
+)
+
+const (
+	webReplayDir       = "localStorage://s30/replays"
+	webReplayKeyPrefix = "s30.replay."
+)
+
+// ReplayDir returns the browser-local virtual replay directory.
+func ReplayDir() (string, error) {
+	_, err := browserstore.Open()
+	if err != nil {
+		return "", err
+	}
+	return webReplayDir, nil
+}
+
+// ReadReplayFile returns the contents of a duel recording kept in browser
+// storage.
+func ReadReplayFile(replayPath string) ([]byte, error) {
+	filename, ok := strings.CutPrefix(replayPath, webReplayDir+"/")
+	if !ok {
+		return nil, fmt.Errorf("invalid browser replay path %q", replayPath)
+	}
+	storage, err := browserstore.Open()
+	if err != nil {
+		return nil, err
+	}
+	value, found, err := storage.Get(webReplayKeyPrefix + filename)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("replay file does not exist")
+	}
+	return browserstore.Decode(value)
+}
+
+// WriteReplayFile stores filename in browser storage and returns its virtual
+// path.
+func WriteReplayFile(filename string, data []byte) (string, error) {
+	if filename == "" || path.Base(filename) != filename {
+		return "", fmt.Errorf("invalid replay file name %q", filename)
+	}
+	storage, err := browserstore.Open()
+	if err != nil {
+		return "", err
+	}
+	encoded, err := browserstore.Encode(data)
+	if err != nil {
+		return "", err
+	}
+	if err := storage.Set(webReplayKeyPrefix+filename, encoded); err != nil {
+		return "", fmt.Errorf("write browser replay: %w", err)
+	}
+	return webReplayDir + "/" + filename, nil
+}
@@ -0,0 +1,44 @@
+//go:build !js
+
+package save
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ReplayDir returns the directory duel recordings are written to. It sits
+// beside the save directory.
+func ReplayDir() (string, error) {
+	saveDir, err := SaveDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(saveDir), "replays"), nil
+}
+
+// ReadReplayFile returns the contents of a duel recording.
+func ReadReplayFile(replayPath string) ([]byte, error) {
+	return os.ReadFile(replayPath)
+}
+
+// WriteReplayFile writes filename into the replay directory and returns its
+// path.
+func WriteReplayFile(filename string, data []byte) (string, error) {
+	if filename == "" || filepath.Base(filename) != filename {
+		return "", fmt.Errorf("invalid replay file name %q", filename)
+	}
+	replayDir, err := ReplayDir()
+	if err != nil {
+		return "", fmt.Errorf("get replay directory: %w", err)
+	}
+	if err := os.MkdirAll(replayDir, 0755); err != nil {
+		return "", fmt.Errorf("create replay directory: %w", err)
+	}
+	replayPath := filepath.Join(replayDir, filename)
+	if err := os.WriteFile(replayPath, data, 0644); err != nil {
+		return "", fmt.Errorf("write replay file: %w", err)
+	}
+	return replayPath, nil
+}
@@ -8,12 +8,9 @@ import (
 	"github.com/benprew/s30/game/world"
 )
 
-const (
-	currentSaveVersion         = 1
-	legacyMovementSpeedMinimum = 5
-	legacyMovementSpeedMaximum = 11
-	legacyMovementSpeedScale   = 6
-)
+// currentSaveVersion is the version written by SaveGame. Older saves are
+// upgraded by the migrations in migrate.go when loaded.
+const currentSaveVersion = 10
 
 // SaveGame writes the level to disk using the game's stable name and keeps only
 // the latest save of that game by pruning any earlier ones.
@@ -32,49 +29,38 @@ func SaveGame(level *world.Level) (string, error) {
 	return savePath, nil
 }
 
-func serializeSave(level *world.Level) ([]byte, error) {
-	saveData := &SaveData{
+// NewSaveData wraps the level in the current save format.
+func NewSaveData(level *world.Level, savedAt time.Time) *SaveData {
+	return &SaveData{
 		Name:    level.SaveName(),
 		GameID:  level.GameID,
 		Version: currentSaveVersion,
-		SavedAt: time.Now(),
+		SavedAt: savedAt,
 		World:   level,
 	}
+}
 
-	return json.Marshal(saveData)
+func serializeSave(level *world.Level) ([]byte, error) {
+	return json.Marshal(NewSaveData(level, time.Now()))
 }
 
 func deserializeSave(jsonData []byte) (*SaveData, error) {
-	var saveData SaveData
-	if err := json.Unmarshal(jsonData, &saveData); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal save data: %w", err)
+	migrated, err := migrateSave(jsonData)
+	if err != nil {
+		return nil, err
 	}
 
-	if saveData.Version != currentSaveVersion {
-		return nil, fmt.Errorf("unsupported save version: %d", saveData.Version)
+	var saveData SaveData
+	if err := json.Unmarshal(migrated, &saveData); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal save data: %w", err)
 	}
-	normalizeMovementSpeed(&saveData)
-
 	return &saveData, nil
 }
 
-func normalizeMovementSpeed(saveData *SaveData) {
-	if saveData.World == nil {
-		return
-	}
-	if saveData.World.Player != nil {
-		saveData.World.Player.MoveSpeed = normalizeSavedMovementSpeed(saveData.World.Player.MoveSpeed)
-	}
-	for i := range saveData.World.Enemies {
-		saveData.World.Enemies[i].MoveSpeed = normalizeSavedMovementSpeed(saveData.World.Enemies[i].MoveSpeed)
-	}
-}
-
-func normalizeSavedMovementSpeed(speed float64) float64 {
-	if speed < legacyMovementSpeedMinimum || speed > legacyMovementSpeedMaximum {
-		return speed
-	}
-	return speed / legacyMovementSpeedScale
+// ParseSaveData decodes save data written by any version of the game, such
+// as the world state embedded in a bug report, upgrading it as LoadGame does.
+func ParseSaveData(jsonData []byte) (*SaveData, error) {
+	return deserializeSave(jsonData)
 }
 
 func LoadGame(savePath string) (*world.Level, error) {
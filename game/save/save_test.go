@@ -5,6 +5,8 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+
+	"github.com/benprew/s30/game/world"
 )
 
 // func TestSerializePlayer(t *testing.T) {
@@ -297,3 +299,58 @@ func TestGetSaveFilePath(t *testing.T) {
 		t.Error("Expected non-empty filename")
 	}
 }
+
+func TestSaveRoundTripKeepsWorldSeed(t *testing.T) {
+	data := []byte(`{"version": 1, "world": {"Seed": 8675309, "Player": {}}}`)
+
+	got, err := deserializeSave(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.World.Seed != 8675309 {
+		t.Fatalf("Seed = %d, want 8675309", got.World.Seed)
+	}
+
+	out, err := json.Marshal(got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := deserializeSave(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.World.Seed != 8675309 {
+		t.Errorf("Seed after round trip = %d, want 8675309", again.World.Seed)
+	}
+}
+
+func TestSaveRoundTripKeepsLairs(t *testing.T) {
+	data := []byte(`{"version": 2, "world": {"Player": {}, "RandomEncounters": [
+		{"Tile": {"X": 3, "Y": 4}, "SpriteIndex": 1, "TerrainType": 2},
+		{"Tile": {"X": 5, "Y": 6}, "SpriteIndex": 2, "TerrainType": 3, "Lair": 8}
+	]}}`)
+
+	got, err := deserializeSave(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := json.Marshal(got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := deserializeSave(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	encounters := again.World.RandomEncounters
+	if len(encounters) != 2 {
+		t.Fatalf("got %d encounters, want 2", len(encounters))
+	}
+	if encounters[0].Lair != world.LairNone {
+		t.Errorf("encounter saved without a lair loaded as %s, want an unnamed lair", encounters[0].Lair)
+	}
+	if encounters[1].Lair != world.LairLostCity {
+		t.Errorf("lair after round trip = %s, want %s", encounters[1].Lair, world.LairLostCity)
+	}
+}
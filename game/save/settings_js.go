@@ -0,0 +1,50 @@
+//go:build js
+
+package save
+
+import (
+	"fmt"
+
+	"github.com/benprew/s30/game/save/internal/browserstore"
+)
+
+const webSettingsKey = "s30.settings"
+
+// SettingsPath returns the browser storage key settings are kept under.
+func SettingsPath() (string, error) {
+	_, err := browserstore.Open()
+	if err != nil {
+		return "", err
+	}
+	return "localStorage://" + webSettingsKey, nil
+}
+
+// ReadSettingsFile returns the settings kept in browser storage, or nil when
+// none have been written yet.
+func ReadSettingsFile() ([]byte, error) {
+	storage, err := browserstore.Open()
+	if err != nil {
+		return nil, err
+	}
+	value, found, err := storage.Get(webSettingsKey)
+	if err != nil || !found {
+		return nil, err
+	}
+	return browserstore.Decode(value)
+}
+
+// WriteSettingsFile replaces the settings kept in browser storage with data.
+func WriteSettingsFile(data []byte) error {
+	storage, err := browserstore.Open()
+	if err != nil {
+		return err
+	}
+	encoded, err := browserstore.Encode(data)
+	if err != nil {
+		return err
+	}
+	if err := storage.Set(webSettingsKey, encoded); err != nil {
+		return fmt.Errorf("write browser settings: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+//go:build !js
+
+package save
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// SettingsPath returns the path of the settings file. It sits beside the save
+// directory, so settings are shared by every saved game.
+func SettingsPath() (string, error) {
+	saveDir, err := SaveDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(saveDir), "settings.json"), nil
+}
+
+// ReadSettingsFile returns the contents of the settings file, or nil when
+// none has been written yet.
+func ReadSettingsFile() ([]byte, error) {
+	settingsPath, err := SettingsPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(settingsPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	return data, err
+}
+
+// WriteSettingsFile replaces the settings file with data.
+func WriteSettingsFile(data []byte) error {
+	settingsPath, err := SettingsPath()
+	if err != nil {
+		return fmt.Errorf("get settings path: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(settingsPath), 0755); err != nil {
+		return fmt.Errorf("create settings directory: %w", err)
+	}
+	if err := os.WriteFile(settingsPath, data, 0644); err != nil {
+		return fmt.Errorf("write settings file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+//go:build !js
+
+package save
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestSettingsFileLivesBesideSaves(t *testing.T) {
+	root := t.TempDir()
+	SetSaveDir(filepath.Join(root, "saves"))
+	t.Cleanup(func() { SetSaveDir("") })
+
+	settingsPath, err := SettingsPath()
+	if err != nil {
+		t.Fatalf("SettingsPath: %v", err)
+	}
+	if want := filepath.Join(root, "settings.json"); settingsPath != want {
+		t.Fatalf("SettingsPath = %q, want %q", settingsPath, want)
+	}
+
+	data, err := ReadSettingsFile()
+	if err != nil || data != nil {
+		t.Fatalf("ReadSettingsFile before writing = %q, %v; want nil", data, err)
+	}
+
+	if err := WriteSettingsFile([]byte(`{"bgm_volume":0.5}`)); err != nil {
+		t.Fatalf("WriteSettingsFile: %v", err)
+	}
+	data, err = ReadSettingsFile()
+	if err != nil || string(data) != `{"bgm_volume":0.5}` {
+		t.Fatalf("ReadSettingsFile = %q, %v", data, err)
+	}
+}
@@ -0,0 +1,25 @@
+package save
+
+import "testing"
+
+func TestDeserializeSaveFromBeforeSideboardsHasEmptyOnes(t *testing.T) {
+	data := []byte(`{
+		"version": 5,
+		"world": {
+			"Player": {
+				"MoveSpeed": 1,
+				"CardCollection": [
+					{"card_id": "2ed-162-lightning-bolt", "card_name": "Lightning Bolt", "count": 2, "deck_counts": [1]}
+				]
+			}
+		}
+	}`)
+
+	got, err := deserializeSave(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sb := got.World.Player.CardCollection.GetSideboard(0); len(sb) != 0 {
+		t.Errorf("sideboard = %v, want it empty", sb)
+	}
+}
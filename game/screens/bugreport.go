@@ -3,6 +3,7 @@ package screens
 import (
 	"fmt"
 	"image/color"
+	"sync"
 	"time"
 
 	"github.com/benprew/s30/assets"
@@ -41,6 +42,11 @@ type BugReportScreen struct {
 	statusColor    color.Color
 	submitting     bool
 	submittedAt    time.Time
+	// outbox holds reports that couldn't be sent; it is nil when no worker
+	// is configured.
+	outbox  *bugreport.Outbox
+	queued  []*bugreport.QueuedReport
+	queueMu sync.Mutex
 }
 
 // NewBugReportScreen creates a new BugReportScreen overlay.
@@ -50,17 +56,20 @@ func NewBugReportScreen(level *world.Level, prevScreenName screenui.ScreenName,
 
 	input := elements.NewTextInput(bugPanelX+30, bugPanelY+100, bugPanelW-60, 260, "Describe what happened and any steps to reproduce...")
 
+	submitter := bugreport.NewDefaultSubmitter()
 	s := &BugReportScreen{
 		level:          level,
 		prevScreenName: prevScreenName,
 		prevScreen:     prevScreen,
 		textInput:      input,
 		panelBg:        panelBg,
-		submitter:      bugreport.NewDefaultSubmitter(),
+		submitter:      submitter,
 		statusColor:    color.RGBA{220, 220, 220, 255},
+		outbox:         submitter.Outbox,
 	}
 
 	s.setupButtons()
+	s.refreshQueue()
 	return s
 }
 
@@ -75,34 +84,60 @@ func (s *BugReportScreen) setupButtons() {
 	}
 	fontFace := &text.GoTextFace{Source: fonts.MtgFont, Size: 18}
 
-	btnY := bugPanelY + bugPanelH - 70
-	submitW, _ := elements.TextButtonSize("Submit", fontFace)
-	copyW, _ := elements.TextButtonSize("Copy & Save", fontFace)
-	closeW, _ := elements.TextButtonSize("Close", fontFace)
+	type buttonSpec struct {
+		label, id string
+		btn       **elements.Button
+	}
+	specs := []buttonSpec{
+		{"Submit", "submit", &s.submitBtn},
+		{"Copy & Save", "copy_save", &s.copyBtn},
+	}
+	var sendQueuedBtn *elements.Button
+	if s.outbox != nil {
+		specs = append(specs, buttonSpec{"Send Queued", "send_queued", &sendQueuedBtn})
+	}
+	specs = append(specs, buttonSpec{"Close", "close", &s.closeBtn})
 
+	btnY := bugPanelY + bugPanelH - 70
 	gap := 16
-	totalW := submitW + gap + copyW + gap + closeW
-	startX := bugPanelX + (bugPanelW-totalW)/2
-
-	s.submitBtn = elements.NewButtonFromConfig(elements.ButtonConfig{
-		Normal: btnSprites[0][0], Hover: btnSprites[0][1], Pressed: btnSprites[0][2],
-		Text: "Submit", Font: fontFace, ID: "submit",
-		X: startX, Y: btnY,
-	})
-
-	s.copyBtn = elements.NewButtonFromConfig(elements.ButtonConfig{
-		Normal: btnSprites[0][0], Hover: btnSprites[0][1], Pressed: btnSprites[0][2],
-		Text: "Copy & Save", Font: fontFace, ID: "copy_save",
-		X: startX + submitW + gap, Y: btnY,
-	})
-
-	s.closeBtn = elements.NewButtonFromConfig(elements.ButtonConfig{
-		Normal: btnSprites[0][0], Hover: btnSprites[0][1], Pressed: btnSprites[0][2],
-		Text: "Close", Font: fontFace, ID: "close",
-		X: startX + submitW + gap + copyW + gap, Y: btnY,
-	})
-
-	s.buttons = []*elements.Button{s.submitBtn, s.copyBtn, s.closeBtn}
+	widths := make([]int, len(specs))
+	totalW := gap * (len(specs) - 1)
+	for i, spec := range specs {
+		widths[i], _ = elements.TextButtonSize(spec.label, fontFace)
+		totalW += widths[i]
+	}
+
+	x := bugPanelX + (bugPanelW-totalW)/2
+	s.buttons = nil
+	for i, spec := range specs {
+		*spec.btn = elements.NewButtonFromConfig(elements.ButtonConfig{
+			Normal: btnSprites[0][0], Hover: btnSprites[0][1], Pressed: btnSprites[0][2],
+			Text: spec.label, Font: fontFace, ID: spec.id,
+			X: x, Y: btnY,
+		})
+		s.buttons = append(s.buttons, *spec.btn)
+		x += widths[i] + gap
+	}
+}
+
+// refreshQueue reloads the reports waiting in the outbox.
+func (s *BugReportScreen) refreshQueue() {
+	if s.outbox == nil {
+		return
+	}
+	queued, err := s.outbox.Pending()
+	if err != nil {
+		logger.Warn("failed to read queued bug reports", "err", err)
+	}
+	s.queueMu.Lock()
+	s.queued = queued
+	s.queueMu.Unlock()
+}
+
+func (s *BugReportScreen) queuedCount() int {
+	s.queueMu.Lock()
+	defer s.queueMu.Unlock()
+	return len(s.queued)
 }
 
 func (s *BugReportScreen) SubmitSync() (*bugreport.SubmitResult, error) {
@@ -120,6 +155,7 @@ func (s *BugReportScreen) SubmitSync() (*bugreport.SubmitResult, error) {
 	result, err := s.submitter.Submit(report)
 	s.submitting = false
 	s.submittedAt = time.Now()
+	s.refreshQueue()
 
 	if err != nil {
 		s.statusMsg = fmt.Sprintf("Submission failed: %v", err)
@@ -130,6 +166,9 @@ func (s *BugReportScreen) SubmitSync() (*bugreport.SubmitResult, error) {
 	if result.IssueURL != "" {
 		s.statusMsg = fmt.Sprintf("Report sent! Issue: %s", result.IssueURL)
 		s.statusColor = color.RGBA{100, 240, 120, 255}
+	} else if result.Queued {
+		s.statusMsg = "Couldn't reach the tracker; report saved and queued to send later"
+		s.statusColor = color.RGBA{220, 200, 100, 255}
 	} else if result.LocalFilePath != "" {
 		s.statusMsg = fmt.Sprintf("Report saved locally: %s", result.LocalFilePath)
 		s.statusColor = color.RGBA{120, 220, 240, 255}
@@ -141,6 +180,48 @@ func (s *BugReportScreen) SubmitSync() (*bugreport.SubmitResult, error) {
 	return result, nil
 }
 
+// SendQueuedSync sends every report waiting in the outbox, whether or not
+// its backoff has passed.
+func (s *BugReportScreen) SendQueuedSync() (bugreport.OutboxResult, error) {
+	s.submitting = true
+	s.statusMsg = "Sending queued reports..."
+	s.statusColor = color.RGBA{220, 200, 100, 255}
+
+	result, err := s.outbox.Send(true)
+	s.submitting = false
+	s.refreshQueue()
+
+	if err != nil {
+		s.statusMsg = fmt.Sprintf("Sending queued reports failed: %v", err)
+		s.statusColor = color.RGBA{240, 100, 100, 255}
+		return result, err
+	}
+	s.statusMsg = fmt.Sprintf("Sent %d queued report(s)", result.Sent)
+	s.statusColor = color.RGBA{100, 240, 120, 255}
+	if result.Failed > 0 {
+		s.statusMsg += fmt.Sprintf("; %d still waiting for the tracker", result.Failed)
+		s.statusColor = color.RGBA{220, 200, 100, 255}
+	}
+	if result.Dropped > 0 {
+		s.statusMsg += fmt.Sprintf("; %d rejected", result.Dropped)
+	}
+	return result, nil
+}
+
+func (s *BugReportScreen) triggerSendQueued() {
+	if s.submitting {
+		return
+	}
+	if s.queuedCount() == 0 {
+		s.statusMsg = "No reports are waiting to be sent"
+		s.statusColor = color.RGBA{220, 220, 220, 255}
+		return
+	}
+	go func() {
+		_, _ = s.SendQueuedSync()
+	}()
+}
+
 func (s *BugReportScreen) triggerSubmit() {
 	if s.submitting {
 		return
@@ -188,6 +269,8 @@ func (s *BugReportScreen) Update(W, H int, scale float64) (screenui.ScreenName,
 			s.triggerSubmit()
 		case "copy_save":
 			s.triggerCopyAndSave()
+		case "send_queued":
+			s.triggerSendQueued()
 		case "close":
 			return screenui.PopScr, nil, nil
 		}
@@ -236,7 +319,41 @@ func (s *BugReportScreen) Draw(screen *ebiten.Image, W, H int, scale float64) {
 		text.Draw(screen, s.statusMsg, statusFace, stOpts)
 	}
 
+	if summary := s.queueSummary(); summary != "" {
+		queueFace := &text.GoTextFace{Source: fonts.MtgFont, Size: 13}
+		qOpts := &text.DrawOptions{}
+		qOpts.GeoM.Translate(float64(bugPanelX+30)*scale, float64(bugPanelY+405)*scale)
+		qOpts.ColorScale.ScaleWithColor(color.RGBA{180, 170, 160, 255})
+		text.Draw(screen, summary, queueFace, qOpts)
+	}
+
 	for _, b := range s.buttons {
 		b.Draw(screen, opts, scale)
 	}
 }
+
+// queueSummary describes the reports waiting in the outbox, or returns ""
+// when there are none.
+func (s *BugReportScreen) queueSummary() string {
+	s.queueMu.Lock()
+	defer s.queueMu.Unlock()
+	if len(s.queued) == 0 {
+		return ""
+	}
+	next := s.queued[0].NextAttempt
+	for _, q := range s.queued[1:] {
+		if q.NextAttempt.Before(next) {
+			next = q.NextAttempt
+		}
+	}
+	summary := fmt.Sprintf("%d report(s) waiting to be sent", len(s.queued))
+	switch wait := time.Until(next); {
+	case wait <= 0:
+		summary += ", retried on next launch"
+	case wait < time.Hour:
+		summary += fmt.Sprintf(", next try in %dm", int(wait.Minutes())+1)
+	default:
+		summary += fmt.Sprintf(", next try in %dh", int(wait.Hours())+1)
+	}
+	return summary
+}
@@ -0,0 +1,67 @@
+//go:build !js
+
+package screens
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/benprew/s30/game/bugreport"
+	"github.com/benprew/s30/game/save"
+	"github.com/benprew/s30/game/ui/screenui"
+)
+
+func TestBugReportScreenSendsQueuedReports(t *testing.T) {
+	save.SetSaveDir(filepath.Join(t.TempDir(), "saves"))
+	t.Cleanup(func() { save.SetSaveDir("") })
+
+	var online atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !online.Load() {
+			http.Error(w, "offline", http.StatusBadGateway)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(bugreport.SubmitResult{Success: true, IssueURL: "https://github.com/benprew/s30/issues/8"})
+	}))
+	defer server.Close()
+
+	worker := bugreport.NewWorkerSubmitter(server.URL)
+	outbox := bugreport.NewOutbox(worker)
+	scr := NewBugReportScreen(nil, screenui.WorldScr, nil)
+	scr.submitter = &bugreport.CompositeSubmitter{
+		Worker: worker,
+		Local:  bugreport.NewLocalFileSubmitter(t.TempDir(), func(string) error { return nil }),
+		Outbox: outbox,
+	}
+	scr.outbox = outbox
+	scr.setupButtons()
+
+	scr.textInput.SetText("The shop lost my gold")
+	result, err := scr.SubmitSync()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Queued {
+		t.Fatalf("result = %+v, want the report queued while the worker is down", result)
+	}
+	if !strings.HasPrefix(scr.queueSummary(), "1 report(s) waiting") {
+		t.Errorf("queue summary = %q", scr.queueSummary())
+	}
+
+	online.Store(true)
+	sent, err := scr.SendQueuedSync()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sent.Sent != 1 {
+		t.Errorf("sent = %+v, want the queued report sent", sent)
+	}
+	if scr.queueSummary() != "" || !strings.HasPrefix(scr.statusMsg, "Sent 1") {
+		t.Errorf("after sending: summary %q, status %q", scr.queueSummary(), scr.statusMsg)
+	}
+}
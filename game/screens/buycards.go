@@ -3,6 +3,7 @@ package screens
 import (
 	"fmt"
 	"image/color"
+	"math"
 
 	"github.com/benprew/s30/assets"
 	gameaudio "github.com/benprew/s30/game/audio"
@@ -28,6 +29,10 @@ type BuyCardsScreen struct {
 	ErrorMsg         string // error message to display (e.g. not enough money)
 	cardPlaceholders map[int]bool
 	W, H             int // Screen Width/Height used for making card buttons
+	// ReturnScr is where Done and Escape lead, the city unless the cards are
+	// sold somewhere else.
+	ReturnScr screenui.ScreenName
+	search    *cardSearch // hides the cards for sale that don't match
 }
 
 func (s *BuyCardsScreen) IsFramed() bool {
@@ -38,15 +43,15 @@ func (s *BuyCardsScreen) IsOverlay() bool { return false }
 
 const cardArtScale = 0.45
 
+const buyCardsSearchW = 360
+
 func NewBuyCardsScreen(city *domain.City, player *domain.Player, W, H int) *BuyCardsScreen {
 	bgImg, err := imageutil.LoadImage(assets.BuyCards_png)
 	if err != nil {
 		panic(fmt.Sprintf("Unable to load BuyCards.png: %s", err))
 	}
 
-	if len(city.CardsForSale) == 0 {
-		city.CardsForSale = domain.MkCards()
-	}
+	city.OpenMarket(player.Days)
 
 	sprite := imageutil.LoadButtonMap(assets.BuyCardsSprite_png, assets.BuyCardsSpriteMap_json)
 	title := imageutil.ScaleImage(ebiten.NewImageFromImage(sprite[4]), SCALE)
@@ -67,6 +72,8 @@ func NewBuyCardsScreen(city *domain.City, player *domain.Player, W, H int) *BuyC
 		ErrorMsg:    "",
 		W:           W,
 		H:           H,
+		ReturnScr:   screenui.CityScr,
+		search:      newCardSearch((W-buyCardsSearchW)/2, 366, buyCardsSearchW, 40),
 	}
 	screen.Buttons, screen.cardPlaceholders = screen.mkCardButtons()
 	screen.PurchaseButtons = mkPurchaseButtons()
@@ -99,6 +106,9 @@ func (s *BuyCardsScreen) Draw(screen *ebiten.Image, W, H int, scale float64) {
 	for _, b := range s.Buttons {
 		b.Draw(screen, frameOpts, scale)
 	}
+	if s.search != nil {
+		s.search.draw(screen)
+	}
 
 	// Draw card preview if active
 	if s.PreviewIdx >= 0 && s.PreviewIdx < len(s.City.CardsForSale) {
@@ -175,11 +185,21 @@ func (s *BuyCardsScreen) Update(W, H int, scale float64) (screenui.ScreenName, s
 		return screenui.BuyCardsScr, nil, nil
 	}
 
+	if s.search != nil {
+		typing, changed := s.search.update()
+		if changed {
+			s.Buttons, s.cardPlaceholders = s.mkCardButtons()
+		}
+		if typing {
+			return screenui.BuyCardsScr, nil, nil
+		}
+	}
+
 	for i := range s.Buttons {
 		b := s.Buttons[i]
 		b.Update(options, scale, W, H)
 		if b.ID == "done" && b.IsClicked() {
-			return screenui.CityScr, nil, nil
+			return s.ReturnScr, nil, nil
 		}
 		// Detect card or price click
 		if b.IsClicked() {
@@ -199,7 +219,7 @@ func (s *BuyCardsScreen) Update(W, H int, scale float64) (screenui.ScreenName, s
 	}
 
 	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
-		return screenui.CityScr, nil, nil
+		return s.ReturnScr, nil, nil
 	}
 	return screenui.BuyCardsScr, nil, nil
 }
@@ -248,10 +268,12 @@ func (s *BuyCardsScreen) layoutPurchaseButtons(W, H int) {
 func (s *BuyCardsScreen) buyCard() {
 	s.ErrorMsg = ""
 	card := s.City.CardsForSale[s.PreviewIdx]
-	if s.Player.Gold >= card.Price {
-		fmt.Println("Buying card:", s.PreviewIdx, "name:", card.Name(), "for", card.Price, "gold")
-		s.Player.Gold -= card.Price
+	price := s.price(card)
+	if s.Player.Gold >= price {
+		logger.Info("buying card", "card", card.Name(), "index", s.PreviewIdx, "gold", price)
+		s.Player.Gold -= price
 		s.Player.CardCollection.AddCard(card, 1)
+		s.City.RecordPurchase(card)
 		if am := gameaudio.Get(); am != nil {
 			am.PlaySFX(gameaudio.SFXTreasure)
 		}
@@ -263,6 +285,26 @@ func (s *BuyCardsScreen) buyCard() {
 	}
 }
 
+// price is what the player pays for card, after any world magic discount.
+func (s *BuyCardsScreen) price(card *domain.Card) int {
+	price := s.City.BuyPrice(card)
+	if s.Player == nil {
+		return price
+	}
+	return s.Player.TownPrice(price)
+}
+
+// slotX is where the slot'th of n cards goes: 160 apart, or closer when a
+// capital's stock wouldn't otherwise fit on the screen.
+func (s *BuyCardsScreen) slotX(slot, n int) int {
+	step := 160
+	if n > 1 && s.W > 0 {
+		artW := int(math.Ceil(domain.CardFullWidth * cardArtScale))
+		step = min(step, (s.W-240-artW)/(n-1))
+	}
+	return 120 + slot*step
+}
+
 func (s *BuyCardsScreen) mkCardButtons() ([]*elements.Button, map[int]bool) {
 	sprite := imageutil.LoadButtonMap(assets.BuyCardsSprite_png, assets.BuyCardsSpriteMap_json)
 	fontFace := &text.GoTextFace{
@@ -272,19 +314,26 @@ func (s *BuyCardsScreen) mkCardButtons() ([]*elements.Button, map[int]bool) {
 
 	placeholders := make(map[int]bool)
 	cards := make([]*elements.Button, 0)
+	var shown []int
 	for i, card := range s.City.CardsForSale {
+		if s.search.matches(card) {
+			shown = append(shown, i)
+		}
+	}
+	for slot, i := range shown {
+		card := s.City.CardsForSale[i]
 		if !card.ImageLoaded() {
 			placeholders[i] = true
 		}
 
 		cardUpperImg, err := card.CardImage(domain.CardViewArtOnly)
 		if err != nil {
-			fmt.Printf("WARN: Unable to load card image for %s: %v\n", card.Name(), err)
+			logger.Warn("unable to load card image", "card", card.Name(), "err", err)
 			continue
 		}
 
 		priceLabel := ebiten.NewImageFromImage(sprite[4])
-		priceText := fmt.Sprintf("%d", card.Price)
+		priceText := fmt.Sprintf("%d", s.price(card))
 		priceFontFace := &text.GoTextFace{
 			Source: fonts.MtgFont,
 			Size:   16,
@@ -294,7 +343,7 @@ func (s *BuyCardsScreen) mkCardButtons() ([]*elements.Button, map[int]bool) {
 		priceOptions.GeoM.Translate(textX, textY)
 		text.Draw(priceLabel, priceText, priceFontFace, &text.DrawOptions{DrawImageOptions: *priceOptions})
 
-		x := 120 + (i * 160)
+		x := s.slotX(slot, len(shown))
 		cardBtn := elements.NewButton(cardUpperImg, cardUpperImg, cardUpperImg, x, 200, cardArtScale)
 		cardBtn.ID = fmt.Sprintf("card_%d", i)
 		cards = append(cards, cardBtn)
@@ -320,7 +369,7 @@ func (s *BuyCardsScreen) mkCardButtons() ([]*elements.Button, map[int]bool) {
 	buttons := []*elements.Button{doneBtn}
 	buttons = append(buttons, cards...)
 
-	fmt.Println("Buttons:", len(buttons))
+	logger.Debug("card buttons", "count", len(buttons))
 
 	return buttons, placeholders
 }
@@ -0,0 +1,83 @@
+package screens
+
+import (
+	"image/color"
+
+	"github.com/benprew/s30/game/domain"
+	"github.com/benprew/s30/game/ui/elements"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// cardSearch is a search box that filters cards with a domain.CardQuery as
+// the player types. While the text doesn't parse, the last good query stays
+// in force and the box shows the error.
+type cardSearch struct {
+	input *elements.TextInput
+	query domain.CardQuery // nil matches every card
+	text  string           // text of the query in force
+	err   string
+}
+
+func newCardSearch(x, y, w, h int) *cardSearch {
+	input := elements.NewTextInput(x, y, w, h, "Search: c:r t:creature cmc<=3")
+	input.Multiline = false
+	input.Focused = false
+	return &cardSearch{input: input}
+}
+
+// moveTo places the box with its top left corner at x, y.
+func (cs *cardSearch) moveTo(x, y int) {
+	cs.input.X, cs.input.Y = x, y
+}
+
+// update handles typing in the box. It reports whether the box has the
+// keyboard, so the screen can ignore its own shortcuts, and whether the query
+// changed. Enter and Escape give the keyboard back.
+func (cs *cardSearch) update() (focused, changed bool) {
+	if !cs.input.Update(1) {
+		return false, false
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) || inpututil.IsKeyJustPressed(ebiten.KeyEnter) ||
+		inpututil.IsKeyJustPressed(ebiten.KeyNumpadEnter) {
+		cs.input.Focused = false
+	}
+	return true, cs.set(cs.input.Text())
+}
+
+// set parses text and, if it parses, makes it the query in force. It reports
+// whether the query changed.
+func (cs *cardSearch) set(text string) bool {
+	if text == cs.text {
+		cs.err = ""
+		return false
+	}
+	q, err := domain.ParseCardQuery(text)
+	if err != nil {
+		cs.err = err.Error()
+		return false
+	}
+	cs.err = ""
+	cs.text = text
+	cs.query = q
+	if text == "" {
+		cs.query = nil
+	}
+	return true
+}
+
+// matches reports whether card passes the search.
+func (cs *cardSearch) matches(card *domain.Card) bool {
+	return cs == nil || cs.query == nil || cs.query(card)
+}
+
+// draw draws the box, with any parse error just above it.
+func (cs *cardSearch) draw(screen *ebiten.Image) {
+	cs.input.Draw(screen, 1)
+	if cs.err == "" {
+		return
+	}
+	errText := elements.NewText(13, cs.err, cs.input.X, cs.input.Y-18)
+	errText.Color = color.RGBA{240, 120, 100, 255}
+	errText.Draw(screen, &ebiten.DrawImageOptions{}, 1)
+}
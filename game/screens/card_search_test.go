@@ -0,0 +1,73 @@
+package screens
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/benprew/s30/game/domain"
+)
+
+func TestCardSearchKeepsLastGoodQuery(t *testing.T) {
+	cs := newCardSearch(0, 0, 200, 40)
+	bolt := domain.FindCardByName("Lightning Bolt")
+	angel := domain.FindCardByName("Serra Angel")
+
+	if !cs.set("c:r") {
+		t.Fatal("expected c:r to change the search")
+	}
+	if !cs.matches(bolt) || cs.matches(angel) {
+		t.Error("expected c:r to match only the bolt")
+	}
+
+	if cs.set("c:r (") || cs.err == "" {
+		t.Errorf("expected a half-typed query to keep the last search and show an error, err %q", cs.err)
+	}
+	if !cs.matches(bolt) || cs.matches(angel) {
+		t.Error("expected the last good query to stay in force")
+	}
+
+	if !cs.set("") || cs.err != "" || !cs.matches(angel) {
+		t.Error("expected an empty search to match every card")
+	}
+}
+
+func TestEditDeckSearchFiltersCollection(t *testing.T) {
+	collection := domain.NewCardCollection()
+	collection.AddCard(domain.FindCardByName("Lightning Bolt"), 2)
+	collection.AddCard(domain.FindCardByName("Serra Angel"), 1)
+	screen := &EditDeckScreen{
+		Player: &domain.Player{Character: domain.Character{CardCollection: collection}},
+		filter: newCollectionFilter(),
+		search: newCardSearch(0, 0, editDeckSearchW, filterBtnSize),
+	}
+	screen.search.set("t:creature k:flying")
+
+	buttons, err := screen.createCollectionButtons()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(buttons) != 1 || buttons[0].ID != "Serra Angel" {
+		t.Errorf("expected only Serra Angel in the collection, got %d buttons", len(buttons))
+	}
+}
+
+func TestBuyCardsSearchHidesCards(t *testing.T) {
+	city := &domain.City{CardsForSale: []*domain.Card{
+		domain.FindCardByName("Serra Angel"),
+		domain.FindCardByName("Lightning Bolt"),
+	}}
+	screen := &BuyCardsScreen{City: city, PreviewIdx: -1, search: newCardSearch(0, 0, buyCardsSearchW, 40)}
+	screen.search.set("t:instant")
+
+	buttons, _ := screen.mkCardButtons()
+	var ids []string
+	for _, b := range buttons {
+		ids = append(ids, b.ID)
+	}
+	if want := []string{"done", "card_1", "price_1"}; !slices.Equal(ids, want) {
+		t.Errorf("buttons = %q, want %q", ids, want)
+	}
+	if x := buttons[1].Bounds.Min.X; x != 120 {
+		t.Errorf("expected the bolt to move into the first slot, at x %d", x)
+	}
+}
@@ -44,7 +44,7 @@ func NewCityScreen(city *domain.City, player *domain.Player, level *world.Level)
 		city.WisemanBoon = pickBoon(city, player, level)
 	}
 	return &CityScreen{
-		Buttons: mkButtons(SCALE-0.4, city),
+		Buttons: mkButtons(SCALE-0.4, city, player),
 		City:    city,
 		Player:  player,
 		Level:   level,
@@ -76,6 +76,11 @@ func pickBoon(city *domain.City, player *domain.Player, level *world.Level) doma
 			options = append(options, domain.BoonBonusCard)
 		}
 	}
+	if level != nil && level.HasDungeonClues(image.Point{X: city.X, Y: city.Y}) {
+		for range 2 {
+			options = append(options, domain.BoonDungeonClue)
+		}
+	}
 
 	if len(options) == 0 {
 		return domain.BoonBonusLife
@@ -141,7 +146,7 @@ func (c *CityScreen) Update(W, H int, scale float64) (screenui.ScreenName, scree
 			}
 		case "buyfood":
 			if b.IsClicked() {
-				cost := c.City.FoodCost()
+				cost := c.Player.FoodPrice(c.City)
 				if c.Player.Gold >= cost {
 					c.Player.Gold -= cost
 					c.Player.Food += 10
@@ -156,6 +161,21 @@ func (c *CityScreen) Update(W, H int, scale float64) (screenui.ScreenName, scree
 				s, err := NewEditDeckScreen(c.Player, c.City, W, H)
 				return screenui.EditDeckScr, s, err
 			}
+		case "worldmagic":
+			if b.IsClicked() {
+				return screenui.WorldMagicScr, NewWorldMagicScreen(c.City, c.Player, c.Level), nil
+			}
+		case "leap":
+			if b.IsClicked() {
+				return screenui.LeapOfFateScr, NewLeapOfFateScreen(c.City, c.Level), nil
+			}
+		case "liberate":
+			if b.IsClicked() {
+				b.State = elements.StateNormal
+				if duel := c.liberationDuel(); duel != nil {
+					return screenui.DuelScr, duel, nil
+				}
+			}
 		}
 	}
 
@@ -165,17 +185,53 @@ func (c *CityScreen) Update(W, H int, scale float64) (screenui.ScreenName, scree
 	return screenui.CityScr, nil, nil
 }
 
+// liberationDuel starts the duel against the minion holding the city, or
+// returns nil if there is no one to fight.
+func (c *CityScreen) liberationDuel() *DuelScreen {
+	name, ok := c.Level.OccupierName(c.City)
+	if !ok {
+		return nil
+	}
+	enemy, err := domain.NewEnemy(name)
+	if err != nil {
+		logger.Error("failed to load occupier", "occupier", name, "err", err)
+		return nil
+	}
+	if am := gameaudio.Get(); am != nil {
+		am.PlaySFX(gameaudio.SFXDice)
+	}
+	return NewLiberationDuelScreen(c.Player, &enemy, c.Level, c.City)
+}
+
 func (c *CityScreen) drawCityName(screen *ebiten.Image) {
 	W := screen.Bounds().Dx()
 	cityName := elements.NewText(30, c.City.Name, 0, 100)
 	cityName.HAlign = elements.AlignCenter
 	cityName.BoundsW = float64(W)
 	cityName.Draw(screen, &ebiten.DrawImageOptions{}, 1.0)
+
+	if c.City.IsConquered() {
+		held := elements.NewText(22, c.heldLabel(), 0, 140)
+		held.Color = color.RGBA{R: 230, G: 110, B: 90, A: 255}
+		held.HAlign = elements.AlignCenter
+		held.BoundsW = float64(W)
+		held.Draw(screen, &ebiten.DrawImageOptions{}, 1.0)
+	}
+}
+
+// heldLabel says who holds a conquered city: the minion the player duels to
+// liberate it.
+func (c *CityScreen) heldLabel() string {
+	name, ok := c.Level.OccupierName(c.City)
+	if !ok {
+		name = "Arzakon's minions"
+	}
+	return fmt.Sprintf("Held by %s - the shops and the Wiseman are closed", name)
 }
 
 // Make buttons for City screen
 // Iconb and Icons "b" stands for border
-func mkButtons(scale float64, city *domain.City) []*elements.Button {
+func mkButtons(scale float64, city *domain.City, player *domain.Player) []*elements.Button {
 	Icons, err := imageutil.LoadSpriteSheet(12, 2, assets.Icons_png)
 	if err != nil {
 		panic(fmt.Errorf("failed to load icons sprite sheet: %w", err))
@@ -191,6 +247,10 @@ func mkButtons(scale float64, city *domain.City) []*elements.Button {
 		Size:   20,
 	}
 
+	if city.IsConquered() {
+		return mkConqueredCityButtons(scale, fontFace, Icons, Iconb, player)
+	}
+
 	questText := "Talk to Wiseman"
 	if city.WisemanBoon.IsQuest() && !city.BoonGranted {
 		questText = "Begin Quest"
@@ -199,11 +259,39 @@ func mkButtons(scale float64, city *domain.City) []*elements.Button {
 	buttonConfigs := []ButtonConfig{
 		{ID: "buycards", Text: "Buy Cards", Index: 3, Position: &layout.Position{Anchor: layout.WFTopLeft, OffsetX: 100, OffsetY: 50}},
 		{ID: "quest", Text: questText, Index: 2, Position: &layout.Position{Anchor: layout.WFCenter, OffsetX: -50, OffsetY: 0}},
-		{ID: "buyfood", Text: fmt.Sprintf("%d gold = 10 food", city.FoodCost()), Index: 0, Position: &layout.Position{Anchor: layout.WFBottomLeft, OffsetX: 100, OffsetY: -125}},
+		{ID: "buyfood", Text: fmt.Sprintf("%d gold = 10 food", player.FoodPrice(city)), Index: 0, Position: &layout.Position{Anchor: layout.WFBottomLeft, OffsetX: 100, OffsetY: -125}},
+		{ID: "leave", Text: "Leave Village", Index: 1, Position: &layout.Position{Anchor: layout.WFBottomRight, OffsetX: -250, OffsetY: -125}},
+		{ID: "editdeck", Text: "Edit Deck", Index: 4, Position: &layout.Position{Anchor: layout.WFTopRight, OffsetX: -250, OffsetY: 50}},
+	}
+	if city.HasWorldMagic() {
+		buttonConfigs = append(buttonConfigs, ButtonConfig{ID: "worldmagic", Text: "World Magic", Index: 9, Position: &layout.Position{Anchor: layout.WFTopLeft, OffsetX: 335, OffsetY: 50}})
+	}
+	if player.HasWorldMagicNamed(domain.WorldMagicLeapOfFate) {
+		buttonConfigs = append(buttonConfigs, leapButtonConfig())
+	}
+	return mkCityButtons(buttonConfigs, fontFace, Icons, Iconb, scale)
+}
+
+func leapButtonConfig() ButtonConfig {
+	return ButtonConfig{ID: "leap", Text: "Leap of Fate", Index: 6, Position: &layout.Position{Anchor: layout.WFBottomLeft, OffsetX: 335, OffsetY: -125}}
+}
+
+// mkConqueredCityButtons makes the buttons for a city held by one of
+// Arzakon's minions. Its shops and Wiseman are closed; in their place the
+// player may challenge the occupier.
+func mkConqueredCityButtons(scale float64, fontFace *text.GoTextFace, Icons, Iconb [][]*ebiten.Image, player *domain.Player) []*elements.Button {
+	buttonConfigs := []ButtonConfig{
+		{ID: "liberate", Text: "Liberate City", Index: 7, Position: &layout.Position{Anchor: layout.WFCenter, OffsetX: -50, OffsetY: 0}},
 		{ID: "leave", Text: "Leave Village", Index: 1, Position: &layout.Position{Anchor: layout.WFBottomRight, OffsetX: -250, OffsetY: -125}},
 		{ID: "editdeck", Text: "Edit Deck", Index: 4, Position: &layout.Position{Anchor: layout.WFTopRight, OffsetX: -250, OffsetY: 50}},
 	}
+	if player.HasWorldMagicNamed(domain.WorldMagicLeapOfFate) {
+		buttonConfigs = append(buttonConfigs, leapButtonConfig())
+	}
+	return mkCityButtons(buttonConfigs, fontFace, Icons, Iconb, scale)
+}
 
+func mkCityButtons(buttonConfigs []ButtonConfig, fontFace *text.GoTextFace, Icons, Iconb [][]*ebiten.Image, scale float64) []*elements.Button {
 	buttons := make([]*elements.Button, len(buttonConfigs))
 	for i, config := range buttonConfigs {
 		btn := mkButton(config, fontFace, Icons, Iconb, scale)
@@ -0,0 +1,62 @@
+package screens
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/benprew/s30/game/domain"
+	"github.com/benprew/s30/game/world"
+)
+
+func cityButtonIDs(city *domain.City, player *domain.Player) []string {
+	var ids []string
+	for _, b := range mkButtons(SCALE-0.4, city, player) {
+		ids = append(ids, b.ID)
+	}
+	return ids
+}
+
+func TestConqueredCityClosesShopsAndWiseman(t *testing.T) {
+	player := &domain.Player{}
+	city := &domain.City{Tier: domain.TierTown, AssignedWorldMagic: &domain.WorldMagic{}}
+
+	free := cityButtonIDs(city, player)
+	for _, id := range []string{"buycards", "quest", "buyfood", "worldmagic"} {
+		if !slices.Contains(free, id) {
+			t.Errorf("free city has no %q button: %v", id, free)
+		}
+	}
+	if slices.Contains(free, "liberate") {
+		t.Errorf("free city offers liberation: %v", free)
+	}
+
+	city.ConqueredBy = domain.ColorRed
+	city.Occupier = "Someone"
+	held := cityButtonIDs(city, player)
+	for _, id := range []string{"buycards", "quest", "buyfood", "worldmagic"} {
+		if slices.Contains(held, id) {
+			t.Errorf("conquered city still has a %q button: %v", id, held)
+		}
+	}
+	for _, id := range []string{"liberate", "leave", "editdeck"} {
+		if !slices.Contains(held, id) {
+			t.Errorf("conquered city has no %q button: %v", id, held)
+		}
+	}
+}
+
+func TestHeldLabelNamesTheMinionToDuel(t *testing.T) {
+	level := &world.Level{}
+	city := &domain.City{ConqueredBy: domain.ColorRed, Occupier: "Renamed Minion"}
+	s := &CityScreen{City: city, Level: level}
+
+	name, ok := level.OccupierName(city)
+	if !ok {
+		t.Fatal("no red minion holds the city")
+	}
+	label := s.heldLabel()
+	if !strings.Contains(label, name) || strings.Contains(label, city.Occupier) {
+		t.Errorf("label = %q, want it to name %s, the minion the player duels", label, name)
+	}
+}
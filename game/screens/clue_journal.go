@@ -0,0 +1,152 @@
+package screens
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+
+	gameaudio "github.com/benprew/s30/game/audio"
+	"github.com/benprew/s30/game/domain"
+	"github.com/benprew/s30/game/ui"
+	"github.com/benprew/s30/game/ui/elements"
+	"github.com/benprew/s30/game/ui/fonts"
+	"github.com/benprew/s30/game/ui/screenui"
+	"github.com/benprew/s30/game/world"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+)
+
+// journalLinesPerPage is how many lines of clues fit on the journal panel
+// above its footer.
+const journalLinesPerPage = 15
+
+// ClueJournalScreen is the transparent overlay listing every dungeon clue the
+// player has found, opened from the world frame's dungeon button. A click or
+// the right arrow turns the page, closing the journal after the last one;
+// Escape closes it straight away.
+type ClueJournalScreen struct {
+	level   *world.Level
+	page    int
+	panelBg *ebiten.Image
+}
+
+func NewClueJournalScreen(level *world.Level) *ClueJournalScreen {
+	panelBg := ebiten.NewImage(questPanelW, questPanelH)
+	panelBg.Fill(color.RGBA{20, 12, 4, 220})
+	return &ClueJournalScreen{
+		level:   level,
+		panelBg: panelBg,
+	}
+}
+
+func (s *ClueJournalScreen) IsFramed() bool { return true }
+
+func (s *ClueJournalScreen) IsOverlay() bool { return true }
+
+func (s *ClueJournalScreen) Update(W, H int, scale float64) (screenui.ScreenName, screenui.Screen, error) {
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		return s.close()
+	}
+	if ui.Click(image.Rect(0, 0, W, H)) || inpututil.IsKeyJustPressed(ebiten.KeyRight) {
+		if s.page+1 >= len(s.pages()) {
+			return s.close()
+		}
+		s.page++
+		if am := gameaudio.Get(); am != nil {
+			am.PlaySFX(gameaudio.SFXClick2)
+		}
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyLeft) && s.page > 0 {
+		s.page--
+	}
+	return screenui.ClueJournalScr, nil, nil
+}
+
+// close leaves the journal, opening it at the first page next time.
+func (s *ClueJournalScreen) close() (screenui.ScreenName, screenui.Screen, error) {
+	s.page = 0
+	return screenui.PopScr, nil, nil
+}
+
+func (s *ClueJournalScreen) Draw(screen *ebiten.Image, W, H int, scale float64) {
+	panelOpts := &ebiten.DrawImageOptions{}
+	panelOpts.GeoM.Scale(scale, scale)
+	panelOpts.GeoM.Translate(float64(questPanelX)*scale, float64(questPanelY)*scale)
+	screen.DrawImage(s.panelBg, panelOpts)
+
+	title := elements.NewText(24, "Clue Journal", questPanelX+20, questPanelY+16)
+	title.Color = color.White
+	title.Draw(screen, &ebiten.DrawImageOptions{}, scale)
+
+	pages := s.pages()
+	page := min(s.page, len(pages)-1)
+	y := questPanelY + 56
+	for _, line := range pages[page] {
+		txt := elements.NewText(20, line, questPanelX+20, y)
+		txt.Color = color.RGBA{R: 230, G: 220, B: 200, A: 255}
+		txt.Draw(screen, &ebiten.DrawImageOptions{}, scale)
+		y += 26
+	}
+
+	if len(pages) > 1 {
+		footer := elements.NewText(16, fmt.Sprintf("Page %d of %d - click for more", page+1, len(pages)), questPanelX+20, questPanelY+questPanelH-30)
+		footer.Color = color.RGBA{R: 160, G: 160, B: 180, A: 255}
+		footer.Draw(screen, &ebiten.DrawImageOptions{}, scale)
+	}
+}
+
+// pages splits the journal into pages, always returning at least one.
+func (s *ClueJournalScreen) pages() [][]string {
+	lines := s.journalLines()
+	var pages [][]string
+	for len(lines) > journalLinesPerPage {
+		pages = append(pages, lines[:journalLinesPerPage])
+		lines = lines[journalLinesPerPage:]
+	}
+	return append(pages, lines)
+}
+
+// journalLines lists the player's clues by dungeon, wrapped to the panel.
+func (s *ClueJournalScreen) journalLines() []string {
+	clues := s.level.Player.Clues
+	names := clues.Dungeons()
+	if len(names) == 0 {
+		return []string{
+			"You have found no clues yet.",
+			"",
+			"Wisemen, ruined towers, dungeon scrolls and the",
+			"enemies you beat may tell you about the dungeons.",
+		}
+	}
+
+	face := &text.GoTextFace{Source: fonts.MtgFont, Size: 20}
+	var lines []string
+	for _, name := range names {
+		heading := name
+		if d := s.dungeon(name); d != nil && d.Cleared {
+			heading += " (cleared)"
+		}
+		lines = append(lines, heading)
+		for _, c := range clues.RevealedClues[name] {
+			for i, l := range wrapText(c.Text, face, questPanelW-80) {
+				prefix := "    "
+				if i == 0 {
+					prefix = "  - "
+				}
+				lines = append(lines, prefix+l)
+			}
+		}
+		lines = append(lines, "")
+	}
+	return lines[:len(lines)-1]
+}
+
+func (s *ClueJournalScreen) dungeon(name string) *domain.Dungeon {
+	for _, d := range s.level.Dungeons {
+		if d.Name == name {
+			return d
+		}
+	}
+	return nil
+}
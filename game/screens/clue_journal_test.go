@@ -0,0 +1,59 @@
+package screens
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/benprew/s30/game/domain"
+	"github.com/benprew/s30/game/world"
+)
+
+func TestClueJournalOverlayFlags(t *testing.T) {
+	s := NewClueJournalScreen(&world.Level{Player: &domain.Player{}})
+	if !s.IsFramed() || !s.IsOverlay() {
+		t.Error("clue journal should be a framed overlay")
+	}
+}
+
+func TestClueJournalLinesEmpty(t *testing.T) {
+	s := NewClueJournalScreen(&world.Level{Player: &domain.Player{}})
+	if lines := strings.Join(s.journalLines(), "\n"); !strings.Contains(lines, "no clues yet") {
+		t.Errorf("empty journal should say so, got:\n%s", lines)
+	}
+}
+
+func TestClueJournalListsCluesByDungeon(t *testing.T) {
+	p := &domain.Player{}
+	p.RecordClue("Sunken Vault", domain.DungeonClue{Type: domain.ClueLocation, Text: "Sunken Vault lies close by Tenby."})
+	p.RecordClue("Ember Spire", domain.DungeonClue{Type: domain.CluePopulation, Text: "3 foes guard Ember Spire."})
+	level := &world.Level{Player: p, Dungeons: []*domain.Dungeon{{Name: "Ember Spire", Cleared: true}, {Name: "Sunken Vault"}}}
+
+	got := NewClueJournalScreen(level).journalLines()
+	want := []string{
+		"Ember Spire (cleared)",
+		"  - 3 foes guard Ember Spire.",
+		"",
+		"Sunken Vault",
+		"  - Sunken Vault lies close by Tenby.",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("journal lines =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestClueJournalPagesLongJournals(t *testing.T) {
+	p := &domain.Player{}
+	for i := range 8 {
+		p.RecordClue(fmt.Sprintf("Dungeon %d", i), domain.DungeonClue{Text: "A clue."})
+	}
+	pages := NewClueJournalScreen(&world.Level{Player: p}).pages()
+	if len(pages) < 2 {
+		t.Fatalf("got %d pages for 8 dungeons, want at least 2", len(pages))
+	}
+	for i, page := range pages {
+		if len(page) > journalLinesPerPage {
+			t.Errorf("page %d has %d lines, want at most %d", i, len(page), journalLinesPerPage)
+		}
+	}
+}
@@ -25,6 +25,7 @@ import (
 	gameaudio "github.com/benprew/s30/game/audio"
 	"github.com/benprew/s30/game/bugreport"
 	"github.com/benprew/s30/game/domain"
+	"github.com/benprew/s30/game/replay"
 	"github.com/benprew/s30/game/ui"
 	"github.com/benprew/s30/game/ui/elements"
 	"github.com/benprew/s30/game/ui/fonts"
@@ -51,6 +52,12 @@ const (
 
 const FinalBossName = "Arzakon"
 
+var logger = logging.Logger(logging.Duel)
+
+// loopStopTimeout is how long Close waits for a cancelled game loop to
+// finish.
+const loopStopTimeout = 2 * time.Second
+
 // Minimum time each game message stays on screen before the next one is shown,
 // so phases don't flash past faster than the player can follow. Enemy actions
 // and life-total changes linger longer because those are the moments the player
@@ -109,6 +116,11 @@ type DuelScreen struct {
 	idx       int
 	dungeon   *dungeonDuelContext
 	finalBoss bool
+	// arenaPrize is what beating a Spectral Arena challenger wins, on top of
+	// the usual duel reward.
+	arenaPrize []*domain.Card
+	// liberating is the conquered city this duel is fought to free.
+	liberating *domain.City
 
 	game       *mage.Game
 	human      *interactive.HumanPlayer
@@ -118,6 +130,13 @@ type DuelScreen struct {
 	loopCancel context.CancelFunc
 	loopDone   chan struct{}
 
+	// Every duel is recorded so it can be attached to bug reports and, with
+	// RecordReplays, saved for playback. playback is set instead when the
+	// screen replays a recording.
+	ids      *replay.IDSource
+	recorder *replay.Recorder
+	playback *replayPlayback
+
 	autoPlay      bool
 	autoStrategy  ai.AIStrategy
 	autoResponded bool
@@ -179,9 +198,13 @@ type DuelScreen struct {
 
 	anteCard      *domain.Card
 	enemyAnteCard *domain.Card
+	// enemyDeck is the enemy's deck for this duel, after it has sideboarded
+	// against the player's colors.
+	enemyDeck domain.Deck
 
-	// diceNotice describes the dungeon dice effects active for this duel, shown
-	// as a banner at the top of the screen. Empty for ordinary duels.
+	// diceNotice describes the dungeon dice effects, enchantment and card
+	// restriction active for this duel, shown as a banner at the top of the
+	// screen. Empty for ordinary duels.
 	diceNotice string
 
 	choiceRequest  *interactive.ChoiceRequest
@@ -243,7 +266,23 @@ func (s *DuelScreen) EnableAutoPlay() {
 }
 
 func NewDuelScreen(player *domain.Player, enemy *domain.Enemy, lvl *world.Level, idx int, anteCard *domain.Card, enemyAnteCard *domain.Card) *DuelScreen {
-	s := &DuelScreen{
+	s := newDuelScreen(player, enemy, lvl, idx, anteCard, enemyAnteCard)
+	s.start()
+	return s
+}
+
+// start deals the game and lays out the screen. Constructors that need more
+// than newDuelScreen's fields in place before the deal, such as the dungeon a
+// duel is fought in, set them first and then call start.
+func (s *DuelScreen) start() {
+	s.initGameState()
+	s.loadImages()
+	s.placeHands()
+	s.initMulligan()
+}
+
+func newDuelScreen(player *domain.Player, enemy *domain.Enemy, lvl *world.Level, idx int, anteCard *domain.Card, enemyAnteCard *domain.Card) *DuelScreen {
+	return &DuelScreen{
 		player:           player,
 		enemy:            enemy,
 		lvl:              lvl,
@@ -260,29 +299,33 @@ func NewDuelScreen(player *domain.Player, enemy *domain.Enemy, lvl *world.Level,
 		pendingBlockers:  make(map[uuid.UUID]uuid.UUID),
 		cardActions:      make(map[uuid.UUID][]interactive.ActionOption),
 	}
+}
 
-	s.initGameState()
-	s.loadImages()
-
+func (s *DuelScreen) placeHands() {
 	s.self.handX = 860
 	s.self.handY = 430
 	s.opponent.handX = 860
 	s.opponent.handY = 310
-
-	s.initMulligan()
-
-	return s
 }
 
 // NewDungeonDuelScreen starts a duel against a dungeon enemy. There is no ante
 // screen or bribe option inside a dungeon: the duel begins immediately when the
 // player lands on the enemy's tile. Dungeon duels do not wager ante cards.
+// The dungeon's enchantment starts in play for both players and the cards it
+// forbids are left out of the player's deck.
 func NewDungeonDuelScreen(player *domain.Player, enemy *domain.Enemy, level *world.Level, state *domain.DungeonState, tile *domain.DungeonTile) *DuelScreen {
-	var anteCard *domain.Card
-	var enemyAnteCard *domain.Card
-	s := NewDuelScreen(player, enemy, level, -1, anteCard, enemyAnteCard)
+	s := newDuelScreen(player, enemy, level, -1, nil, nil)
 	s.dungeon = &dungeonDuelContext{state: state, tile: tile}
-	s.diceNotice = diceNotice(player.BonusDuelLife, player.BonusDuelCards)
+	// The notice is built before start, which spends the dice bonuses.
+	var notices []string
+	if n := diceNotice(player.BonusDuelLife, player.BonusDuelCards); n != "" {
+		notices = append(notices, n)
+	}
+	if n := dungeonNotice(s.currentDungeon()); n != "" {
+		notices = append(notices, n)
+	}
+	s.diceNotice = strings.Join(notices, "  -  ")
+	s.start()
 	return s
 }
 
@@ -298,6 +341,22 @@ func NewFinalBossDuelScreen(player *domain.Player, level *world.Level) *DuelScre
 	return s
 }
 
+// NewArenaDuelScreen starts a Spectral Arena duel. Like dungeon duels there
+// is no ante, and the challenger isn't on the map; winning also grants prize.
+func NewArenaDuelScreen(player *domain.Player, enemy *domain.Enemy, level *world.Level, prize []*domain.Card) *DuelScreen {
+	s := NewDuelScreen(player, enemy, level, -1, nil, nil)
+	s.arenaPrize = prize
+	return s
+}
+
+// NewLiberationDuelScreen starts the no-ante duel against the minion holding a
+// conquered city; winning it liberates the city.
+func NewLiberationDuelScreen(player *domain.Player, enemy *domain.Enemy, level *world.Level, city *domain.City) *DuelScreen {
+	s := NewDuelScreen(player, enemy, level, -1, nil, nil)
+	s.liberating = city
+	return s
+}
+
 // diceNotice builds the banner text summarizing the dice effects in force for a
 // dungeon duel. Returns "" when there are no effects.
 func diceNotice(lifeBonus int, cards []*domain.Card) string {
@@ -316,6 +375,53 @@ func diceNotice(lifeBonus int, cards []*domain.Card) string {
 	return "Dice: " + strings.Join(parts, ", ")
 }
 
+// dungeonNotice describes the enchantment and card restriction a dungeon
+// imposes on its duels. Returns "" when it imposes neither.
+func dungeonNotice(d *domain.Dungeon) string {
+	if d == nil {
+		return ""
+	}
+	var parts []string
+	if d.Enchantment != nil {
+		parts = append(parts, fmt.Sprintf("%s in play", d.Enchantment.CardName))
+	}
+	if r := d.CardRestriction.String(); r != "" {
+		parts = append(parts, r)
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return "Dungeon: " + strings.Join(parts, ", ")
+}
+
+// currentDungeon is the dungeon this duel is fought in, or nil outside of
+// dungeons.
+func (s *DuelScreen) currentDungeon() *domain.Dungeon {
+	if s.dungeon == nil || s.dungeon.state == nil {
+		return nil
+	}
+	return s.dungeon.state.CurrentDungeon
+}
+
+// duelDeck is the deck the player brings to this duel: their duel deck, less
+// whatever the dungeon forbids.
+func (s *DuelScreen) duelDeck() domain.Deck {
+	if d := s.currentDungeon(); d != nil {
+		deck, _ := d.DuelDeck(s.player)
+		return deck
+	}
+	return s.player.GetDuelDeck()
+}
+
+// opponentDeck returns the deck the enemy plays this duel, or its unsideboarded
+// deck before the duel has been set up.
+func (s *DuelScreen) opponentDeck() domain.Deck {
+	if s.enemyDeck != nil {
+		return s.enemyDeck
+	}
+	return s.enemy.Character.GetActiveDeck()
+}
+
 func buildCardImageMap(decks ...domain.Deck) map[string]*domain.Card {
 	m := make(map[string]*domain.Card)
 	for _, deck := range decks {
@@ -370,28 +476,40 @@ func permanentArtName(perm interactive.PermanentState, printedCard *domain.Card)
 }
 
 func (s *DuelScreen) initGameState() {
+	// Seeding the card IDs lets a recording of this duel be played back
+	// against the same IDs.
+	seed := time.Now().UnixNano()
+	s.ids = replay.NewIDSource(seed)
+	s.ids.Install()
+	s.recorder = replay.NewRecorder(seed)
+
+	deck := s.duelDeck()
 	s.human = interactive.NewHumanPlayer("You")
-	s.human.SetLife(s.player.Life + s.player.BonusDuelLife)
-	s.aiPlayer = ai.NewAIPlayer(s.enemy.Name(), heuristic.NewAdaptive())
+	s.human.SetLife(s.player.DuelStartingLife() + s.player.ManaLinkLife(deck.Colors()))
+	s.aiPlayer = ai.NewAIPlayer(s.enemy.Name(), s.recorder.Strategy(heuristic.NewAdaptive(), s.ids))
 	enemyLife := s.enemy.Character.Life
 	if s.lvl != nil && s.lvl.EnemyStartingLife > 0 {
 		enemyLife = s.lvl.EnemyStartingLife
 	}
-	s.aiPlayer.SetLife(enemyLife)
+	s.aiPlayer.SetLife(s.player.OpponentStartingLife(enemyLife))
 
-	playerAnte := addDeckToLibrary(s.human, s.player.GetDuelDeck(), s.anteCard)
+	humanSeat := replay.Seat{Name: "You", PrimaryColor: s.player.PrimaryColor, Life: s.human.Life()}
+	playerAnte := addDeckToLibrary(s.human, &humanSeat, deck, s.anteCard)
 	for _, card := range s.player.BonusDuelCards {
 		c, err := mage.CreateCard(card.CardName)
 		if err != nil {
-			logging.Printf(logging.Duel, "Failed to create bonus card %s: %v\n", card.CardName, err)
+			logger.Error("failed to create bonus card", "card", card.CardName, "err", err)
 			continue
 		}
 		s.human.AddToLibrary(c)
+		humanSeat.AddCard(c)
 	}
 	bonusPermanents := s.player.BonusDuelCards
 	s.player.BonusDuelLife = 0
 	s.player.BonusDuelCards = nil
-	enemyAnte := addDeckToLibrary(s.aiPlayer, s.enemy.Character.GetActiveDeck(), s.enemyAnteCard)
+	enemySeat := replay.Seat{Name: s.enemy.Name(), PrimaryColor: s.enemy.Character.PrimaryColor, Life: s.aiPlayer.Life()}
+	s.enemyDeck = s.enemy.Character.DuelDeckAgainst(deck.Colors(), s.enemyAnteCard)
+	enemyAnte := addDeckToLibrary(s.aiPlayer, &enemySeat, s.enemyDeck, s.enemyAnteCard)
 
 	var err error
 	s.game, err = mage.NewGameWithAnte(s.human, s.aiPlayer, playerAnte, enemyAnte)
@@ -403,44 +521,53 @@ func (s *DuelScreen) initGameState() {
 	mage.DebugPriority = logging.Enabled(logging.Duel)
 	search.DebugStats = logging.Enabled(logging.Duel)
 
-	logging.Printf(logging.Duel, "Drawing cards\n")
-
-	for range 7 {
+	for range s.player.OpeningHandSize() {
 		s.human.DrawCard()
 	}
-	for range 7 {
+	for range domain.DefaultOpeningHandSize {
 		s.aiPlayer.DrawCard()
 	}
-	logging.Printf(logging.Duel, "Cards drawn\n")
-
 	s.putBonusPermanentsInPlay(bonusPermanents)
+	for _, card := range bonusPermanents {
+		humanSeat.Permanents = append(humanSeat.Permanents, card.CardName)
+	}
+	var enchantment domain.Deck
+	if d := s.currentDungeon(); d != nil && d.Enchantment != nil {
+		enchantment = domain.Deck{d.Enchantment: 1}
+		s.putDungeonEnchantmentInPlay(d.Enchantment)
+		enemySeat.Permanents = append(enemySeat.Permanents, d.Enchantment.CardName)
+	}
+	s.recorder.SetSeat(replay.HumanSeat, humanSeat)
+	s.recorder.SetSeat(replay.AISeat, enemySeat)
 
-	s.cardImageMap = buildCardImageMap(s.player.GetDuelDeck(), s.enemy.Character.GetActiveDeck())
+	s.cardImageMap = buildCardImageMap(deck, s.enemyDeck, enchantment)
 
 	s.self = &duelPlayer{name: "You"}
 	s.opponent = &duelPlayer{name: s.enemy.Name()}
 
 	s.evaluateQuestConstraints()
 
-	logging.Printf(logging.Duel, "Game init: human library=%d hand=%d, ai library=%d hand=%d, human life=%d, ai life=%d\n",
-		len(s.human.Library()), len(s.human.Hand()),
-		len(s.aiPlayer.Library()), len(s.aiPlayer.Hand()),
-		s.human.Life(), s.aiPlayer.Life())
-	logging.Printf(logging.Duel, "Game init: IsGameOver=%v Winner=%q\n", s.game.IsGameOver(), s.game.Winner())
+	logger.Debug("game init",
+		"human_library", len(s.human.Library()), "human_hand", len(s.human.Hand()),
+		"ai_library", len(s.aiPlayer.Library()), "ai_hand", len(s.aiPlayer.Hand()),
+		"human_life", s.human.Life(), "ai_life", s.aiPlayer.Life(),
+		"game_over", s.game.IsGameOver(), "winner", s.game.Winner())
 }
 
-func addDeckToLibrary(player mage.Player, deck domain.Deck, anteCard *domain.Card) []mage.Card {
+func addDeckToLibrary(player mage.Player, seat *replay.Seat, deck domain.Deck, anteCard *domain.Card) []mage.Card {
 	var ante []mage.Card
 	for card, count := range deck {
 		for range count {
 			c, err := mage.CreateCard(card.CardName)
 			if err != nil {
-				logging.Printf(logging.Duel, "Failed to create card %s: %v\n", card.CardName, err)
+				logger.Error("failed to create card", "card", card.CardName, "err", err)
 				continue
 			}
 			player.AddToLibrary(c)
+			seat.AddCard(c)
 			if anteCard != nil && len(ante) == 0 && card.CardName == anteCard.CardName {
 				ante = []mage.Card{c}
+				seat.AddAnte(c)
 			}
 		}
 	}
@@ -451,14 +578,28 @@ func addDeckToLibrary(player mage.Player, deck domain.Deck, anteCard *domain.Car
 // player's battlefield before the game loop starts. Used by dungeon dice that
 // grant a card "in play" for the duel.
 func (s *DuelScreen) putBonusPermanentsInPlay(cards []*domain.Card) {
+	s.putPermanentsInPlay(s.human, cards)
+}
+
+// putDungeonEnchantmentInPlay puts the dungeon's enchantment onto the
+// battlefield under the dungeon enemy's control. There's a single copy: the
+// enchantments are picked to affect both players whoever controls them, and
+// a second copy would double the effect.
+func (s *DuelScreen) putDungeonEnchantmentInPlay(card *domain.Card) {
+	s.putPermanentsInPlay(s.aiPlayer, []*domain.Card{card})
+}
+
+// putPermanentsInPlay drops each card directly onto p's battlefield before
+// the game loop starts.
+func (s *DuelScreen) putPermanentsInPlay(p mage.Player, cards []*domain.Card) {
 	for _, card := range cards {
 		c, err := mage.CreateCard(card.CardName)
 		if err != nil {
-			logging.Printf(logging.Duel, "Failed to create bonus permanent %s: %v\n", card.CardName, err)
+			logger.Error("failed to create bonus permanent", "card", card.CardName, "err", err)
 			continue
 		}
-		c.SetOwner(s.human.PlayerID())
-		s.game.PutOnBattlefield(c, s.human.PlayerID())
+		c.SetOwner(p.PlayerID())
+		s.game.PutOnBattlefield(c, p.PlayerID())
 	}
 }
 
@@ -470,11 +611,15 @@ func (s *DuelScreen) startGameLoop() {
 	s.loopCancel = cancel
 	s.loopDone = make(chan struct{})
 	pause := 300 * time.Millisecond
-	if s.autoPlay {
+	if s.autoPlay || s.playback != nil {
 		pause = 0
 	}
+	s.recorder.Deal(replay.HumanSeat, s.human)
+	s.recorder.Deal(replay.AISeat, s.aiPlayer)
+	ids := s.ids
 	go func() {
 		defer close(s.loopDone)
+		defer ids.Uninstall()
 		defer func() {
 			if r := recover(); r != nil {
 				bugreport.HandleCrash(s.lvl, "Duel", s, r, debug.Stack())
@@ -485,11 +630,22 @@ func (s *DuelScreen) startGameLoop() {
 	}()
 }
 
-// Close stops the game loop when this screen is no longer active.
+// Close stops the game loop when this screen is no longer active, and waits
+// for it to finish so it makes no more IDs from under the next duel.
 func (s *DuelScreen) Close() {
 	if s.loopCancel != nil {
 		s.loopCancel()
 		s.loopCancel = nil
+		select {
+		case <-s.loopDone:
+		case <-time.After(loopStopTimeout):
+			logger.Warn("game loop still running after close", "timeout", loopStopTimeout)
+		}
+	}
+	if s.loopDone == nil {
+		// The game loop never started, so nothing else will put back the
+		// default ID source.
+		s.ids.Uninstall()
 	}
 }
 
@@ -498,6 +654,7 @@ func (s *DuelScreen) drainMessages() {
 	if delay <= 0 {
 		delay = phaseDisplayDelay
 	}
+	delay = time.Duration(float64(delay) * MessagePacing)
 	if !s.autoPlay && time.Since(s.lastMsgTime) < delay {
 		return
 	}
@@ -518,6 +675,9 @@ func (s *DuelScreen) applyGameMsg(msg interactive.GameMsg) {
 	s.syncSpellAnimations(prev, &msg, now)
 	s.lastMsg = &msg
 	s.msgHistory = append(s.msgHistory, msg)
+	if s.playback != nil {
+		s.playback.shown++
+	}
 	s.autoResponded = false
 	s.refreshDamageAssignmentPrompt(&msg)
 	s.lastMsgTime = now
@@ -529,8 +689,8 @@ func (s *DuelScreen) applyGameMsg(msg interactive.GameMsg) {
 		for i, o := range msg.Options {
 			optNames[i] = fmt.Sprintf("%s(%s)", o.Type, o.CardName)
 		}
-		logging.Printf(logging.Duel, "MSG: turn=%d step=%q active=%q prompt=%v options=%v gameover=%v\n",
-			msg.State.Turn, msg.State.Step, msg.State.ActivePlayer, msg.Prompt, optNames, msg.GameOver)
+		logger.Debug("message", "turn", msg.State.Turn, "step", msg.State.Step, "active", msg.State.ActivePlayer,
+			"prompt", msg.Prompt, "options", optNames, "game_over", msg.GameOver)
 	}
 }
 
@@ -788,8 +948,8 @@ func (s *DuelScreen) drainChoiceRequests() {
 				for i, o := range req.Options {
 					optLabels[i] = o.Label
 				}
-				logging.Printf(logging.Duel, "CHOICE_REQ: type=%d reason=%q amount=%d options=%v\n",
-					req.Type, req.Reason, req.Amount, optLabels)
+				logger.Debug("choice request", "type", int(req.Type), "reason", req.Reason,
+					"amount", req.Amount, "options", optLabels)
 			}
 		default:
 			return
@@ -885,7 +1045,7 @@ func suggestedDamageAssignment(blockers []interactive.PermanentState, total int)
 
 func (s *DuelScreen) checkSoundTriggers(prev, msg *interactive.GameMsg) {
 	am := gameaudio.Get()
-	if am == nil {
+	if am == nil || s.fastForwarding() {
 		return
 	}
 	for _, sfx := range soundEffectsForDuelTransition(prev, msg) {
@@ -1012,7 +1172,7 @@ func (s *DuelScreen) loadImages() {
 
 	statbutt, err := imageutil.LoadSpriteSheet(16, 1, assets.Statbutt_png)
 	if err != nil {
-		fmt.Printf("Error loading Statbutt sprite sheet: %v\n", err)
+		logger.Error("failed to load Statbutt sprite sheet", "err", err)
 		return
 	}
 	s.doneBtn = [3]*ebiten.Image{statbutt[0][11], statbutt[0][12], statbutt[0][13]}
@@ -1032,12 +1192,12 @@ func (s *DuelScreen) loadImages() {
 func loadDuelImage(name string) *ebiten.Image {
 	data, err := assets.DuelFS.ReadFile("art/screens/duel/" + name)
 	if err != nil {
-		fmt.Printf("Error loading duel image %s: %v\n", name, err)
+		logger.Error("failed to load duel image", "image", name, "err", err)
 		return nil
 	}
 	img, err := imageutil.LoadImage(data)
 	if err != nil {
-		fmt.Printf("Error decoding duel image %s: %v\n", name, err)
+		logger.Error("failed to decode duel image", "image", name, "err", err)
 		return nil
 	}
 	return img
@@ -1136,6 +1296,9 @@ const (
 )
 
 func (s *DuelScreen) Update(W, H int, scale float64) (screenui.ScreenName, screenui.Screen, error) {
+	if s.playback != nil {
+		return s.updateReplay()
+	}
 	if s.inMulligan {
 		s.updateMulliganUI(W, H)
 		return screenui.DuelScr, nil, nil
@@ -1154,6 +1317,8 @@ func (s *DuelScreen) Update(W, H int, scale float64) (screenui.ScreenName, scree
 	}
 	if s.autoPlay {
 		s.updateAutoPlay()
+	} else {
+		s.updateAutoPass()
 	}
 
 	if inpututil.IsKeyJustPressed(ebiten.KeySpace) && s.targetingCardID == uuid.Nil && !s.isChoosingAbility() {
@@ -1218,10 +1383,10 @@ func (s *DuelScreen) Update(W, H int, scale float64) (screenui.ScreenName, scree
 		if !s.questProgressApplied {
 			s.applyQuestProgress(s.lastMsg.Winner == "You")
 			s.questProgressApplied = true
-			logging.Printf(logging.Duel, "GameOver! Winner=%q Step=%q Turn=%d YouLife=%d OppLife=%d YouLib=%d OppLib=%d\n",
-				s.lastMsg.Winner, s.lastMsg.State.Step, s.lastMsg.State.Turn,
-				s.lastMsg.State.You.Life, s.lastMsg.State.Opponent.Life,
-				s.lastMsg.State.You.LibraryCount, s.lastMsg.State.Opponent.LibraryCount)
+			s.finishRecording(s.lastMsg.Winner, s.lastMsg.State.Turn)
+			logger.Debug("game over", "winner", s.lastMsg.Winner, "step", s.lastMsg.State.Step, "turn", s.lastMsg.State.Turn,
+				"you_life", s.lastMsg.State.You.Life, "opponent_life", s.lastMsg.State.Opponent.Life,
+				"you_library", s.lastMsg.State.You.LibraryCount, "opponent_library", s.lastMsg.State.Opponent.LibraryCount)
 		}
 		if !s.autoPlay && !s.lossAnimationComplete(time.Now()) {
 			return screenui.DuelScr, nil, nil
@@ -1239,14 +1404,44 @@ func (s *DuelScreen) updateAutoPlay() {
 	if s.autoResponded {
 		return
 	}
-	action, ok := s.autoPlayAction()
+	var action interactive.PriorityAction
+	var ok bool
+	s.ids.Isolate(func() { action, ok = s.autoPlayAction() })
 	if !ok {
 		return
 	}
-	select {
-	case s.human.FromTUI() <- action:
+	if s.sendAction(action) {
 		s.autoResponded = true
+	}
+}
+
+// sendAction offers pa to the game loop without blocking and records it if
+// the loop took it.
+func (s *DuelScreen) sendAction(pa interactive.PriorityAction) bool {
+	select {
+	case s.human.FromTUI() <- pa:
+		s.recorder.Add(replay.Event{Seat: replay.HumanSeat, Kind: replay.KindAction, Action: &pa})
+		return true
+	default:
+		return false
+	}
+}
+
+// sendChoice answers the pending choice request and records the answer.
+func (s *DuelScreen) sendChoice(resp interactive.ChoiceResponse) {
+	s.human.ChoiceResponses() <- resp
+	s.recorder.Add(replay.Event{Seat: replay.HumanSeat, Kind: replay.KindChoice, Choice: &resp})
+}
+
+// trySendChoice is sendChoice without blocking. It reports whether the game
+// loop took the answer.
+func (s *DuelScreen) trySendChoice(resp interactive.ChoiceResponse) bool {
+	select {
+	case s.human.ChoiceResponses() <- resp:
+		s.recorder.Add(replay.Event{Seat: replay.HumanSeat, Kind: replay.KindChoice, Choice: &resp})
+		return true
 	default:
+		return false
 	}
 }
 
@@ -1290,14 +1485,8 @@ func (s *DuelScreen) autoPlayAction() (interactive.PriorityAction, bool) {
 }
 
 func (s *DuelScreen) respondToAutoChoice() {
-	response := autoChoiceResponse(*s.choiceRequest)
-	select {
-	case s.human.ChoiceResponses() <- response:
-		s.choiceRequest = nil
-		s.choiceButtons = nil
-		s.choiceCardImg = nil
-		s.choiceCardName = ""
-	default:
+	if s.trySendChoice(autoChoiceResponse(*s.choiceRequest)) {
+		s.clearChoice()
 	}
 }
 
@@ -1812,7 +2001,7 @@ func (s *DuelScreen) handleCardClick(mx, my int) {
 	}
 	hand := handDisplayOrder(s.lastMsg.State.You.Hand)
 	if idx := s.handCardIdxAtPoint(mx, my, s.self.handX, s.self.handY, len(hand), s.self); idx >= 0 {
-		logging.Printf(logging.Duel, "HAND CLICK: idx: %d\n", idx)
+		logger.Debug("hand click", "index", idx)
 		s.selectedCardIdx = idx
 		card := hand[idx]
 		s.performCardAction(card.ID, card.Name)
@@ -1955,7 +2144,7 @@ func (s *DuelScreen) updateHoverPreview(mx, my int) {
 func (s *DuelScreen) performCardAction(id uuid.UUID, name string) {
 	actions, ok := s.cardActions[id]
 	if !ok || len(actions) == 0 {
-		logging.Printf(logging.Duel, "CLICK: %s (no action available)\n", name)
+		logger.Debug("click with no action available", "card", name)
 		return
 	}
 
@@ -1973,10 +2162,7 @@ func (s *DuelScreen) performCardAction(id uuid.UUID, name string) {
 		if tid, ok := s.autoCounterTarget(actions[0]); ok {
 			pa := actionOptionToPriorityAction(actions[0])
 			pa.Targets = []uuid.UUID{tid}
-			select {
-			case s.human.FromTUI() <- pa:
-			default:
-			}
+			s.sendAction(pa)
 			return
 		}
 		s.enterTargetingMode(id, name, actions)
@@ -1984,12 +2170,8 @@ func (s *DuelScreen) performCardAction(id uuid.UUID, name string) {
 	}
 
 	action := actions[0]
-	logging.Printf(logging.Duel, "CLICK: %s -> action=%v\n", name, action.Type)
-	pa := actionOptionToPriorityAction(action)
-	select {
-	case s.human.FromTUI() <- pa:
-	default:
-	}
+	logger.Debug("click", "card", name, "action", action.Type)
+	s.sendAction(actionOptionToPriorityAction(action))
 }
 
 func actionOptionToPriorityAction(opt interactive.ActionOption) interactive.PriorityAction {
@@ -2138,10 +2320,7 @@ func (s *DuelScreen) submitTargetingAction() bool {
 	pa := actionOptionToPriorityAction(action)
 	pa.Targets = slices.Clone(s.selectedTargetIDs)
 	pa.XValue = s.xValueForAction()
-	select {
-	case s.human.FromTUI() <- pa:
-	default:
-	}
+	s.sendAction(pa)
 	return true
 }
 
@@ -2293,14 +2472,11 @@ func (s *DuelScreen) submitPendingAndPass() {
 				damage[id] = amount
 			}
 		}
-		select {
-		case s.human.FromTUI() <- interactive.PriorityAction{
+		s.sendAction(interactive.PriorityAction{
 			Type:        interactive.ActionAssignCombatDamage,
 			Damage:      damage,
 			DamageOrder: s.damageAssignmentOrder(),
-		}:
-		default:
-		}
+		})
 		return
 	}
 
@@ -2310,13 +2486,10 @@ func (s *DuelScreen) submitPendingAndPass() {
 			attackerIDs = append(attackerIDs, id)
 		}
 		s.pendingAttackers = make(map[uuid.UUID]bool)
-		select {
-		case s.human.FromTUI() <- interactive.PriorityAction{
+		s.sendAction(interactive.PriorityAction{
 			Type:      interactive.ActionSelectAttackers,
 			Attackers: attackerIDs,
-		}:
-		default:
-		}
+		})
 		return
 	}
 
@@ -2330,20 +2503,14 @@ func (s *DuelScreen) submitPendingAndPass() {
 		}
 		s.setSelectedBlocker(uuid.Nil)
 		s.pendingBlockers = make(map[uuid.UUID]uuid.UUID)
-		select {
-		case s.human.FromTUI() <- interactive.PriorityAction{
+		s.sendAction(interactive.PriorityAction{
 			Type:     interactive.ActionSelectBlockers,
 			Blockers: blockers,
-		}:
-		default:
-		}
+		})
 		return
 	}
 
-	select {
-	case s.human.FromTUI() <- interactive.PriorityAction{Type: interactive.ActionPass}:
-	default:
-	}
+	s.sendAction(interactive.PriorityAction{Type: interactive.ActionPass})
 }
 
 func (s *DuelScreen) damageAssignmentOrder() []uuid.UUID {
@@ -2414,7 +2581,7 @@ func (s *DuelScreen) initChoiceUI() {
 
 	btnSprites, err := imageutil.LoadSpriteSheet(3, 1, assets.Tradbut1_png)
 	if err != nil {
-		logging.Printf(logging.Duel, "Error loading choice button sprites: %v\n", err)
+		logger.Error("failed to load choice button sprites", "err", err)
 		return
 	}
 
@@ -2486,39 +2653,43 @@ func (s *DuelScreen) respondToChoice(index int) {
 	switch req.Type {
 	case interactive.ChoiceMay:
 		accepted := index == 0
-		logging.Printf(logging.Duel, "CHOICE_RESP: type=May accepted=%v reason=%q\n", accepted, req.Reason)
-		s.human.ChoiceResponses() <- interactive.ChoiceResponse{Accepted: accepted}
+		logger.Debug("choice response", "type", "May", "accepted", accepted, "reason", req.Reason)
+		s.sendChoice(interactive.ChoiceResponse{Accepted: accepted})
 	case interactive.ChoiceManaColor:
 		if index < len(req.Options) {
-			logging.Printf(logging.Duel, "CHOICE_RESP: type=ManaColor color=%v reason=%q\n", req.Options[index].Color, req.Reason)
-			s.human.ChoiceResponses() <- interactive.ChoiceResponse{SelectedColor: req.Options[index].Color}
+			logger.Debug("choice response", "type", "ManaColor", "color", req.Options[index].Color, "reason", req.Reason)
+			s.sendChoice(interactive.ChoiceResponse{SelectedColor: req.Options[index].Color})
 		}
 	case interactive.ChoiceMode:
-		logging.Printf(logging.Duel, "CHOICE_RESP: type=Mode index=%d reason=%q\n", index, req.Reason)
-		s.human.ChoiceResponses() <- interactive.ChoiceResponse{SelectedIndex: index}
+		logger.Debug("choice response", "type", "Mode", "index", index, "reason", req.Reason)
+		s.sendChoice(interactive.ChoiceResponse{SelectedIndex: index})
 	case interactive.ChoicePermanent:
 		if index < len(req.Options) {
-			logging.Printf(logging.Duel, "CHOICE_RESP: type=Permanent selected=%q reason=%q\n", req.Options[index].Label, req.Reason)
-			s.human.ChoiceResponses() <- interactive.ChoiceResponse{
+			logger.Debug("choice response", "type", "Permanent", "selected", req.Options[index].Label, "reason", req.Reason)
+			s.sendChoice(interactive.ChoiceResponse{
 				SelectedIDs: []uuid.UUID{req.Options[index].ID},
-			}
+			})
 		}
 	case interactive.ChoiceCardsFromHand:
 		if index < len(req.Options) {
-			logging.Printf(logging.Duel, "CHOICE_RESP: type=CardsFromHand selected=%q reason=%q\n", req.Options[index].Label, req.Reason)
-			s.human.ChoiceResponses() <- interactive.ChoiceResponse{
+			logger.Debug("choice response", "type", "CardsFromHand", "selected", req.Options[index].Label, "reason", req.Reason)
+			s.sendChoice(interactive.ChoiceResponse{
 				SelectedIDs: []uuid.UUID{req.Options[index].ID},
-			}
+			})
 		}
 	default:
 		if index < len(req.Options) {
-			logging.Printf(logging.Duel, "CHOICE_RESP: type=%d selected=%q reason=%q\n", req.Type, req.Options[index].Label, req.Reason)
-			s.human.ChoiceResponses() <- interactive.ChoiceResponse{
+			logger.Debug("choice response", "type", int(req.Type), "selected", req.Options[index].Label, "reason", req.Reason)
+			s.sendChoice(interactive.ChoiceResponse{
 				SelectedIDs: []uuid.UUID{req.Options[index].ID},
-			}
+			})
 		}
 	}
 
+	s.clearChoice()
+}
+
+func (s *DuelScreen) clearChoice() {
 	s.choiceRequest = nil
 	s.choiceButtons = nil
 	s.choiceCardImg = nil
@@ -2613,10 +2784,13 @@ func (s *DuelScreen) handleWin() (screenui.ScreenName, screenui.Screen, error) {
 		}
 	}
 
-	logging.Printf(logging.Duel, "you just beat: %s\n", s.enemy.Name())
+	logger.Info("duel won", "opponent", s.enemy.Name())
 	s.lvl.RecordCombatWin()
 
 	reward := domain.GenerateDuelReward(s.player.GetActiveDeck(), s.enemyAnteCard, s.enemy.Character.Level, s.enemy.ColorMask())
+	if learned := s.player.LearnedCard(s.opponentDeck()); learned != nil {
+		reward.Cards = append(reward.Cards, learned)
+	}
 	for _, card := range reward.Cards {
 		s.player.CardCollection.AddCard(card, 1)
 	}
@@ -2641,8 +2815,18 @@ func (s *DuelScreen) handleWin() (screenui.ScreenName, screenui.Screen, error) {
 	}
 
 	s.lvl.RemoveEnemyAt(s.idx)
+	if s.liberating != nil {
+		s.lvl.LiberateCity(s.liberating)
+	}
 
-	return screenui.DuelWinScr, NewWinDuelScreen(s.player, reward, nil), nil
+	for _, card := range s.arenaPrize {
+		s.player.CardCollection.AddCard(card, 1)
+	}
+	winScreen := NewWinDuelScreen(s.player, reward, s.arenaPrize)
+	if d, clue, ok := s.lvl.RevealDuelClue(); ok {
+		winScreen.Clue = fmt.Sprintf("%s carried a clue to %s:\n%s", s.enemy.Name(), d.Name, clue.Text)
+	}
+	return screenui.DuelWinScr, winScreen, nil
 }
 
 func (s *DuelScreen) completeCastleVictory() []*domain.Card {
@@ -2662,11 +2846,9 @@ func (s *DuelScreen) handleLoss() (screenui.ScreenName, screenui.Screen, error)
 		return screenui.GameLoseScr, NewGameResultScreen(false), nil
 	}
 
-	if s.anteCard != nil {
-		_ = s.player.RemoveCard(s.anteCard)
-	}
 	lostCards := []*domain.Card{}
-	if s.anteCard != nil {
+	if s.anteCard != nil && !s.player.KeepsAnteOnLoss() {
+		_ = s.player.RemoveCard(s.anteCard)
 		lostCards = append(lostCards, s.anteCard)
 	}
 
@@ -2679,6 +2861,7 @@ func (s *DuelScreen) handleLoss() (screenui.ScreenName, screenui.Screen, error)
 		return screenui.DuelLoseScr, NewDuelLoseScreen(lostCards), nil
 	}
 
+	s.lvl.SiegeDuelLost(s.idx)
 	s.lvl.RemoveEnemyAt(s.idx)
 
 	return screenui.DuelLoseScr, NewDuelLoseScreen(lostCards), nil
@@ -2717,6 +2900,7 @@ func (s *DuelScreen) Draw(screen *ebiten.Image, W, H int, scale float64) {
 	if s.canCancel() {
 		s.drawCancelButton(screen, W)
 	}
+	s.drawReplayControls(screen, W, H)
 }
 
 func (s *DuelScreen) drawCancelButton(screen *ebiten.Image, W int) {
@@ -3712,7 +3896,7 @@ func (s *DuelScreen) initMulligan() {
 
 	btnSprites, err := imageutil.LoadSpriteSheet(3, 1, assets.Tradbut1_png)
 	if err != nil {
-		logging.Printf(logging.Duel, "Error loading mulligan button sprites: %v\n", err)
+		logger.Error("failed to load mulligan button sprites", "err", err)
 		return
 	}
 	fontFace := &text.GoTextFace{Source: fonts.MtgFont, Size: 16}
@@ -3789,7 +3973,7 @@ func (s *DuelScreen) doHumanMulligan() {
 		}
 	}
 	s.human.ShuffleLibrary()
-	for range 7 {
+	for range s.player.OpeningHandSize() {
 		s.human.DrawCard()
 	}
 	s.mulliganCount++
@@ -3799,8 +3983,14 @@ func (s *DuelScreen) doHumanMulligan() {
 	}
 }
 
+// mulliganBottomCount is how many cards the player must put on the bottom of
+// their library for the mulligans taken so far.
+func (s *DuelScreen) mulliganBottomCount() int {
+	return s.player.MulliganPenalty(s.mulliganCount)
+}
+
 func (s *DuelScreen) finishMulligan() {
-	if s.mulliganCount > 0 {
+	if s.mulliganBottomCount() > 0 {
 		lib := s.human.Library()
 		for id := range s.mulliganSelected {
 			if c, ok := s.human.RemoveFromHand(id); ok {
@@ -3854,7 +4044,7 @@ func (s *DuelScreen) updateMulliganUI(W, H int) {
 			id := hand[i].ID()
 			if s.mulliganSelected[id] {
 				delete(s.mulliganSelected, id)
-			} else if len(s.mulliganSelected) < s.mulliganCount {
+			} else if len(s.mulliganSelected) < s.mulliganBottomCount() {
 				s.mulliganSelected[id] = true
 			}
 			break
@@ -3866,7 +4056,7 @@ func (s *DuelScreen) updateMulliganUI(W, H int) {
 	if s.mulliganBottoming {
 		s.mulliganConfirmBtn.MoveTo((W-btnW)/2, btnY)
 		s.mulliganConfirmBtn.Update(&ebiten.DrawImageOptions{}, 1.0, W, H)
-		if s.mulliganConfirmBtn.IsClicked() && len(s.mulliganSelected) == s.mulliganCount {
+		if s.mulliganConfirmBtn.IsClicked() && len(s.mulliganSelected) == s.mulliganBottomCount() {
 			s.finishMulligan()
 		}
 	} else {
@@ -3877,7 +4067,7 @@ func (s *DuelScreen) updateMulliganUI(W, H int) {
 			s.mulliganMullBtn.Update(&ebiten.DrawImageOptions{}, 1.0, W, H)
 		}
 		if s.mulliganKeepBtn.IsClicked() {
-			if s.mulliganCount == 0 {
+			if s.mulliganBottomCount() == 0 {
 				s.finishMulligan()
 			} else {
 				s.mulliganBottoming = true
@@ -3948,7 +4138,7 @@ func (s *DuelScreen) drawMulliganUI(screen *ebiten.Image, W, H int) {
 	var title string
 	if s.mulliganBottoming {
 		title = fmt.Sprintf("Select %d card(s) to put on the bottom of your library (%d selected)",
-			s.mulliganCount, len(s.mulliganSelected))
+			s.mulliganBottomCount(), len(s.mulliganSelected))
 	} else if s.mulliganCount == 0 {
 		title = "Opening hand — Keep or Mulligan?"
 	} else {
@@ -4042,7 +4232,7 @@ func (s *DuelScreen) DuelReportState() *bugreport.DuelReportState {
 		state.OpponentName = s.enemy.Name()
 		if s.enemy.Character != nil {
 			state.OpponentRogue = s.enemy.Character.Name
-			for card, count := range s.enemy.Character.GetActiveDeck() {
+			for card, count := range s.opponentDeck() {
 				for range count {
 					state.AIDeck = append(state.AIDeck, card.CardName)
 				}
@@ -4090,6 +4280,7 @@ func (s *DuelScreen) DuelReportState() *bugreport.DuelReportState {
 			state.History = append(state.History, summary)
 		}
 	}
+	state.Replay = s.recorder.Recording()
 
 	return state
 }
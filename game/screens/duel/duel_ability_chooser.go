@@ -8,7 +8,6 @@ import (
 	"github.com/benprew/s30/game/ui/elements"
 	"github.com/benprew/s30/game/ui/fonts"
 	"github.com/benprew/s30/game/ui/imageutil"
-	"github.com/benprew/s30/logging"
 
 	"github.com/benprew/s30/assets"
 	"github.com/hajimehoshi/ebiten/v2"
@@ -22,7 +21,7 @@ func (s *DuelScreen) enterAbilityChoosingMode(actions []interactive.ActionOption
 
 	btnSprites, err := imageutil.LoadSpriteSheet(3, 1, assets.Tradbut1_png)
 	if err != nil {
-		logging.Printf(logging.Duel, "Error loading ability choice button sprites: %v\n", err)
+		logger.Error("failed to load ability choice button sprites", "err", err)
 		return
 	}
 
@@ -99,12 +98,8 @@ func (s *DuelScreen) selectAbility(index int) {
 		return
 	}
 
-	logging.Printf(logging.Duel, "CLICK: %s -> ability=%d\n", action.CardName, action.AbilityIndex)
-	pa := actionOptionToPriorityAction(action)
-	select {
-	case s.human.FromTUI() <- pa:
-	default:
-	}
+	logger.Debug("click", "card", action.CardName, "ability", action.AbilityIndex)
+	s.sendAction(actionOptionToPriorityAction(action))
 }
 
 func (s *DuelScreen) drawAbilityChoosingUI(screen *ebiten.Image, W, H int) {
@@ -14,6 +14,7 @@ import (
 	"github.com/benprew/s30/game/ui/screenui"
 	"github.com/benprew/s30/game/world"
 	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
 	"github.com/hajimehoshi/ebiten/v2/text/v2"
 )
 
@@ -30,6 +31,12 @@ type DuelAnteScreen struct {
 	idx               int
 	duelBtn           elements.Button
 	bribeBtn          elements.Button
+	sideboardBtn      elements.Button
+	deckBtn           elements.Button
+	deckBtnY          int
+	btnSprites        [][]*ebiten.Image
+	btnFont           *text.GoTextFace
+	sideboarding      *sideboardStep
 	visageBorder      []*ebiten.Image
 	playerStatsUI     []*ebiten.Image
 	player            *domain.Player
@@ -52,12 +59,18 @@ func NewDuelAnteScreenWithEnemy(l *world.Level, idx int) *DuelAnteScreen {
 		lvl:    l,
 		idx:    idx,
 	}
+	// A deck with nothing to ante can't be played, so fall back to one that can.
+	if len(l.Player.GetActiveDeck().ValidAnteCards(domain.ExcludeBasicLand)) == 0 {
+		switchToNextAnteDeck(l.Player)
+	}
 
 	btnSprites, err := imageutil.LoadSpriteSheet(3, 1, assets.Tradbut1_png)
 	if err != nil {
 		panic(fmt.Sprintf("Error loading button sprites: %v", err))
 	}
 	fontFace := &text.GoTextFace{Source: fonts.MtgFont, Size: 20}
+	s.btnSprites = btnSprites
+	s.btnFont = fontFace
 
 	duelText := "1. Duel the Enemy"
 	bribeText := fmt.Sprintf("2. Bribe for %d gold", enemy.BribeAmount())
@@ -89,6 +102,10 @@ func NewDuelAnteScreenWithEnemy(l *world.Level, idx int) *DuelAnteScreen {
 		})
 	}
 
+	s.sideboardBtn = s.anteButton("3. Sideboard", "sideboard", btnY+2*(duelH+10))
+	s.deckBtnY = btnY + 3*(duelH+10)
+	s.deckBtn = s.anteButton(s.deckButtonText(), "deck", s.deckBtnY)
+
 	s.background = loadBackgroundForEnemy(enemy)
 
 	s.playerAnteCard = selectPlayerAnteCard(l.Player.GetActiveDeck())
@@ -117,6 +134,25 @@ func NewDuelAnteScreenWithEnemy(l *world.Level, idx int) *DuelAnteScreen {
 	return s
 }
 
+// anteButton makes a button centered on the screen at y.
+func (s *DuelAnteScreen) anteButton(label, id string, y int) elements.Button {
+	w, _ := elements.TextButtonSize(label, s.btnFont)
+	return *elements.NewButtonFromConfig(elements.ButtonConfig{
+		Normal:  s.btnSprites[0][0],
+		Hover:   s.btnSprites[0][1],
+		Pressed: s.btnSprites[0][2],
+		Text:    label,
+		Font:    s.btnFont,
+		ID:      id,
+		X:       512 - w/2,
+		Y:       y,
+	})
+}
+
+func (s *DuelAnteScreen) deckButtonText() string {
+	return "4. Deck: " + s.player.DeckName(s.player.ActiveDeck)
+}
+
 func borderedVisage(visage, border *ebiten.Image) *ebiten.Image {
 	borderedVisageImg := ebiten.NewImageFromImage(border)
 	opts := &ebiten.DrawImageOptions{}
@@ -127,6 +163,13 @@ func borderedVisage(visage, border *ebiten.Image) *ebiten.Image {
 }
 
 func (s *DuelAnteScreen) Update(W, H int, scale float64) (screenui.ScreenName, screenui.Screen, error) {
+	if s.sideboarding != nil {
+		if s.sideboarding.update(scale) {
+			s.sideboarding = nil
+		}
+		return screenui.DuelAnteScr, nil, nil
+	}
+
 	if ebiten.IsKeyPressed(ebiten.Key1) {
 		return s.startDuel()
 	}
@@ -135,9 +178,24 @@ func (s *DuelAnteScreen) Update(W, H int, scale float64) (screenui.ScreenName, s
 		return s.bribe()
 	}
 
+	if inpututil.IsKeyJustPressed(ebiten.Key3) && hasSideboard(s.player) {
+		s.startSideboarding()
+		return screenui.DuelAnteScr, nil, nil
+	}
+	if inpututil.IsKeyJustPressed(ebiten.Key4) && s.player.NumDecks() > 1 {
+		s.nextDeck()
+		return screenui.DuelAnteScr, nil, nil
+	}
+
 	opts := &ebiten.DrawImageOptions{}
 	s.duelBtn.Update(opts, scale, W, H)
 	s.bribeBtn.Update(opts, scale, W, H)
+	if hasSideboard(s.player) {
+		s.sideboardBtn.Update(opts, scale, W, H)
+	}
+	if s.player.NumDecks() > 1 {
+		s.deckBtn.Update(opts, scale, W, H)
+	}
 
 	if s.duelBtn.IsClicked() {
 		return s.startDuel()
@@ -145,6 +203,12 @@ func (s *DuelAnteScreen) Update(W, H int, scale float64) (screenui.ScreenName, s
 	if s.bribeBtn.IsClicked() {
 		return s.bribe()
 	}
+	if s.sideboardBtn.IsClicked() && hasSideboard(s.player) {
+		s.startSideboarding()
+	}
+	if s.deckBtn.IsClicked() && s.player.NumDecks() > 1 {
+		s.nextDeck()
+	}
 
 	return screenui.DuelAnteScr, nil, nil
 }
@@ -198,6 +262,12 @@ func (s *DuelAnteScreen) Draw(screen *ebiten.Image, W, H int, scale float64) {
 	btnOpts := &ebiten.DrawImageOptions{}
 	s.duelBtn.Draw(screen, btnOpts, scale)
 	s.bribeBtn.Draw(screen, btnOpts, scale)
+	if hasSideboard(s.player) {
+		s.sideboardBtn.Draw(screen, btnOpts, scale)
+	}
+	if s.player.NumDecks() > 1 {
+		s.deckBtn.Draw(screen, btnOpts, scale)
+	}
 
 	// Player stats UI background in lower-left
 	if len(s.playerStatsUI) > 0 && s.playerStatsUI[0] != nil {
@@ -222,6 +292,10 @@ func (s *DuelAnteScreen) Draw(screen *ebiten.Image, W, H int, scale float64) {
 		elements.NewText(12, foodText, 160, int(statsY-5)).Draw(screen, &ebiten.DrawImageOptions{}, 1.0)
 		elements.NewText(12, cardsText, 210, int(statsY-5)).Draw(screen, &ebiten.DrawImageOptions{}, 1.0)
 	}
+
+	if s.sideboarding != nil {
+		s.sideboarding.draw(screen, scale)
+	}
 }
 
 // horizontally center src on dest
@@ -231,6 +305,44 @@ func hCenter(dest, src *ebiten.Image) float64 {
 	return float64((dw / 2) - (sw / 2))
 }
 
+// startSideboarding opens the sideboarding panel, now that the player has
+// seen their opponent.
+func (s *DuelAnteScreen) startSideboarding() {
+	if am := gameaudio.Get(); am != nil {
+		am.PlaySFX(gameaudio.SFXClick2)
+	}
+	s.sideboarding = newSideboardStep(s.player, s.playerAnteCard)
+}
+
+// nextDeck switches the player to their next deck that can put up an ante
+// and draws a new ante card from it.
+func (s *DuelAnteScreen) nextDeck() {
+	if !switchToNextAnteDeck(s.player) {
+		return
+	}
+	s.playerAnteCard = selectPlayerAnteCard(s.player.GetActiveDeck())
+	if card, err := s.playerAnteCard.CardImage(domain.CardViewFull); err == nil && card != nil {
+		s.playerAnteCardImg = imageutil.ScaleImage(card, 0.75)
+	}
+	s.deckBtn = s.anteButton(s.deckButtonText(), "deck", s.deckBtnY)
+	if am := gameaudio.Get(); am != nil {
+		am.PlaySFX(gameaudio.SFXClick2)
+	}
+}
+
+// switchToNextAnteDeck makes the player's next deck with a card to ante
+// active, and reports whether there was one.
+func switchToNextAnteDeck(player *domain.Player) bool {
+	n := player.NumDecks()
+	for step := 1; step < n; step++ {
+		i := (player.ActiveDeck + step) % n
+		if len(player.GetDeck(i).ValidAnteCards(domain.ExcludeBasicLand)) > 0 {
+			return player.SetActiveDeck(i) == nil
+		}
+	}
+	return false
+}
+
 func (s *DuelAnteScreen) startDuel() (screenui.ScreenName, screenui.Screen, error) {
 	if am := gameaudio.Get(); am != nil {
 		am.PlaySFX(gameaudio.SFXDice)
@@ -263,12 +375,12 @@ func loadBackgroundForEnemy(enemy *domain.Enemy) *ebiten.Image {
 
 	data, err := assets.DuelAnteFS.ReadFile(backgroundFile)
 	if err != nil {
-		fmt.Printf("Error loading background %s: %v\n", backgroundFile, err)
+		logger.Error("failed to load background", "file", backgroundFile, "err", err)
 	}
 
 	img, err := imageutil.LoadImage(data)
 	if err != nil {
-		fmt.Printf("Error decoding background %s: %v\n", backgroundFile, err)
+		logger.Error("failed to decode background", "file", backgroundFile, "err", err)
 	}
 	return img
 }
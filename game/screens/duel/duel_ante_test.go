@@ -76,3 +76,23 @@ func TestStartDuel_WinMoves3CardsToPlayerCollection(t *testing.T) {
 		t.Errorf("Expected player to have won cards, but collection is empty")
 	}
 }
+
+func TestSwitchToNextAnteDeckSkipsDecksWithoutAnAnte(t *testing.T) {
+	mountain := domain.FindCardByName("Mountain")
+	bolt := domain.FindCardByName("Lightning Bolt")
+	cc := domain.NewCardCollection()
+	cc.AddCardToDeck(mountain, 0, 10)
+	cc.AddCardToDeck(mountain, 1, 10)
+	cc.AddCardToDeck(bolt, 2, 1)
+	player := &domain.Player{Character: domain.Character{CardCollection: cc}}
+
+	if !switchToNextAnteDeck(player) {
+		t.Fatal("expected a deck to switch to")
+	}
+	if player.ActiveDeck != 2 {
+		t.Errorf("ActiveDeck = %d, want 2: deck 1 is all basic lands", player.ActiveDeck)
+	}
+	if switchToNextAnteDeck(player) {
+		t.Errorf("expected no other deck with an ante, switched to %d", player.ActiveDeck)
+	}
+}
@@ -1,7 +1,6 @@
 package duel
 
 import (
-	"fmt"
 	"image"
 	"image/color"
 
@@ -38,7 +37,10 @@ func NewDuelLoseScreen(cards []*domain.Card) *DuelLoseScreen {
 	}
 
 	textContent := "Lost these cards!"
-	if len(cards) == 1 {
+	switch len(cards) {
+	case 0:
+		textContent = "Your ante was spared!"
+	case 1:
 		textContent = "Lost this card!"
 	}
 	textWidth, textHeight := text.Measure(textContent, fontFace, 0)
@@ -50,8 +52,7 @@ func NewDuelLoseScreen(cards []*domain.Card) *DuelLoseScreen {
 
 	textBg, _ := imageutil.LoadImage(assets.DuelWinTextBox_png)
 	bgBounds := textBg.Bounds()
-	fmt.Println("BgBounds ", bgBounds.Dx(), bgBounds.Dy())
-	fmt.Println("TextBounds ", requiredWidth, requiredHeight)
+	logger.Debug("lose screen layout", "background", bgBounds.Size(), "text_width", requiredWidth, "text_height", requiredHeight)
 	scaleX := requiredWidth / float64(bgBounds.Dx())
 	scaleY := requiredHeight / float64(bgBounds.Dy())
 	scaledBg := imageutil.ScaleImageInd(textBg, scaleX, scaleY)
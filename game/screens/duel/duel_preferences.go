@@ -0,0 +1,48 @@
+package duel
+
+import "github.com/benprew/mage-go/pkg/mage/interactive"
+
+// MessagePacing scales how long each game message stays on screen before the
+// next one is shown: 0.5 is twice as fast, 2 twice as slow.
+var MessagePacing = 1.0
+
+// AutoPassNoPlays passes priority for the player whenever they have no spell
+// to cast or ability to activate.
+var AutoPassNoPlays bool
+
+// AutoPassOpponentTurn passes priority for the player during the opponent's
+// turn while the stack is empty. The player still gets to answer anything the
+// opponent casts.
+var AutoPassOpponentTurn bool
+
+// updateAutoPass passes priority for the player when their auto-pass
+// preferences say to.
+func (s *DuelScreen) updateAutoPass() {
+	if s.autoResponded || !shouldAutoPass(s.lastMsg, AutoPassNoPlays, AutoPassOpponentTurn) {
+		return
+	}
+	if s.sendAction(interactive.PriorityAction{Type: interactive.ActionPass}) {
+		s.autoResponded = true
+	}
+}
+
+// shouldAutoPass reports whether msg is a plain priority prompt the player's
+// auto-pass preferences answer for them. Main phase, combat and targeting
+// prompts always wait for the player.
+func shouldAutoPass(msg *interactive.GameMsg, noPlays, opponentTurn bool) bool {
+	if msg == nil || msg.GameOver || msg.State == nil || msg.Prompt != interactive.PromptPriority {
+		return false
+	}
+	if opponentTurn && msg.State.ActivePlayer != msg.State.You.Name && len(msg.State.StackItems) == 0 {
+		return true
+	}
+	if !noPlays {
+		return false
+	}
+	for _, opt := range msg.Options {
+		if opt.Type == interactive.ActionCastSpell || opt.Type == interactive.ActionActivateAbility {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,68 @@
+package duel
+
+import (
+	"testing"
+
+	"github.com/benprew/mage-go/pkg/mage/interactive"
+)
+
+func autoPassMsg(active string, options ...interactive.ActionType) *interactive.GameMsg {
+	msg := pacingMsg(active, 20, 20)
+	msg.Prompt = interactive.PromptPriority
+	msg.Options = append(msg.Options, interactive.ActionOption{Type: interactive.ActionPass})
+	for _, t := range options {
+		msg.Options = append(msg.Options, interactive.ActionOption{Type: t})
+	}
+	return msg
+}
+
+func TestShouldAutoPass_OffByDefault(t *testing.T) {
+	if shouldAutoPass(autoPassMsg("Sorceress"), false, false) {
+		t.Error("auto-passed with both preferences off")
+	}
+}
+
+func TestShouldAutoPass_NoPlays(t *testing.T) {
+	if !shouldAutoPass(autoPassMsg("You", interactive.ActionPlayLand), true, false) {
+		t.Error("didn't auto-pass with nothing to cast")
+	}
+	if shouldAutoPass(autoPassMsg("You", interactive.ActionCastSpell), true, false) {
+		t.Error("auto-passed with a spell to cast")
+	}
+	if shouldAutoPass(autoPassMsg("You", interactive.ActionActivateAbility), true, false) {
+		t.Error("auto-passed with an ability to activate")
+	}
+}
+
+func TestShouldAutoPass_OpponentTurn(t *testing.T) {
+	msg := autoPassMsg("Sorceress", interactive.ActionCastSpell)
+	if !shouldAutoPass(msg, false, true) {
+		t.Error("didn't auto-pass on the opponent's turn")
+	}
+	if shouldAutoPass(autoPassMsg("You", interactive.ActionCastSpell), false, true) {
+		t.Error("auto-passed on the player's own turn")
+	}
+	msg.State.StackItems = []interactive.StackItemState{{}}
+	if shouldAutoPass(msg, false, true) {
+		t.Error("auto-passed with the opponent's spell on the stack")
+	}
+}
+
+func TestShouldAutoPass_WaitsOnOtherPrompts(t *testing.T) {
+	state := pacingMsg("Sorceress", 20, 20).State
+	for _, msg := range []*interactive.GameMsg{
+		{State: state, Prompt: interactive.PromptMainPhaseAction},
+		{State: state, Prompt: interactive.PromptDeclareAttackers},
+		{State: state, Prompt: interactive.PromptDeclareBlockers},
+		{State: state, Prompt: interactive.PromptChooseTargets},
+	} {
+		if shouldAutoPass(msg, true, true) {
+			t.Errorf("auto-passed prompt %v", msg.Prompt)
+		}
+	}
+	over := autoPassMsg("Sorceress")
+	over.GameOver = true
+	if shouldAutoPass(over, true, true) {
+		t.Error("auto-passed after the game ended")
+	}
+}
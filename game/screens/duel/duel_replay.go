@@ -0,0 +1,315 @@
+package duel
+
+import (
+	"bytes"
+	"fmt"
+	"image/color"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	mage "github.com/benprew/mage-go/pkg/mage"
+	"github.com/benprew/mage-go/pkg/mage/interactive"
+	"github.com/benprew/mage-go/pkg/mage/interactive/ai"
+	"github.com/benprew/mage-go/pkg/mage/interactive/ai/heuristic"
+	"github.com/benprew/s30/game/domain"
+	"github.com/benprew/s30/game/replay"
+	"github.com/benprew/s30/game/save"
+	"github.com/benprew/s30/game/ui/elements"
+	"github.com/benprew/s30/game/ui/screenui"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// RecordReplays saves a replay file for every finished duel when set.
+var RecordReplays bool
+
+// replayPlayback is the state of a DuelScreen that plays back a recording
+// instead of taking input.
+type replayPlayback struct {
+	*replay.Playback
+	paused bool
+	// step shows one more message while paused.
+	step bool
+	// shown counts the game messages applied so far, and target is the count
+	// to fast-forward to after a rewind.
+	shown  int
+	target int
+	ended  bool
+}
+
+// NewReplayScreen returns a duel screen that plays rec back. Space pauses,
+// the right arrow steps one message, the left arrow steps back and Escape
+// leaves for the start screen.
+func NewReplayScreen(rec *replay.Recording) (*DuelScreen, error) {
+	return newReplayScreen(rec, 0)
+}
+
+// newReplayScreen plays rec back from the start, fast-forwarding through the
+// first skip messages. Stepping back replays from the start since the rules
+// engine can't undo.
+func newReplayScreen(rec *replay.Recording, skip int) (*DuelScreen, error) {
+	human, opponent := rec.Seats[replay.HumanSeat], rec.Seats[replay.AISeat]
+	player := &domain.Player{}
+	player.Name = human.Name
+	player.PrimaryColor = human.PrimaryColor
+	enemy := domain.NewEnemyFromCharacter(replayCharacter(opponent))
+
+	s := newDuelScreen(player, &enemy, nil, -1, nil, nil)
+	s.playback = &replayPlayback{Playback: replay.NewPlayback(rec), target: skip, paused: skip > 0}
+	if err := s.initReplayGameState(); err != nil {
+		s.ids.Uninstall()
+		return nil, err
+	}
+	s.loadImages()
+	s.placeHands()
+	s.startGameLoop()
+	return s, nil
+}
+
+// replayCharacter returns the rogue a recorded opponent was, so the duel
+// shows their art, or a bare character if the rogue no longer exists.
+func replayCharacter(seat replay.Seat) *domain.Character {
+	if c := domain.Rogues[seat.Name]; c != nil {
+		return c
+	}
+	return &domain.Character{Name: seat.Name, PrimaryColor: seat.PrimaryColor}
+}
+
+// initReplayGameState sets the game up the way initGameState did when the
+// duel was recorded, creating everything in the same order so mage-go hands
+// out the same IDs, then deals the recorded hands and libraries.
+func (s *DuelScreen) initReplayGameState() error {
+	rec := s.playback.Recording()
+	s.ids = replay.NewIDSource(rec.Seed)
+	s.ids.Install()
+
+	human, opponent := rec.Seats[replay.HumanSeat], rec.Seats[replay.AISeat]
+	s.human = interactive.NewHumanPlayer("You")
+	s.human.SetLife(human.Life)
+	s.aiPlayer = ai.NewAIPlayer(opponent.Name, s.playback.Strategy(heuristic.NewAdaptive()))
+	s.aiPlayer.SetLife(opponent.Life)
+
+	humanCards, humanAnte, err := human.CreateCards()
+	if err != nil {
+		return fmt.Errorf("replay your deck: %w", err)
+	}
+	for _, c := range humanCards {
+		s.human.AddToLibrary(c)
+	}
+	aiCards, aiAnte, err := opponent.CreateCards()
+	if err != nil {
+		return fmt.Errorf("replay %s's deck: %w", opponent.Name, err)
+	}
+	for _, c := range aiCards {
+		s.aiPlayer.AddToLibrary(c)
+	}
+
+	s.game, err = mage.NewGameWithAnte(s.human, s.aiPlayer, humanAnte, aiAnte)
+	if err != nil {
+		return fmt.Errorf("create replay duel: %w", err)
+	}
+	s.putPermanentsInPlay(s.human, seatPermanents(human))
+	s.putPermanentsInPlay(s.aiPlayer, seatPermanents(opponent))
+	human.Arrange(s.human, humanCards)
+	opponent.Arrange(s.aiPlayer, aiCards)
+
+	s.cardImageMap = buildCardImageMap(replayDeck(human), replayDeck(opponent))
+	s.self = &duelPlayer{name: "You"}
+	s.opponent = &duelPlayer{name: opponent.Name}
+	return nil
+}
+
+// seatPermanents are the cards a seat started the duel with in play.
+func seatPermanents(seat replay.Seat) []*domain.Card {
+	permanents := make([]*domain.Card, len(seat.Permanents))
+	for i, name := range seat.Permanents {
+		permanents[i] = &domain.Card{CardName: name}
+	}
+	return permanents
+}
+
+func replayDeck(seat replay.Seat) domain.Deck {
+	return deckFromNames(slices.Concat(seat.Cards, seat.Permanents))
+}
+
+func (s *DuelScreen) fastForwarding() bool {
+	return s.playback != nil && s.playback.shown < s.playback.target
+}
+
+// updateReplay runs one frame of playback: it handles the replay controls,
+// shows the next game message when it is due and answers the engine's
+// prompts with the recorded decisions.
+func (s *DuelScreen) updateReplay() (screenui.ScreenName, screenui.Screen, error) {
+	pb := s.playback
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		s.Close()
+		return screenui.StartScr, nil, nil
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyLeft) && pb.shown > 1 {
+		s.Close()
+		rewound, err := newReplayScreen(pb.Recording(), pb.shown-1)
+		if err != nil {
+			return screenui.DuelScr, nil, err
+		}
+		return screenui.DuelScr, rewound, nil
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeySpace) {
+		pb.paused = !pb.paused
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyRight) {
+		pb.paused = true
+		pb.step = true
+	}
+
+	s.pruneSpellAnimations(time.Now())
+	if s.fastForwarding() {
+		for s.fastForwarding() {
+			before := pb.shown
+			s.drainQueuedMessage()
+			if pb.shown == before {
+				break
+			}
+		}
+	} else if !pb.paused {
+		s.drainMessages()
+	} else if pb.step {
+		before := pb.shown
+		s.drainQueuedMessage()
+		if pb.shown > before {
+			pb.step = false
+		}
+	}
+	if !pb.paused || pb.step || s.fastForwarding() {
+		s.feedReplay()
+	}
+
+	if s.lastMsg != nil && s.lastMsg.State != nil && s.lastMsg.GameOver && !pb.ended {
+		pb.ended = true
+		pb.CheckResult(s.lastMsg.Winner, s.lastMsg.State.Turn)
+	}
+	return screenui.DuelScr, nil, nil
+}
+
+// drainQueuedMessage applies one waiting game message, if there is one.
+func (s *DuelScreen) drainQueuedMessage() {
+	select {
+	case msg, ok := <-s.human.ToTUI():
+		if ok {
+			s.applyGameMsg(msg)
+		}
+	default:
+	}
+}
+
+// feedReplay offers the human seat's next recorded decision to the engine.
+// Sends don't block, so a decision is only taken when the engine is waiting
+// for one, just as when it was recorded.
+func (s *DuelScreen) feedReplay() {
+	pb := s.playback
+	s.drainChoiceRequests()
+	e, ok := pb.Peek(replay.HumanSeat)
+	if s.choiceRequest != nil {
+		if !ok || e.Kind != replay.KindChoice || e.Choice == nil {
+			pb.Diverge("the engine asked you to choose (%s), the recording has no choice here", s.choiceRequest.Reason)
+			if s.trySendChoice(autoChoiceResponse(*s.choiceRequest)) {
+				s.clearChoice()
+			}
+			return
+		}
+		if s.trySendChoice(*e.Choice) {
+			pb.Advance(replay.HumanSeat)
+			s.clearChoice()
+		}
+		return
+	}
+	if !ok || e.Kind != replay.KindAction || e.Action == nil {
+		return
+	}
+	if s.sendAction(*e.Action) {
+		logger.Debug("replay", "action", e.Label())
+		pb.Advance(replay.HumanSeat)
+	}
+}
+
+// finishRecording records the result of a finished duel and, with
+// RecordReplays, saves the recording.
+func (s *DuelScreen) finishRecording(winner string, turns int) {
+	if s.recorder == nil {
+		return
+	}
+	s.recorder.Finish(winner, turns)
+	if !RecordReplays {
+		return
+	}
+	rec := s.recorder.Recording()
+	var buf bytes.Buffer
+	if err := rec.Encode(&buf); err != nil {
+		logger.Error("failed to encode replay", "err", err)
+		return
+	}
+	name := fmt.Sprintf("%s-%s%s", rec.RecordedAt.Format("20060102-150405"), replayFileSlug(rec.Seats[replay.AISeat].Name), replay.FileExt)
+	path, err := save.WriteReplayFile(name, buf.Bytes())
+	if err != nil {
+		logger.Error("failed to save duel replay", "err", err)
+		return
+	}
+	logger.Info("duel replay saved", "path", path)
+}
+
+// replayFileSlug turns an opponent's name into something safe to use in a
+// file name.
+func replayFileSlug(name string) string {
+	slug := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return '_'
+	}, name)
+	slug = strings.Trim(slug, "_")
+	if slug == "" {
+		return "duel"
+	}
+	return slug
+}
+
+// replayStatus is the line shown in the replay control bar.
+func (s *DuelScreen) replayStatus() string {
+	pb := s.playback
+	state := "Playing"
+	switch {
+	case s.fastForwarding():
+		state = "Rewinding"
+	case pb.ended:
+		state = "Finished"
+	case pb.paused:
+		state = "Paused"
+	}
+	status := fmt.Sprintf("REPLAY  %s  -  %d/%d decisions", state, pb.Played(), len(pb.Recording().Events))
+	if d := pb.Divergence(); d != "" {
+		return status + "  -  Diverged: " + d
+	}
+	if pb.ended && pb.Recording().Result != nil {
+		status += "  -  matches the recording"
+	}
+	return status
+}
+
+func (s *DuelScreen) drawReplayControls(screen *ebiten.Image, W, H int) {
+	if s.playback == nil {
+		return
+	}
+	const barH = 44
+	y := H - barH
+	vector.FillRect(screen, 0, float32(y), float32(W), barH, color.RGBA{10, 10, 20, 220}, false)
+
+	status := elements.NewText(16, s.replayStatus(), 12, y+4)
+	status.Color = color.RGBA{240, 220, 130, 255}
+	status.BoundsW = float64(W - 24)
+	status.Draw(screen, &ebiten.DrawImageOptions{}, 1.0)
+
+	help := elements.NewText(14, "Space: pause/play   Right: step   Left: step back   Esc: exit", 12, y+24)
+	help.Draw(screen, &ebiten.DrawImageOptions{}, 1.0)
+}
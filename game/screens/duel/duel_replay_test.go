@@ -0,0 +1,180 @@
+package duel
+
+import (
+	"slices"
+	"testing"
+
+	mage "github.com/benprew/mage-go/pkg/mage"
+	"github.com/benprew/mage-go/pkg/mage/interactive"
+	"github.com/benprew/s30/game/domain"
+	"github.com/benprew/s30/game/replay"
+	"github.com/benprew/s30/game/world"
+	"github.com/google/uuid"
+)
+
+func TestSendActionRecordsOnlyActionsTheGameTook(t *testing.T) {
+	human, fromTUI := newTestHuman()
+	s := &DuelScreen{human: human, recorder: replay.NewRecorder(1)}
+
+	if !s.sendAction(interactive.PriorityAction{Type: interactive.ActionPass}) {
+		t.Fatal("first action was not sent")
+	}
+	// fromTUI holds one action, so the game isn't taking another.
+	if s.sendAction(interactive.PriorityAction{Type: interactive.ActionPlayLand}) {
+		t.Fatal("second action was sent to a full channel")
+	}
+	<-fromTUI
+
+	events := s.recorder.Recording().Events
+	if len(events) != 1 || events[0].Seat != replay.HumanSeat || events[0].Action.Type != interactive.ActionPass {
+		t.Fatalf("recorded %+v, want only the pass", events)
+	}
+}
+
+func TestFeedReplaySendsTheNextRecordedAction(t *testing.T) {
+	human, fromTUI := newTestHuman()
+	rec := &replay.Recording{Events: []replay.Event{
+		{Seat: replay.AISeat, Kind: replay.KindAction, Action: &interactive.PriorityAction{Type: interactive.ActionPass}},
+		{Seat: replay.HumanSeat, Kind: replay.KindAction, Action: &interactive.PriorityAction{Type: interactive.ActionPlayLand, CardName: "Mountain"}},
+	}}
+	s := &DuelScreen{human: human, playback: &replayPlayback{Playback: replay.NewPlayback(rec)}}
+
+	s.feedReplay()
+	select {
+	case got := <-fromTUI:
+		if got.Type != interactive.ActionPlayLand || got.CardName != "Mountain" {
+			t.Errorf("sent %+v, want the recorded land drop", got)
+		}
+	default:
+		t.Fatal("no action was sent")
+	}
+	if _, ok := s.playback.Peek(replay.HumanSeat); ok {
+		t.Error("the sent action is still waiting")
+	}
+}
+
+func TestReplayDealsTheRecordedCards(t *testing.T) {
+	player, err := domain.NewPlayer("Hero", nil, false, domain.DifficultyEasy, domain.ColorGreen)
+	if err != nil {
+		t.Fatalf("NewPlayer failed: %v", err)
+	}
+	level, err := world.NewLevel(player)
+	if err != nil {
+		t.Fatalf("NewLevel failed: %v", err)
+	}
+	rogue := domain.Rogues["Sea Troll"]
+	if rogue == nil {
+		t.Skip("Sea Troll rogue not found")
+	}
+	enemy := domain.NewEnemyFromCharacter(rogue)
+
+	orig := NewDuelScreen(player, &enemy, level, 0, nil, nil)
+	orig.recorder.Deal(replay.HumanSeat, orig.human)
+	orig.recorder.Deal(replay.AISeat, orig.aiPlayer)
+	rec := orig.recorder.Recording()
+	wantHand, wantLibrary := cardIDs(orig.human.Hand()), cardIDs(orig.human.Library())
+	wantAILibrary := cardIDs(orig.aiPlayer.Library())
+	orig.Close()
+
+	r := newDuelScreen(&domain.Player{}, &enemy, nil, -1, nil, nil)
+	r.playback = &replayPlayback{Playback: replay.NewPlayback(rec)}
+	if err := r.initReplayGameState(); err != nil {
+		t.Fatalf("initReplayGameState: %v", err)
+	}
+	defer r.ids.Uninstall()
+
+	if got := cardIDs(r.human.Hand()); !sameIDs(got, wantHand) {
+		t.Errorf("replayed hand %v, want %v", got, wantHand)
+	}
+	if got := cardIDs(r.human.Library()); !sameIDs(got, wantLibrary) {
+		t.Error("replayed library differs from the recorded one")
+	}
+	if got := cardIDs(r.aiPlayer.Library()); !sameIDs(got, wantAILibrary) {
+		t.Error("replayed opponent library differs from the recorded one")
+	}
+}
+
+func TestRewindKeepsTheRecordedIDs(t *testing.T) {
+	seat := func(name string) replay.Seat {
+		return replay.Seat{
+			Name:    name,
+			Life:    20,
+			Cards:   slices.Repeat([]string{"Mountain"}, 10),
+			Hand:    []int{0, 1, 2, 3, 4, 5, 6},
+			Library: []int{7, 8, 9},
+		}
+	}
+	rec := &replay.Recording{Version: replay.Version, Seed: 42, Seats: [2]replay.Seat{seat("You"), seat("Opponent")}}
+	enemy := domain.NewEnemyFromCharacter(&domain.Character{Name: "Opponent"})
+	replayed := func() *DuelScreen {
+		t.Helper()
+		s := newDuelScreen(&domain.Player{}, &enemy, nil, -1, nil, nil)
+		s.playback = &replayPlayback{Playback: replay.NewPlayback(rec)}
+		if err := s.initReplayGameState(); err != nil {
+			t.Fatalf("initReplayGameState: %v", err)
+		}
+		return s
+	}
+
+	want := replayed()
+	wantHand, wantLibrary := cardIDs(want.human.Hand()), cardIDs(want.human.Library())
+	wantNext := uuid.New()
+	want.Close()
+
+	// Stepping back closes the playing screen and replays from the start.
+	first := replayed()
+	first.startGameLoop()
+	first.Close()
+	select {
+	case <-first.loopDone:
+	default:
+		t.Fatal("Close returned while the game loop was still running")
+	}
+	rewound := replayed()
+	defer rewound.Close()
+	// However late the first screen puts its ID source back, the rewound
+	// duel keeps its own.
+	first.ids.Uninstall()
+
+	if got := cardIDs(rewound.human.Hand()); !sameIDs(got, wantHand) {
+		t.Errorf("rewound hand %v, want %v", got, wantHand)
+	}
+	if got := cardIDs(rewound.human.Library()); !sameIDs(got, wantLibrary) {
+		t.Error("rewound library differs from the recorded one")
+	}
+	if got := uuid.New(); got != wantNext {
+		t.Errorf("next ID %v, want the recording's %v", got, wantNext)
+	}
+}
+
+func TestReplayFileSlug(t *testing.T) {
+	for name, want := range map[string]string{
+		"Sea Troll":     "sea_troll",
+		"Lord of Fate!": "lord_of_fate",
+		"???":           "duel",
+	} {
+		if got := replayFileSlug(name); got != want {
+			t.Errorf("replayFileSlug(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func cardIDs(cards []mage.Card) []uuid.UUID {
+	ids := make([]uuid.UUID, len(cards))
+	for i, c := range cards {
+		ids[i] = c.ID()
+	}
+	return ids
+}
+
+func sameIDs(a, b []uuid.UUID) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -1,8 +1,10 @@
 package duel
 
 import (
+	"slices"
 	"testing"
 
+	"github.com/benprew/mage-go/pkg/mage/interactive"
 	"github.com/benprew/s30/game/bugreport"
 	"github.com/benprew/s30/game/domain"
 	"github.com/benprew/s30/game/world"
@@ -39,4 +41,37 @@ func TestDuelScreenImplementsDuelReporter(t *testing.T) {
 	if state.GameState == nil {
 		t.Error("GameState snapshot was not generated")
 	}
+	if state.Replay == nil || len(state.Replay.Seats[1].Cards) == 0 {
+		t.Error("Replay was not attached to the report")
+	}
+}
+
+func TestNewReportSeatDealsCardsBackOut(t *testing.T) {
+	deck := []string{"Mountain", "Mountain", "Mountain", "Lightning Bolt", "Shivan Dragon", "Goblin Balloon Brigade"}
+	ps := &interactive.PlayerState{
+		Life:        7,
+		Battlefield: []interactive.PermanentState{{Name: "Mountain"}, {Name: "Mountain"}, {Name: "Goblin Token"}},
+		Hand:        []interactive.CardState{{Name: "Shivan Dragon"}},
+		Graveyard:   []interactive.CardState{{Name: "Lightning Bolt"}},
+		HandCount:   3,
+	}
+
+	seat := newReportSeat(deck, ps, 20, 7)
+	if seat.life != 7 {
+		t.Errorf("life = %d, want 7", seat.life)
+	}
+	if want := []string{"Mountain", "Mountain", "Goblin Token"}; !slices.Equal(seat.permanents, want) {
+		t.Errorf("permanents = %q, want %q", seat.permanents, want)
+	}
+	if !slices.Equal(seat.hand, []string{"Shivan Dragon"}) || seat.hidden != 2 {
+		t.Errorf("hand = %q plus %d hidden, want the dragon plus 2", seat.hand, seat.hidden)
+	}
+	if want := []string{"Mountain", "Goblin Balloon Brigade"}; !slices.Equal(seat.library, want) {
+		t.Errorf("library = %q, want %q", seat.library, want)
+	}
+
+	fresh := newReportSeat(deck, nil, 20, 7)
+	if fresh.life != 20 || fresh.hidden != 7 || len(fresh.library) != len(deck) || fresh.permanents != nil {
+		t.Errorf("without a snapshot the seat should start afresh, got %+v", fresh)
+	}
 }
@@ -0,0 +1,200 @@
+package duel
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+	"time"
+
+	mage "github.com/benprew/mage-go/pkg/mage"
+	"github.com/benprew/mage-go/pkg/mage/interactive"
+	"github.com/benprew/mage-go/pkg/mage/interactive/ai"
+	"github.com/benprew/mage-go/pkg/mage/interactive/ai/heuristic"
+	"github.com/benprew/s30/game/bugreport"
+	"github.com/benprew/s30/game/domain"
+	"github.com/benprew/s30/game/replay"
+	"github.com/benprew/s30/game/world"
+)
+
+// reportSeat is one side of a reported duel as it is dealt back out: the
+// cards in play, in hand and left in the library.
+type reportSeat struct {
+	life       int
+	permanents []string
+	hand       []string
+	// hidden is how many more cards are drawn into the hand, for an opponent
+	// whose hand the report only counts.
+	hidden  int
+	library []string
+}
+
+// newReportSeat works out where the cards of deck were when the report was
+// taken. Cards in play, in hand or in the graveyard come out of the library;
+// permanents that weren't in the deck, such as a dungeon's enchantment, are
+// put in play all the same. Without a snapshot the seat starts afresh.
+func newReportSeat(deck []string, ps *interactive.PlayerState, life, handSize int) reportSeat {
+	seat := reportSeat{life: life}
+	if ps == nil {
+		seat.library = slices.Clone(deck)
+		seat.hidden = handSize
+		return seat
+	}
+	seat.life = ps.Life
+
+	remaining := make(map[string]int)
+	for _, name := range deck {
+		remaining[name]++
+	}
+	take := func(name string) {
+		if remaining[name] > 0 {
+			remaining[name]--
+		}
+	}
+	for _, p := range ps.Battlefield {
+		seat.permanents = append(seat.permanents, p.Name)
+		take(p.Name)
+	}
+	for _, c := range ps.Hand {
+		seat.hand = append(seat.hand, c.Name)
+		take(c.Name)
+	}
+	for _, c := range ps.Graveyard {
+		take(c.Name)
+	}
+	seat.hidden = max(ps.HandCount-len(ps.Hand), 0)
+
+	for _, name := range deck {
+		if remaining[name] > 0 {
+			remaining[name]--
+			seat.library = append(seat.library, name)
+		}
+	}
+	return seat
+}
+
+// NewReportDuelScreen sets a reported duel back up against the same opponent
+// with the same decks, life totals, hands and cards in play, so the reporter's
+// situation can be played on from. The engine has no way to set the turn, so
+// play resumes on the first turn with every permanent untapped and the
+// graveyards empty.
+func NewReportDuelScreen(player *domain.Player, lvl *world.Level, state *bugreport.DuelReportState) (*DuelScreen, error) {
+	if len(state.HumanDeck) == 0 || len(state.AIDeck) == 0 {
+		return nil, fmt.Errorf("the report has no decklists")
+	}
+	enemy := domain.NewEnemyFromCharacter(reportCharacter(state))
+	s := newDuelScreen(player, &enemy, lvl, -1, domain.FindCardByName(state.AnteHumanCard), domain.FindCardByName(state.AnteAICard))
+	s.diceNotice = state.DiceNotice
+	if err := s.initReportGameState(state); err != nil {
+		s.ids.Uninstall()
+		return nil, err
+	}
+	s.loadImages()
+	s.placeHands()
+	s.startGameLoop()
+	return s, nil
+}
+
+// reportCharacter returns the rogue a reported opponent was, or a bare
+// character if the rogue no longer exists.
+func reportCharacter(state *bugreport.DuelReportState) *domain.Character {
+	if c := domain.Rogues[state.OpponentRogue]; c != nil {
+		return c
+	}
+	return &domain.Character{Name: state.OpponentName}
+}
+
+func (s *DuelScreen) initReportGameState(state *bugreport.DuelReportState) error {
+	seed := time.Now().UnixNano()
+	s.ids = replay.NewIDSource(seed)
+	s.ids.Install()
+	s.recorder = replay.NewRecorder(seed)
+	rng := rand.New(rand.NewSource(seed))
+
+	var you, opponent *interactive.PlayerState
+	if state.GameState != nil {
+		you, opponent = &state.GameState.You, &state.GameState.Opponent
+	}
+	humanDeck, aiDeck := deckFromNames(state.HumanDeck), deckFromNames(state.AIDeck)
+	humanSeat := newReportSeat(state.HumanDeck, you,
+		s.player.DuelStartingLife()+s.player.ManaLinkLife(humanDeck.Colors()), s.player.OpeningHandSize())
+	aiSeat := newReportSeat(state.AIDeck, opponent,
+		s.player.OpponentStartingLife(s.enemy.Character.Life), domain.DefaultOpeningHandSize)
+
+	s.human = interactive.NewHumanPlayer("You")
+	s.human.SetLife(humanSeat.life)
+	s.aiPlayer = ai.NewAIPlayer(s.enemy.Name(), s.recorder.Strategy(heuristic.NewAdaptive(), s.ids))
+	s.aiPlayer.SetLife(aiSeat.life)
+
+	humanRec := replay.Seat{Name: "You", PrimaryColor: s.player.PrimaryColor, Life: humanSeat.life}
+	humanCards, humanAnte, err := dealReportSeat(s.human, &humanRec, humanSeat, s.anteCard, rng)
+	if err != nil {
+		return fmt.Errorf("restore your deck: %w", err)
+	}
+	aiRec := replay.Seat{Name: s.enemy.Name(), PrimaryColor: s.enemy.Character.PrimaryColor, Life: aiSeat.life}
+	aiCards, aiAnte, err := dealReportSeat(s.aiPlayer, &aiRec, aiSeat, s.enemyAnteCard, rng)
+	if err != nil {
+		return fmt.Errorf("restore %s's deck: %w", s.enemy.Name(), err)
+	}
+
+	s.game, err = mage.NewGameWithAnte(s.human, s.aiPlayer, humanAnte, aiAnte)
+	if err != nil {
+		return fmt.Errorf("create restored duel: %w", err)
+	}
+	s.human.SetLibrary(humanCards)
+	s.aiPlayer.SetLibrary(aiCards)
+	for range len(humanSeat.hand) + humanSeat.hidden {
+		s.human.DrawCard()
+	}
+	for range len(aiSeat.hand) + aiSeat.hidden {
+		s.aiPlayer.DrawCard()
+	}
+	humanRec.Permanents = humanSeat.permanents
+	aiRec.Permanents = aiSeat.permanents
+	s.putPermanentsInPlay(s.human, seatPermanents(humanRec))
+	s.putPermanentsInPlay(s.aiPlayer, seatPermanents(aiRec))
+	s.recorder.SetSeat(replay.HumanSeat, humanRec)
+	s.recorder.SetSeat(replay.AISeat, aiRec)
+
+	s.enemyDeck = aiDeck
+	permanents := deckFromNames(slices.Concat(humanSeat.permanents, aiSeat.permanents))
+	s.cardImageMap = buildCardImageMap(humanDeck, aiDeck, permanents)
+	s.self = &duelPlayer{name: "You"}
+	s.opponent = &duelPlayer{name: s.enemy.Name()}
+	return nil
+}
+
+// dealReportSeat adds a seat's cards to p's library and returns them in the
+// order to deal: the hand first, so drawing deals it, then the rest of the
+// library shuffled. The ante is the first library card named like anteCard;
+// it is left out of the returned cards since the game sets it aside.
+func dealReportSeat(p mage.Player, rec *replay.Seat, seat reportSeat, anteCard *domain.Card, rng *rand.Rand) (cards, ante []mage.Card, err error) {
+	library := slices.Clone(seat.library)
+	rng.Shuffle(len(library), func(i, j int) { library[i], library[j] = library[j], library[i] })
+	for i, name := range slices.Concat(seat.hand, library) {
+		c, err := mage.CreateCard(name)
+		if err != nil {
+			return nil, nil, fmt.Errorf("create %s: %w", name, err)
+		}
+		p.AddToLibrary(c)
+		rec.AddCard(c)
+		if anteCard != nil && len(ante) == 0 && i >= len(seat.hand) && name == anteCard.CardName {
+			ante = []mage.Card{c}
+			rec.AddAnte(c)
+			continue
+		}
+		cards = append(cards, c)
+	}
+	return cards, ante, nil
+}
+
+// deckFromNames builds a deck from card names, skipping any that aren't in
+// the card database.
+func deckFromNames(names []string) domain.Deck {
+	deck := make(domain.Deck)
+	for _, name := range names {
+		if card := domain.FindCardByName(name); card != nil {
+			deck[card]++
+		}
+	}
+	return deck
+}
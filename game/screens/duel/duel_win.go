@@ -50,6 +50,9 @@ type DuelWinScreen struct {
 	Background   *ebiten.Image
 	ReturnScr    screenui.ScreenName
 	ReturnScreen screenui.Screen
+	// Clue is the dungeon clue the beaten enemy was carrying, if any, as
+	// a line naming the dungeon followed by the clue.
+	Clue string
 }
 
 func (s *DuelWinScreen) IsFramed() bool { return false }
@@ -227,6 +230,25 @@ func (s *DuelWinScreen) Draw(screen *ebiten.Image, W, H int, scale float64) {
 	s.doneBtn.Draw(screen, &ebiten.DrawImageOptions{}, scale)
 
 	s.drawBonus(screen)
+	s.drawClue(screen, scale)
+}
+
+// drawClue prints the clue the beaten enemy carried along the bottom of the
+// screen.
+func (s *DuelWinScreen) drawClue(screen *ebiten.Image, scale float64) {
+	if s.Clue == "" {
+		return
+	}
+	lines := strings.Split(s.Clue, "\n")
+	y := winLogicalH - 20 - 26*len(lines)
+	for _, line := range lines {
+		t := elements.NewText(18, line, 40, y)
+		t.Color = color.RGBA{R: 255, G: 230, B: 150, A: 255}
+		t.HAlign = elements.AlignCenter
+		t.BoundsW = winLogicalW - 80
+		t.Draw(screen, &ebiten.DrawImageOptions{}, scale)
+		y += 26
+	}
 }
 
 func (s *DuelWinScreen) drawBonus(screen *ebiten.Image) {
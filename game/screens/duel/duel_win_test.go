@@ -48,6 +48,31 @@ func TestHandleLossDoesNotRecordCombatWin(t *testing.T) {
 	}
 }
 
+func TestHandleLossWithRingOfTheGuardianKeepsAnte(t *testing.T) {
+	ante := domain.FindCardByName("Lightning Bolt")
+	if ante == nil {
+		t.Skip("Lightning Bolt not in card database")
+	}
+	player := &domain.Player{Character: domain.Character{CardCollection: domain.NewCardCollection()}}
+	player.CardCollection.AddCardToDeck(ante, 0, 1)
+	player.AddWorldMagic(domain.FindWorldMagic(domain.WorldMagicRingOfTheGuardian))
+	lvl := &world.Level{Enemies: []domain.Enemy{{Character: &domain.Character{
+		Name: "Regular Enemy", CardCollection: domain.NewCardCollection(),
+	}}}}
+	s := &DuelScreen{player: player, enemy: lvl.GetEnemyAt(0), lvl: lvl, idx: 0, anteCard: ante}
+
+	_, screen, err := s.handleLoss()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if player.NumCards() != 1 {
+		t.Fatalf("player has %d cards after loss, want ante kept", player.NumCards())
+	}
+	if lose, ok := screen.(*DuelLoseScreen); !ok || len(lose.cards) != 0 {
+		t.Fatalf("lose screen = %#v, want no lost cards", screen)
+	}
+}
+
 func TestHandleDungeonWinRecordsCombatWin(t *testing.T) {
 	for _, test := range []struct {
 		name string
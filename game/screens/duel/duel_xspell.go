@@ -8,7 +8,6 @@ import (
 	"github.com/benprew/s30/game/ui/elements"
 	"github.com/benprew/s30/game/ui/fonts"
 	"github.com/benprew/s30/game/ui/imageutil"
-	"github.com/benprew/s30/logging"
 
 	"github.com/benprew/s30/assets"
 	"github.com/hajimehoshi/ebiten/v2"
@@ -26,7 +25,7 @@ func (s *DuelScreen) enterXChoosingMode(actions []interactive.ActionOption) {
 
 	btnSprites, err := imageutil.LoadSpriteSheet(3, 1, assets.Tradbut1_png)
 	if err != nil {
-		logging.Printf(logging.Duel, "Error loading X choice button sprites: %v\n", err)
+		logger.Error("failed to load X choice button sprites", "err", err)
 		return
 	}
 
@@ -108,11 +107,8 @@ func (s *DuelScreen) selectXValue(xValue int) {
 	action := actions[0]
 	pa := actionOptionToPriorityAction(action)
 	pa.XValue = xValue
-	logging.Printf(logging.Duel, "CLICK: %s -> X=%d\n", action.CardName, xValue)
-	select {
-	case s.human.FromTUI() <- pa:
-	default:
-	}
+	logger.Debug("click", "card", action.CardName, "x", xValue)
+	s.sendAction(pa)
 }
 
 func (s *DuelScreen) drawXChoosingUI(screen *ebiten.Image, W, H int) {
@@ -139,6 +139,51 @@ func TestInitGameStateMovesSelectedCardsToAnte(t *testing.T) {
 	}
 }
 
+func TestDungeonNotice(t *testing.T) {
+	if got := dungeonNotice(&domain.Dungeon{}); got != "" {
+		t.Errorf("expected empty notice for a dungeon without effects, got %q", got)
+	}
+	instant := domain.CardTypeInstant
+	got := dungeonNotice(&domain.Dungeon{
+		Enchantment:     &domain.Card{CardName: "Gloom"},
+		CardRestriction: &domain.CardRestriction{ForbiddenType: &instant},
+	})
+	for _, want := range []string{"Gloom", "No Instants"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("notice %q missing %q", got, want)
+		}
+	}
+}
+
+func TestInitGameStateAppliesDungeonEffects(t *testing.T) {
+	bolt := domain.FindCardByName("Lightning Bolt")
+	player, enemy := duelTestPlayers(t, bolt, nil)
+	red := domain.ColorRed
+	dungeon := &domain.Dungeon{
+		Enchantment:     domain.FindCardByName("Crusade"),
+		CardRestriction: &domain.CardRestriction{ForbiddenColor: &red},
+	}
+	s := &DuelScreen{
+		player:  player,
+		enemy:   enemy,
+		dungeon: &dungeonDuelContext{state: &domain.DungeonState{CurrentDungeon: dungeon}},
+	}
+
+	s.initGameState()
+
+	cards := len(s.human.Library()) + len(s.human.Hand())
+	if cards != 8 {
+		t.Errorf("player has %d cards, want the 8 Mountains without the forbidden Lightning Bolt", cards)
+	}
+	bf := s.game.AllBattlefield()
+	if len(bf) != 1 || bf[0].Name() != "Crusade" {
+		t.Fatalf("battlefield = %v, want the dungeon's Crusade", bf)
+	}
+	if bf[0].ControllerID() != s.aiPlayer.PlayerID() {
+		t.Errorf("dungeon enchantment controlled by %s, want the dungeon enemy %s", bf[0].ControllerID(), s.aiPlayer.PlayerID())
+	}
+}
+
 func duelTestPlayers(t *testing.T, playerAnte, enemyAnte *domain.Card) (*domain.Player, *domain.Enemy) {
 	t.Helper()
 	playerCards := domain.NewCardCollection()
@@ -8,9 +8,12 @@ import (
 	"github.com/hajimehoshi/ebiten/v2"
 )
 
-// GameResultScreen is a placeholder ending screen for the final boss result.
+// GameResultScreen is a placeholder ending screen for the final boss result,
+// or for losing the world to Arzakon's minions.
 type GameResultScreen struct {
 	Won bool
+	// Reason is an optional line under the result explaining it.
+	Reason string
 }
 
 func NewGameResultScreen(won bool) *GameResultScreen {
@@ -42,4 +45,13 @@ func (s *GameResultScreen) Draw(screen *ebiten.Image, W, H int, scale float64) {
 	result.X = (W - int(w)) / 2
 	result.Y = (H - int(h)) / 2
 	result.Draw(screen, &ebiten.DrawImageOptions{}, scale)
+
+	if s.Reason != "" {
+		reason := elements.NewText(28, s.Reason, 0, 0)
+		reason.Color = color.RGBA{R: 220, G: 210, B: 190, A: 255}
+		rw, _ := reason.Measure()
+		reason.X = (W - int(rw)) / 2
+		reason.Y = result.Y + int(h) + 24
+		reason.Draw(screen, &ebiten.DrawImageOptions{}, scale)
+	}
 }
@@ -0,0 +1,172 @@
+package duel
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"sort"
+
+	gameaudio "github.com/benprew/s30/game/audio"
+	"github.com/benprew/s30/game/domain"
+	"github.com/benprew/s30/game/ui"
+	"github.com/benprew/s30/game/ui/elements"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// The sideboarding panel lists the deck's spells on the left and its
+// sideboard on the right; clicking a card moves one copy across.
+const (
+	sideboardPanelX    = 112
+	sideboardPanelY    = 70
+	sideboardPanelW    = 800
+	sideboardPanelH    = 640
+	sideboardRowH      = 24
+	sideboardRowsTop   = sideboardPanelY + 70
+	sideboardMaxRows   = (sideboardPanelH - 140) / sideboardRowH
+	sideboardColumnW   = sideboardPanelW/2 - 40
+	sideboardDoneW     = 140
+	sideboardDoneH     = 40
+	sideboardDoneY     = sideboardPanelY + sideboardPanelH - sideboardDoneH - 16
+	sideboardFontSize  = 18
+	sideboardTitleSize = 24
+)
+
+// sideboardRow is one card on the sideboarding panel.
+type sideboardRow struct {
+	card        *domain.Card
+	count       int
+	inSideboard bool
+	bounds      image.Rectangle
+}
+
+// sideboardStep lets the player swap cards between their active deck and its
+// sideboard between games, once they have seen what they are up against. The
+// ante card always keeps a copy in the deck.
+type sideboardStep struct {
+	player *domain.Player
+	ante   *domain.Card
+}
+
+func newSideboardStep(player *domain.Player, ante *domain.Card) *sideboardStep {
+	return &sideboardStep{player: player, ante: ante}
+}
+
+// hasSideboard reports whether the player's active deck has a sideboard to
+// swap cards with.
+func hasSideboard(player *domain.Player) bool {
+	return len(player.CardCollection.GetSideboard(player.ActiveDeck)) > 0
+}
+
+// rows returns the deck's spells and then the sideboard's cards, each sorted
+// by name and laid out in its column.
+func (s *sideboardStep) rows() []sideboardRow {
+	cc := s.player.CardCollection
+	var rows []sideboardRow
+	for col, deck := range []domain.Deck{cc.GetDeck(s.player.ActiveDeck), cc.GetSideboard(s.player.ActiveDeck)} {
+		cards := make([]*domain.Card, 0, len(deck))
+		for card := range deck {
+			if col == 1 || card.CardType != domain.CardTypeLand {
+				cards = append(cards, card)
+			}
+		}
+		sort.Slice(cards, func(i, j int) bool { return cards[i].CardName < cards[j].CardName })
+		x := sideboardPanelX + 20 + col*sideboardPanelW/2
+		for i, card := range cards[:min(len(cards), sideboardMaxRows)] {
+			y := sideboardRowsTop + i*sideboardRowH
+			rows = append(rows, sideboardRow{
+				card:        card,
+				count:       deck[card],
+				inSideboard: col == 1,
+				bounds:      image.Rect(x, y, x+sideboardColumnW, y+sideboardRowH),
+			})
+		}
+	}
+	return rows
+}
+
+// swap moves one copy of row's card to the other side. The last copy of the
+// ante card stays in the deck.
+func (s *sideboardStep) swap(row sideboardRow) bool {
+	cc := s.player.CardCollection
+	deck := s.player.ActiveDeck
+	var err error
+	if row.inSideboard {
+		err = cc.SideboardIn(row.card, deck, 1)
+	} else {
+		if s.ante != nil && row.card == s.ante && cc.GetDeckCount(row.card, deck) <= 1 {
+			return false
+		}
+		err = cc.SideboardOut(row.card, deck, 1)
+	}
+	if err != nil {
+		logger.Error("failed to sideboard", "card", row.card.Name(), "err", err)
+		return false
+	}
+	return true
+}
+
+func sideboardDoneBounds() image.Rectangle {
+	x := sideboardPanelX + (sideboardPanelW-sideboardDoneW)/2
+	return image.Rect(x, sideboardDoneY, x+sideboardDoneW, sideboardDoneY+sideboardDoneH)
+}
+
+// update handles a frame of input and reports whether the player is done.
+func (s *sideboardStep) update(scale float64) bool {
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) || inpututil.IsKeyJustPressed(ebiten.KeyEnter) ||
+		ui.Click(scaleRect(sideboardDoneBounds(), scale)) {
+		return true
+	}
+	for _, row := range s.rows() {
+		if ui.Click(scaleRect(row.bounds, scale)) && s.swap(row) {
+			if am := gameaudio.Get(); am != nil {
+				am.PlaySFX(gameaudio.SFXClick2)
+			}
+			break
+		}
+	}
+	return false
+}
+
+func (s *sideboardStep) draw(screen *ebiten.Image, scale float64) {
+	f := float32(scale)
+	vector.FillRect(screen, sideboardPanelX*f, sideboardPanelY*f, sideboardPanelW*f, sideboardPanelH*f, color.RGBA{20, 12, 4, 235}, false)
+	vector.StrokeRect(screen, sideboardPanelX*f, sideboardPanelY*f, sideboardPanelW*f, sideboardPanelH*f, 2, color.RGBA{210, 190, 170, 255}, false)
+
+	deckSize := s.player.CardCollection.GetDeck(s.player.ActiveDeck).Size()
+	headings := []string{fmt.Sprintf("Deck (%d cards)", deckSize), "Sideboard"}
+	for col, heading := range headings {
+		t := elements.NewText(sideboardTitleSize, heading, sideboardPanelX+20+col*sideboardPanelW/2, sideboardPanelY+20)
+		t.Color = color.White
+		t.Draw(screen, &ebiten.DrawImageOptions{}, scale)
+	}
+
+	pos := ui.Position()
+	for _, row := range s.rows() {
+		clr := color.Color(color.RGBA{230, 220, 200, 255})
+		if pos.In(scaleRect(row.bounds, scale)) {
+			clr = color.RGBA{255, 215, 90, 255}
+		}
+		t := elements.NewText(sideboardFontSize, fmt.Sprintf("%d  %s", row.count, row.card.Name()), row.bounds.Min.X, row.bounds.Min.Y)
+		t.Color = clr
+		t.Draw(screen, &ebiten.DrawImageOptions{}, scale)
+	}
+
+	b := sideboardDoneBounds()
+	vector.FillRect(screen, float32(b.Min.X)*f, float32(b.Min.Y)*f, float32(b.Dx())*f, float32(b.Dy())*f, color.RGBA{55, 45, 35, 240}, false)
+	done := elements.NewText(sideboardFontSize, "Done", b.Min.X, b.Min.Y)
+	done.BoundsW = float64(b.Dx())
+	done.BoundsH = float64(b.Dy())
+	done.HAlign = elements.AlignCenter
+	done.VAlign = elements.AlignMiddle
+	done.Color = color.White
+	done.Draw(screen, &ebiten.DrawImageOptions{}, scale)
+}
+
+func scaleRect(r image.Rectangle, scale float64) image.Rectangle {
+	return image.Rect(
+		int(float64(r.Min.X)*scale), int(float64(r.Min.Y)*scale),
+		int(float64(r.Max.X)*scale), int(float64(r.Max.Y)*scale),
+	)
+}
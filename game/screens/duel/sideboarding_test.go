@@ -0,0 +1,71 @@
+package duel
+
+import (
+	"testing"
+
+	"github.com/benprew/s30/game/domain"
+)
+
+func sideboardingPlayer(t *testing.T) (*domain.Player, *domain.Card, *domain.Card) {
+	t.Helper()
+	bolt := domain.FindCardByName("Lightning Bolt")
+	blast := domain.FindCardByName("Red Elemental Blast")
+	mountain := domain.FindCardByName("Mountain")
+	if bolt == nil || blast == nil || mountain == nil {
+		t.Skip("test cards not in the card database")
+	}
+	cc := domain.NewCardCollection()
+	cc.AddCardToDeck(mountain, 0, 10)
+	cc.AddCardToDeck(bolt, 0, 1)
+	cc.AddCardToSideboard(blast, 0, 2)
+	return &domain.Player{Character: domain.Character{CardCollection: cc}}, bolt, blast
+}
+
+func TestSideboardStepSwapsCards(t *testing.T) {
+	player, bolt, blast := sideboardingPlayer(t)
+	step := newSideboardStep(player, nil)
+
+	if !hasSideboard(player) {
+		t.Fatal("expected the player to have a sideboard")
+	}
+	rows := step.rows()
+	if len(rows) != 2 {
+		t.Fatalf("expected the bolt and the sideboard's blasts, got %d rows", len(rows))
+	}
+	if rows[0].card != bolt || rows[0].inSideboard {
+		t.Errorf("expected Lightning Bolt in the deck column first, got %+v", rows[0])
+	}
+	if rows[1].card != blast || !rows[1].inSideboard || rows[1].count != 2 {
+		t.Errorf("expected 2 Red Elemental Blasts in the sideboard column, got %+v", rows[1])
+	}
+
+	if !step.swap(rows[1]) {
+		t.Fatal("expected the blast to move into the deck")
+	}
+	cc := player.CardCollection
+	if got := cc.GetDeckCount(blast, 0); got != 1 {
+		t.Errorf("expected 1 blast in the deck, got %d", got)
+	}
+	if got := cc.GetSideboardCount(blast, 0); got != 1 {
+		t.Errorf("expected 1 blast left in the sideboard, got %d", got)
+	}
+
+	if !step.swap(rows[0]) {
+		t.Fatal("expected the bolt to move to the sideboard")
+	}
+	if got := cc.GetSideboardCount(bolt, 0); got != 1 {
+		t.Errorf("expected the bolt in the sideboard, got %d", got)
+	}
+}
+
+func TestSideboardStepKeepsTheAnteCard(t *testing.T) {
+	player, bolt, _ := sideboardingPlayer(t)
+	step := newSideboardStep(player, bolt)
+
+	if step.swap(sideboardRow{card: bolt}) {
+		t.Error("expected the last copy of the ante card to stay in the deck")
+	}
+	if got := player.CardCollection.GetDeckCount(bolt, 0); got != 1 {
+		t.Errorf("expected the bolt still in the deck, got %d", got)
+	}
+}
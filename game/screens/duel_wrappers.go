@@ -1,7 +1,9 @@
 package screens
 
 import (
+	"github.com/benprew/s30/game/bugreport"
 	"github.com/benprew/s30/game/domain"
+	"github.com/benprew/s30/game/replay"
 	duelscreen "github.com/benprew/s30/game/screens/duel"
 	"github.com/benprew/s30/game/world"
 )
@@ -10,6 +12,7 @@ type DuelScreen = duelscreen.DuelScreen
 type DuelAnteScreen = duelscreen.DuelAnteScreen
 type DuelWinScreen = duelscreen.DuelWinScreen
 type DuelLoseScreen = duelscreen.DuelLoseScreen
+type GameResultScreen = duelscreen.GameResultScreen
 
 func NewDuelScreen(player *domain.Player, enemy *domain.Enemy, lvl *world.Level, idx int, anteCard *domain.Card, enemyAnteCard *domain.Card) *DuelScreen {
 	return duelscreen.NewDuelScreen(player, enemy, lvl, idx, anteCard, enemyAnteCard)
@@ -19,6 +22,22 @@ func NewDungeonDuelScreen(player *domain.Player, enemy *domain.Enemy, level *wor
 	return duelscreen.NewDungeonDuelScreen(player, enemy, level, state, tile)
 }
 
+func NewArenaDuelScreen(player *domain.Player, enemy *domain.Enemy, level *world.Level, prize []*domain.Card) *DuelScreen {
+	return duelscreen.NewArenaDuelScreen(player, enemy, level, prize)
+}
+
+func NewLiberationDuelScreen(player *domain.Player, enemy *domain.Enemy, level *world.Level, city *domain.City) *DuelScreen {
+	return duelscreen.NewLiberationDuelScreen(player, enemy, level, city)
+}
+
+func NewReplayScreen(rec *replay.Recording) (*DuelScreen, error) {
+	return duelscreen.NewReplayScreen(rec)
+}
+
+func NewReportDuelScreen(player *domain.Player, lvl *world.Level, state *bugreport.DuelReportState) (*DuelScreen, error) {
+	return duelscreen.NewReportDuelScreen(player, lvl, state)
+}
+
 func NewDuelAnteScreen() *DuelAnteScreen {
 	return duelscreen.NewDuelAnteScreen()
 }
@@ -38,3 +57,7 @@ func NewWinDuelScreenFromCards(player *domain.Player, cards []*domain.Card, bonu
 func NewDuelLoseScreen(cards []*domain.Card) *DuelLoseScreen {
 	return duelscreen.NewDuelLoseScreen(cards)
 }
+
+func NewGameResultScreen(won bool) *GameResultScreen {
+	return duelscreen.NewGameResultScreen(won)
+}
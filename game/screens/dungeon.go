@@ -5,6 +5,8 @@ import (
 	"image"
 	"image/color"
 	"math/rand"
+	"strconv"
+	"strings"
 
 	"github.com/benprew/s30/assets"
 	gameaudio "github.com/benprew/s30/game/audio"
@@ -80,6 +82,9 @@ type DungeonScreen struct {
 	overlayBtns   []*elements.Button
 	overlayTitle  string
 	overlayBody   string
+	// overlayPanelH is the overlay panel's height when it needs more room
+	// than the default, as the scroll's answer buttons do.
+	overlayPanelH int
 	ambientTicks  int
 }
 
@@ -245,6 +250,10 @@ func (s *DungeonScreen) Update(W, H int, scale float64) (screenui.ScreenName, sc
 	if tile.Type == domain.DungeonTileDice && tile.Dice != nil {
 		s.openDiceOverlay(target)
 	}
+
+	if tile.Type == domain.DungeonTileScroll {
+		s.openScrollOverlay(target)
+	}
 	return screenui.DungeonScr, nil, nil
 }
 
@@ -292,6 +301,68 @@ func (s *DungeonScreen) openDiceOverlay(p image.Point) {
 	s.overlayBtns = []*elements.Button{makeOverlayButton("Continue", buttonIDLeave, 512)}
 }
 
+// scrollPanelH is the height of the overlay panel that holds a scroll's
+// riddle and its answers.
+const scrollPanelH = 400
+
+// scrollAnswerPrefix starts the IDs of the scroll overlay's answer buttons;
+// the rest of the ID is the index of the choice.
+const scrollAnswerPrefix = "answer-"
+
+// openScrollOverlay shows the riddle on the scroll at p with a button for each
+// answer. Escape leaves the scroll unread for later.
+func (s *DungeonScreen) openScrollOverlay(p image.Point) {
+	tile := s.dungeonState().CurrentDungeon.Tile(p)
+	if tile.Scroll == nil || len(tile.Scroll.Choices) == 0 {
+		// Scrolls in dungeons generated before riddles existed are blank.
+		tile.Scroll = domain.RandomScrollQuestion()
+	}
+	q := tile.Scroll
+	s.overlayActive = true
+	s.overlayTile = p
+	s.overlayTitle = "An Ancient Scroll"
+	s.overlayBody = q.Question
+	s.overlayPanelH = scrollPanelH
+	s.overlayBtns = nil
+	y := (768-scrollPanelH)/2 + 176
+	for i, choice := range q.Choices {
+		b := makeOverlayButton(choice, fmt.Sprintf("%s%d", scrollAnswerPrefix, i), 512)
+		b.MoveTo(b.Bounds.Min.X, y)
+		s.overlayBtns = append(s.overlayBtns, b)
+		y += 46
+	}
+}
+
+// answerScroll resolves the player's choice on the open scroll and replaces
+// the riddle with what the answer earned or cost them.
+func (s *DungeonScreen) answerScroll(choice int) {
+	st := s.dungeonState()
+	from := st.CurrentDungeon.MapTile
+	canClue := s.Level != nil && s.Level.HasDungeonClues(from)
+	out, ok := st.AnswerScroll(st.CurrentDungeon.Tile(s.overlayTile), choice, s.Player, canClue)
+	if !ok {
+		s.closeOverlay()
+		return
+	}
+
+	body := fmt.Sprintf("Wrong! The answer was %s.", out.Answer)
+	if out.Correct {
+		body = "Correct!"
+	}
+	switch {
+	case out.Clue:
+		if d, clue, found := s.Level.RevealDungeonClue(from); found {
+			body += fmt.Sprintf(" The scroll holds a clue to %s: %s", d.Name, clue.Text)
+		}
+	case out.Effect != nil:
+		body += " " + domain.DescribeDiceEffect(out.Effect) + "."
+	}
+	s.overlayTitle = "The Scroll Crumbles"
+	s.overlayBody = body
+	s.overlayPanelH = 0
+	s.overlayBtns = []*elements.Button{makeOverlayButton("Continue", buttonIDLeave, 512)}
+}
+
 func (s *DungeonScreen) updateOverlay(W, H int, scale float64) (screenui.ScreenName, screenui.Screen, error) {
 	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
 		s.closeOverlay()
@@ -307,6 +378,11 @@ func (s *DungeonScreen) updateOverlay(W, H int, scale float64) (screenui.ScreenN
 		if b.ID == "take" {
 			s.collectCurrentReward()
 		}
+		if choice, ok := strings.CutPrefix(b.ID, scrollAnswerPrefix); ok {
+			i, _ := strconv.Atoi(choice)
+			s.answerScroll(i)
+			return screenui.DungeonScr, nil, nil
+		}
 		s.closeOverlay()
 		return screenui.DungeonScr, nil, nil
 	}
@@ -326,6 +402,7 @@ func (s *DungeonScreen) closeOverlay() {
 	s.overlayBtns = nil
 	s.overlayTitle = ""
 	s.overlayBody = ""
+	s.overlayPanelH = 0
 }
 
 func (s *DungeonScreen) Draw(screen *ebiten.Image, W, H int, scale float64) {
@@ -611,6 +688,9 @@ func (s *DungeonScreen) drawOverlay(screen *ebiten.Image, scale float64) {
 	screen.DrawImage(dim, &ebiten.DrawImageOptions{})
 
 	panelW, panelH := 520, 280
+	if s.overlayPanelH > 0 {
+		panelH = s.overlayPanelH
+	}
 	px := (1024 - panelW) / 2
 	py := (768 - panelH) / 2
 	panel := ebiten.NewImage(panelW, panelH)
@@ -623,7 +703,8 @@ func (s *DungeonScreen) drawOverlay(screen *ebiten.Image, scale float64) {
 	title.Color = color.White
 	title.Draw(screen, &ebiten.DrawImageOptions{}, scale)
 
-	body := elements.NewText(20, s.overlayBody, px+30, py+90)
+	face := &text.GoTextFace{Source: fonts.MtgFont, Size: 20}
+	body := elements.NewText(20, strings.Join(wrapText(s.overlayBody, face, float64(panelW-60)), "\n"), px+30, py+90)
 	body.Color = color.RGBA{R: 230, G: 230, B: 240, A: 255}
 	body.Draw(screen, &ebiten.DrawImageOptions{}, scale)
 
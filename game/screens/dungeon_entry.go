@@ -87,6 +87,34 @@ func (s *DungeonEntryScreen) Update(W, H int, scale float64) (screenui.ScreenNam
 	return screenui.DungeonEntryScr, nil, nil
 }
 
+// effectLines warn the player, before they commit to entering, about the
+// enchantment in play during every duel inside and the cards the dungeon
+// takes out of their deck.
+func (s *DungeonEntryScreen) effectLines() []string {
+	var lines []string
+	if s.Dungeon.Enchantment != nil {
+		lines = append(lines, fmt.Sprintf("%s is in play during every duel here.", s.Dungeon.Enchantment.CardName))
+	}
+	if s.Dungeon.CardRestriction == nil {
+		return lines
+	}
+	_, removed := s.Dungeon.DuelDeck(s.Player)
+	n := 0
+	for _, count := range removed {
+		n += count
+	}
+	line := fmt.Sprintf("%s may be played here.", s.Dungeon.CardRestriction)
+	switch n {
+	case 0:
+		line += " Your deck is unaffected."
+	case 1:
+		line += " 1 card will be left out of your deck."
+	default:
+		line += fmt.Sprintf(" %d cards will be left out of your deck.", n)
+	}
+	return append(lines, line)
+}
+
 func (s *DungeonEntryScreen) Draw(screen *ebiten.Image, W, H int, scale float64) {
 	screen.Fill(color.RGBA{R: 12, G: 8, B: 20, A: 255})
 
@@ -98,7 +126,15 @@ func (s *DungeonEntryScreen) Draw(screen *ebiten.Image, W, H int, scale float64)
 	subtitle.Color = color.RGBA{R: 200, G: 200, B: 220, A: 255}
 	subtitle.Draw(screen, &ebiten.DrawImageOptions{}, scale)
 
-	clueY := 220
+	warnY := 180
+	for _, line := range s.effectLines() {
+		t := elements.NewText(20, line, 50, warnY)
+		t.Color = color.RGBA{R: 240, G: 200, B: 120, A: 255}
+		t.Draw(screen, &ebiten.DrawImageOptions{}, scale)
+		warnY += 30
+	}
+
+	clueY := max(220, warnY+20)
 	heading := elements.NewText(24, "Known clues:", 50, clueY)
 	heading.Color = color.White
 	heading.Draw(screen, &ebiten.DrawImageOptions{}, scale)
@@ -0,0 +1,34 @@
+package screens
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/benprew/s30/game/domain"
+)
+
+func TestDungeonEntryWarnsAboutEffects(t *testing.T) {
+	red := domain.ColorRed
+	player := &domain.Player{Character: domain.Character{CardCollection: domain.NewCardCollection()}}
+	player.CardCollection.AddCardToDeck(domain.FindCardByName("Lightning Bolt"), 0, 3)
+	player.CardCollection.AddCardToDeck(domain.FindCardByName("Forest"), 0, 10)
+	dungeon := &domain.Dungeon{
+		Enchantment:     domain.FindCardByName("Gloom"),
+		CardRestriction: &domain.CardRestriction{ForbiddenColor: &red},
+	}
+	s := &DungeonEntryScreen{Dungeon: dungeon, Player: player}
+
+	lines := strings.Join(s.effectLines(), "\n")
+	for _, want := range []string{"Gloom", "No Red cards", "3 cards will be left out"} {
+		if !strings.Contains(lines, want) {
+			t.Errorf("entry warning %q doesn't mention %q", lines, want)
+		}
+	}
+}
+
+func TestDungeonEntryWithoutEffectsHasNoWarning(t *testing.T) {
+	s := &DungeonEntryScreen{Dungeon: &domain.Dungeon{}, Player: &domain.Player{}}
+	if lines := s.effectLines(); len(lines) != 0 {
+		t.Errorf("effectLines = %q, want none", lines)
+	}
+}
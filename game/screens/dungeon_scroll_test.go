@@ -0,0 +1,62 @@
+package screens
+
+import (
+	"image"
+	"strings"
+	"testing"
+
+	"github.com/benprew/s30/game/domain"
+)
+
+func TestOpenScrollOverlayOffersEachChoice(t *testing.T) {
+	_, s := dungeonWithTile(domain.DungeonTile{
+		Type:   domain.DungeonTileScroll,
+		Scroll: &domain.ScrollQuestion{Question: "What color is Serra Angel?", Choices: []string{"Blue", "White", "Green"}, Answer: 1},
+	})
+
+	s.openScrollOverlay(image.Point{})
+
+	if !s.overlayActive || s.overlayBody != "What color is Serra Angel?" {
+		t.Fatalf("overlay = %v %q, want the riddle", s.overlayActive, s.overlayBody)
+	}
+	if len(s.overlayBtns) != 3 {
+		t.Fatalf("got %d buttons, want one per choice", len(s.overlayBtns))
+	}
+	if s.overlayBtns[0].Bounds.Min.Y >= s.overlayBtns[1].Bounds.Min.Y {
+		t.Error("answer buttons aren't stacked")
+	}
+}
+
+func TestAnswerScrollShowsTheOutcome(t *testing.T) {
+	p, s := dungeonWithTile(domain.DungeonTile{
+		Type:   domain.DungeonTileScroll,
+		Scroll: &domain.ScrollQuestion{Question: "What color is Serra Angel?", Choices: []string{"Blue", "White"}, Answer: 1},
+	})
+	s.openScrollOverlay(image.Point{})
+
+	s.answerScroll(0)
+
+	if !strings.Contains(s.overlayBody, "White") {
+		t.Errorf("outcome %q doesn't give the right answer", s.overlayBody)
+	}
+	if p.BonusDuelLife >= 0 {
+		t.Errorf("BonusDuelLife = %d, want a penalty", p.BonusDuelLife)
+	}
+	if len(s.overlayBtns) != 1 || s.overlayBtns[0].ID != buttonIDLeave {
+		t.Errorf("outcome overlay should only offer Continue, got %d buttons", len(s.overlayBtns))
+	}
+	if tile := p.DungeonState.CurrentDungeon.Tile(image.Point{}); tile.Type != domain.DungeonTileEmpty {
+		t.Errorf("scroll tile not cleared, got %v", tile.Type)
+	}
+}
+
+func TestBlankScrollGetsARiddle(t *testing.T) {
+	p, s := dungeonWithTile(domain.DungeonTile{Type: domain.DungeonTileScroll, Scroll: &domain.ScrollQuestion{}})
+
+	s.openScrollOverlay(image.Point{})
+
+	q := p.DungeonState.CurrentDungeon.Tile(image.Point{}).Scroll
+	if q == nil || q.Question == "" || len(s.overlayBtns) != len(q.Choices) {
+		t.Fatalf("blank scroll wasn't given a riddle: %+v", q)
+	}
+}
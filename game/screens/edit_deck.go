@@ -54,8 +54,14 @@ type EditDeckScreen struct {
 	collectionGroups     map[string]*cardGroup    // Map card name to group
 	hoveredCollectionIdx int                      // Index of hovered collection card (-1 if none)
 	hoveredDeckIdx       int                      // Index of hovered deck card (-1 if none)
+	sideboard            sideboardPane            // The active deck's sideboard, below the deck
 	filter               collectionFilter         // Active color/type filters for the collection
 	filterButtons        []*filterButton          // Sprite-sheet toggle buttons for the filter
+	search               *cardSearch              // Card query box beside the filter toggles
+	importFiles          []string                 // Deck files offered by the open import picker (nil when closed)
+	decks                deckManager              // Deck picker for switching, creating and deleting decks
+	violations           []domain.FormatViolation // Rules the active deck breaks in the chosen format
+	deckStatus           string                   // Result of the last deck import or export
 }
 
 type DeckCardDisplay struct {
@@ -109,6 +115,11 @@ func NewEditDeckScreen(player *domain.Player, city *domain.City, W, H int) (*Edi
 		hoveredCollectionIdx: -1,
 		hoveredDeckIdx:       -1,
 		filter:               newCollectionFilter(),
+		search:               newCardSearch(0, 0, editDeckSearchW, filterBtnSize),
+		sideboard:            newSideboardPane(),
+	}
+	if city != nil {
+		city.OpenMarket(player.Days)
 	}
 
 	filterButtons, err := createFilterButtons()
@@ -152,12 +163,19 @@ func NewEditDeckScreen(player *domain.Player, city *domain.City, W, H int) (*Edi
 	screen.dragManager.RegisterDroppable(screen.sellDropArea)
 
 	screen.deckDropArea = dragdrop.NewDropArea(
-		deckAreaBounds,
+		editDeckMainBounds(deckAreaBounds),
 		[]string{"*"}, // Accept any card
 		screen.handleCardDrop,
 	)
 	screen.dragManager.RegisterDroppable(screen.deckDropArea)
 
+	screen.sideboard.dropArea = dragdrop.NewDropArea(
+		editDeckSideboardBounds(deckAreaBounds),
+		[]string{"*"},
+		screen.handleCardDropToSideboard,
+	)
+	screen.dragManager.RegisterDroppable(screen.sideboard.dropArea)
+
 	// Create collection area drop zone
 	collectionY := H - COLLECTION_HEIGHT
 	collectionAreaBounds := image.Rect(0, collectionY, COLLECTION_WIDTH, H)
@@ -196,10 +214,9 @@ func (s *EditDeckScreen) createCollectionButtons() ([]*elements.Button, error) {
 
 	// Group cards by name and calculate available count
 	for card, item := range s.Player.CardCollection {
-		if item.Count > 0 && s.filter.matches(card) {
+		if item.Count > 0 && s.filter.matches(card) && s.search.matches(card) {
 			cardName := card.Name()
-			deckCount := s.Player.CardCollection.GetDeckCount(card, s.Player.ActiveDeck)
-			availableCount := item.Count - deckCount
+			availableCount := s.Player.CardCollection.GetAvailableCount(card)
 
 			if availableCount > 0 {
 				if group, exists := s.collectionGroups[cardName]; exists {
@@ -232,7 +249,7 @@ func (s *EditDeckScreen) createCollectionButtons() ([]*elements.Button, error) {
 		representativeCard := group.cards[0]
 		cardImg, err := representativeCard.CardImage(domain.CardViewArtOnly)
 		if err != nil {
-			fmt.Printf("WARN: Unable to load card image for %s: %v\n", group.name, err)
+			logger.Warn("unable to load card image", "card", group.name, "err", err)
 			continue
 		}
 
@@ -285,6 +302,7 @@ func (s *EditDeckScreen) Draw(screen *ebiten.Image, W, H int, scale float64) {
 
 	screen.DrawImage(s.DeckBackground, &dckOpts)
 	s.deckDropArea.Draw(screen)
+	s.drawSideboard(screen, scale)
 
 	// Calculate position for collection list at bottom of screen
 	collectionY := H - COLLECTION_HEIGHT
@@ -300,6 +318,7 @@ func (s *EditDeckScreen) Draw(screen *ebiten.Image, W, H int, scale float64) {
 	s.drawDeckCards(screen, scale)
 	s.drawDeckStats(screen, scale)
 	s.drawFilterButtons(screen, scale)
+	s.search.draw(screen)
 
 	// Draw the magnifier image if it exists
 	if s.MagnifierImage != nil {
@@ -314,7 +333,7 @@ func (s *EditDeckScreen) Draw(screen *ebiten.Image, W, H int, scale float64) {
 		screen.DrawImage(s.MagnifierImage, magOpts)
 
 		if s.MagnifiedCard != nil {
-			salePrice := s.MagnifiedCard.SalePrice(s.City)
+			salePrice := s.City.SellPrice(s.MagnifiedCard)
 			priceText := fmt.Sprintf("Sale Price: %d gold", salePrice)
 			textX := magX + 10
 			textY := magY - 25
@@ -343,11 +362,14 @@ func (s *EditDeckScreen) Draw(screen *ebiten.Image, W, H int, scale float64) {
 	screen.DrawImage(deckOutline, deckOpts)
 
 	// Draw helper text above collection area
-	helpText := "[A] Add to Deck\n[D] Remove from Deck\n[S] Sell Card"
+	helpText := "[A] Add to Deck\n[D] Remove from Deck\n[B] Move to Sideboard\n[S] Sell Card"
 	helpY := float64(collectionY) - 130
 	helpOpts := &ebiten.DrawImageOptions{}
 	elements.NewText(14, helpText, int(10*scale), int(helpY*scale)).Draw(screen, helpOpts, 1.0)
 	drawDeckActionButton(screen, editDeckBackBounds(W), "Back")
+	s.drawDeckIO(screen, W, H)
+	s.drawDeckFormat(screen)
+	s.drawDeckManager(screen, W, H)
 
 	// Draw drag image if dragging
 	s.dragManager.Draw(screen)
@@ -377,6 +399,17 @@ func (s *EditDeckScreen) drawDeckCards(screen *ebiten.Image, scale float64) {
 
 // Update handles user interactions
 func (s *EditDeckScreen) Update(W, H int, scale float64) (screenui.ScreenName, screenui.Screen, error) {
+	if s.updateDeckManager(W) {
+		return screenui.EditDeckScr, nil, nil
+	}
+	if s.importFiles != nil && inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		s.importFiles = nil
+		return screenui.EditDeckScr, nil, nil
+	}
+	if s.updateDeckIO(W) || s.updateDeckFormat() {
+		return screenui.EditDeckScr, nil, nil
+	}
+
 	// Calculate position for collection list
 	collectionY := H - COLLECTION_HEIGHT
 
@@ -388,21 +421,25 @@ func (s *EditDeckScreen) Update(W, H int, scale float64) (screenui.ScreenName, s
 
 	// Update collection filter toggles. A change rebuilds the list and resets scroll.
 	filterOpts := &ebiten.DrawImageOptions{}
-	if s.updateFilterButtons(filterOpts, scale, W, H) {
+	typing, searched := s.updateSearch(H)
+	if s.updateFilterButtons(filterOpts, scale, W, H) || searched {
 		if err := s.reloadCollectionList(); err != nil {
-			fmt.Printf("Error reloading collection list after filter: %v\n", err)
+			logger.Error("failed to reload collection list after filter", "err", err)
 		}
 		s.CollectionList.ResetScroll()
 	}
 
-	// Combine collection and deck draggable items
-	allDraggables := make([]dragdrop.Draggable, 0, len(s.draggableItems)+len(s.deckDraggableItems))
+	// Combine collection, deck and sideboard draggable items
+	allDraggables := make([]dragdrop.Draggable, 0, len(s.draggableItems)+len(s.deckDraggableItems)+len(s.sideboard.draggableItems))
 	for _, item := range s.draggableItems {
 		allDraggables = append(allDraggables, item)
 	}
 	for _, item := range s.deckDraggableItems {
 		allDraggables = append(allDraggables, item)
 	}
+	for _, item := range s.sideboard.draggableItems {
+		allDraggables = append(allDraggables, item)
+	}
 
 	if drag, started := ui.DragStart(); started {
 		s.dragManager.Start(drag, allDraggables)
@@ -419,9 +456,10 @@ func (s *EditDeckScreen) Update(W, H int, scale float64) (screenui.ScreenName, s
 		if btn.State == elements.StateHover {
 			s.hoveredCollectionIdx = i
 			s.hoveredDeckIdx = -1
+			s.sideboard.hoveredIdx = -1
 			card := domain.FindCardByName(btn.ID)
 			if card == nil {
-				fmt.Println("Error finding card for Button:", btn.ID)
+				logger.Error("no card for button", "button", btn.ID)
 			}
 			if card != nil {
 				img, err := card.CardImage(domain.CardViewFull)
@@ -447,6 +485,7 @@ func (s *EditDeckScreen) Update(W, H int, scale float64) (screenui.ScreenName, s
 			scaledMY >= display.Y && scaledMY < display.Y+cardHeight {
 			s.hoveredDeckIdx = i
 			s.hoveredCollectionIdx = -1
+			s.sideboard.hoveredIdx = -1
 			img, err := display.Card.CardImage(domain.CardViewFull)
 			if err == nil {
 				s.MagnifierImage = img
@@ -456,13 +495,19 @@ func (s *EditDeckScreen) Update(W, H int, scale float64) (screenui.ScreenName, s
 		}
 	}
 
-	// Handle keyboard shortcuts
+	s.updateSideboardHover(scaledMX, scaledMY)
+
+	// Handle keyboard shortcuts, unless they are being typed into the search
+	if typing {
+		return screenui.EditDeckScr, nil, nil
+	}
 	if inpututil.IsKeyJustPressed(ebiten.KeyA) && s.hoveredCollectionIdx >= 0 {
 		s.handleDeckCardAdd(s.hoveredCollectionIdx)
 	}
 	if inpututil.IsKeyJustPressed(ebiten.KeyD) && s.hoveredDeckIdx >= 0 {
 		s.handleDeckCardRemove(s.hoveredDeckIdx)
 	}
+	s.updateSideboardKeys()
 	if inpututil.IsKeyJustPressed(ebiten.KeyS) {
 		s.sellHoveredCard()
 	}
@@ -474,7 +519,9 @@ func (s *EditDeckScreen) Update(W, H int, scale float64) (screenui.ScreenName, s
 }
 
 func (s *EditDeckScreen) sellHoveredCard() {
-	if s.hoveredCollectionIdx >= 0 {
+	if s.sideboard.hoveredIdx >= 0 {
+		s.sellSideboardCard(s.sideboard.hoveredIdx)
+	} else if s.hoveredCollectionIdx >= 0 {
 		s.handleSellCard(s.hoveredCollectionIdx, true)
 	} else if s.hoveredDeckIdx >= 0 {
 		s.handleSellCard(s.hoveredDeckIdx, false)
@@ -507,11 +554,11 @@ func drawDeckActionButton(screen *ebiten.Image, bounds image.Rectangle, label st
 func (s *EditDeckScreen) updateState() {
 	err := s.loadDeckCards()
 	if err != nil {
-		fmt.Printf("Error reloading deck cards: %v\n", err)
+		logger.Error("failed to reload deck cards", "err", err)
 	}
 	err = s.reloadCollectionList()
 	if err != nil {
-		fmt.Printf("Error reloading collection list: %v\n", err)
+		logger.Error("failed to reload collection list", "err", err)
 	}
 }
 
@@ -526,39 +573,33 @@ func (s *EditDeckScreen) handleDeckCardAdd(cardIdx int) {
 	// Get the card group for this button
 	group, exists := s.collectionGroups[btn.ID]
 	if !exists || len(group.cards) == 0 {
-		fmt.Printf("No card group found for %s\n", btn.ID)
+		logger.Warn("no card group found", "button", btn.ID)
 		return
 	}
 
 	// Find an available card from the group to add to deck
 	var cardToAdd *domain.Card
 	for _, card := range group.cards {
-		collectionCount := s.Player.CardCollection.GetTotalCount(card)
-		if collectionCount <= 0 {
-			continue
-		}
-
-		// Check how many of this specific printing are already in deck
-		deckCount := s.Player.CardCollection.GetDeckCount(card, s.Player.ActiveDeck)
-		if deckCount < collectionCount {
+		// Check whether any copy of this printing is in no deck yet
+		if s.Player.CardCollection.GetAvailableCount(card) > 0 {
 			cardToAdd = card
 			break
 		}
 	}
 
 	if cardToAdd == nil {
-		fmt.Printf("All copies of %s are already in deck\n", group.name)
+		logger.Debug("all copies already in deck", "card", group.name)
 		return
 	}
 
 	// Move card to deck
 	err := s.Player.CardCollection.MoveCardToDeck(cardToAdd, s.Player.ActiveDeck, 1)
 	if err != nil {
-		fmt.Printf("Error moving card to deck: %v\n", err)
+		logger.Error("failed to move card to deck", "err", err)
 		return
 	}
 	newCount := s.Player.CardCollection.GetDeckCount(cardToAdd, s.Player.ActiveDeck)
-	fmt.Printf("Added %s to deck (now %d copies)\n", cardToAdd.Name(), newCount)
+	logger.Debug("added card to deck", "card", cardToAdd.Name(), "copies", newCount)
 	s.updateState()
 }
 
@@ -573,11 +614,11 @@ func (s *EditDeckScreen) handleDeckCardRemove(deckIdx int) {
 
 	err := s.Player.CardCollection.MoveCardFromDeck(cardToRemove, s.Player.ActiveDeck, 1)
 	if err != nil {
-		fmt.Printf("Error moving card from deck: %v\n", err)
+		logger.Error("failed to move card from deck", "err", err)
 		return
 	}
 	newCount := s.Player.CardCollection.GetDeckCount(cardToRemove, s.Player.ActiveDeck)
-	fmt.Printf("Removed %s from deck (now %d copies)\n", cardToRemove.Name(), newCount)
+	logger.Debug("removed card from deck", "card", cardToRemove.Name(), "copies", newCount)
 	s.updateState()
 }
 
@@ -592,7 +633,7 @@ func (s *EditDeckScreen) handleSellCard(cardIdx int, isFromCollection bool) {
 		btn := items[cardIdx]
 		group, exists := s.collectionGroups[btn.ID]
 		if !exists || len(group.cards) == 0 {
-			fmt.Printf("No card group found for %s\n", btn.ID)
+			logger.Warn("no card group found", "button", btn.ID)
 			return
 		}
 		cardToSell = group.cards[0]
@@ -626,30 +667,43 @@ func (s *EditDeckScreen) sellDroppedCard(data dragdrop.DragData) bool {
 	if !ok {
 		return false
 	}
+	if strings.HasPrefix(cardData.ID, sideboardCardDragIDPrefix) {
+		return s.sellFromSideboard(card)
+	}
 	return s.sellCard(card, strings.HasPrefix(cardData.ID, deckCardDragIDPrefix))
 }
 
 func (s *EditDeckScreen) sellCard(card *domain.Card, fromDeck bool) bool {
+	// Nobody is left to buy cards in a city Arzakon's minions hold.
+	if s.City != nil && s.City.IsConquered() {
+		return false
+	}
 	deckCount := s.Player.CardCollection.GetDeckCount(card, s.Player.ActiveDeck)
 	if fromDeck {
 		if deckCount == 0 {
 			return false
 		}
 		if err := s.Player.CardCollection.MoveCardFromDeck(card, s.Player.ActiveDeck, 1); err != nil {
-			fmt.Printf("Error moving card from deck before selling: %v\n", err)
+			logger.Error("failed to move card from deck before selling", "err", err)
 			return false
 		}
-	} else if s.Player.CardCollection.GetTotalCount(card) <= deckCount {
+	} else if s.Player.CardCollection.GetAvailableCount(card) == 0 {
 		return false
 	}
 
-	salePrice := card.SalePrice(s.City)
+	// The city buys the card before it leaves the collection, so a refused
+	// sale leaves the player's cards alone.
+	salePrice, err := s.City.SellCards(card, 1)
+	if err != nil {
+		logger.Error("failed to sell card", "err", err)
+		return false
+	}
 	if err := s.Player.CardCollection.DecrementCardCount(card); err != nil {
-		fmt.Printf("Error selling card: %v\n", err)
+		logger.Error("failed to sell card", "err", err)
 		return false
 	}
 	s.Player.Gold += salePrice
-	fmt.Printf("Sold %s for %d gold\n", card.Name(), salePrice)
+	logger.Info("sold card", "card", card.Name(), "gold", salePrice)
 	return true
 }
 
@@ -699,7 +753,7 @@ func (s *EditDeckScreen) loadDeckCards() error {
 			// Load the card art image
 			cardImg, err := representativeCard.CardImage(domain.CardViewArtOnly)
 			if err != nil {
-				fmt.Printf("WARN: Unable to load deck card image for %s: %v\n", group.name, err)
+				logger.Warn("unable to load deck card image", "card", group.name, "err", err)
 				continue
 			}
 
@@ -723,6 +777,9 @@ func (s *EditDeckScreen) loadDeckCards() error {
 	// Create draggable items for deck cards
 	s.createDeckDraggableItems()
 
+	s.loadSideboardCards()
+	s.validateDeck()
+
 	return nil
 }
 
@@ -788,52 +845,49 @@ func (s *EditDeckScreen) handleCardDrop(data dragdrop.DragData) bool {
 	if !ok {
 		return false
 	}
+	if strings.HasPrefix(cardData.ID, sideboardCardDragIDPrefix) {
+		return s.sideboardIn(droppedCard)
+	}
 
 	// Get the card group for this card
 	group, exists := s.collectionGroups[droppedCard.Name()]
 	if !exists || len(group.cards) == 0 {
-		fmt.Printf("No card group found for %s\n", droppedCard.Name())
+		logger.Warn("no card group found", "card", droppedCard.Name())
 		return false
 	}
 
 	// Find an available card from the group to add to deck
 	var cardToAdd *domain.Card
 	for _, card := range group.cards {
-		collectionCount := s.Player.CardCollection.GetTotalCount(card)
-		if collectionCount <= 0 {
-			continue
-		}
-
-		// Check how many of this specific printing are already in deck
-		deckCount := s.Player.CardCollection.GetDeckCount(card, s.Player.ActiveDeck)
-		if deckCount < collectionCount {
+		// Check whether any copy of this printing is in no deck yet
+		if s.Player.CardCollection.GetAvailableCount(card) > 0 {
 			cardToAdd = card
 			break
 		}
 	}
 
 	if cardToAdd == nil {
-		fmt.Printf("All copies of %s are already in deck\n", group.name)
+		logger.Debug("all copies already in deck", "card", group.name)
 		return false
 	}
 
 	// Move card to deck
 	err := s.Player.CardCollection.MoveCardToDeck(cardToAdd, s.Player.ActiveDeck, 1)
 	if err != nil {
-		fmt.Printf("Error moving card to deck: %v\n", err)
+		logger.Error("failed to move card to deck", "err", err)
 		return false
 	}
 	newCount := s.Player.CardCollection.GetDeckCount(cardToAdd, s.Player.ActiveDeck)
-	fmt.Printf("Added %s to deck via drag (now %d copies)\n", cardToAdd.Name(), newCount)
+	logger.Debug("dragged card to deck", "card", cardToAdd.Name(), "copies", newCount)
 
 	// Reload deck display and collection list
 	err = s.loadDeckCards()
 	if err != nil {
-		fmt.Printf("Error reloading deck cards: %v\n", err)
+		logger.Error("failed to reload deck cards", "err", err)
 	}
 	err = s.reloadCollectionList()
 	if err != nil {
-		fmt.Printf("Error reloading collection list: %v\n", err)
+		logger.Error("failed to reload collection list", "err", err)
 	}
 
 	return true
@@ -850,22 +904,25 @@ func (s *EditDeckScreen) handleCardDropToCollection(data dragdrop.DragData) bool
 	if !ok {
 		return false
 	}
+	if strings.HasPrefix(cardData.ID, sideboardCardDragIDPrefix) {
+		return s.sideboardToCollection(droppedCard)
+	}
 
 	err := s.Player.CardCollection.MoveCardFromDeck(droppedCard, s.Player.ActiveDeck, 1)
 	if err != nil {
-		fmt.Printf("Error moving card from deck: %v\n", err)
+		logger.Error("failed to move card from deck", "err", err)
 		return false
 	}
 	newCount := s.Player.CardCollection.GetDeckCount(droppedCard, s.Player.ActiveDeck)
-	fmt.Printf("Removed %s from deck via drag (now %d copies)\n", droppedCard.Name(), newCount)
+	logger.Debug("dragged card from deck", "card", droppedCard.Name(), "copies", newCount)
 
 	err = s.loadDeckCards()
 	if err != nil {
-		fmt.Printf("Error reloading deck cards: %v\n", err)
+		logger.Error("failed to reload deck cards", "err", err)
 	}
 	err = s.reloadCollectionList()
 	if err != nil {
-		fmt.Printf("Error reloading collection list: %v\n", err)
+		logger.Error("failed to reload collection list", "err", err)
 	}
 
 	return true
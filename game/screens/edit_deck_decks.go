@@ -0,0 +1,178 @@
+package screens
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+
+	"github.com/benprew/s30/game/ui"
+	"github.com/benprew/s30/game/ui/elements"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// The deck manager's actions, in the order their buttons are laid out. Each
+// acts on the active deck.
+const (
+	deckActionNew = iota
+	deckActionCopy
+	deckActionRename
+	deckActionDelete
+	deckActionClose
+)
+
+var deckActionLabels = []string{"New", "Copy", "Rename", "Delete", "Close"}
+
+// deckManager is the editor's deck picker: it lists the player's decks to
+// switch between, and creates, copies, renames and deletes them.
+type deckManager struct {
+	open     bool
+	renaming *elements.TextInput // name field while the active deck is renamed (nil otherwise)
+}
+
+func editDeckDecksBounds(W int) image.Rectangle { return image.Rect(W-470, 12, W-362, 56) }
+
+func deckManagerActionBounds(W, i int) image.Rectangle {
+	x := W/2 - 270 + i*110
+	return image.Rect(x, 560, x+100, 604)
+}
+
+func deckManagerRenameBounds(W int) image.Rectangle {
+	return image.Rect(W/2-220, 620, W/2+220, 664)
+}
+
+// updateDeckManager handles the Decks button and the deck manager. It reports
+// whether it consumed this frame's input.
+func (s *EditDeckScreen) updateDeckManager(W int) bool {
+	if !s.decks.open {
+		if ui.Click(editDeckDecksBounds(W)) {
+			s.decks.open = true
+			return true
+		}
+		return false
+	}
+
+	if s.decks.renaming != nil {
+		s.updateDeckRename()
+		return true
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		s.decks.open = false
+		return true
+	}
+	for i := range s.Player.NumDecks() {
+		if ui.Click(deckPickerRowBounds(W, i)) {
+			s.switchDeck(i)
+			s.decks.open = false
+			return true
+		}
+	}
+	for i := range deckActionLabels {
+		if ui.Click(deckManagerActionBounds(W, i)) {
+			s.deckAction(W, i)
+			return true
+		}
+	}
+	return true
+}
+
+func (s *EditDeckScreen) deckAction(W, action int) {
+	p := s.Player
+	switch action {
+	case deckActionNew:
+		i, err := p.NewDeck("")
+		if err != nil {
+			s.deckStatus = fmt.Sprintf("New deck failed: %v", err)
+			return
+		}
+		s.switchDeck(i)
+		s.deckStatus = "Created " + p.DeckName(i)
+	case deckActionCopy:
+		from := p.DeckName(p.ActiveDeck)
+		i, missing, err := p.DuplicateDeck(p.ActiveDeck)
+		if err != nil {
+			s.deckStatus = fmt.Sprintf("Copy failed: %v", err)
+			return
+		}
+		s.switchDeck(i)
+		s.deckStatus = fmt.Sprintf("Copied %s to %s", from, p.DeckName(i))
+		if len(missing) > 0 {
+			s.deckStatus += ". No spare copies of " + summarizeCards(missing)
+		}
+	case deckActionRename:
+		b := deckManagerRenameBounds(W)
+		input := elements.NewTextInput(b.Min.X, b.Min.Y, b.Dx(), b.Dy(), "Deck name")
+		input.Multiline = false
+		input.SetText(p.DeckName(p.ActiveDeck))
+		s.decks.renaming = input
+	case deckActionDelete:
+		name := p.DeckName(p.ActiveDeck)
+		if err := p.DeleteDeck(p.ActiveDeck); err != nil {
+			s.deckStatus = fmt.Sprintf("Delete failed: %v", err)
+			return
+		}
+		s.switchDeck(p.ActiveDeck)
+		s.deckStatus = "Deleted " + name
+	case deckActionClose:
+		s.decks.open = false
+	}
+}
+
+// updateDeckRename edits the active deck's name: Enter keeps it and Escape
+// drops it.
+func (s *EditDeckScreen) updateDeckRename() {
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		s.decks.renaming = nil
+		return
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyEnter) || inpututil.IsKeyJustPressed(ebiten.KeyNumpadEnter) {
+		if err := s.Player.RenameDeck(s.Player.ActiveDeck, s.decks.renaming.Text()); err != nil {
+			s.deckStatus = fmt.Sprintf("Rename failed: %v", err)
+		}
+		s.decks.renaming = nil
+		return
+	}
+	s.decks.renaming.Update(1)
+}
+
+// switchDeck makes deck i the one being edited.
+func (s *EditDeckScreen) switchDeck(i int) {
+	if err := s.Player.SetActiveDeck(i); err != nil {
+		s.deckStatus = fmt.Sprintf("Switch failed: %v", err)
+		return
+	}
+	s.hoveredDeckIdx = -1
+	s.sideboard.hoveredIdx = -1
+	s.updateState()
+}
+
+// drawDeckManager draws the active deck's name and the Decks button, and the
+// deck manager when it is open.
+func (s *EditDeckScreen) drawDeckManager(screen *ebiten.Image, W, H int) {
+	p := s.Player
+	elements.NewText(16, p.DeckName(p.ActiveDeck), 160, 12).Draw(screen, &ebiten.DrawImageOptions{}, 1)
+	drawDeckActionButton(screen, editDeckDecksBounds(W), "Decks")
+	if !s.decks.open {
+		return
+	}
+	vector.FillRect(screen, 0, 0, float32(W), float32(H), color.RGBA{0, 0, 0, 170}, false)
+
+	title := elements.NewText(24, "Decks", 0, 70)
+	title.HAlign = elements.AlignCenter
+	title.BoundsW = float64(W)
+	title.Draw(screen, &ebiten.DrawImageOptions{}, 1)
+	for i := range p.NumDecks() {
+		label := fmt.Sprintf("%s (%d cards)", p.DeckName(i), p.CardCollection.GetDeck(i).Size())
+		if i == p.ActiveDeck {
+			label = "> " + label
+		}
+		drawDeckActionButton(screen, deckPickerRowBounds(W, i), label)
+	}
+	for i, label := range deckActionLabels {
+		drawDeckActionButton(screen, deckManagerActionBounds(W, i), label)
+	}
+	if s.decks.renaming != nil {
+		s.decks.renaming.Draw(screen, 1)
+	}
+}
@@ -0,0 +1,45 @@
+package screens
+
+import (
+	"testing"
+
+	"github.com/benprew/s30/game/domain"
+)
+
+func TestDeckManagerCopiesAndDeletesTheActiveDeck(t *testing.T) {
+	mountain := domain.FindCardByName("Mountain")
+	bolt := domain.FindCardByName("Lightning Bolt")
+	collection := domain.NewCardCollection()
+	collection.AddCardToDeck(mountain, 0, 12)
+	collection.AddCardToDeck(bolt, 0, 2)
+	// Spare copies for the copy, short one bolt.
+	collection.AddCard(mountain, 12)
+	collection.AddCard(bolt, 1)
+	player := &domain.Player{DeckNames: []string{"Burn"}, Character: domain.Character{CardCollection: collection}}
+	screen, err := NewEditDeckScreen(player, nil, 1024, 768)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	screen.deckAction(1024, deckActionCopy)
+	if player.ActiveDeck != 1 || player.DeckName(1) != "Burn copy" {
+		t.Fatalf("expected to be editing the copy, got deck %d %q", player.ActiveDeck, player.DeckName(player.ActiveDeck))
+	}
+	if len(screen.deckCardDisplays) != 2 {
+		t.Errorf("expected the copy's 2 cards on display, got %d", len(screen.deckCardDisplays))
+	}
+	if want := "Copied Burn to Burn copy. No spare copies of 1 Lightning Bolt"; screen.deckStatus != want {
+		t.Errorf("status = %q, want %q", screen.deckStatus, want)
+	}
+	if got := screen.activeDeckList().Name; got != "Burn copy" {
+		t.Errorf("export name = %q, want the deck's name", got)
+	}
+
+	screen.deckAction(1024, deckActionDelete)
+	if player.NumDecks() != 1 || player.ActiveDeck != 0 {
+		t.Errorf("after deleting the copy: %d decks, active %d", player.NumDecks(), player.ActiveDeck)
+	}
+	if screen.deckStatus != "Deleted Burn copy" {
+		t.Errorf("status = %q", screen.deckStatus)
+	}
+}
@@ -26,6 +26,8 @@ const (
 	filterGroupGap         = 24 // gap between the color group and the type group
 	filterGapAboveCarousel = 6  // gap between the filter row and the carousel top
 	filterColorCount       = 5  // number of color toggles (rest are card types)
+	filterSearchGap        = 16 // gap between the type toggles and the search box
+	editDeckSearchW        = 360
 )
 
 // filterButton wraps a sprite-sheet button with the filter toggle it controls
@@ -110,7 +112,7 @@ func createFilterButtons() ([]*filterButton, error) {
 }
 
 // layoutFilterButtons positions the toggles in a horizontal row just above the
-// collection carousel: colors, a group gap, then card types.
+// collection carousel: colors, a group gap, card types, then the search box.
 func (s *EditDeckScreen) layoutFilterButtons(H int) {
 	y := H - COLLECTION_HEIGHT - filterBtnSize - filterGapAboveCarousel
 	x := filterStartX
@@ -121,6 +123,14 @@ func (s *EditDeckScreen) layoutFilterButtons(H int) {
 		fb.btn.MoveTo(x, y)
 		x += filterBtnSize + filterBtnGap
 	}
+	s.search.moveTo(x+filterSearchGap, y)
+}
+
+// updateSearch handles the search box. It reports whether the box has the
+// keyboard and whether the search changed.
+func (s *EditDeckScreen) updateSearch(H int) (typing, changed bool) {
+	s.layoutFilterButtons(H)
+	return s.search.update()
 }
 
 // updateFilterButtons processes clicks on the filter toggles. It returns true
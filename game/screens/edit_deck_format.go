@@ -0,0 +1,78 @@
+package screens
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"slices"
+
+	"github.com/benprew/s30/game/domain"
+	"github.com/benprew/s30/game/ui"
+	"github.com/benprew/s30/game/ui/elements"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// formatViolationsShown caps how many violations the legality panel lists.
+const formatViolationsShown = 12
+
+func editDeckFormatBounds() image.Rectangle { return image.Rect(160, 34, 292, 68) }
+
+// deckFormat returns the format the editor checks the deck against.
+func (s *EditDeckScreen) deckFormat() *domain.Format {
+	if f, ok := domain.FormatByID(s.Player.DeckFormat); ok {
+		return f
+	}
+	return domain.FormatShandalar
+}
+
+// validateDeck checks the active deck against the editor's format.
+func (s *EditDeckScreen) validateDeck() {
+	s.violations = s.deckFormat().Validate(s.Player.GetActiveDeck())
+}
+
+// updateDeckFormat switches to the next format when the legality badge is
+// clicked, and reports whether it was.
+func (s *EditDeckScreen) updateDeckFormat() bool {
+	if !ui.Click(editDeckFormatBounds()) {
+		return false
+	}
+	i := slices.Index(domain.Formats, s.deckFormat())
+	s.Player.DeckFormat = domain.Formats[(i+1)%len(domain.Formats)].ID
+	s.validateDeck()
+	return true
+}
+
+// drawDeckFormat draws the legality badge and, while it is hovered, the rules
+// the deck breaks.
+func (s *EditDeckScreen) drawDeckFormat(screen *ebiten.Image) {
+	b := editDeckFormatBounds()
+	bg := color.RGBA{35, 90, 45, 240}
+	status := "Legal"
+	if n := len(s.violations); n > 0 {
+		bg = color.RGBA{120, 35, 30, 240}
+		status = fmt.Sprintf("%d problems", n)
+		if n == 1 {
+			status = "1 problem"
+		}
+	}
+	vector.FillRect(screen, float32(b.Min.X), float32(b.Min.Y), float32(b.Dx()), float32(b.Dy()), bg, false)
+	label := fmt.Sprintf("%s\n%s", s.deckFormat().Name, status)
+	elements.NewText(13, label, b.Min.X+6, b.Min.Y+2).Draw(screen, &ebiten.DrawImageOptions{}, 1)
+
+	if len(s.violations) == 0 || !ui.Position().In(b) {
+		return
+	}
+	lines := make([]string, 0, formatViolationsShown+1)
+	for _, v := range s.violations[:min(len(s.violations), formatViolationsShown)] {
+		lines = append(lines, v.String())
+	}
+	if more := len(s.violations) - formatViolationsShown; more > 0 {
+		lines = append(lines, fmt.Sprintf("and %d more", more))
+	}
+	panelH := 16 + 20*len(lines)
+	vector.FillRect(screen, float32(b.Min.X), float32(b.Max.Y+4), 420, float32(panelH), color.RGBA{15, 12, 10, 240}, false)
+	for i, line := range lines {
+		elements.NewText(15, line, b.Min.X+10, b.Max.Y+10+i*20).Draw(screen, &ebiten.DrawImageOptions{}, 1)
+	}
+}
@@ -0,0 +1,26 @@
+package screens
+
+import (
+	"testing"
+
+	"github.com/benprew/s30/game/domain"
+)
+
+func TestValidateDeckUsesThePlayersFormat(t *testing.T) {
+	collection := domain.NewCardCollection()
+	collection.AddCardToDeck(domain.FindCardByName("Mountain"), 0, 56)
+	collection.AddCardToDeck(domain.FindCardByName("Lightning Bolt"), 0, 5)
+	player := &domain.Player{Character: domain.Character{CardCollection: collection}}
+	screen := &EditDeckScreen{Player: player}
+
+	screen.validateDeck()
+	if screen.deckFormat() != domain.FormatShandalar || len(screen.violations) != 0 {
+		t.Errorf("expected a legal Shandalar deck, got %v", screen.violations)
+	}
+
+	player.DeckFormat = domain.FormatOldSchool.ID
+	screen.validateDeck()
+	if len(screen.violations) != 1 || screen.violations[0].Kind != domain.ViolationTooManyCopies {
+		t.Errorf("expected five bolts to break Old School, got %v", screen.violations)
+	}
+}
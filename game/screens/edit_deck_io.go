@@ -0,0 +1,177 @@
+package screens
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/benprew/s30/game/domain"
+	"github.com/benprew/s30/game/save"
+	"github.com/benprew/s30/game/ui"
+	"github.com/benprew/s30/game/ui/elements"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// deckImportMaxFiles caps how many deck files the import picker lists.
+const deckImportMaxFiles = 8
+
+// deckStatusMaxItems caps how many missing cards an import message names.
+const deckStatusMaxItems = 3
+
+var deckFileNameUnsafe = regexp.MustCompile(`[^a-z0-9]+`)
+
+func editDeckExportBounds(W int) image.Rectangle { return image.Rect(W-230, 12, W-122, 56) }
+
+func editDeckImportBounds(W int) image.Rectangle { return image.Rect(W-350, 12, W-242, 56) }
+
+func deckPickerRowBounds(W, i int) image.Rectangle {
+	return image.Rect(W/2-220, 110+i*52, W/2+220, 152+i*52)
+}
+
+// updateDeckIO handles the Import and Export buttons and the import picker.
+// It reports whether it consumed this frame's input.
+func (s *EditDeckScreen) updateDeckIO(W int) bool {
+	if s.importFiles != nil {
+		for i, p := range s.importFiles {
+			if ui.Click(deckPickerRowBounds(W, i)) {
+				s.importFiles = nil
+				s.importDeckFile(p)
+				return true
+			}
+		}
+		if ui.Click(deckPickerRowBounds(W, len(s.importFiles))) {
+			s.importFiles = nil
+		}
+		return true
+	}
+
+	if ui.Click(editDeckExportBounds(W)) {
+		s.deckStatus = s.exportDeck()
+		return true
+	}
+	if ui.Click(editDeckImportBounds(W)) {
+		s.openImportPicker()
+		return true
+	}
+	return false
+}
+
+// exportDeck writes the active deck to the deck directory as a .dck file.
+func (s *EditDeckScreen) exportDeck() string {
+	dl := s.activeDeckList()
+	var buf bytes.Buffer
+	if err := dl.Encode(&buf, domain.DeckFormatDck); err != nil {
+		return fmt.Sprintf("Export failed: %v", err)
+	}
+	p, err := save.WriteDeckFile(deckFileName(dl.Name, domain.DeckFormatDck), buf.Bytes())
+	if err != nil {
+		return fmt.Sprintf("Export failed: %v", err)
+	}
+	return "Exported to " + p
+}
+
+// activeDeckList returns the deck being edited as a deck list.
+func (s *EditDeckScreen) activeDeckList() *domain.DeckList {
+	name := s.Player.DeckName(s.Player.ActiveDeck)
+	if s.Player.Name != "" {
+		name = fmt.Sprintf("%s %s", s.Player.Name, name)
+	}
+	dl := domain.NewDeckList(name)
+	dl.Main = s.Player.CardCollection.GetDeck(s.Player.ActiveDeck)
+	dl.Sideboard = s.Player.CardCollection.GetSideboard(s.Player.ActiveDeck)
+	return dl
+}
+
+// deckFileName turns a deck name into a safe file name.
+func deckFileName(name string, format domain.DeckFormat) string {
+	slug := strings.Trim(deckFileNameUnsafe.ReplaceAllString(strings.ToLower(name), "_"), "_")
+	if slug == "" {
+		slug = "deck"
+	}
+	return slug + format.Ext()
+}
+
+func (s *EditDeckScreen) openImportPicker() {
+	files, err := save.ListDeckFiles()
+	if err != nil {
+		s.deckStatus = fmt.Sprintf("Import failed: %v", err)
+		return
+	}
+	if len(files) == 0 {
+		dir, _ := save.DeckDir()
+		s.deckStatus = fmt.Sprintf("No .dck or .txt decks in %s", dir)
+		return
+	}
+	if len(files) > deckImportMaxFiles {
+		files = files[:deckImportMaxFiles]
+	}
+	s.importFiles = files
+}
+
+func (s *EditDeckScreen) importDeckFile(deckPath string) {
+	data, err := save.ReadDeckFile(deckPath)
+	if err != nil {
+		s.deckStatus = fmt.Sprintf("Import failed: %v", err)
+		return
+	}
+	s.deckStatus = s.importDeck(filepath.Base(deckPath), data)
+	s.updateState()
+}
+
+// importDeck rebuilds the active deck and its sideboard from a deck file, using
+// the cards the player owns, and returns a message describing the result.
+func (s *EditDeckScreen) importDeck(name string, data []byte) string {
+	dl, err := domain.ParseDeckList(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Sprintf("Import failed: %v", err)
+	}
+	missing := s.Player.CardCollection.BuildDeckFromList(s.Player.ActiveDeck, dl.Main)
+	missing = append(missing, s.Player.CardCollection.BuildSideboardFromList(s.Player.ActiveDeck, dl.Sideboard)...)
+
+	msg := "Imported " + name
+	if len(missing) > 0 {
+		msg += ". Not owned: " + summarizeCards(missing)
+	}
+	if len(dl.Unknown) > 0 {
+		msg += ". Unknown: " + summarizeCards(dl.Unknown)
+	}
+	return msg
+}
+
+func summarizeCards(entries []string) string {
+	if len(entries) <= deckStatusMaxItems {
+		return strings.Join(entries, ", ")
+	}
+	return fmt.Sprintf("%s and %d more", strings.Join(entries[:deckStatusMaxItems], ", "), len(entries)-deckStatusMaxItems)
+}
+
+// drawDeckIO draws the Import and Export buttons, the last import or export
+// message, and the import picker when it is open.
+func (s *EditDeckScreen) drawDeckIO(screen *ebiten.Image, W, H int) {
+	drawDeckActionButton(screen, editDeckImportBounds(W), "Import")
+	drawDeckActionButton(screen, editDeckExportBounds(W), "Export")
+	if s.deckStatus != "" {
+		status := elements.NewText(16, s.deckStatus, 310, 70)
+		status.BoundsW = float64(W - 320)
+		status.Draw(screen, &ebiten.DrawImageOptions{}, 1)
+	}
+
+	if s.importFiles == nil {
+		return
+	}
+	vector.FillRect(screen, 0, 0, float32(W), float32(H), color.RGBA{0, 0, 0, 170}, false)
+
+	title := elements.NewText(24, "Import which deck?", 0, 70)
+	title.HAlign = elements.AlignCenter
+	title.BoundsW = float64(W)
+	title.Draw(screen, &ebiten.DrawImageOptions{}, 1)
+	for i, p := range s.importFiles {
+		drawDeckActionButton(screen, deckPickerRowBounds(W, i), filepath.Base(p))
+	}
+	drawDeckActionButton(screen, deckPickerRowBounds(W, len(s.importFiles)), "Cancel")
+}
@@ -0,0 +1,92 @@
+package screens
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/benprew/s30/game/domain"
+)
+
+func TestImportDeckBuildsActiveDeckFromOwnedCards(t *testing.T) {
+	mountain := domain.FindCardByName("Mountain")
+	bolt := domain.FindCardByName("Lightning Bolt")
+	collection := domain.NewCardCollection()
+	collection.AddCard(mountain, 20)
+	collection.AddCard(bolt, 2)
+	player := &domain.Player{Character: domain.Character{CardCollection: collection}}
+	screen := &EditDeckScreen{Player: player}
+
+	msg := screen.importDeck("burn.txt", []byte("4 Lightning Bolt\n16 Mountain\n2 Not A Real Card\n"))
+
+	if got := collection.GetDeckCount(mountain, 0); got != 16 {
+		t.Errorf("Mountains in deck = %d, want 16", got)
+	}
+	if got := collection.GetDeckCount(bolt, 0); got != 2 {
+		t.Errorf("Lightning Bolts in deck = %d, want 2", got)
+	}
+	want := "Imported burn.txt. Not owned: 2 Lightning Bolt. Unknown: 2 Not A Real Card"
+	if msg != want {
+		t.Errorf("message = %q, want %q", msg, want)
+	}
+}
+
+func TestImportDeckReportsParseErrors(t *testing.T) {
+	collection := domain.NewCardCollection()
+	player := &domain.Player{Character: domain.Character{CardCollection: collection}}
+	screen := &EditDeckScreen{Player: player}
+
+	if msg := screen.importDeck("bad.txt", []byte("many Mountains\n")); !strings.HasPrefix(msg, "Import failed") {
+		t.Errorf("message = %q, want an import failure", msg)
+	}
+}
+
+func TestActiveDeckListExportsCurrentDeck(t *testing.T) {
+	mountain := domain.FindCardByName("Mountain")
+	collection := domain.NewCardCollection()
+	collection.AddCardToDeck(mountain, 1, 12)
+	collection.AddCard(mountain, 3)
+	player := &domain.Player{Name: "Ben", ActiveDeck: 1, Character: domain.Character{CardCollection: collection}}
+	screen := &EditDeckScreen{Player: player}
+
+	dl := screen.activeDeckList()
+	if dl.Name != "Ben Deck 2" {
+		t.Errorf("Name = %q, want %q", dl.Name, "Ben Deck 2")
+	}
+	var buf bytes.Buffer
+	if err := dl.Encode(&buf, domain.DeckFormatText); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "12 Mountain\n" {
+		t.Errorf("exported %q, want %q", buf.String(), "12 Mountain\n")
+	}
+	if got := deckFileName(dl.Name, domain.DeckFormatDck); got != "ben_deck_2.dck" {
+		t.Errorf("deckFileName = %q, want ben_deck_2.dck", got)
+	}
+}
+
+func TestImportAndExportKeepTheSideboard(t *testing.T) {
+	mountain := domain.FindCardByName("Mountain")
+	bolt := domain.FindCardByName("Lightning Bolt")
+	collection := domain.NewCardCollection()
+	collection.AddCard(mountain, 20)
+	collection.AddCard(bolt, 3)
+	player := &domain.Player{Character: domain.Character{CardCollection: collection}}
+	screen := &EditDeckScreen{Player: player}
+
+	msg := screen.importDeck("burn.txt", []byte("2 Lightning Bolt\n16 Mountain\n\nSideboard\n2 Lightning Bolt\n"))
+
+	if got := collection.GetSideboardCount(bolt, 0); got != 1 {
+		t.Errorf("Lightning Bolts in sideboard = %d, want 1", got)
+	}
+	if want := "Imported burn.txt. Not owned: 1 Lightning Bolt"; msg != want {
+		t.Errorf("message = %q, want %q", msg, want)
+	}
+	var buf bytes.Buffer
+	if err := screen.activeDeckList().Encode(&buf, domain.DeckFormatText); err != nil {
+		t.Fatal(err)
+	}
+	if want := "2 Lightning Bolt\n16 Mountain\n\nSideboard\n1 Lightning Bolt\n"; buf.String() != want {
+		t.Errorf("exported %q, want %q", buf.String(), want)
+	}
+}
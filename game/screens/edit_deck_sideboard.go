@@ -0,0 +1,275 @@
+package screens
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"sort"
+	"strings"
+
+	"github.com/benprew/s30/game/domain"
+	"github.com/benprew/s30/game/ui/dragdrop"
+	"github.com/benprew/s30/game/ui/elements"
+	"github.com/benprew/s30/game/ui/imageutil"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const (
+	sideboardCardDragIDPrefix = "side:"
+	// sideboardPaneH is the height of the sideboard pane along the bottom of
+	// the deck area.
+	sideboardPaneH = 100
+	// sideboardCardScale is smaller than the deck's so a whole sideboard fits
+	// on one row.
+	sideboardCardScale = 0.28
+	sideboardCardStep  = 70
+	sideboardLabelW    = 96
+)
+
+// sideboardPane shows the active deck's sideboard. Cards are dragged in from
+// the collection or the deck and back out again, or moved with [B].
+type sideboardPane struct {
+	dropArea       *dragdrop.DropArea
+	draggableItems []*dragdrop.DraggableButton
+	cardDisplays   []DeckCardDisplay
+	cardImages     map[string]*ebiten.Image // Cached resized card images
+	hoveredIdx     int                      // Index of hovered sideboard card (-1 if none)
+}
+
+func newSideboardPane() sideboardPane {
+	return sideboardPane{cardImages: make(map[string]*ebiten.Image), hoveredIdx: -1}
+}
+
+// editDeckMainBounds is the part of the deck area that shows the deck itself.
+func editDeckMainBounds(deckArea image.Rectangle) image.Rectangle {
+	return image.Rect(deckArea.Min.X, deckArea.Min.Y, deckArea.Max.X, deckArea.Max.Y-sideboardPaneH)
+}
+
+// editDeckSideboardBounds is the part of the deck area that shows the
+// sideboard.
+func editDeckSideboardBounds(deckArea image.Rectangle) image.Rectangle {
+	return image.Rect(deckArea.Min.X, deckArea.Max.Y-sideboardPaneH, deckArea.Max.X, deckArea.Max.Y)
+}
+
+// loadSideboardCards lays out the active deck's sideboard in a single row,
+// squeezing the cards together when they don't fit.
+func (s *EditDeckScreen) loadSideboardCards() {
+	p := &s.sideboard
+	p.cardDisplays = p.cardDisplays[:0]
+	p.draggableItems = p.draggableItems[:0]
+	if p.dropArea == nil {
+		return
+	}
+
+	sideboard := s.Player.CardCollection.GetSideboard(s.Player.ActiveDeck)
+	counts := make(map[string]int)
+	firsts := make(map[string]*domain.Card)
+	for card, n := range sideboard {
+		if _, ok := firsts[card.Name()]; !ok {
+			firsts[card.Name()] = card
+		}
+		counts[card.Name()] += n
+	}
+	names := make([]string, 0, len(counts))
+	for name := range counts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	bounds := p.dropArea.GetDropBounds()
+	step := sideboardCardStep
+	if n := len(names); n > 1 {
+		step = min(step, (bounds.Dx()-sideboardLabelW-sideboardCardStep)/(n-1))
+	}
+	for i, name := range names {
+		img, ok := p.cardImages[name]
+		if !ok {
+			cardImg, err := firsts[name].CardImage(domain.CardViewArtOnly)
+			if err != nil {
+				logger.Warn("unable to load sideboard card image", "card", name, "err", err)
+				continue
+			}
+			img = imageutil.ScaleImage(cardImg, sideboardCardScale)
+			p.cardImages[name] = img
+		}
+		display := DeckCardDisplay{
+			Card:  firsts[name],
+			Count: counts[name],
+			Image: img,
+			X:     bounds.Min.X + sideboardLabelW + i*step,
+			Y:     bounds.Min.Y + (bounds.Dy()-img.Bounds().Dy())/2,
+		}
+		p.cardDisplays = append(p.cardDisplays, display)
+
+		btn := elements.NewButton(img, img, img, display.X, display.Y, 1.0)
+		btn.ID = sideboardCardDragIDPrefix + name
+		p.draggableItems = append(p.draggableItems, dragdrop.NewDraggableButton(btn, display.Card))
+	}
+}
+
+func (s *EditDeckScreen) drawSideboard(screen *ebiten.Image, scale float64) {
+	p := &s.sideboard
+	if p.dropArea == nil {
+		return
+	}
+	b := p.dropArea.GetDropBounds()
+	vector.FillRect(screen, float32(float64(b.Min.X)*scale), float32(float64(b.Min.Y)*scale),
+		float32(float64(b.Dx())*scale), float32(float64(b.Dy())*scale), color.RGBA{20, 20, 40, 170}, false)
+	p.dropArea.Draw(screen)
+
+	total := 0
+	for _, d := range p.cardDisplays {
+		total += d.Count
+	}
+	scaleOpts := &ebiten.DrawImageOptions{}
+	scaleOpts.GeoM.Scale(scale, scale)
+	label := fmt.Sprintf("Sideboard\n%d cards", total)
+	elements.NewText(14, label, b.Min.X+10, b.Min.Y+30).Draw(screen, scaleOpts, scale)
+
+	for i, d := range p.cardDisplays {
+		opts := &ebiten.DrawImageOptions{}
+		opts.GeoM.Scale(scale, scale)
+		opts.GeoM.Translate(float64(d.X)*scale, float64(d.Y)*scale)
+		if i == p.hoveredIdx {
+			opts.ColorScale.Scale(1.2, 1.2, 1.2, 1)
+		}
+		screen.DrawImage(d.Image, opts)
+		if d.Count > 1 {
+			s.drawCountOverlay(screen, scale, d.X, d.Y, d.Image.Bounds().Dx(), d.Image.Bounds().Dy(), d.Count)
+		}
+	}
+}
+
+// updateSideboardHover tracks the sideboard card under the pointer, given in
+// unscaled screen coordinates, and magnifies it. Later cards overlap earlier
+// ones, so they are checked first.
+func (s *EditDeckScreen) updateSideboardHover(x, y int) {
+	p := &s.sideboard
+	for i := len(p.cardDisplays) - 1; i >= 0; i-- {
+		d := p.cardDisplays[i]
+		if !image.Pt(x, y).In(d.Image.Bounds().Add(image.Pt(d.X, d.Y))) {
+			continue
+		}
+		p.hoveredIdx = i
+		s.hoveredCollectionIdx = -1
+		s.hoveredDeckIdx = -1
+		if img, err := d.Card.CardImage(domain.CardViewFull); err == nil {
+			s.MagnifierImage = img
+			s.MagnifiedCard = d.Card
+		}
+		return
+	}
+}
+
+// updateSideboardKeys handles the keys for the sideboard: [B] moves the
+// hovered collection or deck card to the sideboard, and [A] and [D] move the
+// hovered sideboard card to the deck or back to the collection.
+func (s *EditDeckScreen) updateSideboardKeys() {
+	if inpututil.IsKeyJustPressed(ebiten.KeyB) {
+		switch {
+		case s.hoveredCollectionIdx >= 0:
+			items := s.CollectionList.GetItems()
+			if s.hoveredCollectionIdx < len(items) {
+				if group, ok := s.collectionGroups[items[s.hoveredCollectionIdx].ID]; ok {
+					s.collectionToSideboard(group.cards[0])
+				}
+			}
+		case s.hoveredDeckIdx >= 0 && s.hoveredDeckIdx < len(s.deckCardDisplays):
+			s.sideboardOut(s.deckCardDisplays[s.hoveredDeckIdx].Card)
+		}
+	}
+	idx := s.sideboard.hoveredIdx
+	if idx < 0 || idx >= len(s.sideboard.cardDisplays) {
+		return
+	}
+	card := s.sideboard.cardDisplays[idx].Card
+	if inpututil.IsKeyJustPressed(ebiten.KeyA) {
+		s.sideboardIn(card)
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyD) {
+		s.sideboardToCollection(card)
+	}
+}
+
+// handleCardDropToSideboard moves a card dropped on the sideboard pane there
+// from the deck or the collection.
+func (s *EditDeckScreen) handleCardDropToSideboard(data dragdrop.DragData) bool {
+	cardData, ok := data.(*dragdrop.CardDragData)
+	if !ok {
+		return false
+	}
+	card, ok := cardData.Card.(*domain.Card)
+	if !ok {
+		return false
+	}
+	switch {
+	case strings.HasPrefix(cardData.ID, sideboardCardDragIDPrefix):
+		return false
+	case strings.HasPrefix(cardData.ID, deckCardDragIDPrefix):
+		return s.sideboardOut(card)
+	default:
+		return s.collectionToSideboard(card)
+	}
+}
+
+// collectionToSideboard moves a copy of card, or of another printing of it,
+// from the collection to the sideboard.
+func (s *EditDeckScreen) collectionToSideboard(card *domain.Card) bool {
+	cc := s.Player.CardCollection
+	candidates := []*domain.Card{card}
+	if group, ok := s.collectionGroups[card.Name()]; ok {
+		candidates = append(candidates, group.cards...)
+	}
+	for _, c := range candidates {
+		if cc.GetAvailableCount(c) > 0 && cc.MoveCardToSideboard(c, s.Player.ActiveDeck, 1) == nil {
+			s.updateState()
+			return true
+		}
+	}
+	logger.Debug("no copies left to put in the sideboard", "card", card.Name())
+	return false
+}
+
+// sideboardOut moves a copy of card from the deck to the sideboard.
+func (s *EditDeckScreen) sideboardOut(card *domain.Card) bool {
+	return s.moveSideboardCard(card, s.Player.CardCollection.SideboardOut)
+}
+
+// sideboardIn moves a copy of card from the sideboard to the deck.
+func (s *EditDeckScreen) sideboardIn(card *domain.Card) bool {
+	return s.moveSideboardCard(card, s.Player.CardCollection.SideboardIn)
+}
+
+// sideboardToCollection takes a copy of card out of the sideboard.
+func (s *EditDeckScreen) sideboardToCollection(card *domain.Card) bool {
+	return s.moveSideboardCard(card, s.Player.CardCollection.MoveCardFromSideboard)
+}
+
+func (s *EditDeckScreen) moveSideboardCard(card *domain.Card, move func(*domain.Card, int, int) error) bool {
+	if err := move(card, s.Player.ActiveDeck, 1); err != nil {
+		logger.Error("failed to move card", "card", card.Name(), "err", err)
+		return false
+	}
+	s.updateState()
+	return true
+}
+
+func (s *EditDeckScreen) sellSideboardCard(idx int) {
+	if idx < len(s.sideboard.cardDisplays) && s.sellFromSideboard(s.sideboard.cardDisplays[idx].Card) {
+		s.updateState()
+	}
+}
+
+// sellFromSideboard sells a copy of card out of the sideboard.
+func (s *EditDeckScreen) sellFromSideboard(card *domain.Card) bool {
+	if s.City != nil && s.City.IsConquered() {
+		return false
+	}
+	if err := s.Player.CardCollection.MoveCardFromSideboard(card, s.Player.ActiveDeck, 1); err != nil {
+		logger.Error("failed to move card from sideboard before selling", "err", err)
+		return false
+	}
+	return s.sellCard(card, false)
+}
@@ -83,6 +83,68 @@ func TestSellDroppedCardFromDeck(t *testing.T) {
 	}
 }
 
+func TestSellDroppedCardFloodsCityMarket(t *testing.T) {
+	bolt := domain.FindCardByName("Lightning Bolt")
+	collection := domain.NewCardCollection()
+	collection.AddCard(bolt, 2)
+	player := &domain.Player{Character: domain.Character{CardCollection: collection}}
+	city := &domain.City{Tier: domain.TierTown}
+	screen := &EditDeckScreen{Player: player, City: city}
+
+	first := city.SellPrice(bolt)
+	screen.sellDroppedCard(&dragdrop.CardDragData{ID: bolt.Name(), Card: bolt})
+	if player.Gold != first {
+		t.Fatalf("gold after one sale = %d, want %d", player.Gold, first)
+	}
+	second := city.SellPrice(bolt)
+	if second >= first {
+		t.Fatalf("SellPrice after a sale = %d, want less than %d", second, first)
+	}
+	screen.sellDroppedCard(&dragdrop.CardDragData{ID: bolt.Name(), Card: bolt})
+	if player.Gold != first+second {
+		t.Errorf("gold after two sales = %d, want %d", player.Gold, first+second)
+	}
+}
+
+func TestSellDroppedCardInUseLeavesCityMarketAlone(t *testing.T) {
+	bolt := domain.FindCardByName("Lightning Bolt")
+	collection := domain.NewCardCollection()
+	collection.AddCardToDeck(bolt, 1, 1)
+	player := &domain.Player{Character: domain.Character{CardCollection: collection}}
+	city := &domain.City{Tier: domain.TierTown}
+	screen := &EditDeckScreen{Player: player, City: city}
+
+	price := city.SellPrice(bolt)
+	if screen.sellDroppedCard(&dragdrop.CardDragData{ID: bolt.Name(), Card: bolt}) {
+		t.Fatal("sold the only bolt out of another deck")
+	}
+	if player.Gold != 0 || collection.GetDeckCount(bolt, 1) != 1 {
+		t.Errorf("refused sale left gold %d and %d bolts in deck 2", player.Gold, collection.GetDeckCount(bolt, 1))
+	}
+	if got := city.SellPrice(bolt); got != price {
+		t.Errorf("SellPrice after a refused sale = %d, want %d", got, price)
+	}
+}
+
+func TestSellDroppedCardRefusedInConqueredCity(t *testing.T) {
+	mountain := domain.FindCardByName("Mountain")
+	collection := domain.NewCardCollection()
+	collection.AddCard(mountain, 2)
+	player := &domain.Player{
+		Character:  domain.Character{CardCollection: collection},
+		ActiveDeck: 0,
+	}
+	city := &domain.City{Tier: domain.TierHamlet, ConqueredBy: domain.ColorBlack}
+	screen := &EditDeckScreen{Player: player, City: city}
+
+	if screen.sellDroppedCard(&dragdrop.CardDragData{ID: mountain.Name(), Card: mountain}) {
+		t.Fatal("sellDroppedCard() = true in a conquered city, want false")
+	}
+	if got := collection.GetTotalCount(mountain); got != 2 {
+		t.Fatalf("collection count = %d, want 2", got)
+	}
+}
+
 func TestCreateCollectionButtons_ExcludesDeckCards(t *testing.T) {
 	mountain := domain.FindCardByName("Mountain")
 	forest := domain.FindCardByName("Forest")
@@ -0,0 +1,144 @@
+package screens
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/benprew/s30/assets"
+	gameaudio "github.com/benprew/s30/game/audio"
+	"github.com/benprew/s30/game/domain"
+	"github.com/benprew/s30/game/ui/elements"
+	"github.com/benprew/s30/game/ui/fonts"
+	"github.com/benprew/s30/game/ui/imageutil"
+	"github.com/benprew/s30/game/ui/screenui"
+	"github.com/benprew/s30/game/world"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+)
+
+// leapMaxCities caps how many destinations fit in the frame at once.
+const leapMaxCities = 8
+
+// LeapOfFateScreen lists the towns the player has visited and teleports them
+// to the one they pick.
+type LeapOfFateScreen struct {
+	City         *domain.City
+	Level        *world.Level
+	Destinations []*domain.City
+	Buttons      []*elements.Button
+}
+
+func (s *LeapOfFateScreen) IsFramed() bool { return true }
+
+func (s *LeapOfFateScreen) IsOverlay() bool { return false }
+
+func NewLeapOfFateScreen(city *domain.City, level *world.Level) *LeapOfFateScreen {
+	s := &LeapOfFateScreen{
+		City:         city,
+		Level:        level,
+		Destinations: leapDestinations(city, level),
+	}
+	s.Buttons = s.mkButtons()
+	return s
+}
+
+// leapDestinations returns the visited cities other than the current one.
+func leapDestinations(city *domain.City, level *world.Level) []*domain.City {
+	var cities []*domain.City
+	for _, c := range level.VisitedCities() {
+		if c != city {
+			cities = append(cities, c)
+		}
+	}
+	if len(cities) > leapMaxCities {
+		cities = cities[len(cities)-leapMaxCities:]
+	}
+	return cities
+}
+
+func (s *LeapOfFateScreen) mkButtons() []*elements.Button {
+	btnSprites, err := imageutil.LoadSpriteSheet(3, 1, assets.Tradbut1_png)
+	if err != nil {
+		panic(fmt.Sprintf("Unable to load leap of fate buttons: %s", err))
+	}
+	fontFace := &text.GoTextFace{Source: fonts.MtgFont, Size: 18}
+
+	mk := func(label, id string, x, y int) *elements.Button {
+		return elements.NewButtonFromConfig(elements.ButtonConfig{
+			Normal:    btnSprites[0][0],
+			Hover:     btnSprites[0][1],
+			Pressed:   btnSprites[0][2],
+			Text:      label,
+			Font:      fontFace,
+			ID:        id,
+			Important: true,
+			X:         x,
+			Y:         y,
+		})
+	}
+
+	// Two columns of destinations, with Cancel centered below them.
+	colW := FrameWidth / 2
+	var buttons []*elements.Button
+	for i, c := range s.Destinations {
+		w, _ := elements.TextButtonSize(c.Name, fontFace)
+		x := FrameOffsetX + (i%2)*colW + (colW-w)/2
+		y := FrameOffsetY + 90 + (i/2)*62
+		buttons = append(buttons, mk(c.Name, fmt.Sprintf("city_%d", i), x, y))
+	}
+	cancelW, _ := elements.TextButtonSize("Cancel", fontFace)
+	buttons = append(buttons, mk("Cancel", "cancel", FrameOffsetX+(FrameWidth-cancelW)/2, FrameOffsetY+FrameHeight-65))
+	return buttons
+}
+
+func (s *LeapOfFateScreen) Update(W, H int, scale float64) (screenui.ScreenName, screenui.Screen, error) {
+	opts := &ebiten.DrawImageOptions{}
+	for _, b := range s.Buttons {
+		b.Update(opts, scale, W, H)
+		if !b.IsClicked() {
+			continue
+		}
+		if b.ID == "cancel" {
+			return screenui.CityScr, nil, nil
+		}
+		idx := -1
+		fmt.Sscanf(b.ID, "city_%d", &idx)
+		if idx < 0 || idx >= len(s.Destinations) {
+			continue
+		}
+		dest := s.Destinations[idx]
+		if err := s.Level.LeapToCity(dest); err != nil {
+			return screenui.LeapOfFateScr, nil, err
+		}
+		if am := gameaudio.Get(); am != nil {
+			am.PlaySFX(gameaudio.WorldMagicSFXForColor(domain.ColorMaskToString(dest.AmuletColor)))
+		}
+		s.Level.Player.VisitCity(dest)
+		return screenui.CityScr, NewCityScreen(dest, s.Level.Player, s.Level), nil
+	}
+
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		return screenui.CityScr, nil, nil
+	}
+	return screenui.LeapOfFateScr, nil, nil
+}
+
+func (s *LeapOfFateScreen) Draw(screen *ebiten.Image, W, H int, scale float64) {
+	drawTownPanel(screen, s.City, scale)
+
+	heading := "Where shall fate carry you?"
+	if len(s.Destinations) == 0 {
+		heading = "You have visited no other towns."
+	}
+	title := elements.NewText(30, heading, FrameOffsetX, FrameOffsetY+30)
+	title.Color = color.RGBA{255, 230, 150, 255}
+	title.HAlign = elements.AlignCenter
+	title.BoundsW = FrameWidth
+	title.Draw(screen, &ebiten.DrawImageOptions{}, scale)
+
+	opts := &ebiten.DrawImageOptions{}
+	for _, b := range s.Buttons {
+		b.Draw(screen, opts, scale)
+	}
+}
@@ -10,10 +10,13 @@ import (
 	"github.com/benprew/s30/game/domain"
 	"github.com/benprew/s30/game/ui/screenui"
 	"github.com/benprew/s30/game/world"
+	"github.com/benprew/s30/logging"
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/inpututil"
 )
 
+var logger = logging.Logger(logging.UI)
+
 type LevelScreen struct {
 	Level *world.Level
 }
@@ -40,6 +43,11 @@ func (s *LevelScreen) Update(W, H int, scale float64) (screenui.ScreenName, scre
 	if err := s.Level.UpdateWorld(W, H); err != nil {
 		return screenui.WorldScr, nil, err
 	}
+	if s.Level.CampaignLost() {
+		result := NewGameResultScreen(false)
+		result.Reason = fmt.Sprintf("Arzakon's minions hold %d cities", s.Level.ConqueredCities())
+		return screenui.GameLoseScr, result, nil
+	}
 
 	currentTile := s.Level.CharacterTile()
 	tile := s.Level.Tile(currentTile)
@@ -63,9 +71,13 @@ func (s *LevelScreen) Update(W, H int, scale float64) (screenui.ScreenName, scre
 	if currentTile != (image.Point{X: -1, Y: -1}) {
 		if tile != nil {
 			if tile.IsCity() && prevTile != currentTile {
+				s.Level.Player.VisitCity(tile.City)
 				if am := gameaudio.Get(); am != nil {
 					am.PlayBGM(gameaudio.CastleBGMForColor(domain.ColorMaskToString(tile.City.AmuletColor)))
 				}
+				if tile.City.IsConquered() {
+					return screenui.CityScr, NewCityScreen(tile.City, s.Level.Player, s.Level), nil
+				}
 				if rewards := s.Level.Player.RedeemFulfilledQuests(tile.City); len(rewards) > 0 {
 					if am := gameaudio.Get(); am != nil {
 						am.PlaySFX(questRewardSound(rewards))
@@ -15,6 +15,7 @@ import (
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/inpututil"
 	"github.com/hajimehoshi/ebiten/v2/text/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
 )
 
 const (
@@ -46,6 +47,17 @@ type RandomEncounterScreen struct {
 	Player     *domain.Player
 	LandName   string
 	DoneButton *elements.Button
+
+	Level *world.Level
+	Lair  *world.Lair
+	// Lines describe a named lair, then what came of the player's last choice.
+	Lines   []string
+	Choices []*elements.Button
+
+	oasisOffers     []*domain.Card
+	arenaChallenger *domain.Character
+	arenaPrize      *domain.Card
+	btnSprites      [][]*ebiten.Image
 }
 
 // ==============================================================================
@@ -77,57 +89,242 @@ type RandomEncounterScreen struct {
 //   The Lost City and Thieves Hideout have a chance of taking gold/amulets
 //   depending on the level of difficulty.
 
-func NewRandomEncounterScreen(player *domain.Player, landName string, terrainType int) *RandomEncounterScreen {
-	card := domain.FindCardByName(landName)
-	if card != nil {
-		player.CardCollection.AddCard(card, 1)
-		if am := gameaudio.Get(); am != nil {
-			am.PlaySFX(gameaudio.SFXFindCard)
-		}
-	} else {
-		fmt.Println("Warning: could not find land card:", landName)
-	}
-
+func NewRandomEncounterScreen(level *world.Level, re world.RandomEncounter) *RandomEncounterScreen {
 	btnSprites, err := imageutil.LoadSpriteSheet(3, 1, assets.Tradbut1_png)
 	if err != nil {
 		panic(err)
 	}
 
-	fontFace := &text.GoTextFace{Source: fonts.MtgFont, Size: 20}
-	btn := elements.NewButton(btnSprites[0][0], btnSprites[0][1], btnSprites[0][2], 0, 0, 1.0)
-	btn.ButtonText = elements.ButtonText{
-		Text:      "Done",
-		Font:      fontFace,
-		TextColor: color.White,
-		HAlign:    elements.AlignCenter,
-		VAlign:    elements.AlignMiddle,
+	lair := level.NewLair(re)
+	s := &RandomEncounterScreen{
+		Player:     level.Player,
+		LandName:   lair.LandName(),
+		Level:      level,
+		Lair:       lair,
+		btnSprites: btnSprites,
+	}
+	s.Background = encounterBackground(re.TerrainType, s.LandName)
+
+	if lair.Kind == world.LairNone {
+		s.grantLand()
+		s.DoneButton = s.mkButton("Done", "done")
+		return s
 	}
 
-	var cardImg *ebiten.Image
-	if card != nil {
-		cardImg, _ = card.CardImage(domain.CardViewFull)
+	if am := gameaudio.Get(); am != nil {
+		am.PlaySFX(gameaudio.SFXEncounter)
 	}
+	switch lair.Kind {
+	case world.LairOasisOfMouldoon:
+		s.oasisOffers = lair.OasisOffers()
+	case world.LairSpectralArena:
+		s.arenaChallenger, s.arenaPrize = lair.ArenaChallenger()
+	}
+	s.Lines = s.lairIntro()
+	s.DoneButton = s.mkButton("Leave", "done")
+	s.setChoices()
+	return s
+}
 
+func encounterBackground(terrainType int, landName string) *ebiten.Image {
 	bgFile := encounterBgFiles[terrainType]
 	if terrainType == world.TerrainSnow {
 		bgFile = landToBgFile[landName]
 	}
-	var bg *ebiten.Image
-	if bgFile != "" {
-		data, err := assets.RandEncounterFS.ReadFile(bgFile)
-		if err == nil {
-			bg, _ = imageutil.LoadImage(data)
+	if bgFile == "" {
+		return nil
+	}
+	data, err := assets.RandEncounterFS.ReadFile(bgFile)
+	if err != nil {
+		return nil
+	}
+	bg, _ := imageutil.LoadImage(data)
+	return bg
+}
+
+// grantLand gives the player the basic land an unnamed lair holds.
+func (s *RandomEncounterScreen) grantLand() {
+	card := domain.FindCardByName(s.LandName)
+	if card == nil {
+		logger.Warn("could not find land card", "card", s.LandName)
+		return
+	}
+	s.Player.CardCollection.AddCard(card, 1)
+	if am := gameaudio.Get(); am != nil {
+		am.PlaySFX(gameaudio.SFXFindCard)
+	}
+	s.showCard(card)
+}
+
+func (s *RandomEncounterScreen) showCard(card *domain.Card) {
+	s.Card = card
+	s.CardImg, _ = card.CardImage(domain.CardViewFull)
+}
+
+func (s *RandomEncounterScreen) mkButton(label, id string) *elements.Button {
+	return elements.NewButtonFromConfig(elements.ButtonConfig{
+		Normal:  s.btnSprites[0][0],
+		Hover:   s.btnSprites[0][1],
+		Pressed: s.btnSprites[0][2],
+		Text:    label,
+		Font:    &text.GoTextFace{Source: fonts.MtgFont, Size: 20},
+		ID:      id,
+	})
+}
+
+// lairIntro is what the player is told on arriving at a named lair.
+func (s *RandomEncounterScreen) lairIntro() []string {
+	switch s.Lair.Kind {
+	case world.LairThievesHideout:
+		return []string{"Rumor has it the thieves keep their loot here.", "They may not be far away."}
+	case world.LairOasisOfMouldoon:
+		if len(s.oasisOffers) == 0 {
+			return []string{"The spirit of the oasis wants a card from your deck,", "but you have nothing it desires."}
+		}
+		return []string{"The spirit of the oasis will trade a card from your deck", fmt.Sprintf("for a blessing of +%d life in your next duel.", world.OasisLifeBonus)}
+	case world.LairRuinedTower:
+		return []string{"Old writings cover the walls of the tower."}
+	case world.LairDiamondMine:
+		return []string{"The miners trade what they dig up for amulets.", fmt.Sprintf("You carry %d amulets.", s.amuletCount())}
+	case world.LairGemCutterGuild:
+		return []string{fmt.Sprintf("The gem cutters sell amulets for %d gold each.", world.GemCutterAmuletCost), fmt.Sprintf("You have %d gold.", s.Player.Gold)}
+	case world.LairGuardianGhost:
+		return []string{"A ghost guards the secrets of the wizards.", "It will share one for an amulet."}
+	case world.LairSpectralArena:
+		if s.arenaChallenger == nil {
+			return []string{"The arena stands empty."}
+		}
+		lines := []string{fmt.Sprintf("The spectre of %s challenges you to a duel.", s.arenaChallenger.Name)}
+		if s.arenaPrize != nil {
+			lines = append(lines, fmt.Sprintf("Defeat it and the %s is yours.", s.arenaPrize.CardName))
+		}
+		return lines
+	case world.LairLostCity:
+		return []string{"The ruins of El'Arkan hide a trove of amulets,", "but its guardians still walk the streets."}
+	case world.LairNomadsBazaar:
+		return []string{"Nomads have spread their cards out on rugs."}
+	}
+	return nil
+}
+
+func (s *RandomEncounterScreen) amuletCount() int {
+	n := 0
+	for _, c := range s.Player.Amulets {
+		n += c
+	}
+	return n
+}
+
+// setChoices builds the buttons for what the player can still do here.
+func (s *RandomEncounterScreen) setChoices() {
+	s.Choices = nil
+	add := func(label, id string) {
+		s.Choices = append(s.Choices, s.mkButton(label, id))
+	}
+	switch s.Lair.Kind {
+	case world.LairThievesHideout:
+		add("Search the hideout", "search")
+	case world.LairOasisOfMouldoon:
+		for i, c := range s.oasisOffers {
+			add("Trade "+c.CardName, fmt.Sprintf("trade_%d", i))
+		}
+	case world.LairRuinedTower:
+		add("Search the tower", "search")
+	case world.LairDiamondMine:
+		if s.amuletCount() > 0 {
+			add("Trade an amulet", "mine")
+		}
+	case world.LairGemCutterGuild:
+		if s.Player.Gold >= world.GemCutterAmuletCost {
+			for i, c := range domain.GetAllAmuletColors() {
+				add("Buy "+domain.NewAmulet(c).Name, fmt.Sprintf("buy_%d", i))
+			}
+		}
+	case world.LairGuardianGhost:
+		if s.amuletCount() > 0 {
+			add("Offer an amulet", "ghost")
+		}
+	case world.LairSpectralArena:
+		if s.arenaChallenger != nil {
+			add("Accept the challenge", "duel")
+		}
+	case world.LairLostCity:
+		add("Explore the ruins", "explore")
+	case world.LairNomadsBazaar:
+		add("Browse the wares", "browse")
+	}
+}
+
+// choose carries out the choice with id. It returns a screen to switch to
+// when the choice leads elsewhere, such as a duel.
+func (s *RandomEncounterScreen) choose(id string, W, H int) (screenui.ScreenName, screenui.Screen) {
+	lair := s.Lair
+	repeatable := false
+	var idx int
+	switch {
+	case id == "search" && lair.Kind == world.LairThievesHideout:
+		s.Lines = lair.SearchHideout()
+	case id == "search":
+		s.Lines = lair.SearchTower()
+	case id == "explore":
+		s.Lines = lair.ExploreLostCity()
+	case id == "ghost":
+		s.Lines = lair.ConsultGhost()
+	case id == "mine":
+		var card *domain.Card
+		s.Lines, card = lair.MineDiamonds()
+		if card != nil {
+			s.showCard(card)
+			if am := gameaudio.Get(); am != nil {
+				am.PlaySFX(gameaudio.SFXFindCard)
+			}
+		}
+		repeatable = true
+	case scanID(id, "buy_%d", &idx):
+		colors := domain.GetAllAmuletColors()
+		lines, err := lair.BuyAmulet(colors[idx])
+		if err != nil {
+			lines = []string{err.Error()}
+		} else if am := gameaudio.Get(); am != nil {
+			am.PlaySFX(gameaudio.SFXTreasure)
 		}
+		s.Lines = append(lines, fmt.Sprintf("You have %d gold.", s.Player.Gold))
+		repeatable = true
+	case scanID(id, "trade_%d", &idx):
+		lines, err := lair.TradeAtOasis(s.oasisOffers[idx])
+		if err != nil {
+			lines = []string{err.Error()}
+		}
+		s.Lines = lines
+	case id == "duel":
+		enemy, err := domain.NewEnemy(s.arenaChallenger.Name)
+		if err != nil {
+			s.Lines = []string{"The spectre fades away."}
+			break
+		}
+		var prize []*domain.Card
+		if s.arenaPrize != nil {
+			prize = []*domain.Card{s.arenaPrize}
+		}
+		return screenui.DuelScr, NewArenaDuelScreen(s.Player, &enemy, s.Level, prize)
+	case id == "browse":
+		shop := NewBuyCardsScreen(lair.Bazaar(), s.Player, W, H)
+		shop.ReturnScr = screenui.WorldScr
+		return screenui.BuyCardsScr, shop
 	}
 
-	return &RandomEncounterScreen{
-		Card:       card,
-		CardImg:    cardImg,
-		Background: bg,
-		Player:     player,
-		LandName:   landName,
-		DoneButton: btn,
+	if repeatable {
+		s.setChoices()
+	} else {
+		s.Choices = nil
 	}
+	s.DoneButton = s.mkButton("Done", "done")
+	return screenui.RandomEncounterScr, nil
+}
+
+func scanID(id, format string, idx *int) bool {
+	n, err := fmt.Sscanf(id, format, idx)
+	return err == nil && n == 1
 }
 
 func (s *RandomEncounterScreen) Update(W, H int, scale float64) (screenui.ScreenName, screenui.Screen, error) {
@@ -135,8 +332,19 @@ func (s *RandomEncounterScreen) Update(W, H int, scale float64) (screenui.Screen
 		return screenui.WorldScr, nil, nil
 	}
 
-	s.DoneButton.MoveTo(W/2-50, H-100)
 	opts := &ebiten.DrawImageOptions{}
+	y := H/2 + 20
+	for _, b := range s.Choices {
+		b.MoveTo(W/2-b.Bounds.Dx()/2, y)
+		y += b.Bounds.Dy() + 8
+		b.Update(opts, scale, W, H)
+		if b.IsClicked() {
+			name, screen := s.choose(b.ID, W, H)
+			return name, screen, nil
+		}
+	}
+
+	s.DoneButton.MoveTo(W/2-s.DoneButton.Bounds.Dx()/2, H-100)
 	s.DoneButton.Update(opts, scale, W, H)
 	if s.DoneButton.IsClicked() {
 		return screenui.WorldScr, nil, nil
@@ -156,6 +364,21 @@ func (s *RandomEncounterScreen) Draw(screen *ebiten.Image, W, H int, scale float
 		screen.DrawImage(s.Background, bgOpts)
 	}
 
+	if s.Lair.Kind == world.LairNone {
+		s.drawLand(screen, W, H, scale)
+	} else {
+		s.drawLair(screen, W, H, scale)
+	}
+
+	btnOpts := &ebiten.DrawImageOptions{}
+	btnOpts.GeoM.Scale(scale, scale)
+	for _, b := range s.Choices {
+		b.Draw(screen, btnOpts, scale)
+	}
+	s.DoneButton.Draw(screen, btnOpts, scale)
+}
+
+func (s *RandomEncounterScreen) drawLand(screen *ebiten.Image, W, H int, scale float64) {
 	centerX := float64(W) / 2
 	centerY := float64(H) / 2
 
@@ -174,10 +397,40 @@ func (s *RandomEncounterScreen) Draw(screen *ebiten.Image, W, H int, scale float
 	foundText.HAlign = elements.AlignCenter
 	foundText.BoundsW = float64(W)
 	foundText.Draw(screen, &ebiten.DrawImageOptions{}, 1.0)
+}
 
-	btnOpts := &ebiten.DrawImageOptions{}
-	btnOpts.GeoM.Scale(scale, scale)
-	s.DoneButton.Draw(screen, btnOpts, scale)
+// drawLair shows the lair's name and message over a dimmed panel, with the
+// card the player just gained beside it.
+func (s *RandomEncounterScreen) drawLair(screen *ebiten.Image, W, H int, scale float64) {
+	const lineH = 32
+	panelY := 40
+	panelH := 90 + lineH*len(s.Lines)
+	vector.FillRect(screen, 40, float32(panelY), float32(W-80), float32(panelH), color.RGBA{0, 0, 0, 170}, false)
+
+	title := elements.NewText(40, fmt.Sprintf("You found a %s!", s.Lair.Kind), 0, panelY+12)
+	title.HAlign = elements.AlignCenter
+	title.BoundsW = float64(W)
+	title.Color = color.RGBA{255, 230, 150, 255}
+	title.Draw(screen, &ebiten.DrawImageOptions{}, 1.0)
+
+	y := panelY + 76
+	for _, line := range s.Lines {
+		t := elements.NewText(22, line, 0, y)
+		t.HAlign = elements.AlignCenter
+		t.BoundsW = float64(W)
+		t.Color = color.White
+		t.Draw(screen, &ebiten.DrawImageOptions{}, 1.0)
+		y += lineH
+	}
+
+	if s.CardImg != nil {
+		op := &ebiten.DrawImageOptions{}
+		cardScale := scale * 0.6
+		op.GeoM.Scale(cardScale, cardScale)
+		w := float64(s.CardImg.Bounds().Dx()) * cardScale
+		op.GeoM.Translate(float64(W)-w-60, float64(panelY+panelH+20))
+		screen.DrawImage(s.CardImg, op)
+	}
 }
 
 func (s *RandomEncounterScreen) IsFramed() bool {
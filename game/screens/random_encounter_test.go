@@ -0,0 +1,87 @@
+package screens
+
+import (
+	"image"
+	"testing"
+
+	"github.com/benprew/s30/game/domain"
+	"github.com/benprew/s30/game/ui/screenui"
+	"github.com/benprew/s30/game/world"
+)
+
+func newLairTestLevel(gold int) *world.Level {
+	return &world.Level{Player: &domain.Player{
+		Gold:      gold,
+		Amulets:   make(map[domain.ColorMask]int),
+		Character: domain.Character{CardCollection: domain.NewCardCollection()},
+	}}
+}
+
+func TestUnnamedEncounterGrantsTerrainLand(t *testing.T) {
+	level := newLairTestLevel(0)
+	s := NewRandomEncounterScreen(level, world.RandomEncounter{Tile: image.Pt(1, 1), TerrainType: world.TerrainForest})
+
+	forest := domain.FindCardByName("Forest")
+	if got := level.Player.CardCollection.GetTotalCount(forest); got != 1 {
+		t.Errorf("got %d Forests, want 1", got)
+	}
+	if len(s.Choices) != 0 {
+		t.Errorf("unnamed encounter offered %d choices", len(s.Choices))
+	}
+}
+
+func TestGemCutterGuildOffersAmuletsWhileGoldLasts(t *testing.T) {
+	level := newLairTestLevel(2*world.GemCutterAmuletCost + 10)
+	s := NewRandomEncounterScreen(level, world.RandomEncounter{TerrainType: world.TerrainPlains, Lair: world.LairGemCutterGuild})
+	if len(s.Choices) != len(domain.GetAllAmuletColors()) {
+		t.Fatalf("got %d choices, want one per amulet color", len(s.Choices))
+	}
+
+	s.choose("buy_0", 1024, 768)
+	if len(s.Choices) == 0 {
+		t.Fatal("guild stopped selling with gold left for another amulet")
+	}
+	s.choose("buy_1", 1024, 768)
+	if len(s.Choices) != 0 {
+		t.Errorf("guild still offers %d amulets to a player who can't afford one", len(s.Choices))
+	}
+	if level.Player.Amulets[domain.ColorWhite] != 1 || level.Player.Amulets[domain.ColorBlue] != 1 {
+		t.Errorf("amulets = %v, want one white and one blue", level.Player.Amulets)
+	}
+}
+
+func TestOneShotLairClearsChoices(t *testing.T) {
+	level := newLairTestLevel(100)
+	s := NewRandomEncounterScreen(level, world.RandomEncounter{TerrainType: world.TerrainPlains, Lair: world.LairThievesHideout})
+	if len(s.Choices) != 1 {
+		t.Fatalf("got %d choices, want 1", len(s.Choices))
+	}
+
+	name, next := s.choose("search", 1024, 768)
+	if name != screenui.RandomEncounterScr || next != nil {
+		t.Errorf("searching the hideout moved to %s", screenui.ScreenNameToString(name))
+	}
+	if len(s.Choices) != 0 {
+		t.Error("hideout can be searched twice")
+	}
+	if len(s.Lines) == 0 {
+		t.Error("no outcome shown")
+	}
+}
+
+func TestNomadsBazaarReturnsToTheWorld(t *testing.T) {
+	level := newLairTestLevel(100)
+	s := NewRandomEncounterScreen(level, world.RandomEncounter{TerrainType: world.TerrainPlains, Lair: world.LairNomadsBazaar})
+
+	name, next := s.choose("browse", 1024, 768)
+	shop, ok := next.(*BuyCardsScreen)
+	if name != screenui.BuyCardsScr || !ok {
+		t.Fatalf("browsing went to %s, want the buy cards screen", screenui.ScreenNameToString(name))
+	}
+	if len(shop.City.CardsForSale) == 0 {
+		t.Error("bazaar has nothing for sale")
+	}
+	if shop.ReturnScr != screenui.WorldScr {
+		t.Errorf("leaving the bazaar goes to %s, want the world", screenui.ScreenNameToString(shop.ReturnScr))
+	}
+}
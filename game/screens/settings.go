@@ -0,0 +1,262 @@
+package screens
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+
+	gameaudio "github.com/benprew/s30/game/audio"
+	"github.com/benprew/s30/game/settings"
+	"github.com/benprew/s30/game/ui"
+	"github.com/benprew/s30/game/ui/elements"
+	"github.com/benprew/s30/game/ui/screenui"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// Settings rows sit on the quest panel; each has a label on the left and its
+// value between a pair of arrows on the right.
+const (
+	settingsRowY      = questPanelY + 64
+	settingsRowH      = 38
+	settingsLabelX    = questPanelX + 40
+	settingsLeftX     = questPanelX + 430
+	settingsValueX    = questPanelX + 460
+	settingsValueW    = 200
+	settingsRightX    = settingsValueX + settingsValueW + 10
+	settingsArrowSize = 30
+)
+
+// settingsRow is one option on the settings screen. change moves the option
+// dir steps, or flips it for an on/off option.
+type settingsRow struct {
+	label  string
+	value  func(s settings.Settings) string
+	change func(s *settings.Settings, dir int)
+}
+
+var settingsRows = []settingsRow{
+	{
+		label:  "Music volume",
+		value:  func(s settings.Settings) string { return volumeLabel(s.BGMVolume) },
+		change: func(s *settings.Settings, dir int) { s.BGMVolume = settings.StepVolume(s.BGMVolume, dir) },
+	},
+	{
+		label:  "Sound effects volume",
+		value:  func(s settings.Settings) string { return volumeLabel(s.SFXVolume) },
+		change: func(s *settings.Settings, dir int) { s.SFXVolume = settings.StepVolume(s.SFXVolume, dir) },
+	},
+	{
+		label:  "Sound (M)",
+		value:  func(s settings.Settings) string { return onOffLabel(!s.Muted) },
+		change: func(s *settings.Settings, dir int) { s.Muted = !s.Muted },
+	},
+	{
+		label: "Window size",
+		value: func(s settings.Settings) string {
+			return fmt.Sprintf("%dx%d", int(virtScreenW*s.WindowScale), int(virtScreenH*s.WindowScale))
+		},
+		change: func(s *settings.Settings, dir int) { s.WindowScale = s.StepWindowScale(dir) },
+	},
+	{
+		label:  "Fullscreen",
+		value:  func(s settings.Settings) string { return onOffLabel(s.Fullscreen) },
+		change: func(s *settings.Settings, dir int) { s.Fullscreen = !s.Fullscreen },
+	},
+	{
+		label:  "Duel message speed",
+		value:  func(s settings.Settings) string { return s.Pacing.String() },
+		change: func(s *settings.Settings, dir int) { s.Pacing = s.Pacing.Step(dir) },
+	},
+	{
+		label:  "Auto-pass with nothing to play",
+		value:  func(s settings.Settings) string { return onOffLabel(s.AutoPassNoPlays) },
+		change: func(s *settings.Settings, dir int) { s.AutoPassNoPlays = !s.AutoPassNoPlays },
+	},
+	{
+		label:  "Auto-pass on opponent's turn",
+		value:  func(s settings.Settings) string { return onOffLabel(s.AutoPassOpponentTurn) },
+		change: func(s *settings.Settings, dir int) { s.AutoPassOpponentTurn = !s.AutoPassOpponentTurn },
+	},
+	{
+		label:  "Show opponent's hand",
+		value:  func(s settings.Settings) string { return onOffLabel(s.ShowOpponentHand) },
+		change: func(s *settings.Settings, dir int) { s.ShowOpponentHand = !s.ShowOpponentHand },
+	},
+}
+
+func volumeLabel(v float64) string {
+	return fmt.Sprintf("%d%%", int(v*100+0.5))
+}
+
+func onOffLabel(on bool) string {
+	if on {
+		return "On"
+	}
+	return "Off"
+}
+
+// SettingsScreen is the overlay for the player's audio, display and duel
+// settings, opened from the start screen and the world frame. It reads the
+// game's settings through current and hands every change to change, which
+// applies and saves it, so nothing is lost if the game is closed with the
+// screen open. The arrow keys move between options and change them; Escape
+// or Enter closes the screen.
+type SettingsScreen struct {
+	current  func() settings.Settings
+	change   func(settings.Settings)
+	selected int
+	doneBtn  *elements.Button
+	panelBg  *ebiten.Image
+	dimBg    *ebiten.Image
+}
+
+func NewSettingsScreen(current func() settings.Settings, change func(settings.Settings)) *SettingsScreen {
+	panelBg := ebiten.NewImage(questPanelW, questPanelH)
+	panelBg.Fill(color.RGBA{20, 12, 4, 235})
+	dimBg := ebiten.NewImage(virtScreenW, virtScreenH)
+	dimBg.Fill(color.RGBA{0, 0, 0, 140})
+	doneBtn := makeOverlayButton("Done", "done", questPanelX+questPanelW/2)
+	doneBtn.MoveTo(doneBtn.Bounds.Min.X, questPanelY+questPanelH-60)
+	return &SettingsScreen{
+		current: current,
+		change:  change,
+		doneBtn: doneBtn,
+		panelBg: panelBg,
+		dimBg:   dimBg,
+	}
+}
+
+func (s *SettingsScreen) IsFramed() bool { return false }
+
+func (s *SettingsScreen) IsOverlay() bool { return true }
+
+func (s *SettingsScreen) Update(W, H int, scale float64) (screenui.ScreenName, screenui.Screen, error) {
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) || inpututil.IsKeyJustPressed(ebiten.KeyEnter) {
+		return s.close()
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyUp) {
+		s.selected = (s.selected + len(settingsRows) - 1) % len(settingsRows)
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyDown) {
+		s.selected = (s.selected + 1) % len(settingsRows)
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyLeft) {
+		s.step(s.selected, -1)
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyRight) {
+		s.step(s.selected, 1)
+	}
+
+	pos := ui.Position()
+	for i := range settingsRows {
+		if pos.In(settingsRowBounds(i, scale)) {
+			s.selected = i
+		}
+		if ui.Click(settingsArrowBounds(i, -1, scale)) {
+			s.step(i, -1)
+		}
+		if ui.Click(settingsArrowBounds(i, 1, scale)) {
+			s.step(i, 1)
+		}
+	}
+
+	s.doneBtn.Update(&ebiten.DrawImageOptions{}, scale, W, H)
+	if s.doneBtn.IsClicked() {
+		s.doneBtn.State = elements.StateNormal
+		return s.close()
+	}
+	return screenui.SettingsScr, nil, nil
+}
+
+// step changes row i by dir and hands the new settings to the game.
+func (s *SettingsScreen) step(i, dir int) {
+	cur := s.current()
+	settingsRows[i].change(&cur, dir)
+	s.change(cur)
+	if am := gameaudio.Get(); am != nil {
+		am.PlaySFX(gameaudio.SFXClick2)
+	}
+}
+
+// close leaves the settings screen with the first option selected next time.
+func (s *SettingsScreen) close() (screenui.ScreenName, screenui.Screen, error) {
+	s.selected = 0
+	return screenui.PopScr, nil, nil
+}
+
+func (s *SettingsScreen) Draw(screen *ebiten.Image, W, H int, scale float64) {
+	dimOpts := &ebiten.DrawImageOptions{}
+	dimOpts.GeoM.Scale(scale, scale)
+	screen.DrawImage(s.dimBg, dimOpts)
+
+	panelOpts := &ebiten.DrawImageOptions{}
+	panelOpts.GeoM.Scale(scale, scale)
+	panelOpts.GeoM.Translate(float64(questPanelX)*scale, float64(questPanelY)*scale)
+	screen.DrawImage(s.panelBg, panelOpts)
+
+	title := elements.NewText(28, "Settings", questPanelX+20, questPanelY+16)
+	title.Color = color.White
+	title.Draw(screen, &ebiten.DrawImageOptions{}, scale)
+
+	cur := s.current()
+	for i, row := range settingsRows {
+		y := settingsRowY + i*settingsRowH
+		clr := color.Color(color.RGBA{R: 230, G: 220, B: 200, A: 255})
+		if i == s.selected {
+			clr = color.RGBA{R: 255, G: 215, B: 90, A: 255}
+		}
+
+		label := elements.NewText(22, row.label, settingsLabelX, y)
+		label.Color = clr
+		label.Draw(screen, &ebiten.DrawImageOptions{}, scale)
+
+		for _, dir := range []int{-1, 1} {
+			arrow := "<"
+			if dir > 0 {
+				arrow = ">"
+			}
+			b := settingsArrowBounds(i, dir, 1)
+			txt := elements.NewText(22, arrow, b.Min.X, b.Min.Y)
+			txt.Color = clr
+			txt.BoundsW = float64(b.Dx())
+			txt.BoundsH = float64(b.Dy())
+			txt.HAlign = elements.AlignCenter
+			txt.VAlign = elements.AlignMiddle
+			txt.Draw(screen, &ebiten.DrawImageOptions{}, scale)
+		}
+
+		value := elements.NewText(22, row.value(cur), settingsValueX, y)
+		value.Color = clr
+		value.BoundsW = settingsValueW
+		value.HAlign = elements.AlignCenter
+		value.VAlign = elements.AlignTop
+		value.Draw(screen, &ebiten.DrawImageOptions{}, scale)
+	}
+
+	s.doneBtn.Draw(screen, &ebiten.DrawImageOptions{}, scale)
+}
+
+// settingsRowBounds returns the area of row i, scaled to the screen.
+func settingsRowBounds(i int, scale float64) image.Rectangle {
+	y := settingsRowY + i*settingsRowH
+	return scaleRect(image.Rect(questPanelX, y-6, questPanelX+questPanelW, y+settingsRowH-6), scale)
+}
+
+// settingsArrowBounds returns the area of row i's left arrow, or its right
+// arrow when dir is positive, scaled to the screen.
+func settingsArrowBounds(i, dir int, scale float64) image.Rectangle {
+	x := settingsLeftX
+	if dir > 0 {
+		x = settingsRightX
+	}
+	y := settingsRowY + i*settingsRowH - 2
+	return scaleRect(image.Rect(x, y, x+settingsArrowSize, y+settingsArrowSize), scale)
+}
+
+func scaleRect(r image.Rectangle, scale float64) image.Rectangle {
+	return image.Rect(
+		int(float64(r.Min.X)*scale), int(float64(r.Min.Y)*scale),
+		int(float64(r.Max.X)*scale), int(float64(r.Max.Y)*scale),
+	)
+}
@@ -0,0 +1,89 @@
+package screens
+
+import (
+	"testing"
+
+	"github.com/benprew/s30/game/settings"
+	"github.com/benprew/s30/game/ui/screenui"
+)
+
+func newTestSettingsScreen() (*SettingsScreen, *settings.Settings, *int) {
+	cur := settings.Default()
+	changes := 0
+	s := NewSettingsScreen(
+		func() settings.Settings { return cur },
+		func(next settings.Settings) {
+			cur = next
+			changes++
+		},
+	)
+	return s, &cur, &changes
+}
+
+func settingsRowIndex(t *testing.T, label string) int {
+	t.Helper()
+	for i, row := range settingsRows {
+		if row.label == label {
+			return i
+		}
+	}
+	t.Fatalf("no settings row %q", label)
+	return -1
+}
+
+func TestSettingsScreenStepsVolumes(t *testing.T) {
+	s, cur, changes := newTestSettingsScreen()
+	music := settingsRowIndex(t, "Music volume")
+
+	s.step(music, 1)
+	if cur.BGMVolume != 0.3 {
+		t.Errorf("BGMVolume = %f after stepping up, want 0.3", cur.BGMVolume)
+	}
+	if got := settingsRows[music].value(*cur); got != "30%" {
+		t.Errorf("music volume shows %q, want 30%%", got)
+	}
+	for range 5 {
+		s.step(music, -1)
+	}
+	if cur.BGMVolume != 0 {
+		t.Errorf("BGMVolume = %f after stepping past the bottom, want 0", cur.BGMVolume)
+	}
+	if *changes != 6 {
+		t.Errorf("change called %d times, want 6", *changes)
+	}
+}
+
+func TestSettingsScreenTogglesOptions(t *testing.T) {
+	s, cur, _ := newTestSettingsScreen()
+
+	s.step(settingsRowIndex(t, "Show opponent's hand"), 1)
+	s.step(settingsRowIndex(t, "Auto-pass on opponent's turn"), -1)
+	s.step(settingsRowIndex(t, "Sound (M)"), 1)
+	if !cur.ShowOpponentHand || !cur.AutoPassOpponentTurn || !cur.Muted {
+		t.Errorf("settings = %+v, want hand shown, opponent turns passed and sound muted", *cur)
+	}
+
+	window := settingsRowIndex(t, "Window size")
+	s.step(window, 1)
+	if got := settingsRows[window].value(*cur); got != "1280x960" {
+		t.Errorf("window size shows %q, want 1280x960", got)
+	}
+	speed := settingsRowIndex(t, "Duel message speed")
+	s.step(speed, 1)
+	if cur.Pacing != settings.PacingSlow {
+		t.Errorf("Pacing = %s, want Slow", cur.Pacing)
+	}
+}
+
+func TestSettingsScreenCloseResetsSelection(t *testing.T) {
+	s, _, _ := newTestSettingsScreen()
+	s.selected = 4
+
+	name, _, err := s.close()
+	if err != nil || name != screenui.PopScr {
+		t.Fatalf("close = %v, %v; want PopScr", name, err)
+	}
+	if s.selected != 0 {
+		t.Errorf("selected = %d after closing, want 0", s.selected)
+	}
+}
@@ -3,15 +3,18 @@ package screens
 import (
 	"fmt"
 	"image"
+	"slices"
 	"strings"
 
 	"github.com/benprew/s30/assets"
+	"github.com/benprew/s30/game/bugreport"
 	"github.com/benprew/s30/game/domain"
 	"github.com/benprew/s30/game/save"
 	"github.com/benprew/s30/game/ui/elements"
 	"github.com/benprew/s30/game/ui/fonts"
 	"github.com/benprew/s30/game/ui/imageutil"
 	"github.com/benprew/s30/game/ui/screenui"
+	"github.com/benprew/s30/game/world"
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/text/v2"
 )
@@ -21,6 +24,7 @@ type startMode int
 const (
 	startModeMenu startMode = iota
 	startModeLoad
+	startModeReports
 	startModeDifficulty
 	startModeColor
 )
@@ -32,18 +36,33 @@ type StartScreen struct {
 	menu3Bg            *ebiten.Image
 	newGameBtn         *elements.Button
 	loadGameBtn        *elements.Button
+	settingsBtn        *elements.Button
+	reportsBtn         *elements.Button
 	backBtn            *elements.Button
 	saveButtons        []*elements.Button
+	reportButtons      []*elements.Button
 	difficultyButtons  []*elements.Button
 	colorButtons       []*elements.Button
 	difficultyLabels   []string
+	seedInput          *elements.TextInput
+	mapBtn             *elements.Button
 	saves              []save.SaveInfo
 	hasSaves           bool
 	savesChecked       bool
 	SelectedSave       string
 	SelectedDifficulty domain.Difficulty
 	SelectedColor      domain.ColorMask
-	NewGame            bool
+	// SelectedSeed is the world seed typed or shared on the difficulty screen.
+	SelectedSeed int64
+	// SelectedMap names the bundled map to lay the world out from, or is
+	// empty to generate it from the seed.
+	SelectedMap string
+	NewGame     bool
+	// SelectedReport is the bug report to restore the game from, picked
+	// from reports.
+	SelectedReport string
+	reports        []bugreport.ReportInfo
+	hasReports     bool
 }
 
 func (s *StartScreen) IsFramed() bool { return false }
@@ -54,6 +73,21 @@ func (s *StartScreen) IsOverlay() bool { return false }
 const virtScreenW = 1024
 const virtScreenH = 768
 
+// seedInputX/Y place the world seed field on the difficulty screen.
+const seedInputX = 60
+const seedInputY = 690
+
+// worldMaps are the worlds the difficulty screen cycles through: a generated
+// one, then the bundled maps.
+var worldMaps = []string{"", world.ShandalarMapName}
+
+func worldMapLabel(name string) string {
+	if name == "" {
+		return "World: Random"
+	}
+	return "World: " + name
+}
+
 var difficultyOrder = []domain.Difficulty{
 	domain.DifficultyEasy,   // Apprentice
 	domain.DifficultyMedium, // Magician
@@ -138,6 +172,30 @@ func NewStartScreen() *StartScreen {
 		Y:       btnY + newGameH + 20,
 	})
 
+	settingsW, _ := elements.TextButtonSize("Settings", fontFace)
+	s.settingsBtn = elements.NewButtonFromConfig(elements.ButtonConfig{
+		Normal:  btnSprites[0][0],
+		Hover:   btnSprites[0][1],
+		Pressed: btnSprites[0][2],
+		Text:    "Settings",
+		Font:    fontFace,
+		ID:      "settings",
+		X:       centerX - settingsW/2,
+		Y:       btnY + newGameH + 20,
+	})
+
+	reportsW, _ := elements.TextButtonSize("Bug Reports", fontFace)
+	s.reportsBtn = elements.NewButtonFromConfig(elements.ButtonConfig{
+		Normal:  btnSprites[0][0],
+		Hover:   btnSprites[0][1],
+		Pressed: btnSprites[0][2],
+		Text:    "Bug Reports",
+		Font:    fontFace,
+		ID:      "bug_reports",
+		X:       centerX - reportsW/2,
+		Y:       btnY + 2*(newGameH+20),
+	})
+
 	backW, _ := elements.TextButtonSize("Back", fontFace)
 	s.backBtn = elements.NewButtonFromConfig(elements.ButtonConfig{
 		Normal:  btnSprites[0][0],
@@ -173,9 +231,36 @@ func NewStartScreen() *StartScreen {
 	})
 	s.difficultyLabels = difficultyLabelText
 
+	// The seed field sits below the warrior portrait. It is pre-filled with a
+	// random seed so the player can note it down or replace it with a shared one.
+	s.seedInput = elements.NewTextInput(seedInputX, seedInputY, 300, 44, "Random")
+	s.seedInput.Multiline = false
+	s.seedInput.Focused = false
+
+	// The world map button sits beside the seed; it is sized for the longest
+	// label so it doesn't change size as it cycles.
+	s.mapBtn = elements.NewButtonFromConfig(elements.ButtonConfig{
+		Normal:  btnSprites[0][0],
+		Hover:   btnSprites[0][1],
+		Pressed: btnSprites[0][2],
+		Text:    worldMapLabel(world.ShandalarMapName),
+		Font:    &text.GoTextFace{Source: fonts.MtgFont, Size: 20},
+		ID:      "world_map",
+		X:       seedInputX + 320,
+		Y:       seedInputY - 4,
+	})
+	s.mapBtn.ButtonText.Text = worldMapLabel(s.SelectedMap)
+
 	return s
 }
 
+// cycleWorldMap moves to the next world on the difficulty screen.
+func (s *StartScreen) cycleWorldMap() {
+	i := (slices.Index(worldMaps, s.SelectedMap) + 1) % len(worldMaps)
+	s.SelectedMap = worldMaps[i]
+	s.mapBtn.ButtonText.Text = worldMapLabel(s.SelectedMap)
+}
+
 // scaledFullScreen loads a 640x480 .pic.png background and scales it to fill
 // the 1024x768 virtual canvas.
 func scaledFullScreen(asset []byte) *ebiten.Image {
@@ -260,6 +345,7 @@ func (s *StartScreen) Update(W, H int, scale float64) (screenui.ScreenName, scre
 		if s.newGameBtn.IsClicked() {
 			s.NewGame = true
 			s.mode = startModeDifficulty
+			s.seedInput.SetText(fmt.Sprint(world.NewSeed()))
 			s.newGameBtn.State = elements.StateNormal
 			return screenui.StartScr, nil, nil
 		}
@@ -271,6 +357,20 @@ func (s *StartScreen) Update(W, H int, scale float64) (screenui.ScreenName, scre
 			}
 		}
 
+		s.settingsBtn.Update(opts, scale, W, H)
+		if s.settingsBtn.IsClicked() {
+			s.settingsBtn.State = elements.StateNormal
+			return screenui.SettingsScr, nil, nil
+		}
+
+		if s.hasReports {
+			s.reportsBtn.Update(opts, scale, W, H)
+			if s.reportsBtn.IsClicked() {
+				s.reportsBtn.State = elements.StateNormal
+				s.loadReportList()
+			}
+		}
+
 	case startModeLoad:
 		for _, btn := range s.saveButtons {
 			btn.Update(opts, scale, W, H)
@@ -289,11 +389,36 @@ func (s *StartScreen) Update(W, H int, scale float64) (screenui.ScreenName, scre
 			s.backBtn.State = elements.StateNormal
 		}
 
+	case startModeReports:
+		for _, btn := range s.reportButtons {
+			btn.Update(opts, scale, W, H)
+		}
+		s.backBtn.Update(opts, scale, W, H)
+
+		for i, btn := range s.reportButtons {
+			if btn.IsClicked() {
+				s.SelectedReport = s.reports[i].Path
+				return screenui.WorldScr, nil, nil
+			}
+		}
+
+		if s.backBtn.IsClicked() {
+			s.mode = startModeMenu
+			s.backBtn.State = elements.StateNormal
+		}
+
 	case startModeDifficulty:
+		s.seedInput.Update(scale)
+		s.mapBtn.Update(opts, scale, W, H)
+		if s.mapBtn.IsClicked() {
+			s.mapBtn.State = elements.StateNormal
+			s.cycleWorldMap()
+		}
 		for i, btn := range s.difficultyButtons {
 			btn.Update(opts, scale, W, H)
 			if btn.IsClicked() {
 				s.SelectedDifficulty = difficultyOrder[i]
+				s.SelectedSeed = world.ParseSeed(s.seedInput.Text())
 				s.mode = startModeColor
 				btn.State = elements.StateNormal
 				return screenui.StartScr, nil, nil
@@ -325,6 +450,21 @@ func (s *StartScreen) refreshHasSaves() {
 	if err == nil {
 		s.hasSaves = len(saves) > 0
 	}
+	reports, err := bugreport.ListReports()
+	s.hasReports = err == nil && len(reports) > 0
+	s.placeSettingsButton()
+}
+
+// placeSettingsButton puts the settings button below the load game button,
+// or in its place when there are no saves to load, and the bug reports
+// button below that.
+func (s *StartScreen) placeSettingsButton() {
+	y := s.loadGameBtn.Bounds.Min.Y
+	if s.hasSaves {
+		y = s.loadGameBtn.Bounds.Max.Y + 20
+	}
+	s.settingsBtn.MoveTo(s.settingsBtn.Bounds.Min.X, y)
+	s.reportsBtn.MoveTo(s.reportsBtn.Bounds.Min.X, s.settingsBtn.Bounds.Max.Y+20)
 }
 
 func (s *StartScreen) Draw(screen *ebiten.Image, W, H int, scale float64) {
@@ -337,28 +477,34 @@ func (s *StartScreen) Draw(screen *ebiten.Image, W, H int, scale float64) {
 		if s.hasSaves {
 			s.loadGameBtn.Draw(screen, opts, scale)
 		}
+		s.settingsBtn.Draw(screen, opts, scale)
+		if s.hasReports {
+			s.reportsBtn.Draw(screen, opts, scale)
+		}
 
-	case startModeLoad:
+	case startModeLoad, startModeReports:
 		screen.DrawImage(s.background, &ebiten.DrawImageOptions{})
 		headerFont := &text.GoTextFace{Source: fonts.MtgFont, Size: 30}
-		headerText := "Load Game"
+		headerText, noneText, buttons := "Load Game", "No saved games found", s.saveButtons
+		if s.mode == startModeReports {
+			headerText, noneText, buttons = "Restore Bug Report", "No bug reports found", s.reportButtons
+		}
 		headerW, _ := text.Measure(headerText, headerFont, 0)
 		headerOpts := &text.DrawOptions{}
 		headerOpts.GeoM.Translate(float64(W)/2-headerW/2, 280)
 		headerOpts.ColorScale.Scale(1, 1, 1, 1)
 		text.Draw(screen, headerText, headerFont, headerOpts)
 
-		if len(s.saveButtons) == 0 {
+		if len(buttons) == 0 {
 			noSavesFont := &text.GoTextFace{Source: fonts.MtgFont, Size: 20}
-			noSavesText := "No saved games found"
-			noSavesW, _ := text.Measure(noSavesText, noSavesFont, 0)
+			noSavesW, _ := text.Measure(noneText, noSavesFont, 0)
 			noSavesOpts := &text.DrawOptions{}
 			noSavesOpts.GeoM.Translate(float64(W)/2-noSavesW/2, 380)
 			noSavesOpts.ColorScale.Scale(0.7, 0.7, 0.7, 1)
-			text.Draw(screen, noSavesText, noSavesFont, noSavesOpts)
+			text.Draw(screen, noneText, noSavesFont, noSavesOpts)
 		}
 
-		for _, btn := range s.saveButtons {
+		for _, btn := range buttons {
 			btn.Draw(screen, opts, scale)
 		}
 
@@ -395,6 +541,14 @@ func (s *StartScreen) Draw(screen *ebiten.Image, W, H int, scale float64) {
 			text.Draw(screen, label, labelFont, lblOpts)
 		}
 
+		seedFont := &text.GoTextFace{Source: fonts.MtgFont, Size: 22}
+		seedOpts := &text.DrawOptions{}
+		seedOpts.GeoM.Translate(seedInputX, seedInputY-30)
+		seedOpts.ColorScale.Scale(1, 1, 1, 1)
+		text.Draw(screen, "World Seed", seedFont, seedOpts)
+		s.seedInput.Draw(screen, scale)
+		s.mapBtn.Draw(screen, opts, scale)
+
 	case startModeColor:
 		screen.DrawImage(s.menu3Bg, &ebiten.DrawImageOptions{})
 
@@ -463,34 +617,64 @@ func (s *StartScreen) loadSaveList() {
 
 	saveDir, err := save.SaveDir()
 	if err != nil {
-		fmt.Printf("Error getting save directory: %v\n", err)
+		logger.Error("failed to get save directory", "err", err)
 		return
 	}
 
 	saves, err := save.ListSaves(saveDir)
 	if err != nil {
-		fmt.Printf("Error listing saves: %v\n", err)
+		logger.Error("failed to list saves", "err", err)
 		return
 	}
 
 	s.saves = saves
-	s.saveButtons = nil
+	labels := make([]string, len(saves))
+	for i, sv := range saves {
+		labels[i] = fmt.Sprintf("%s  -  %s", sv.Name, sv.SavedAt.Format("Jan 02 2006 15:04"))
+	}
+	s.saveButtons = listButtons(labels, "save")
+}
 
-	btnSprites, err := imageutil.LoadSpriteSheet(3, 1, assets.Tradbut1_png)
+// loadReportList lists the bug and crash reports the game can be restored
+// from, newest first.
+func (s *StartScreen) loadReportList() {
+	s.mode = startModeReports
+
+	reports, err := bugreport.ListReports()
 	if err != nil {
-		fmt.Printf("Error loading button sprites: %v\n", err)
+		logger.Error("failed to list bug reports", "err", err)
 		return
 	}
+
+	s.reports = reports
+	labels := make([]string, len(reports))
+	for i, r := range reports {
+		labels[i] = fmt.Sprintf("%s  -  %s", r.Timestamp.Local().Format("Jan 02 2006 15:04"), r.Title)
+		if r.HasDuel {
+			labels[i] += " (duel)"
+		}
+	}
+	s.reportButtons = listButtons(labels, "report")
+}
+
+// listButtons stacks a button for each of the first eight labels down the
+// middle of the screen.
+func listButtons(labels []string, idPrefix string) []*elements.Button {
+	btnSprites, err := imageutil.LoadSpriteSheet(3, 1, assets.Tradbut1_png)
+	if err != nil {
+		logger.Error("failed to load button sprites", "err", err)
+		return nil
+	}
 	fontFace := &text.GoTextFace{Source: fonts.MtgFont, Size: 18}
 
-	maxVisible := min(len(saves), 8)
+	maxVisible := min(len(labels), 8)
 
 	centerX := 512
 	startY := 330
 
+	var buttons []*elements.Button
 	for i := range maxVisible {
-		sv := saves[i]
-		label := fmt.Sprintf("%s  -  %s", sv.Name, sv.SavedAt.Format("Jan 02 2006 15:04"))
+		label := labels[i]
 		btnW, btnH := elements.TextButtonSize(label, fontFace)
 
 		btn := elements.NewButtonFromConfig(elements.ButtonConfig{
@@ -499,10 +683,11 @@ func (s *StartScreen) loadSaveList() {
 			Pressed: btnSprites[0][2],
 			Text:    label,
 			Font:    fontFace,
-			ID:      fmt.Sprintf("save_%d", i),
+			ID:      fmt.Sprintf("%s_%d", idPrefix, i),
 			X:       centerX - btnW/2,
 			Y:       startY + i*(btnH+10),
 		})
-		s.saveButtons = append(s.saveButtons, btn)
+		buttons = append(buttons, btn)
 	}
+	return buttons
 }
@@ -279,7 +279,7 @@ func (s *WisemanScreen) generateDefeatEnemyQuest() {
 func (s *WisemanScreen) spawnQuestEnemy(enemyName string) {
 	cityTile := image.Point{X: s.City.X, Y: s.City.Y}
 	if err := s.Level.SpawnEnemyNear(enemyName, cityTile); err != nil {
-		fmt.Println("Failed to spawn quest enemy:", err)
+		logger.Error("failed to spawn quest enemy", "err", err)
 	}
 }
 
@@ -432,6 +432,11 @@ var questFlavor = map[string][]string{
 		"Win your duel without sending a single attacker,",
 		"and the village will sing of your restraint.",
 	},
+	"old_school_win": {
+		"Before the Dominarian rifts, mages fought",
+		"with the first spells ever scribed. Build a deck",
+		"the old masters would own and win with it.",
+	},
 }
 
 // questFlavorLines returns a fresh copy of the Wiseman's flavor intro for a
@@ -603,6 +608,8 @@ func (s *WisemanScreen) grantBoon() {
 		s.giveWorldMagicLocation()
 	case domain.BoonBonusCard:
 		s.giveBonusCard()
+	case domain.BoonDungeonClue:
+		s.giveDungeonClue()
 	default:
 		s.loadStory()
 		return
@@ -697,6 +704,33 @@ func (s *WisemanScreen) giveBonusCard() {
 	}
 }
 
+// wisemanTextW is how wide (in design px) the Wiseman's lines may run before
+// they wrap.
+const wisemanTextW = 420
+
+// giveDungeonClue tells the player a clue to one of the dungeons they haven't
+// found yet, which also goes into their clue journal.
+func (s *WisemanScreen) giveDungeonClue() {
+	if s.Level == nil {
+		s.loadStory()
+		return
+	}
+	d, clue, ok := s.Level.RevealDungeonClue(image.Point{X: s.City.X, Y: s.City.Y})
+	if !ok {
+		s.loadStory()
+		return
+	}
+	face := &text.GoTextFace{Source: fonts.MtgFont, Size: 24}
+	s.TextLines = []string{
+		"I have walked the dark places",
+		fmt.Sprintf("of this land. Of %s,", d.Name),
+		"I can tell you this:",
+		"",
+	}
+	s.TextLines = append(s.TextLines, wrapText(clue.Text, face, wisemanTextW)...)
+	s.TextLines = append(s.TextLines, "", "The clue is in your journal.")
+}
+
 // --- Story text ---
 
 func loadStories() []string {
@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/benprew/s30/game/domain"
+	"github.com/benprew/s30/game/world"
 )
 
 func TestWisemanStoryClickUsesViewport(t *testing.T) {
@@ -111,6 +112,54 @@ func TestGrantBoonEnemyDeckInfo(t *testing.T) {
 	}
 }
 
+func TestGrantBoonDungeonClue(t *testing.T) {
+	city := &domain.City{WisemanBoon: domain.BoonDungeonClue, X: 2, Y: 2}
+	player := &domain.Player{}
+	dungeon := &domain.Dungeon{Name: "Ember Spire", MapTile: image.Pt(8, 8)}
+	level := &world.Level{Player: player, Dungeons: []*domain.Dungeon{dungeon}}
+	s := &WisemanScreen{City: city, Player: player, Level: level}
+
+	s.grantBoon()
+
+	if !city.BoonGranted {
+		t.Error("Expected BoonGranted to be true")
+	}
+	if !dungeon.Clues[domain.ClueLocation].Revealed {
+		t.Error("the Wiseman revealed no clue")
+	}
+	if got := player.Clues.RevealedClues["Ember Spire"]; len(got) != 1 {
+		t.Errorf("journal has %d clues to Ember Spire, want 1", len(got))
+	}
+	if !strings.Contains(strings.Join(s.TextLines, " "), "Ember Spire") {
+		t.Errorf("Wiseman's words %v don't name the dungeon", s.TextLines)
+	}
+}
+
+func TestPickBoonOffersDungeonCluesOnlyWhileSomeAreLeft(t *testing.T) {
+	city := &domain.City{Name: "TestCity", X: 2, Y: 2}
+	player := &domain.Player{}
+	dungeon := &domain.Dungeon{Name: "Ember Spire", MapTile: image.Pt(8, 8)}
+	level := &world.Level{Player: player, Dungeons: []*domain.Dungeon{dungeon}}
+
+	offered := false
+	for range 200 {
+		if pickBoon(city, player, level) == domain.BoonDungeonClue {
+			offered = true
+			break
+		}
+	}
+	if !offered {
+		t.Error("pickBoon never offered a dungeon clue")
+	}
+
+	dungeon.Cleared = true
+	for range 200 {
+		if pickBoon(city, player, level) == domain.BoonDungeonClue {
+			t.Fatal("pickBoon offered a clue with none left to find")
+		}
+	}
+}
+
 func TestBoonGrantedShowsStory(t *testing.T) {
 	disableDeckQuestOffers(t)
 	city := &domain.City{
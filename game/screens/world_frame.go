@@ -11,15 +11,17 @@ import (
 	"github.com/benprew/s30/game/domain"
 	"github.com/benprew/s30/game/ui"
 	"github.com/benprew/s30/game/ui/elements"
+	"github.com/benprew/s30/game/ui/fonts"
 	"github.com/benprew/s30/game/ui/imageutil"
 	"github.com/benprew/s30/game/ui/screenui"
 	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
 )
 
 // This is the frame that you see when you're walking around the world and in cities
 //
 // World frame shows character stats, current quest, available money, etc
-// And has buttons to go to the minimap
+// And has buttons to go to the minimap, the dungeon clue journal and settings
 // Not technically a screen, but it has draw and update functions, so I'm including it here
 
 // Buybuttons.spr.png - buy buttons
@@ -86,9 +88,14 @@ func NewWorldFrame(p *domain.Player) (*WorldFrame, error) {
 		return nil, err
 	}
 
+	btnSprs, err := imageutil.LoadSpriteSheet(3, 1, assets.Tradbut1_png)
+	if err != nil {
+		return nil, err
+	}
+
 	return &WorldFrame{
 		img:               img,
-		Buttons:           mkWfButtons(worldSprs),
+		Buttons:           append(mkWfButtons(worldSprs), mkWfSettingsButton(btnSprs[0])),
 		player:            p,
 		amuletSprites:     amuletSprs[0],
 		questScrollEmpty:  questSprs[0][0],
@@ -196,6 +203,16 @@ func (f *WorldFrame) Update(W, H int, scale float64) (screenui.ScreenName, scree
 		if b.ID == "minimap" && b.IsClicked() {
 			return screenui.MiniMapScr, nil, nil
 		}
+		if b.ID == "dungeon" && b.IsClicked() {
+			if am := gameaudio.Get(); am != nil {
+				am.PlaySFX(gameaudio.SFXClick2)
+			}
+			return screenui.ClueJournalScr, nil, nil
+		}
+		if b.ID == "settings" && b.IsClicked() {
+			b.State = elements.StateNormal
+			return screenui.SettingsScr, nil, nil
+		}
 		if (b.ID == "character" || b.ID == "book") && b.IsClicked() {
 			if am := gameaudio.Get(); am != nil {
 				am.PlayBGM(gameaudio.BGMStatsScreen)
@@ -235,16 +252,36 @@ func mkWfButtons(worldSprs [][]*ebiten.Image) []*elements.Button {
 	return buttons
 }
 
+// mkWfSettingsButton builds the settings button, which sits on the empty
+// panel in the frame's lower-right corner.
+func mkWfSettingsButton(sprs []*ebiten.Image) *elements.Button {
+	font := &text.GoTextFace{Source: fonts.MtgFont, Size: 18}
+	w, _ := elements.TextButtonSize("Settings", font)
+	return elements.NewButtonFromConfig(elements.ButtonConfig{
+		Normal: sprs[0], Hover: sprs[1], Pressed: sprs[2],
+		Text: "Settings", Font: font, ID: "settings",
+		X: 948 - w/2, Y: 700,
+	})
+}
+
 func mkWfText(p *domain.Player) []*elements.Text {
 	amuletCounts := p.GetAmuletCount()
 
+	food := elements.NewText(30, fmt.Sprintf("%d", p.Food), 270, 560)
 	texts := []*elements.Text{
 		elements.NewText(30, fmt.Sprintf("%d", p.Gold), 140, 560),
-		elements.NewText(30, fmt.Sprintf("%d", p.Food), 270, 560),
+		food,
 		elements.NewText(30, fmt.Sprintf("%d", p.Life), 400, 560),
 		elements.NewText(30, fmt.Sprintf("%d", p.NumCards()), 530, 560),
 	}
 
+	if warning, clr := foodWarning(p); warning != "" {
+		food.Color = clr
+		label := elements.NewText(16, warning, 250, 596)
+		label.Color = clr
+		texts = append(texts, label)
+	}
+
 	amuletColors := []domain.ColorMask{
 		domain.ColorWhite,
 		domain.ColorBlue,
@@ -265,3 +302,15 @@ func mkWfText(p *domain.Player) []*elements.Text {
 
 	return texts
 }
+
+// foodWarning returns the label and color used to flag the food counter when
+// the player is running low or starving, or "" when they are well fed.
+func foodWarning(p *domain.Player) (string, color.Color) {
+	switch {
+	case p.IsStarving():
+		return "Starving!", color.RGBA{230, 60, 50, 255}
+	case p.IsLowOnFood():
+		return "Low food", color.RGBA{240, 190, 60, 255}
+	}
+	return "", nil
+}
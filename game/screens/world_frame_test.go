@@ -4,6 +4,7 @@ import (
 	"image"
 	"testing"
 
+	"github.com/benprew/s30/game/domain"
 	"github.com/hajimehoshi/ebiten/v2"
 )
 
@@ -22,3 +23,20 @@ func TestWorldFrameQuestScrollClickedUsesScaledBounds(t *testing.T) {
 		t.Fatal("quest scroll click was not reported")
 	}
 }
+
+func TestWorldFrameWarnsWhenFoodRunsLow(t *testing.T) {
+	cases := []struct {
+		food int
+		want string
+	}{
+		{50, ""},
+		{domain.LowFoodDays*domain.FoodPerDay - 1, "Low food"},
+		{0, "Starving!"},
+	}
+	for _, c := range cases {
+		p := &domain.Player{Food: c.food}
+		if got, _ := foodWarning(p); got != c.want {
+			t.Errorf("Food %d: warning = %q, want %q", c.food, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,195 @@
+package screens
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/benprew/s30/assets"
+	gameaudio "github.com/benprew/s30/game/audio"
+	"github.com/benprew/s30/game/domain"
+	"github.com/benprew/s30/game/ui/elements"
+	"github.com/benprew/s30/game/ui/fonts"
+	"github.com/benprew/s30/game/ui/imageutil"
+	"github.com/benprew/s30/game/ui/screenui"
+	"github.com/benprew/s30/game/world"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// WorldMagicScreen lets the player buy the world magic a city keeps. Each
+// world magic is sold in exactly one city, assigned at world generation.
+type WorldMagicScreen struct {
+	City    *domain.City
+	Player  *domain.Player
+	Level   *world.Level
+	Magic   *domain.WorldMagic
+	Message string
+	Buttons []*elements.Button
+	bought  bool
+}
+
+func (s *WorldMagicScreen) IsFramed() bool { return true }
+
+func (s *WorldMagicScreen) IsOverlay() bool { return false }
+
+func NewWorldMagicScreen(city *domain.City, player *domain.Player, level *world.Level) *WorldMagicScreen {
+	s := &WorldMagicScreen{
+		City:   city,
+		Player: player,
+		Level:  level,
+		Magic:  city.GetWorldMagic(),
+	}
+	if player.HasWorldMagic(s.Magic) {
+		s.Message = "You already possess this magic."
+	}
+	s.Buttons = s.mkButtons()
+	return s
+}
+
+func (s *WorldMagicScreen) mkButtons() []*elements.Button {
+	btnSprites, err := imageutil.LoadSpriteSheet(3, 1, assets.Tradbut1_png)
+	if err != nil {
+		panic(fmt.Sprintf("Unable to load world magic buttons: %s", err))
+	}
+	fontFace := &text.GoTextFace{Source: fonts.MtgFont, Size: 20}
+
+	labels := []struct{ id, text string }{
+		{"buy", fmt.Sprintf("Buy for %d gold", s.price())},
+		{"leave", "Leave"},
+	}
+	if s.Player.HasWorldMagic(s.Magic) {
+		labels = labels[1:]
+	}
+
+	totalW := 0
+	widths := make([]int, len(labels))
+	for i, l := range labels {
+		widths[i], _ = elements.TextButtonSize(l.text, fontFace)
+		totalW += widths[i]
+	}
+	totalW += 16 * (len(labels) - 1)
+	x := FrameOffsetX + (FrameWidth-totalW)/2
+	y := FrameOffsetY + FrameHeight - 70
+
+	buttons := make([]*elements.Button, len(labels))
+	for i, l := range labels {
+		buttons[i] = elements.NewButtonFromConfig(elements.ButtonConfig{
+			Normal:    btnSprites[0][0],
+			Hover:     btnSprites[0][1],
+			Pressed:   btnSprites[0][2],
+			Text:      l.text,
+			Font:      fontFace,
+			ID:        l.id,
+			Important: true,
+			X:         x,
+			Y:         y,
+		})
+		x += widths[i] + 16
+	}
+	return buttons
+}
+
+func (s *WorldMagicScreen) price() int {
+	return s.Player.TownPrice(s.Magic.Cost)
+}
+
+func (s *WorldMagicScreen) Update(W, H int, scale float64) (screenui.ScreenName, screenui.Screen, error) {
+	opts := &ebiten.DrawImageOptions{}
+	for _, b := range s.Buttons {
+		b.Update(opts, scale, W, H)
+		if !b.IsClicked() {
+			continue
+		}
+		switch b.ID {
+		case "buy":
+			if s.buy() {
+				s.Buttons = s.mkButtons()
+			}
+			b.State = elements.StateNormal
+		case "leave":
+			return s.leave()
+		}
+	}
+
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		return s.leave()
+	}
+	return screenui.WorldMagicScr, nil, nil
+}
+
+// leave returns to the city, rebuilding it after a purchase because new
+// magics change its prices and buttons.
+func (s *WorldMagicScreen) leave() (screenui.ScreenName, screenui.Screen, error) {
+	if s.bought {
+		return screenui.CityScr, NewCityScreen(s.City, s.Player, s.Level), nil
+	}
+	return screenui.CityScr, nil, nil
+}
+
+// buy purchases the city's world magic and reports whether it succeeded.
+func (s *WorldMagicScreen) buy() bool {
+	if s.Player.HasWorldMagic(s.Magic) {
+		return false
+	}
+	cost := s.price()
+	if s.Player.Gold < cost {
+		s.Message = "Not enough gold!"
+		return false
+	}
+	s.Player.Gold -= cost
+	s.Player.AddWorldMagic(s.Magic)
+	s.bought = true
+	s.Message = fmt.Sprintf("The %s is yours.", s.Magic.Name)
+	if am := gameaudio.Get(); am != nil {
+		am.PlaySFX(gameaudio.WorldMagicSFXForColor(domain.ColorMaskToString(s.City.AmuletColor)))
+	}
+	return true
+}
+
+func (s *WorldMagicScreen) Draw(screen *ebiten.Image, W, H int, scale float64) {
+	drawTownPanel(screen, s.City, scale)
+
+	title := elements.NewText(36, s.Magic.Name, FrameOffsetX, FrameOffsetY+40)
+	title.Color = color.RGBA{255, 230, 150, 255}
+	title.HAlign = elements.AlignCenter
+	title.BoundsW = FrameWidth
+	title.Draw(screen, &ebiten.DrawImageOptions{}, scale)
+
+	lines := []string{
+		s.Magic.Description + ".",
+		"",
+		fmt.Sprintf("Price: %d gold   You have: %d gold", s.price(), s.Player.Gold),
+		s.Message,
+	}
+	y := FrameOffsetY + 120
+	for _, line := range lines {
+		txt := elements.NewText(24, line, FrameOffsetX, y)
+		txt.HAlign = elements.AlignCenter
+		txt.BoundsW = FrameWidth
+		txt.Draw(screen, &ebiten.DrawImageOptions{}, scale)
+		y += 36
+	}
+
+	opts := &ebiten.DrawImageOptions{}
+	for _, b := range s.Buttons {
+		b.Draw(screen, opts, scale)
+	}
+}
+
+// drawTownPanel draws the city background inside the world frame and darkens
+// it so text drawn on top stays readable.
+func drawTownPanel(screen *ebiten.Image, city *domain.City, scale float64) {
+	if city.BackgroundImage != nil {
+		cityOpts := &ebiten.DrawImageOptions{}
+		cityOpts.GeoM.Scale(scale, scale)
+		cityOpts.GeoM.Scale(SCALE, SCALE)
+		cityOpts.GeoM.Translate(FrameOffsetX*scale, FrameOffsetY*scale)
+		screen.DrawImage(city.BackgroundImage, cityOpts)
+	}
+	vector.FillRect(screen,
+		float32(FrameOffsetX*scale), float32(FrameOffsetY*scale),
+		float32(FrameWidth*scale), float32(FrameHeight*scale),
+		color.RGBA{0, 0, 0, 170}, false)
+}
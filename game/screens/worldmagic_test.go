@@ -0,0 +1,77 @@
+package screens
+
+import (
+	"testing"
+
+	"github.com/benprew/s30/game/domain"
+)
+
+func TestWorldMagicScreenBuy(t *testing.T) {
+	magic := domain.FindWorldMagic(domain.WorldMagicQuickening)
+	city := &domain.City{Name: "Test", AssignedWorldMagic: magic}
+	player := &domain.Player{Gold: magic.Cost - 1}
+
+	s := NewWorldMagicScreen(city, player, nil)
+	if s.buy() {
+		t.Fatal("buy succeeded without enough gold")
+	}
+	if player.HasWorldMagic(magic) {
+		t.Fatal("player received the magic without paying")
+	}
+
+	player.Gold = magic.Cost + 10
+	if !s.buy() {
+		t.Fatalf("buy failed: %s", s.Message)
+	}
+	if !player.HasWorldMagic(magic) || player.Gold != 10 {
+		t.Fatalf("after buy: has magic %v, gold %d; want true, 10", player.HasWorldMagic(magic), player.Gold)
+	}
+	if s.buy() {
+		t.Fatal("the same magic was sold twice")
+	}
+}
+
+func TestWorldMagicScreenHagglersCoinDiscount(t *testing.T) {
+	magic := domain.FindWorldMagic(domain.WorldMagicLeapOfFate)
+	city := &domain.City{Name: "Test", AssignedWorldMagic: magic}
+	player := &domain.Player{Gold: magic.Cost}
+	player.AddWorldMagic(domain.FindWorldMagic(domain.WorldMagicHagglersCoin))
+
+	s := NewWorldMagicScreen(city, player, nil)
+	if !s.buy() {
+		t.Fatalf("buy failed: %s", s.Message)
+	}
+	if want := magic.Cost - player.TownPrice(magic.Cost); player.Gold != want {
+		t.Errorf("gold after discounted buy = %d, want %d", player.Gold, want)
+	}
+}
+
+func TestCityButtonsOfferWorldMagicAndLeap(t *testing.T) {
+	city := &domain.City{Tier: domain.TierCapital}
+	player := &domain.Player{}
+
+	has := func(buttons []string, id string) bool {
+		for _, b := range buttons {
+			if b == id {
+				return true
+			}
+		}
+		return false
+	}
+	ids := func() []string {
+		var out []string
+		for _, b := range mkButtons(1, city, player) {
+			out = append(out, b.ID)
+		}
+		return out
+	}
+
+	if got := ids(); has(got, "worldmagic") || has(got, "leap") {
+		t.Fatalf("plain city offered %v", got)
+	}
+	city.AssignedWorldMagic = domain.FindWorldMagic(domain.WorldMagicDwarvenPick)
+	player.AddWorldMagic(domain.FindWorldMagic(domain.WorldMagicLeapOfFate))
+	if got := ids(); !has(got, "worldmagic") || !has(got, "leap") {
+		t.Fatalf("city buttons = %v, want worldmagic and leap", got)
+	}
+}
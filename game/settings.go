@@ -0,0 +1,89 @@
+package game
+
+import (
+	"github.com/benprew/mage-go/pkg/mage/interactive"
+	duelscreen "github.com/benprew/s30/game/screens/duel"
+	"github.com/benprew/s30/game/settings"
+	"github.com/benprew/s30/logging"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// loadSettings reads the player's settings, falling back to the defaults
+// when the settings file can't be read.
+func loadSettings() settings.Settings {
+	s, err := settings.Load()
+	if err != nil {
+		logger.Error("failed to load settings", "err", err)
+	}
+	return s
+}
+
+// applySettings puts all of the game's settings into effect.
+func (g *Game) applySettings() {
+	g.applyAudioSettings()
+	g.applyWindowSettings()
+	applyDuelSettings(g.options, g.settings)
+	applyLogSettings(g.options, g.settings)
+}
+
+// Settings returns the game's current settings.
+func (g *Game) Settings() settings.Settings {
+	return g.settings
+}
+
+// changeSettings is called by the settings screen whenever the player changes
+// an option. The new settings take effect and are saved straight away. The
+// window is only resized when its own options changed, so a window the player
+// has dragged to a new size keeps it.
+func (g *Game) changeSettings(s settings.Settings) {
+	resize := s.WindowScale != g.settings.WindowScale || s.Fullscreen != g.settings.Fullscreen
+	g.settings = s
+	g.applyAudioSettings()
+	if resize {
+		g.applyWindowSettings()
+	}
+	applyDuelSettings(g.options, s)
+	g.saveSettings()
+}
+
+func (g *Game) saveSettings() {
+	if err := settings.Save(g.settings); err != nil {
+		logger.Error("failed to save settings", "err", err)
+	}
+}
+
+func (g *Game) applyAudioSettings() {
+	if g.audio == nil {
+		return
+	}
+	g.audio.SetVolume(g.settings.BGMVolume, g.settings.SFXVolume)
+	if g.settings.Muted != g.audio.Muted() {
+		g.audio.ToggleMute()
+	}
+}
+
+func (g *Game) applyWindowSettings() {
+	scale := g.settings.WindowScale
+	ebiten.SetWindowSize(int(float64(g.ScreenW)*scale), int(float64(g.ScreenH)*scale))
+	ebiten.SetFullscreen(g.settings.Fullscreen)
+}
+
+// applyDuelSettings sets the duel screen's options from s. The
+// -show-opponent-hand flag reveals the opponent's hand whatever the setting.
+func applyDuelSettings(options Options, s settings.Settings) {
+	interactive.RevealOpponentHand = options.ShowOpponentHand || s.ShowOpponentHand
+	duelscreen.MessagePacing = s.Pacing.Scale()
+	duelscreen.AutoPassNoPlays = s.AutoPassNoPlays
+	duelscreen.AutoPassOpponentTurn = s.AutoPassOpponentTurn
+}
+
+// applyLogSettings sets the log levels from s, then from the -v flag so the
+// flag wins for any subsystem both name.
+func applyLogSettings(options Options, s settings.Settings) {
+	if err := logging.Configure(s.Logging); err != nil {
+		logger.Warn("ignoring log settings", "err", err)
+	}
+	if err := logging.Configure(options.Verbose); err != nil {
+		logger.Warn("ignoring -v", "err", err)
+	}
+}
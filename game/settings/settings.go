@@ -0,0 +1,158 @@
+// Package settings holds the player's preferences that outlive any one saved
+// game: audio, display and duel options.
+package settings
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/benprew/s30/game/save"
+)
+
+// Pacing controls how long the duel screen lingers on each engine message.
+type Pacing int
+
+const (
+	PacingNormal Pacing = iota
+	PacingFast
+	PacingSlow
+)
+
+// pacingOrder is the order the settings screen cycles through.
+var pacingOrder = []Pacing{PacingFast, PacingNormal, PacingSlow}
+
+func (p Pacing) String() string {
+	switch p {
+	case PacingFast:
+		return "Fast"
+	case PacingSlow:
+		return "Slow"
+	default:
+		return "Normal"
+	}
+}
+
+// Scale returns the multiplier applied to the duel screen's message delays.
+func (p Pacing) Scale() float64 {
+	switch p {
+	case PacingFast:
+		return 0.5
+	case PacingSlow:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// Step returns the pacing dir places along from p, stopping at either end.
+func (p Pacing) Step(dir int) Pacing {
+	i := slices.Index(pacingOrder, p)
+	if i < 0 {
+		i = slices.Index(pacingOrder, PacingNormal)
+	}
+	return pacingOrder[max(0, min(len(pacingOrder)-1, i+dir))]
+}
+
+// WindowScales are the window sizes offered, as multiples of the 1024x768
+// virtual canvas.
+var WindowScales = []float64{1, 1.25, 1.5, 2}
+
+// VolumeStep is how much one click changes a volume.
+const VolumeStep = 0.1
+
+type Settings struct {
+	BGMVolume   float64 `json:"bgm_volume"`
+	SFXVolume   float64 `json:"sfx_volume"`
+	Muted       bool    `json:"muted"`
+	WindowScale float64 `json:"window_scale"`
+	Fullscreen  bool    `json:"fullscreen"`
+	Pacing      Pacing  `json:"pacing"`
+	// AutoPassNoPlays passes priority for the player when they have no spell
+	// to cast or ability to activate.
+	AutoPassNoPlays bool `json:"auto_pass_no_plays"`
+	// AutoPassOpponentTurn passes priority for the player during the
+	// opponent's turn.
+	AutoPassOpponentTurn bool `json:"auto_pass_opponent_turn"`
+	ShowOpponentHand     bool `json:"show_opponent_hand"`
+	// Logging sets log levels per subsystem, in the -v flag's form, e.g.
+	// "duel,world=warn".
+	Logging string `json:"logging,omitempty"`
+}
+
+// Default returns the settings used before the player changes anything. The
+// volumes match the audio manager's own defaults.
+func Default() Settings {
+	return Settings{
+		BGMVolume:   0.2,
+		SFXVolume:   0.7,
+		WindowScale: 1,
+	}
+}
+
+// StepWindowScale returns the window scale dir places along from the current
+// one, stopping at either end of WindowScales.
+func (s Settings) StepWindowScale(dir int) float64 {
+	i := slices.Index(WindowScales, s.WindowScale)
+	if i < 0 {
+		i = 0
+	}
+	return WindowScales[max(0, min(len(WindowScales)-1, i+dir))]
+}
+
+// StepVolume returns v moved dir steps of VolumeStep, kept within 0 to 1.
+func StepVolume(v float64, dir int) float64 {
+	v = math.Round((v+float64(dir)*VolumeStep)*10) / 10
+	return max(0, min(1, v))
+}
+
+// normalize clamps values a hand-edited or older settings file may have got
+// wrong.
+func (s *Settings) normalize() {
+	s.BGMVolume = max(0, min(1, s.BGMVolume))
+	s.SFXVolume = max(0, min(1, s.SFXVolume))
+	if !slices.Contains(WindowScales, s.WindowScale) {
+		s.WindowScale = 1
+	}
+	if !slices.Contains(pacingOrder, s.Pacing) {
+		s.Pacing = PacingNormal
+	}
+}
+
+// Decode reads settings from JSON. Fields missing from data keep their
+// defaults.
+func Decode(data []byte) (Settings, error) {
+	s := Default()
+	if err := json.Unmarshal(data, &s); err != nil {
+		return Default(), fmt.Errorf("decode settings: %w", err)
+	}
+	s.normalize()
+	return s, nil
+}
+
+// Encode writes s as JSON.
+func (s Settings) Encode() ([]byte, error) {
+	return json.MarshalIndent(s, "", "  ")
+}
+
+// Load reads the settings file, returning the defaults when there is none.
+func Load() (Settings, error) {
+	data, err := save.ReadSettingsFile()
+	if err != nil {
+		return Default(), fmt.Errorf("read settings: %w", err)
+	}
+	if data == nil {
+		return Default(), nil
+	}
+	return Decode(data)
+}
+
+// Save writes s to the settings file.
+func Save(s Settings) error {
+	data, err := s.Encode()
+	if err != nil {
+		return fmt.Errorf("encode settings: %w", err)
+	}
+	return save.WriteSettingsFile(data)
+}
@@ -0,0 +1,34 @@
+//go:build !js
+
+package settings
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/benprew/s30/game/save"
+)
+
+func TestLoadAndSave(t *testing.T) {
+	save.SetSaveDir(filepath.Join(t.TempDir(), "saves"))
+	t.Cleanup(func() { save.SetSaveDir("") })
+
+	s, err := Load()
+	if err != nil || s != Default() {
+		t.Fatalf("Load with no file = %+v, %v; want the defaults", s, err)
+	}
+
+	s.SFXVolume = 0.4
+	s.Pacing = PacingSlow
+	s.AutoPassOpponentTurn = true
+	if err := Save(s); err != nil {
+		t.Fatal(err)
+	}
+	got, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != s {
+		t.Errorf("Load = %+v, want %+v", got, s)
+	}
+}
@@ -0,0 +1,69 @@
+package settings
+
+import "testing"
+
+func TestDecodeKeepsDefaultsForMissingFields(t *testing.T) {
+	s, err := Decode([]byte(`{"fullscreen":true}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Default()
+	want.Fullscreen = true
+	if s != want {
+		t.Errorf("Decode = %+v, want %+v", s, want)
+	}
+}
+
+func TestDecodeNormalizesBadValues(t *testing.T) {
+	s, err := Decode([]byte(`{"bgm_volume":3,"sfx_volume":-1,"window_scale":7,"pacing":9}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.BGMVolume != 1 || s.SFXVolume != 0 {
+		t.Errorf("volumes = %f, %f; want 1, 0", s.BGMVolume, s.SFXVolume)
+	}
+	if s.WindowScale != 1 {
+		t.Errorf("WindowScale = %f, want 1", s.WindowScale)
+	}
+	if s.Pacing != PacingNormal {
+		t.Errorf("Pacing = %s, want Normal", s.Pacing)
+	}
+}
+
+func TestDecodeRejectsGarbage(t *testing.T) {
+	s, err := Decode([]byte("not json"))
+	if err == nil {
+		t.Fatal("Decode accepted garbage")
+	}
+	if s != Default() {
+		t.Errorf("Decode returned %+v on error, want the defaults", s)
+	}
+}
+
+func TestStepping(t *testing.T) {
+	if got := StepVolume(0.2, 1); got != 0.3 {
+		t.Errorf("StepVolume(0.2, 1) = %f, want 0.3", got)
+	}
+	if got := StepVolume(1, 1); got != 1 {
+		t.Errorf("StepVolume(1, 1) = %f, want 1", got)
+	}
+	if got := StepVolume(0.05, -1); got != 0 {
+		t.Errorf("StepVolume(0.05, -1) = %f, want 0", got)
+	}
+
+	s := Default()
+	if got := s.StepWindowScale(-1); got != 1 {
+		t.Errorf("StepWindowScale(-1) from 1 = %f, want 1", got)
+	}
+	s.WindowScale = s.StepWindowScale(1)
+	if s.WindowScale != 1.25 {
+		t.Errorf("StepWindowScale(1) from 1 = %f, want 1.25", s.WindowScale)
+	}
+
+	if got := PacingNormal.Step(-1); got != PacingFast {
+		t.Errorf("Normal.Step(-1) = %s, want Fast", got)
+	}
+	if got := PacingSlow.Step(1); got != PacingSlow {
+		t.Errorf("Slow.Step(1) = %s, want Slow", got)
+	}
+}
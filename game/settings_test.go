@@ -0,0 +1,52 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/benprew/mage-go/pkg/mage/interactive"
+	duelscreen "github.com/benprew/s30/game/screens/duel"
+	"github.com/benprew/s30/game/settings"
+)
+
+func TestApplyDuelSettings(t *testing.T) {
+	originalHand := interactive.RevealOpponentHand
+	originalPacing := duelscreen.MessagePacing
+	t.Cleanup(func() {
+		interactive.RevealOpponentHand = originalHand
+		duelscreen.MessagePacing = originalPacing
+		duelscreen.AutoPassNoPlays = false
+		duelscreen.AutoPassOpponentTurn = false
+	})
+
+	s := settings.Default()
+	s.Pacing = settings.PacingSlow
+	s.AutoPassNoPlays = true
+	applyDuelSettings(Options{}, s)
+
+	if interactive.RevealOpponentHand {
+		t.Error("opponent hand is revealed, want hidden")
+	}
+	if duelscreen.MessagePacing != 2 {
+		t.Errorf("MessagePacing = %f, want 2", duelscreen.MessagePacing)
+	}
+	if !duelscreen.AutoPassNoPlays || duelscreen.AutoPassOpponentTurn {
+		t.Errorf("auto-pass = %v, %v; want only no-plays", duelscreen.AutoPassNoPlays, duelscreen.AutoPassOpponentTurn)
+	}
+}
+
+func TestShowOpponentHandFlagOverridesSetting(t *testing.T) {
+	original := interactive.RevealOpponentHand
+	t.Cleanup(func() { interactive.RevealOpponentHand = original })
+
+	applyDuelSettings(Options{ShowOpponentHand: true}, settings.Default())
+	if !interactive.RevealOpponentHand {
+		t.Error("-show-opponent-hand didn't reveal the hand")
+	}
+
+	s := settings.Default()
+	s.ShowOpponentHand = true
+	applyDuelSettings(Options{}, s)
+	if !interactive.RevealOpponentHand {
+		t.Error("the setting didn't reveal the hand")
+	}
+}
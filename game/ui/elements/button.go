@@ -1,7 +1,6 @@
 package elements
 
 import (
-	"fmt"
 	"image"
 	"image/color"
 
@@ -9,6 +8,7 @@ import (
 	"github.com/benprew/s30/game/ui"
 	"github.com/benprew/s30/game/ui/imageutil"
 	"github.com/benprew/s30/game/ui/layout"
+	"github.com/benprew/s30/logging"
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/text/v2"
 )
@@ -33,6 +33,8 @@ const (
 	textPaddingY = 20.0
 )
 
+var logger = logging.Logger(logging.UI)
+
 type ButtonState int
 
 const (
@@ -176,7 +178,7 @@ func (b *Button) Update(opts *ebiten.DrawImageOptions, scale float64, screenW, s
 	// TODO button position should be set by layout when created and stored in Bounds
 	bounds := b.getPositionWithDims(screenW, screenH, scale)
 	if b.updatePointer(bounds, ui.Position(), ui.Pressed(), ui.Click(bounds)) {
-		fmt.Printf("Button Clicked: %s\n", b.ID)
+		logger.Debug("button clicked", "id", b.ID)
 		if am := audio.Get(); am != nil {
 			am.PlaySFX(b.clickSFX())
 		}
@@ -7,11 +7,14 @@ import (
 	_ "image/png" // Import for PNG decoding side effects
 	"strings"
 
+	"github.com/benprew/s30/logging"
 	"github.com/hajimehoshi/ebiten/v2"
 	"golang.org/x/image/font"
 	"golang.org/x/image/font/opentype"
 )
 
+var logger = logging.Logger(logging.UI)
+
 // SubimageInfo holds the position and dimensions of a found subimage.
 type SubimageInfo struct {
 	X      int `json:"x"`
@@ -90,11 +93,11 @@ func LoadSubimages(fileBytes []byte, sprMap *[]SprInfo) ([]*ebiten.Image, error)
 		// Note: SubImage panics if rect is outside bounds, so this check is important
 		// although ideally the JSON generation script ensures valid rects.
 		if !rect.In(sheet.Bounds()) {
-			fmt.Printf("Warning: Subimage rectangle %v is outside the sheet bounds %v. Skipping.\n", rect, sheet.Bounds())
+			logger.Warn("skipping subimage outside the sheet", "rect", rect, "sheet", sheet.Bounds())
 			continue
 		}
 		if rect.Empty() {
-			fmt.Printf("Warning: Subimage rectangle %v is empty. Skipping.\n", rect)
+			logger.Warn("skipping empty subimage", "rect", rect)
 			continue
 		}
 
@@ -160,11 +163,11 @@ func LoadMappedSprite(spriteData []byte, spriteMap []byte) (map[string]*ebiten.I
 		rect := image.Rect(info.X, info.Y, info.X+info.Width, info.Y+info.Height)
 
 		if !rect.In(sheet.Bounds()) {
-			fmt.Printf("Warning: Sprite '%s' rectangle %v is outside the sheet bounds %v. Skipping.\n", name, rect, sheet.Bounds())
+			logger.Warn("skipping sprite outside the sheet", "sprite", name, "rect", rect, "sheet", sheet.Bounds())
 			continue
 		}
 		if rect.Empty() {
-			fmt.Printf("Warning: Sprite '%s' rectangle %v is empty. Skipping.\n", name, rect)
+			logger.Warn("skipping empty sprite", "sprite", name, "rect", rect)
 			continue
 		}
 
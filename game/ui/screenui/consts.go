@@ -26,6 +26,10 @@ const (
 	GameWinScr
 	GameLoseScr
 	BugReportScr
+	WorldMagicScr
+	LeapOfFateScr
+	ClueJournalScr
+	SettingsScr
 )
 
 type Screen interface {
@@ -76,6 +80,14 @@ func ScreenNameToString(sn ScreenName) string {
 		return "GameLose"
 	case BugReportScr:
 		return "BugReport"
+	case WorldMagicScr:
+		return "WorldMagic"
+	case LeapOfFateScr:
+		return "LeapOfFate"
+	case ClueJournalScr:
+		return "ClueJournal"
+	case SettingsScr:
+		return "Settings"
 	default:
 		return "Unknown"
 	}
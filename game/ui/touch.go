@@ -1,16 +1,17 @@
 package ui
 
 import (
-	"fmt"
-
+	"github.com/benprew/s30/logging"
 	"github.com/hajimehoshi/ebiten/v2"
 )
 
+var logger = logging.Logger(logging.UI)
+
 func TouchPosition() (X, Y int) {
 	var touches []ebiten.TouchID
 	touches = ebiten.AppendTouchIDs(touches)
 	if len(touches) > 0 {
-		fmt.Println("Got touch")
+		logger.Debug("touch", "id", touches[0])
 		X, Y = ebiten.TouchPosition(touches[0])
 		return X, Y
 	}
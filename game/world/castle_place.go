@@ -1,7 +1,6 @@
 package world
 
 import (
-	"fmt"
 	"image"
 	"math/rand"
 	"slices"
@@ -115,7 +114,7 @@ func (l *Level) placeCastles(seed int64, castles1, castles2 [][]*ebiten.Image,
 	ss *SpriteSheet, foliage, Sfoliage [][]*ebiten.Image) {
 	candidates := l.castleCandidateTiles()
 	if len(candidates) == 0 {
-		fmt.Println("Warning: no candidate tiles for castle placement.")
+		logger.Warn("no candidate tiles for castle placement")
 		return
 	}
 
@@ -130,35 +129,46 @@ func (l *Level) placeCastles(seed int64, castles1, castles2 [][]*ebiten.Image,
 	for idx, color := range colors {
 		loc, ok := pickCastleLocation(candidates, placed, []int{castleMinDist, 9, 6, 0})
 		if !ok {
-			fmt.Printf("Warning: no location available for %s castle.\n", domain.ColorMaskToString(color))
+			logger.Warn("no location available for castle", "color", domain.ColorMaskToString(color))
 			continue
 		}
 
 		terrain := castleZoneTerrain[color]
 		l.stampZone(loc, terrain, castleZoneRadius, ss, foliage, Sfoliage, rng)
+		l.addCastle(loc, color, castleNames[idx%len(castleNames)], castles1, castles2)
+		placed = append(placed, loc)
+	}
+}
 
-		castle := &domain.Castle{
-			Name:      castleNames[idx%len(castleNames)],
-			Color:     color,
-			RogueName: castleRogues[color],
-			MapTile:   loc,
-		}
-		tile := l.Tile(loc)
-		tile.IsCastle = true
-		tile.Castle = castle
-
-		spec := castleSpecs[color]
-		sheet := castles1
-		if spec.sheet == 2 {
-			sheet = castles2
-		}
-		if sheet != nil {
-			addCastleSprites(tile, sheet, spec, false)
-		}
+// addCastle puts color's castle on the tile at loc and adds its sprites when
+// the sheets are loaded.
+func (l *Level) addCastle(loc image.Point, color domain.ColorMask, name string, castles1, castles2 [][]*ebiten.Image) {
+	castle := &domain.Castle{
+		Name:      name,
+		Color:     color,
+		RogueName: castleRogues[color],
+		MapTile:   loc,
+	}
+	tile := l.Tile(loc)
+	tile.IsCastle = true
+	tile.Castle = castle
 
-		l.Castles = append(l.Castles, castle)
-		placed = append(placed, loc)
+	spec := castleSpecs[color]
+	sheet := castles1
+	if spec.sheet == 2 {
+		sheet = castles2
+	}
+	if sheet != nil {
+		addCastleSprites(tile, sheet, spec, false)
 	}
+
+	l.Castles = append(l.Castles, castle)
+}
+
+// defaultCastleName is the name a generated world gives color's castle.
+func defaultCastleName(color domain.ColorMask) string {
+	idx := slices.Index(domain.GetAllAmuletColors(), color)
+	return castleNames[max(idx, 0)%len(castleNames)]
 }
 
 // pickCastleLocation walks the candidate list and returns the first tile that
@@ -0,0 +1,280 @@
+package world
+
+import (
+	"fmt"
+	"image"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/benprew/s30/game/domain"
+)
+
+// Arzakon's campaign: every so often each undefeated castle wizard sends a
+// minion of its color to besiege a city. A city whose besieger is still on
+// the map when the siege ends falls to it, closing its shops and Wiseman
+// until the player duels the occupier to liberate it. Lose too many cities
+// and the game is lost. A city mana linked to the player holds out twice as
+// long, and when it would fall its link breaks instead.
+
+const (
+	// siegeDays is how long a minion besieges a city before it falls.
+	siegeDays = 6
+	// linkedSiegeDays is how long a city mana linked to the player holds out.
+	linkedSiegeDays = 2 * siegeDays
+	// siegeTargetChoices is how many of the free cities closest to a castle
+	// its wizard picks a target from.
+	siegeTargetChoices = 3
+)
+
+// conquestPace is how hard Arzakon's campaign presses at a difficulty.
+type conquestPace struct {
+	// dispatchDays is the number of days between minions from each castle.
+	dispatchDays int
+	// maxConquered is how many cities may fall before the game is lost.
+	maxConquered int
+}
+
+var conquestPaces = map[domain.Difficulty]conquestPace{
+	domain.DifficultyEasy:   {dispatchDays: 40, maxConquered: 12},
+	domain.DifficultyMedium: {dispatchDays: 30, maxConquered: 10},
+	domain.DifficultyHard:   {dispatchDays: 24, maxConquered: 8},
+	domain.DifficultyExpert: {dispatchDays: 18, maxConquered: 6},
+}
+
+// Campaign is the clock driving Arzakon's conquest of the cities. It runs on
+// the player's days, so time only presses while the player travels.
+type Campaign struct {
+	// Day is the last day the clock has run for.
+	Day int
+	// NextDispatch is the day each undefeated castle, by color, next sends a
+	// minion out. Castles are scheduled the first time the clock runs.
+	NextDispatch map[domain.ColorMask]int
+}
+
+func (l *Level) conquestPace() conquestPace {
+	if pace, ok := conquestPaces[l.Difficulty]; ok {
+		return pace
+	}
+	return conquestPaces[domain.DifficultyEasy]
+}
+
+// AdvanceCampaign runs the campaign clock for the player's current day:
+// sieges that have run their course take their cities, and castles whose
+// turn has come send out new minions.
+func (l *Level) AdvanceCampaign() {
+	l.advanceCampaign(rand.New(rand.NewSource(time.Now().UnixNano())))
+}
+
+func (l *Level) advanceCampaign(rng *rand.Rand) {
+	day := l.Player.Days
+	l.Campaign.Day = day
+
+	for i := len(l.Enemies) - 1; i >= 0; i-- {
+		e := l.Enemies[i]
+		if e.Siege == nil || day < e.Siege.EndsDay {
+			continue
+		}
+		l.captureCity(e.Siege, e.Name())
+		l.RemoveEnemyAt(i)
+	}
+
+	if l.Campaign.NextDispatch == nil {
+		l.Campaign.NextDispatch = make(map[domain.ColorMask]int)
+	}
+	pace := l.conquestPace()
+	for i, castle := range l.Castles {
+		if castle == nil || castle.Defeated {
+			continue
+		}
+		next, ok := l.Campaign.NextDispatch[castle.Color]
+		if !ok {
+			// The first minions leave half way through the first interval,
+			// staggered so the castles don't all strike at once.
+			l.Campaign.NextDispatch[castle.Color] = day + pace.dispatchDays/2 + i*pace.dispatchDays/len(l.Castles)
+			continue
+		}
+		if day < next {
+			continue
+		}
+		l.Campaign.NextDispatch[castle.Color] = day + pace.dispatchDays
+		if err := l.dispatchMinion(rng, castle); err != nil {
+			logger.Warn("castle sent no minion", "wizard", castle.RogueName, "err", err)
+		}
+	}
+}
+
+// dispatchMinion sends a minion of castle's color to besiege one of the free
+// cities nearest the castle.
+func (l *Level) dispatchMinion(rng *rand.Rand, castle *domain.Castle) error {
+	target, ok := l.siegeTarget(rng, castle.MapTile)
+	if !ok {
+		return fmt.Errorf("no city left to besiege")
+	}
+	name, ok := minionName(rng, domain.Rogues, castle.Color, l.EnemySpawnMaxLevelAt(target))
+	if !ok {
+		return fmt.Errorf("no %s minion available", domain.ColorMaskToString(castle.Color))
+	}
+	if err := l.SpawnEnemyNear(name, target); err != nil {
+		return err
+	}
+	days := siegeDays
+	if l.Tile(target).City.IsManaLinked {
+		days = linkedSiegeDays
+	}
+	l.Enemies[len(l.Enemies)-1].Siege = &domain.Siege{
+		City:    target,
+		Color:   castle.Color,
+		EndsDay: l.Player.Days + days,
+	}
+	return nil
+}
+
+// siegeTarget picks a city that is neither conquered nor already under siege
+// from the few closest to from.
+func (l *Level) siegeTarget(rng *rand.Rand, from image.Point) (image.Point, bool) {
+	var free []image.Point
+	for _, p := range l.cityTileLocations() {
+		if !l.Tile(p).City.IsConquered() && !l.IsBesieged(p) {
+			free = append(free, p)
+		}
+	}
+	if len(free) == 0 {
+		return image.Point{}, false
+	}
+	sort.SliceStable(free, func(i, j int) bool {
+		return tileDistance(free[i], from) < tileDistance(free[j], from)
+	})
+	return free[rng.Intn(min(siegeTargetChoices, len(free)))], true
+}
+
+// minionName picks a rogue of color no stronger than maxLevel, falling back
+// to the weakest rogue of that color when all of them are stronger. Castle
+// wizards and Arzakon never serve as minions.
+func minionName(rng *rand.Rand, rogues map[string]*domain.Character, color domain.ColorMask, maxLevel int) (string, bool) {
+	var names []string
+	for name, rogue := range rogues {
+		if isMinion(rogue, color) && rogue.Level <= maxLevel {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return weakestMinionName(rogues, color)
+	}
+	sort.Strings(names)
+	return names[rng.Intn(len(names))], true
+}
+
+func weakestMinionName(rogues map[string]*domain.Character, color domain.ColorMask) (string, bool) {
+	weakest := ""
+	for name, rogue := range rogues {
+		if !isMinion(rogue, color) {
+			continue
+		}
+		if weakest == "" || rogue.Level < rogues[weakest].Level ||
+			(rogue.Level == rogues[weakest].Level && name < weakest) {
+			weakest = name
+		}
+	}
+	return weakest, weakest != ""
+}
+
+func isMinion(rogue *domain.Character, color domain.ColorMask) bool {
+	return rogue != nil && rogue.PrimaryColor == domain.ColorMaskToString(color) &&
+		rogue.Level <= domain.MaxRandomEnemyLevel
+}
+
+// enemyTarget is where the enemy heads this update. A besieging minion keeps
+// to its city unless it is chasing the player; every other enemy hunts the
+// player.
+func (l *Level) enemyTarget(e *domain.Enemy) image.Point {
+	pLoc := l.Player.Loc()
+	if e.Siege == nil {
+		return pLoc
+	}
+	if l.pursues(e, pLoc) {
+		return pLoc
+	}
+	return l.pixelInTile(e.Siege.City)
+}
+
+// captureCity hands the city under siege to its besieger and returns it. A
+// city mana linked to the player holds, losing its link instead, and nil is
+// returned.
+func (l *Level) captureCity(siege *domain.Siege, occupier string) *domain.City {
+	tile := l.Tile(siege.City)
+	if tile == nil || !tile.IsCity() {
+		return nil
+	}
+	if tile.City.IsManaLinked {
+		l.Player.UnlinkCity(tile.City)
+		return nil
+	}
+	tile.City.ConqueredBy = siege.Color
+	tile.City.Occupier = occupier
+	return tile.City
+}
+
+// SiegeDuelLost is called when the player loses a duel to the enemy at idx.
+// If the enemy was besieging a city, the city falls to it straight away
+// unless it is mana linked; the captured city is returned, or nil.
+func (l *Level) SiegeDuelLost(idx int) *domain.City {
+	if idx < 0 || idx >= len(l.Enemies) || l.Enemies[idx].Siege == nil {
+		return nil
+	}
+	return l.captureCity(l.Enemies[idx].Siege, l.Enemies[idx].Name())
+}
+
+// IsBesieged reports whether a minion on the map is besieging the city at
+// tile.
+func (l *Level) IsBesieged(tile image.Point) bool {
+	for _, e := range l.Enemies {
+		if e.Siege != nil && e.Siege.City == tile {
+			return true
+		}
+	}
+	return false
+}
+
+// OccupierName names the minion holding a conquered city, whom the player
+// duels to liberate it.
+func (l *Level) OccupierName(city *domain.City) (string, bool) {
+	if city == nil || !city.IsConquered() {
+		return "", false
+	}
+	if _, ok := domain.Rogues[city.Occupier]; ok {
+		return city.Occupier, true
+	}
+	// The occupier may have been renamed since the game was saved; the
+	// weakest minion of the conqueror's color holds the city instead.
+	return weakestMinionName(domain.Rogues, city.ConqueredBy)
+}
+
+// LiberateCity frees a conquered city once the player has beaten its
+// occupier, reopening its shops and Wiseman.
+func (l *Level) LiberateCity(city *domain.City) {
+	city.ConqueredBy = 0
+	city.Occupier = ""
+}
+
+// ConqueredCities returns how many cities Arzakon's minions hold.
+func (l *Level) ConqueredCities() int {
+	count := 0
+	for _, p := range l.cityTileLocations() {
+		if l.Tile(p).City.IsConquered() {
+			count++
+		}
+	}
+	return count
+}
+
+// MaxConqueredCities is how many cities may fall before the game is lost.
+func (l *Level) MaxConqueredCities() int {
+	return l.conquestPace().maxConquered
+}
+
+// CampaignLost reports whether Arzakon's minions hold so many cities that the
+// game is lost.
+func (l *Level) CampaignLost() bool {
+	return l.ConqueredCities() >= l.MaxConqueredCities()
+}
@@ -0,0 +1,291 @@
+package world
+
+import (
+	"encoding/json"
+	"image"
+	"math/rand"
+	"testing"
+
+	"github.com/benprew/s30/game/domain"
+)
+
+// newCampaignLevel builds a small level with a red castle in one corner and
+// a city near it and another far away.
+func newCampaignLevel() *Level {
+	l := createTestLevel(20, 20)
+	l.TileWidth = 206
+	l.TileHeight = 102
+	l.Player = &domain.Player{}
+	l.Castles = []*domain.Castle{{Name: "Red Castle", Color: domain.ColorRed, RogueName: "Kiora", MapTile: image.Pt(2, 2)}}
+	for _, c := range []*domain.City{
+		{Name: "Near", X: 4, Y: 4},
+		{Name: "Far", X: 16, Y: 18},
+	} {
+		l.Tile(image.Pt(c.X, c.Y)).City = c
+	}
+	return l
+}
+
+func TestMinionNameIsColorMatchedAndNeverAWizard(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for _, color := range []domain.ColorMask{domain.ColorWhite, domain.ColorBlue, domain.ColorBlack, domain.ColorRed, domain.ColorGreen} {
+		for range 20 {
+			name, ok := minionName(rng, domain.Rogues, color, 4)
+			if !ok {
+				t.Fatalf("no %s minion", domain.ColorMaskToString(color))
+			}
+			rogue := domain.Rogues[name]
+			if rogue.PrimaryColor != domain.ColorMaskToString(color) {
+				t.Errorf("%s minion %s is %s", domain.ColorMaskToString(color), name, rogue.PrimaryColor)
+			}
+			if rogue.Level > domain.MaxRandomEnemyLevel {
+				t.Errorf("%s is too strong to be a minion (level %d)", name, rogue.Level)
+			}
+		}
+	}
+}
+
+func TestMinionNameFallsBackToTheWeakest(t *testing.T) {
+	rogues := map[string]*domain.Character{
+		"Brute":  {Name: "Brute", PrimaryColor: "Red", Level: 6},
+		"Goblin": {Name: "Goblin", PrimaryColor: "Red", Level: 3},
+		"Wizard": {Name: "Wizard", PrimaryColor: "Red", Level: domain.MaxRandomEnemyLevel + 1},
+	}
+	name, ok := minionName(rand.New(rand.NewSource(1)), rogues, domain.ColorRed, 1)
+	if !ok || name != "Goblin" {
+		t.Errorf("minionName = %q, %v; want Goblin", name, ok)
+	}
+	if _, ok := minionName(rand.New(rand.NewSource(1)), rogues, domain.ColorBlue, 10); ok {
+		t.Error("minionName found a blue minion among red rogues")
+	}
+}
+
+func TestCampaignSchedulesThenDispatchesAMinion(t *testing.T) {
+	l := newCampaignLevel()
+	rng := rand.New(rand.NewSource(3))
+	pace := l.conquestPace()
+
+	l.Player.Days = 1
+	l.advanceCampaign(rng)
+	next, ok := l.Campaign.NextDispatch[domain.ColorRed]
+	if !ok || next <= 1 {
+		t.Fatalf("red castle scheduled for day %d (%v), want a later day", next, ok)
+	}
+	if len(l.Enemies) != 0 {
+		t.Fatalf("a minion left before its day: %d enemies", len(l.Enemies))
+	}
+
+	l.Player.Days = next
+	l.advanceCampaign(rng)
+	if len(l.Enemies) != 1 {
+		t.Fatalf("got %d enemies after the dispatch day, want 1", len(l.Enemies))
+	}
+	siege := l.Enemies[0].Siege
+	if siege == nil || siege.Color != domain.ColorRed || siege.EndsDay != next+siegeDays {
+		t.Fatalf("minion siege = %+v", siege)
+	}
+	if l.Enemies[0].Character.PrimaryColor != "Red" {
+		t.Errorf("minion %s is not red", l.Enemies[0].Name())
+	}
+	if !l.IsBesieged(siege.City) {
+		t.Errorf("city at %v not reported besieged", siege.City)
+	}
+	if got := l.Campaign.NextDispatch[domain.ColorRed]; got != next+pace.dispatchDays {
+		t.Errorf("next dispatch = day %d, want %d", got, next+pace.dispatchDays)
+	}
+}
+
+func TestDefeatedCastlesSendNoMinions(t *testing.T) {
+	l := newCampaignLevel()
+	l.Castles[0].Defeated = true
+	l.Campaign.NextDispatch = map[domain.ColorMask]int{domain.ColorRed: 1}
+	l.Player.Days = 5
+	l.advanceCampaign(rand.New(rand.NewSource(1)))
+	if len(l.Enemies) != 0 {
+		t.Errorf("a defeated castle sent %d minions", len(l.Enemies))
+	}
+}
+
+// besiege puts the weakest red minion outside city until endsDay.
+func besiege(l *Level, city image.Point, endsDay int) {
+	name, _ := weakestMinionName(domain.Rogues, domain.ColorRed)
+	l.Enemies = append(l.Enemies, domain.Enemy{
+		Character: domain.Rogues[name],
+		Siege:     &domain.Siege{City: city, Color: domain.ColorRed, EndsDay: endsDay},
+	})
+}
+
+func TestSiegeTakesTheCityWhenItEnds(t *testing.T) {
+	l := newCampaignLevel()
+	l.Campaign.NextDispatch = map[domain.ColorMask]int{domain.ColorRed: 100}
+	near := image.Pt(4, 4)
+	besiege(l, near, 8)
+
+	l.Player.Days = 7
+	l.advanceCampaign(rand.New(rand.NewSource(1)))
+	if l.Tile(near).City.IsConquered() {
+		t.Fatal("city fell before its siege ended")
+	}
+
+	besieger := l.Enemies[0].Name()
+	l.Player.Days = 8
+	l.advanceCampaign(rand.New(rand.NewSource(1)))
+	city := l.Tile(near).City
+	if city.ConqueredBy != domain.ColorRed || city.Occupier != besieger {
+		t.Fatalf("city after the siege = conquered by %d, occupier %q", city.ConqueredBy, city.Occupier)
+	}
+	if len(l.Enemies) != 0 {
+		t.Errorf("the besieger stayed on the map: %d enemies", len(l.Enemies))
+	}
+	if got := l.ConqueredCities(); got != 1 {
+		t.Errorf("ConqueredCities = %d, want 1", got)
+	}
+}
+
+func TestBeatenBesiegerLiftsTheSiege(t *testing.T) {
+	l := newCampaignLevel()
+	near := image.Pt(4, 4)
+	besiege(l, near, 8)
+	l.RemoveEnemyAt(0)
+
+	l.Player.Days = 8
+	l.advanceCampaign(rand.New(rand.NewSource(1)))
+	if l.Tile(near).City.IsConquered() {
+		t.Error("city fell to a minion the player had beaten")
+	}
+}
+
+func TestLosingToABesiegerGivesItTheCity(t *testing.T) {
+	l := newCampaignLevel()
+	near := image.Pt(4, 4)
+	besiege(l, near, 8)
+	if city := l.SiegeDuelLost(0); city == nil || city.Name != "Near" {
+		t.Fatalf("SiegeDuelLost = %v, want Near", city)
+	}
+	if !l.Tile(near).City.IsConquered() {
+		t.Error("city still free after the player lost to its besieger")
+	}
+}
+
+func TestSiegeTargetsSkipHeldAndBesiegedCities(t *testing.T) {
+	l := newCampaignLevel()
+	near, far := image.Pt(4, 4), image.Pt(16, 18)
+	rng := rand.New(rand.NewSource(1))
+
+	if got, ok := l.siegeTarget(rng, image.Pt(2, 2)); !ok || (got != near && got != far) {
+		t.Fatalf("siegeTarget = %v, %v", got, ok)
+	}
+	besiege(l, near, 8)
+	if got, ok := l.siegeTarget(rng, image.Pt(2, 2)); !ok || got != far {
+		t.Errorf("siegeTarget with Near besieged = %v, %v; want Far", got, ok)
+	}
+	l.Tile(far).City.ConqueredBy = domain.ColorBlue
+	if got, ok := l.siegeTarget(rng, image.Pt(2, 2)); ok {
+		t.Errorf("siegeTarget found %v with every city taken or besieged", got)
+	}
+}
+
+func TestLiberateCity(t *testing.T) {
+	l := newCampaignLevel()
+	city := l.Tile(image.Pt(4, 4)).City
+	occupier, _ := weakestMinionName(domain.Rogues, domain.ColorRed)
+	city.ConqueredBy = domain.ColorRed
+	city.Occupier = occupier
+
+	name, ok := l.OccupierName(city)
+	if !ok || name != occupier {
+		t.Fatalf("OccupierName = %q, %v", name, ok)
+	}
+	l.LiberateCity(city)
+	if city.IsConquered() || city.Occupier != "" {
+		t.Errorf("city still held after liberation: %+v", city)
+	}
+	if _, ok := l.OccupierName(city); ok {
+		t.Error("a free city has an occupier")
+	}
+}
+
+func TestOccupierNameFallsBackForUnknownRogues(t *testing.T) {
+	l := newCampaignLevel()
+	city := l.Tile(image.Pt(4, 4)).City
+	city.ConqueredBy = domain.ColorGreen
+	city.Occupier = "No Such Rogue"
+	name, ok := l.OccupierName(city)
+	if !ok || domain.Rogues[name].PrimaryColor != "Green" {
+		t.Errorf("OccupierName = %q, %v; want a green rogue", name, ok)
+	}
+}
+
+func TestCampaignLostWhenTooManyCitiesFall(t *testing.T) {
+	l := createTestLevel(30, 30)
+	l.Difficulty = domain.DifficultyExpert
+	limit := l.MaxConqueredCities()
+	for i := range limit {
+		l.Tile(image.Pt(i, 0)).City = &domain.City{X: i, ConqueredBy: domain.ColorBlack}
+	}
+	l.Tile(image.Pt(0, 1)).City = &domain.City{Y: 1}
+	if !l.CampaignLost() {
+		t.Errorf("campaign not lost with %d of %d cities taken", l.ConqueredCities(), limit)
+	}
+	l.LiberateCity(l.Tile(image.Pt(0, 0)).City)
+	if l.CampaignLost() {
+		t.Error("campaign still lost after a city was liberated")
+	}
+}
+
+func TestCampaignSurvivesJSONRoundTrip(t *testing.T) {
+	l := newCampaignLevel()
+	l.Campaign = Campaign{Day: 9, NextDispatch: map[domain.ColorMask]int{domain.ColorRed: 21}}
+	besiege(l, image.Pt(4, 4), 12)
+
+	data, err := json.Marshal(l)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got Level
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Campaign.Day != 9 || got.Campaign.NextDispatch[domain.ColorRed] != 21 {
+		t.Errorf("campaign after round trip = %+v", got.Campaign)
+	}
+	if len(got.Enemies) != 1 || got.Enemies[0].Siege == nil || got.Enemies[0].Siege.EndsDay != 12 {
+		t.Errorf("siege lost in round trip: %+v", got.Enemies)
+	}
+}
+
+func TestLinkedCityHoldsOutLonger(t *testing.T) {
+	l := newCampaignLevel()
+	l.Tile(image.Pt(16, 18)).City.ConqueredBy = domain.ColorBlue
+	near := l.Tile(image.Pt(4, 4)).City
+	l.Player.LinkCity(near)
+
+	if err := l.dispatchMinion(rand.New(rand.NewSource(1)), l.Castles[0]); err != nil {
+		t.Fatal(err)
+	}
+	if got := l.Enemies[0].Siege.EndsDay; got != linkedSiegeDays {
+		t.Errorf("siege of a linked city ends on day %d, want %d", got, linkedSiegeDays)
+	}
+}
+
+func TestLinkedCityLosesItsLinkInsteadOfFalling(t *testing.T) {
+	l := newCampaignLevel()
+	near := image.Pt(4, 4)
+	city := l.Tile(near).City
+	l.Player.LinkCity(city)
+	besiege(l, near, 8)
+
+	l.Player.Days = 8
+	l.advanceCampaign(rand.New(rand.NewSource(1)))
+	if city.IsConquered() {
+		t.Fatal("a linked city fell to its siege")
+	}
+	if city.IsManaLinked || len(l.Player.ManaLinks) != 0 {
+		t.Errorf("the link survived the siege: %+v", l.Player.ManaLinks)
+	}
+
+	besiege(l, near, 20)
+	if got := l.SiegeDuelLost(0); got == nil || !city.IsConquered() {
+		t.Error("a city whose link broke did not fall to the next besieger")
+	}
+}
@@ -0,0 +1,128 @@
+package world
+
+import (
+	"fmt"
+	"image"
+	"math/rand"
+	"time"
+
+	"github.com/benprew/s30/game/domain"
+)
+
+// DuelClueChance is the chance that an enemy beaten on the world map was
+// carrying a clue to one of the dungeons.
+const DuelClueChance = 0.25
+
+// RevealDungeonClue reveals the next unrevealed clue of a random uncleared
+// dungeon other than the one at from and records it in the player's clue
+// journal. ok is false when every clue has been found.
+func (l *Level) RevealDungeonClue(from image.Point) (d *domain.Dungeon, clue domain.DungeonClue, ok bool) {
+	return l.revealDungeonClue(from, rand.New(rand.NewSource(time.Now().UnixNano())))
+}
+
+// RevealDuelClue is RevealDungeonClue for an enemy beaten on the world map,
+// which only carries a clue DuelClueChance of the time.
+func (l *Level) RevealDuelClue() (d *domain.Dungeon, clue domain.DungeonClue, ok bool) {
+	return l.revealDuelClue(rand.New(rand.NewSource(time.Now().UnixNano())))
+}
+
+func (l *Level) revealDuelClue(rng *rand.Rand) (*domain.Dungeon, domain.DungeonClue, bool) {
+	if rng.Float64() >= DuelClueChance {
+		return nil, domain.DungeonClue{}, false
+	}
+	// Off the map, so no dungeon is skipped.
+	return l.revealDungeonClue(image.Point{-1, -1}, rng)
+}
+
+// HasDungeonClues reports whether RevealDungeonClue has a clue left to give
+// from from.
+func (l *Level) HasDungeonClues(from image.Point) bool {
+	return len(l.clueCandidates(from)) > 0
+}
+
+func (l *Level) clueCandidates(from image.Point) []*domain.Dungeon {
+	var candidates []*domain.Dungeon
+	for _, d := range l.Dungeons {
+		if d.Cleared || d.IsCastle() || d.MapTile == from || clueSlot(d) < 0 {
+			continue
+		}
+		candidates = append(candidates, d)
+	}
+	return candidates
+}
+
+func (l *Level) revealDungeonClue(from image.Point, rng *rand.Rand) (*domain.Dungeon, domain.DungeonClue, bool) {
+	candidates := l.clueCandidates(from)
+	if len(candidates) == 0 {
+		return nil, domain.DungeonClue{}, false
+	}
+	d := candidates[rng.Intn(len(candidates))]
+	// Dungeons from older saves were placed before clues were written.
+	d.WriteClues(l.locationClue(d))
+	i := clueSlot(d)
+	d.Clues[i].Revealed = true
+	if l.Player != nil {
+		l.Player.RecordClue(d.Name, d.Clues[i])
+	}
+	return d, d.Clues[i], true
+}
+
+// locationClue says where d lies from the nearest city, or roughly where on
+// the map when there are no cities.
+func (l *Level) locationClue(d *domain.Dungeon) string {
+	var city *domain.City
+	var at image.Point
+	for _, p := range l.cityTileLocations() {
+		if city == nil || tileDistance(p, d.MapTile) < tileDistance(at, d.MapTile) {
+			city, at = l.Tile(p).City, p
+		}
+	}
+	if city == nil {
+		center := image.Pt(l.W/2, l.H/2)
+		if dir := compassDirection(center, d.MapTile); dir != "" {
+			return fmt.Sprintf("%s lies in the %s of the realm.", d.Name, dir)
+		}
+		return fmt.Sprintf("%s lies at the heart of the realm.", d.Name)
+	}
+	dist := tileDistance(at, d.MapTile)
+	if dir := compassDirection(at, d.MapTile); dir != "" {
+		return fmt.Sprintf("%s lies to the %s of %s, %d leagues away.", d.Name, dir, city.Name, dist)
+	}
+	return fmt.Sprintf("%s lies close by %s.", d.Name, city.Name)
+}
+
+// clueSlot returns the index of d's first unrevealed clue, or -1.
+func clueSlot(d *domain.Dungeon) int {
+	for i, c := range d.Clues {
+		if !c.Revealed {
+			return i
+		}
+	}
+	return -1
+}
+
+// compassDirection returns the compass point to from from, e.g. "northeast",
+// or "" when to is about on top of from.
+func compassDirection(from, to image.Point) string {
+	dx, dy := to.X-from.X, to.Y-from.Y
+	// Map rows are half a tile tall.
+	dy /= 2
+	var dir string
+	switch {
+	case dy < -absInt(dx)/2:
+		dir = "north"
+	case dy > absInt(dx)/2:
+		dir = "south"
+	}
+	switch {
+	case dx > absInt(dy)/2:
+		dir += "east"
+	case dx < -absInt(dy)/2:
+		dir += "west"
+	}
+	return dir
+}
+
+func tileDistance(a, b image.Point) int {
+	return max(absInt(a.X-b.X), absInt(a.Y-b.Y)/2)
+}
@@ -0,0 +1,81 @@
+package world
+
+import (
+	"image"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/benprew/s30/game/domain"
+)
+
+func TestPlacedDungeonsHaveEveryClueWritten(t *testing.T) {
+	l := createTestLevel(20, 20)
+	l.Tile(image.Pt(10, 10)).City = &domain.City{Name: "Tenby"}
+	l.placeDungeons(3, 3, 1, nil)
+
+	for _, d := range l.Dungeons {
+		for i, c := range d.Clues {
+			if c.Type != domain.ClueType(i) || c.Text == "" || c.Revealed {
+				t.Errorf("%s clue %d = %+v, want an unrevealed clue of type %d", d.Name, i, c, i)
+			}
+		}
+		if loc := d.Clues[domain.ClueLocation].Text; !strings.Contains(loc, "Tenby") {
+			t.Errorf("%s location clue %q doesn't name the nearest city", d.Name, loc)
+		}
+	}
+}
+
+func TestLocationClueNamesTheNearestCity(t *testing.T) {
+	l := createTestLevel(20, 20)
+	l.Tile(image.Pt(2, 2)).City = &domain.City{Name: "Far"}
+	l.Tile(image.Pt(10, 10)).City = &domain.City{Name: "Near"}
+
+	d := &domain.Dungeon{Name: "Sunken Vault", MapTile: image.Pt(14, 10)}
+	if got, want := l.locationClue(d), "Sunken Vault lies to the east of Near, 4 leagues away."; got != want {
+		t.Errorf("locationClue = %q, want %q", got, want)
+	}
+
+	d.MapTile = image.Pt(10, 11)
+	if got, want := l.locationClue(d), "Sunken Vault lies close by Near."; got != want {
+		t.Errorf("locationClue = %q, want %q", got, want)
+	}
+}
+
+func TestRevealDungeonClueRecordsItInTheJournal(t *testing.T) {
+	level := createTestLevel(10, 10)
+	level.Player = &domain.Player{}
+	d := &domain.Dungeon{Name: "Ember Spire", MapTile: image.Pt(8, 8)}
+	level.Dungeons = []*domain.Dungeon{d}
+
+	for range 3 {
+		if _, _, ok := level.RevealDungeonClue(image.Pt(1, 1)); !ok {
+			t.Fatal("RevealDungeonClue found no clue")
+		}
+	}
+
+	got := level.Player.Clues.RevealedClues["Ember Spire"]
+	if len(got) != 3 {
+		t.Fatalf("journal has %d clues to Ember Spire, want 3", len(got))
+	}
+	for i, c := range got {
+		if c.Type != domain.ClueType(i) || c.Text != d.Clues[i].Text {
+			t.Errorf("journal clue %d = %+v, want %+v", i, c, d.Clues[i])
+		}
+	}
+}
+
+func TestRevealDuelClueIsUncommon(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	found := 0
+	for range 400 {
+		level := createTestLevel(10, 10)
+		level.Dungeons = []*domain.Dungeon{{Name: "Gloom Bastion", MapTile: image.Pt(8, 8)}}
+		if _, _, ok := level.revealDuelClue(rng); ok {
+			found++
+		}
+	}
+	if found < 60 || found > 140 {
+		t.Errorf("found a clue after %d of 400 duels, want about %d", found, int(400*DuelClueChance))
+	}
+}
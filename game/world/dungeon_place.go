@@ -42,13 +42,7 @@ func (l *Level) placeDungeons(numDungeons, minDistance int, seed int64, dungeonS
 	cityLocs := l.cityTileLocations()
 	castleLocs := l.castleTileLocations()
 	placed := []image.Point{}
-
-	// Dice grant a card from the player's own deck (lands excluded), so the
-	// pool is shared across every dungeon placed in this world.
-	var diceCardPool []*domain.Card
-	if l.Player != nil {
-		diceCardPool = l.Player.GetDuelDeck().NonLandCards()
-	}
+	diceCardPool := l.diceCardPool()
 
 	for _, loc := range candidates {
 		if len(placed) >= numDungeons {
@@ -63,31 +57,52 @@ func (l *Level) placeDungeons(numDungeons, minDistance int, seed int64, dungeonS
 
 		idx := len(placed)
 		color := dungeonColors[idx%len(dungeonColors)]
-		difficulty := randomDungeonDifficulty(rng)
-		dungeon := domain.GenerateDungeon(domain.DungeonGenOptions{
-			Name:          dungeonName(idx),
-			Difficulty:    difficulty,
-			Color:         color,
-			Theme:         domain.DungeonTheme(idx % 3),
-			NumGoldChests: 2,
-			EnemyPool:     domain.DungeonEnemyPool(color, difficulty),
-			DiceCardPool:  diceCardPool,
-			Seed:          seed + int64(idx),
-		})
-		dungeon.MapTile = loc
-
-		tile := l.Tile(loc)
-		tile.IsDungeon = true
-		tile.Dungeon = dungeon
-		if dungeonSprites != nil {
-			addDungeonSprites(tile, dungeonSprites, idx)
-		}
-
-		l.Dungeons = append(l.Dungeons, dungeon)
+		l.addDungeon(idx, loc, dungeonName(idx), color, randomDungeonDifficulty(rng), seed, diceCardPool, dungeonSprites)
 		placed = append(placed, loc)
 	}
 }
 
+// addDungeon generates the world's idx'th dungeon and puts its entrance on
+// the tile at loc.
+func (l *Level) addDungeon(idx int, loc image.Point, name string, color domain.ColorMask, difficulty domain.DungeonDifficulty,
+	seed int64, diceCardPool []*domain.Card, dungeonSprites [][]*ebiten.Image) {
+	// Effects are rolled from their own source so they don't shift where
+	// later dungeons are placed.
+	effects := rand.New(rand.NewSource(seed + int64(idx)))
+	dungeon := domain.GenerateDungeon(domain.DungeonGenOptions{
+		Name:            name,
+		Difficulty:      difficulty,
+		Color:           color,
+		Theme:           domain.DungeonTheme(idx % 3),
+		Enchantment:     domain.RollDungeonEnchantment(color, effects),
+		CardRestriction: domain.RollCardRestriction(color, difficulty, effects),
+		NumGoldChests:   2,
+		EnemyPool:       domain.DungeonEnemyPool(color, difficulty),
+		DiceCardPool:    diceCardPool,
+		Seed:            seed + int64(idx),
+	})
+	dungeon.MapTile = loc
+	dungeon.WriteClues(l.locationClue(dungeon))
+
+	tile := l.Tile(loc)
+	tile.IsDungeon = true
+	tile.Dungeon = dungeon
+	if dungeonSprites != nil {
+		addDungeonSprites(tile, dungeonSprites, idx)
+	}
+
+	l.Dungeons = append(l.Dungeons, dungeon)
+}
+
+// diceCardPool is the cards dungeon dice grant: the player's own deck, lands
+// excluded, shared across every dungeon in the world.
+func (l *Level) diceCardPool() []*domain.Card {
+	if l.Player == nil {
+		return nil
+	}
+	return l.Player.GetDuelDeck().NonLandCards()
+}
+
 func randomDungeonDifficulty(rng *rand.Rand) domain.DungeonDifficulty {
 	roll := rng.Intn(100)
 	switch {
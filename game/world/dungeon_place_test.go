@@ -128,3 +128,24 @@ func TestPlaceDungeonsCyclesOrdinarySpriteThemes(t *testing.T) {
 		}
 	}
 }
+
+func TestPlaceDungeonsRollsEnchantmentsAndRestrictions(t *testing.T) {
+	l := createTestLevel(30, 30)
+	l.placeDungeons(12, 1, 17, nil)
+
+	for _, d := range l.Dungeons {
+		if d.Enchantment == nil {
+			t.Errorf("%s has no enchantment", d.Name)
+		}
+		switch d.Difficulty {
+		case domain.DungeonDifficultyEasy:
+			if d.CardRestriction != nil {
+				t.Errorf("easy dungeon %s restricts cards: %s", d.Name, d.CardRestriction)
+			}
+		case domain.DungeonDifficultyHard:
+			if d.CardRestriction == nil {
+				t.Errorf("hard dungeon %s has no card restriction", d.Name)
+			}
+		}
+	}
+}
@@ -13,8 +13,6 @@ import (
 )
 
 const (
-	Seed = 12345 // Fixed seed for consistency
-
 	// Terrain thresholds
 	Water     = 0.35 // Deeper water
 	Sand      = 0.43 // Beach/Sandy areas
@@ -44,12 +42,12 @@ var Directions = [2][8]image.Point{
 }
 var DirNames = []string{"N", "S", "E", "W", "NE", "NW", "SE", "SW"}
 
-func generateTerrain(w, h int) [][]float64 {
+func generateTerrain(w, h int, seed int64) [][]float64 {
 	// Multiple Perlin noise generators with different frequencies
-	baseNoise := perlin.NewPerlin(2, 2, 3, Seed)
-	riverNoise := perlin.NewPerlin(2, 2, 4, Seed+1)
-	forestNoise := perlin.NewPerlin(3, 2, 3, Seed+2)
-	desertNoise := perlin.NewPerlin(2, 2, 3, Seed+3)
+	baseNoise := perlin.NewPerlin(2, 2, 3, seed)
+	riverNoise := perlin.NewPerlin(2, 2, 4, seed+1)
+	forestNoise := perlin.NewPerlin(3, 2, 3, seed+2)
+	desertNoise := perlin.NewPerlin(2, 2, 3, seed+3)
 
 	terrain := make([][]float64, h)
 	rivers := make([][]float64, h)
@@ -128,7 +126,7 @@ func generateTerrain(w, h int) [][]float64 {
 }
 
 // mapTerrainTypes assigns terrain based on noise values and returns potential city locations.
-func (l *Level) mapTerrainTypes(terrain [][]float64, ss *SpriteSheet, foliage, Sfoliage, foliage2, Sfoliage2, Cstline2, citySprites [][]*ebiten.Image) []image.Point {
+func (l *Level) mapTerrainTypes(rng *rand.Rand, terrain [][]float64, ss *SpriteSheet, foliage, Sfoliage, foliage2, Sfoliage2, Cstline2, citySprites [][]*ebiten.Image) []image.Point {
 	// Fill each tile with one or more sprites randomly.
 	l.Tiles = make([][]*Tile, l.H)
 	validCityLocations := []image.Point{} // Store potential city coordinates
@@ -139,7 +137,7 @@ func (l *Level) mapTerrainTypes(terrain [][]float64, ss *SpriteSheet, foliage, S
 			t := &Tile{}
 			isBorderSpace := x < 4 || y < 8 || x > l.W-4 || y > l.H-8
 			val := terrain[y][x]
-			folIdx := rand.Intn(11)
+			folIdx := rng.Intn(11)
 			isWater := false // Track if the tile is water
 			switch {
 			case isBorderSpace:
@@ -148,7 +146,7 @@ func (l *Level) mapTerrainTypes(terrain [][]float64, ss *SpriteSheet, foliage, S
 			case val < Water:
 				paintTerrain(t, TerrainWater, ss, foliage, Sfoliage, folIdx)
 				isWater = true
-				if rand.Float64() < 0.1 {
+				if rng.Float64() < 0.1 {
 					t.AddFoliageSprite(Sfoliage2[folIdx][0])
 					t.AddFoliageSprite(foliage2[folIdx][0])
 				}
@@ -198,7 +196,7 @@ func paintTerrain(t *Tile, terrain int, ss *SpriteSheet, foliage, Sfoliage [][]*
 		t.AddSprite(ss.Forest)
 		t.AddFoliageSprite(Sfoliage[folRow][2])
 		t.AddFoliageSprite(foliage[folRow][2])
-	case TerrainMountains:
+	case TerrainMountains, TerrainSnow:
 		// Mountains share the plains base; foliage carries the silhouette.
 		t.AddSprite(ss.Plains)
 		t.AddFoliageSprite(Sfoliage[folRow][3])
@@ -208,16 +206,16 @@ func paintTerrain(t *Tile, terrain int, ss *SpriteSheet, foliage, Sfoliage [][]*
 }
 
 // placeCities places cities and returns their locations.
-func (l *Level) placeCities(validLocations []image.Point, citySprites [][]*ebiten.Image, numCities, minDistance int) {
+func (l *Level) placeCities(rng *rand.Rand, validLocations []image.Point, citySprites [][]*ebiten.Image, numCities, minDistance int) {
 	if len(validLocations) == 0 || numCities <= 0 {
-		fmt.Println("Warning: No valid locations provided or numCities <= 0.")
+		logger.Warn("no city locations to choose from", "cities", numCities)
 	}
 
 	placedCities := []image.Point{}
 	castleLocs := l.castleTileLocations()
 
 	// Shuffle valid locations for random placement
-	rand.Shuffle(len(validLocations), func(i, j int) {
+	rng.Shuffle(len(validLocations), func(i, j int) {
 		validLocations[i], validLocations[j] = validLocations[j], validLocations[i]
 	})
 
@@ -247,28 +245,19 @@ func (l *Level) placeCities(validLocations []image.Point, citySprites [][]*ebite
 		// Place the city
 		tile := l.Tile(loc)
 		if tile != nil { // Should always be non-nil based on how validLocations is generated
-			cityIdx := rand.Intn(12)
-			cityX := cityIdx % 6
-			cityY := 0
-			if cityIdx > 5 {
-				cityY = 2
-			}
-
+			addCitySprites(tile, citySprites, rng)
 			tier := domain.TierCapital
-
-			tile.AddCitySprite(citySprites[cityY][cityX])
-			tile.AddCitySprite(citySprites[cityY+1][cityX])
 			amuletColor := assignAmuletColor(len(placedCities))
 
 			// Create city
 			city := &domain.City{
 				Tier:            tier,
-				Name:            genCityName(),
+				Name:            genCityName(rng),
 				X:               loc.X,
 				Y:               loc.Y,
 				BackgroundImage: cityBgImage(int(tier)),
 				AmuletColor:     amuletColor,
-				CardsForSale:    domain.MkCards(),
+				CardsForSale:    domain.MarketStockWithRand(rng, tier, amuletColor),
 			}
 
 			tile.City = city
@@ -278,25 +267,43 @@ func (l *Level) placeCities(validLocations []image.Point, citySprites [][]*ebite
 		if len(placedCities) > 1 {
 			path := l.connectCityBFS(loc)
 			// fmt.Println(path)
-			l.drawRoadAlongPath(path)
+			l.addRoad(path)
 		}
 	}
 
 	if len(placedCities) < numCities {
-		fmt.Printf("Warning: Could only place %d out of %d requested cities with min distance %d.\n", len(placedCities), numCities, minDistance)
+		logger.Warn("could not place every city", "placed", len(placedCities), "requested", numCities, "min_distance", minDistance)
 	}
 
-	// Randomly assign world magics to cities
-	if len(placedCities) > 0 {
-		shuffledCities := make([]image.Point, len(placedCities))
-		copy(shuffledCities, placedCities)
-		rand.Shuffle(len(shuffledCities), func(i, j int) {
+	l.assignWorldMagics(rng, placedCities)
+}
+
+// addCitySprites gives a city tile one of the twelve city sprites and its
+// shadow.
+func addCitySprites(tile *Tile, citySprites [][]*ebiten.Image, rng *rand.Rand) {
+	cityIdx := rng.Intn(12)
+	cityX := cityIdx % 6
+	cityY := 0
+	if cityIdx > 5 {
+		cityY = 2
+	}
+	tile.AddCitySprite(citySprites[cityY][cityX])
+	tile.AddCitySprite(citySprites[cityY+1][cityX])
+}
+
+// assignWorldMagics offers each world magic for sale in a random one of the
+// cities.
+func (l *Level) assignWorldMagics(rng *rand.Rand, cities []image.Point) {
+	if len(cities) > 0 {
+		shuffledCities := make([]image.Point, len(cities))
+		copy(shuffledCities, cities)
+		rng.Shuffle(len(shuffledCities), func(i, j int) {
 			shuffledCities[i], shuffledCities[j] = shuffledCities[j], shuffledCities[i]
 		})
 
 		availableWorldMagics := make([]*domain.WorldMagic, len(domain.AllWorldMagics))
 		copy(availableWorldMagics, domain.AllWorldMagics)
-		rand.Shuffle(len(availableWorldMagics), func(i, j int) {
+		rng.Shuffle(len(availableWorldMagics), func(i, j int) {
 			availableWorldMagics[i], availableWorldMagics[j] = availableWorldMagics[j], availableWorldMagics[i]
 		})
 
@@ -336,7 +343,7 @@ func (l *Level) connectCityBFS(start image.Point) []image.Point {
 				// Check if parent exists to prevent infinite loop if start node wasn't set correctly
 				parent, ok := visited[temp]
 				if !ok || parent == temp {
-					fmt.Printf("Error reconstructing path: Parent not found or is self for %v\n", temp)
+					logger.Error("failed to reconstruct path: parent not found or is self", "tile", temp)
 					return nil // Error in path reconstruction
 				}
 				temp = parent
@@ -410,6 +417,15 @@ func (l *Level) getRoadSprite(direction string) *ebiten.Image {
 	panic(fmt.Sprintf("Warning: No road sprite definition found for direction %s.\n", direction))
 }
 
+// addRoad records a road along path and draws it.
+func (l *Level) addRoad(path []image.Point) {
+	if len(path) < 2 {
+		return
+	}
+	l.Roads = append(l.Roads, path)
+	l.drawRoadAlongPath(path)
+}
+
 // drawRoadAlongPath adds road sprites to tiles along a given path.
 func (l *Level) drawRoadAlongPath(path []image.Point) {
 	// fmt.Println("path:", path)
@@ -420,7 +436,7 @@ func (l *Level) drawRoadAlongPath(path []image.Point) {
 	for i, currentPos := range path {
 		tile := l.Tile(currentPos)
 		if tile == nil {
-			fmt.Printf("Warning: Tile not found at %v during road drawing.\n", currentPos)
+			logger.Warn("tile not found while drawing road", "tile", currentPos)
 			continue
 		}
 
@@ -0,0 +1,352 @@
+package world
+
+import (
+	"fmt"
+	"image"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/benprew/s30/game/domain"
+)
+
+// LairKind says what a random encounter holds. Unnamed encounters grant a
+// basic land matching the terrain; named lairs greet the player with a dialog
+// and a choice of their own.
+type LairKind int
+
+const (
+	LairNone LairKind = iota
+	LairThievesHideout
+	LairOasisOfMouldoon
+	LairRuinedTower
+	LairDiamondMine
+	LairGemCutterGuild
+	LairGuardianGhost
+	LairSpectralArena
+	LairLostCity
+	LairNomadsBazaar
+)
+
+var namedLairs = []LairKind{
+	LairThievesHideout,
+	LairOasisOfMouldoon,
+	LairRuinedTower,
+	LairDiamondMine,
+	LairGemCutterGuild,
+	LairGuardianGhost,
+	LairSpectralArena,
+	LairLostCity,
+	LairNomadsBazaar,
+}
+
+func (k LairKind) String() string {
+	switch k {
+	case LairThievesHideout:
+		return "Thieves Hideout"
+	case LairOasisOfMouldoon:
+		return "Oasis of Mouldoon"
+	case LairRuinedTower:
+		return "Ruined Tower"
+	case LairDiamondMine:
+		return "Diamond Mine"
+	case LairGemCutterGuild:
+		return "Gem Cutter Guild"
+	case LairGuardianGhost:
+		return "Guardian Ghost"
+	case LairSpectralArena:
+		return "Spectral Arena"
+	case LairLostCity:
+		return "Lost City of El'Arkan"
+	case LairNomadsBazaar:
+		return "Nomad's Bazaar"
+	default:
+		return "Lair"
+	}
+}
+
+const (
+	// NamedLairChance is the share of random encounters that are named lairs.
+	NamedLairChance = 0.35
+
+	ThievesHideoutGold = 500
+	OasisLifeBonus     = 5
+	// OasisOffers is how many cards from the deck the oasis asks for.
+	OasisOffers         = 3
+	GemCutterAmuletCost = 200
+)
+
+func randomLairKind(rng *rand.Rand) LairKind {
+	if rng.Float64() >= NamedLairChance {
+		return LairNone
+	}
+	return namedLairs[rng.Intn(len(namedLairs))]
+}
+
+// Lair is a random encounter the player has walked onto. Its methods carry out
+// the choices offered by the encounter screen.
+type Lair struct {
+	Kind    LairKind
+	Tile    image.Point
+	Terrain int
+
+	level *Level
+	rng   *rand.Rand
+}
+
+// NewLair opens the lair at encounter re.
+func (l *Level) NewLair(re RandomEncounter) *Lair {
+	return l.newLairWithRand(re, rand.New(rand.NewSource(time.Now().UnixNano())))
+}
+
+func (l *Level) newLairWithRand(re RandomEncounter, rng *rand.Rand) *Lair {
+	return &Lair{Kind: re.Lair, Tile: re.Tile, Terrain: re.TerrainType, level: l, rng: rng}
+}
+
+// Risk is the chance the Thieves Hideout and Lost City turn on the player.
+// It grows with the difficulty the game was started at.
+func (lr *Lair) Risk() float64 {
+	switch lr.level.Difficulty {
+	case domain.DifficultyMedium:
+		return 0.2
+	case domain.DifficultyHard:
+		return 0.3
+	case domain.DifficultyExpert:
+		return 0.4
+	default:
+		return 0.1
+	}
+}
+
+// penaltyGold is how much gold a sprung trap costs, capped at what the player
+// carries.
+func (lr *Lair) penaltyGold() int {
+	return min(lr.level.Player.Gold, 100*(int(lr.level.Difficulty)+1))
+}
+
+// LandName is the basic land an unnamed lair grants.
+func (lr *Lair) LandName() string {
+	return TerrainToLandName(lr.Terrain)
+}
+
+// SearchHideout searches the Thieves Hideout for its gold. With probability
+// Risk the thieves are home and take some of the player's gold instead.
+func (lr *Lair) SearchHideout() []string {
+	p := lr.level.Player
+	if lr.rng.Float64() < lr.Risk() {
+		lost := lr.penaltyGold()
+		p.Gold -= lost
+		return []string{"The thieves were waiting for you!", fmt.Sprintf("They make off with %d gold.", lost)}
+	}
+	p.Gold += ThievesHideoutGold
+	return []string{"The thieves are out.", fmt.Sprintf("You help yourself to %d gold.", ThievesHideoutGold)}
+}
+
+// OasisOffers returns the cards from the player's active deck the oasis will
+// take in trade. Basic lands are not wanted.
+func (lr *Lair) OasisOffers() []*domain.Card {
+	p := lr.level.Player
+	deck := p.CardCollection.GetDeck(p.ActiveDeck)
+	var cards []*domain.Card
+	for card := range deck {
+		if !domain.IsBasicLand(card) {
+			cards = append(cards, card)
+		}
+	}
+	sort.Slice(cards, func(i, j int) bool { return cards[i].CardName < cards[j].CardName })
+	lr.rng.Shuffle(len(cards), func(i, j int) { cards[i], cards[j] = cards[j], cards[i] })
+	return cards[:min(OasisOffers, len(cards))]
+}
+
+// TradeAtOasis gives up card for extra life in the next duel.
+func (lr *Lair) TradeAtOasis(card *domain.Card) ([]string, error) {
+	p := lr.level.Player
+	if err := p.RemoveCard(card); err != nil {
+		return nil, fmt.Errorf("trade %s at oasis: %w", card.CardName, err)
+	}
+	p.BonusDuelLife += OasisLifeBonus
+	return []string{
+		fmt.Sprintf("You leave your %s at the water's edge.", card.CardName),
+		fmt.Sprintf("You will start your next duel with +%d life.", OasisLifeBonus),
+	}, nil
+}
+
+// SearchTower searches the Ruined Tower for a clue to one of the dungeons.
+func (lr *Lair) SearchTower() []string {
+	d, clue, ok := lr.revealDungeonClue()
+	if !ok {
+		return []string{"The tower has been picked clean.", "You find nothing of interest."}
+	}
+	return []string{fmt.Sprintf("Scrawled on the wall is a clue to %s:", d.Name), clue.Text}
+}
+
+// revealDungeonClue reveals a clue to one of the dungeons and records it in
+// the player's journal.
+func (lr *Lair) revealDungeonClue() (*domain.Dungeon, domain.DungeonClue, bool) {
+	return lr.level.revealDungeonClue(lr.Tile, lr.rng)
+}
+
+// takeRandomAmulet removes one of the player's amulets at random, weighted by
+// how many of each color they carry.
+func (lr *Lair) takeRandomAmulet() (domain.Amulet, bool) {
+	p := lr.level.Player
+	var colors []domain.ColorMask
+	for _, c := range domain.GetAllAmuletColors() {
+		for range p.Amulets[c] {
+			colors = append(colors, c)
+		}
+	}
+	if len(colors) == 0 {
+		return domain.Amulet{}, false
+	}
+	c := colors[lr.rng.Intn(len(colors))]
+	if err := p.RemoveAmulet(c); err != nil {
+		return domain.Amulet{}, false
+	}
+	return domain.NewAmulet(c), true
+}
+
+// MineDiamonds trades one of the player's amulets, chosen at random, for a
+// powerful card of the amulet's color.
+func (lr *Lair) MineDiamonds() ([]string, *domain.Card) {
+	amulet, ok := lr.takeRandomAmulet()
+	if !ok {
+		return []string{"The miners only trade for amulets."}, nil
+	}
+	cards := domain.RandomHighCardsForColor(amulet.Color, 1)
+	if len(cards) == 0 {
+		lr.level.Player.AddAmulet(amulet)
+		return []string{"The miners have nothing to trade today."}, nil
+	}
+	lr.level.Player.CardCollection.AddCard(cards[0], 1)
+	return []string{fmt.Sprintf("The miners take your %s", amulet.Name), fmt.Sprintf("and dig up a %s for you.", cards[0].CardName)}, cards[0]
+}
+
+// BuyAmulet buys an amulet of color from the Gem Cutter Guild.
+func (lr *Lair) BuyAmulet(color domain.ColorMask) ([]string, error) {
+	p := lr.level.Player
+	if p.Gold < GemCutterAmuletCost {
+		return nil, fmt.Errorf("not enough gold: an amulet costs %d", GemCutterAmuletCost)
+	}
+	p.Gold -= GemCutterAmuletCost
+	amulet := domain.NewAmulet(color)
+	p.AddAmulet(amulet)
+	return []string{fmt.Sprintf("You buy an %s for %d gold.", amulet.Name, GemCutterAmuletCost)}, nil
+}
+
+// ConsultGhost trades a random amulet for a look at the deck of one of the
+// wizards still holding a castle.
+func (lr *Lair) ConsultGhost() []string {
+	var castles []*domain.Castle
+	for _, c := range lr.level.Castles {
+		if !c.Defeated && domain.Rogues[c.RogueName] != nil {
+			castles = append(castles, c)
+		}
+	}
+	if len(castles) == 0 {
+		return []string{"The ghost has nothing left to guard."}
+	}
+	amulet, ok := lr.takeRandomAmulet()
+	if !ok {
+		return []string{"The ghost wants an amulet for its secrets."}
+	}
+	castle := castles[lr.rng.Intn(len(castles))]
+	wizard := domain.Rogues[castle.RogueName]
+	return append([]string{
+		fmt.Sprintf("The ghost takes your %s and shows you", amulet.Name),
+		fmt.Sprintf("the %s deck of %s:", domain.ColorMaskToString(castle.Color), wizard.Name),
+	}, bestCards(wizard.GetActiveDeck(), 6)...)
+}
+
+// bestCards returns the names of the n most valuable cards in deck.
+func bestCards(deck domain.Deck, n int) []string {
+	var cards []*domain.Card
+	for c := range deck {
+		if !domain.IsBasicLand(c) {
+			cards = append(cards, c)
+		}
+	}
+	sort.Slice(cards, func(i, j int) bool {
+		if cards[i].Price != cards[j].Price {
+			return cards[i].Price > cards[j].Price
+		}
+		return cards[i].CardName < cards[j].CardName
+	})
+	names := make([]string, 0, n)
+	for _, c := range cards[:min(n, len(cards))] {
+		names = append(names, fmt.Sprintf("  %d x %s", deck[c], c.CardName))
+	}
+	return names
+}
+
+// ArenaChallenger picks the spectre the player faces in the Spectral Arena and
+// the rare card won by beating it. Stronger spectres haunt harder games.
+func (lr *Lair) ArenaChallenger() (*domain.Character, *domain.Card) {
+	color := lr.terrainColor()
+	pool := domain.DungeonEnemyPool(color, lr.arenaDifficulty())
+	if len(pool) == 0 {
+		return nil, nil
+	}
+	challenger := pool[lr.rng.Intn(len(pool))]
+	var prize *domain.Card
+	if cards := domain.RandomPowerfulCardsForColor(color, 1); len(cards) > 0 {
+		prize = cards[0]
+	}
+	return challenger, prize
+}
+
+func (lr *Lair) arenaDifficulty() domain.DungeonDifficulty {
+	switch lr.level.Difficulty {
+	case domain.DifficultyEasy:
+		return domain.DungeonDifficultyEasy
+	case domain.DifficultyMedium:
+		return domain.DungeonDifficultyMedium
+	default:
+		return domain.DungeonDifficultyHard
+	}
+}
+
+func (lr *Lair) terrainColor() domain.ColorMask {
+	switch lr.Terrain {
+	case TerrainPlains:
+		return domain.ColorWhite
+	case TerrainWater, TerrainSand:
+		return domain.ColorBlue
+	case TerrainMarsh:
+		return domain.ColorBlack
+	case TerrainMountains:
+		return domain.ColorRed
+	case TerrainForest:
+		return domain.ColorGreen
+	default:
+		colors := domain.GetAllAmuletColors()
+		return colors[lr.rng.Intn(len(colors))]
+	}
+}
+
+// ExploreLostCity searches El'Arkan for its amulets. With probability Risk
+// the city's guardians take an amulet, or gold if the player has none.
+func (lr *Lair) ExploreLostCity() []string {
+	p := lr.level.Player
+	if lr.rng.Float64() < lr.Risk() {
+		if amulet, ok := lr.takeRandomAmulet(); ok {
+			return []string{"The city's guardians catch you!", fmt.Sprintf("They take your %s.", amulet.Name)}
+		}
+		lost := lr.penaltyGold()
+		p.Gold -= lost
+		return []string{"The city's guardians catch you!", fmt.Sprintf("They take %d gold.", lost)}
+	}
+	for _, c := range domain.GetAllAmuletColors() {
+		p.AddAmulet(domain.NewAmulet(c))
+	}
+	return []string{"Among the ruins you find an amulet", "of every color."}
+}
+
+// Bazaar returns the market the Nomad's Bazaar sells from. Cards cost what
+// they would in town.
+func (lr *Lair) Bazaar() *domain.City {
+	return &domain.City{
+		Name:         LairNomadsBazaar.String(),
+		CardsForSale: domain.MkCardsWithRand(lr.rng),
+	}
+}
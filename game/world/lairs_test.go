@@ -0,0 +1,303 @@
+package world
+
+import (
+	"image"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/benprew/s30/game/domain"
+)
+
+func newTestLair(kind LairKind, difficulty domain.Difficulty, seed int64) *Lair {
+	l := createTestLevel(10, 10)
+	l.Difficulty = difficulty
+	l.Player = &domain.Player{
+		Gold:      1000,
+		Amulets:   make(map[domain.ColorMask]int),
+		Character: domain.Character{CardCollection: domain.NewCardCollection()},
+	}
+	re := RandomEncounter{Tile: image.Pt(2, 2), TerrainType: TerrainPlains, Lair: kind}
+	return l.newLairWithRand(re, rand.New(rand.NewSource(seed)))
+}
+
+func TestRandomLairKindSpawnsEveryLair(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	seen := make(map[LairKind]int)
+	for range 2000 {
+		seen[randomLairKind(rng)]++
+	}
+	if seen[LairNone] < 1000 {
+		t.Errorf("got %d unnamed encounters out of 2000, want most of them", seen[LairNone])
+	}
+	for _, k := range namedLairs {
+		if seen[k] == 0 {
+			t.Errorf("%s never spawned", k)
+		}
+	}
+}
+
+func TestLairRiskGrowsWithDifficulty(t *testing.T) {
+	prev := 0.0
+	for _, d := range []domain.Difficulty{domain.DifficultyEasy, domain.DifficultyMedium, domain.DifficultyHard, domain.DifficultyExpert} {
+		risk := newTestLair(LairThievesHideout, d, 1).Risk()
+		if risk <= prev {
+			t.Errorf("%s risk %.2f, want more than %.2f", domain.DifficultyToString(d), risk, prev)
+		}
+		prev = risk
+	}
+}
+
+func TestSearchHideoutPaysOrRobs(t *testing.T) {
+	for seed := range int64(50) {
+		lair := newTestLair(LairThievesHideout, domain.DifficultyExpert, seed)
+		caught := rand.New(rand.NewSource(seed)).Float64() < lair.Risk()
+
+		lair.SearchHideout()
+
+		want := 1000 + ThievesHideoutGold
+		if caught {
+			want = 1000 - 400
+		}
+		if got := lair.level.Player.Gold; got != want {
+			t.Errorf("seed %d: gold = %d, want %d (caught %v)", seed, got, want, caught)
+		}
+	}
+}
+
+func TestSpringingATrapNeverLeavesNegativeGold(t *testing.T) {
+	for seed := range int64(50) {
+		lair := newTestLair(LairThievesHideout, domain.DifficultyExpert, seed)
+		lair.level.Player.Gold = 30
+		lair.SearchHideout()
+		if lair.level.Player.Gold < 0 {
+			t.Fatalf("seed %d: gold went negative: %d", seed, lair.level.Player.Gold)
+		}
+	}
+}
+
+func TestExploreLostCity(t *testing.T) {
+	for seed := range int64(50) {
+		lair := newTestLair(LairLostCity, domain.DifficultyHard, seed)
+		p := lair.level.Player
+		p.AddAmulet(domain.NewAmulet(domain.ColorRed))
+		caught := rand.New(rand.NewSource(seed)).Float64() < lair.Risk()
+
+		lair.ExploreLostCity()
+
+		if caught {
+			if p.Amulets[domain.ColorRed] != 0 || p.Gold != 1000 {
+				t.Errorf("seed %d: caught, want the amulet taken and gold kept, got %v and %d gold", seed, p.Amulets, p.Gold)
+			}
+			continue
+		}
+		for _, c := range domain.GetAllAmuletColors() {
+			want := 1
+			if c == domain.ColorRed {
+				want = 2
+			}
+			if p.Amulets[c] != want {
+				t.Errorf("seed %d: %s amulets = %d, want %d", seed, domain.ColorMaskToString(c), p.Amulets[c], want)
+			}
+		}
+	}
+}
+
+func TestLostCityTakesGoldWithoutAmulets(t *testing.T) {
+	for seed := range int64(50) {
+		lair := newTestLair(LairLostCity, domain.DifficultyMedium, seed)
+		if rand.New(rand.NewSource(seed)).Float64() >= lair.Risk() {
+			continue
+		}
+		lair.ExploreLostCity()
+		if got := lair.level.Player.Gold; got != 800 {
+			t.Errorf("seed %d: gold = %d, want 800", seed, got)
+		}
+		return
+	}
+	t.Fatal("no seed sprang the trap")
+}
+
+func TestOasisTradesACardForLife(t *testing.T) {
+	lair := newTestLair(LairOasisOfMouldoon, domain.DifficultyEasy, 1)
+	p := lair.level.Player
+	for _, name := range []string{"Mountain", "Lightning Bolt", "Shivan Dragon", "Giant Growth", "Dark Ritual"} {
+		p.CardCollection.AddCardToDeck(domain.FindCardByName(name), 0, 1)
+	}
+
+	offers := lair.OasisOffers()
+	if len(offers) != OasisOffers {
+		t.Fatalf("got %d offers, want %d", len(offers), OasisOffers)
+	}
+	for _, c := range offers {
+		if domain.IsBasicLand(c) {
+			t.Errorf("oasis offered to take basic land %s", c.CardName)
+		}
+	}
+
+	if _, err := lair.TradeAtOasis(offers[0]); err != nil {
+		t.Fatal(err)
+	}
+	if p.CardCollection.GetTotalCount(offers[0]) != 0 {
+		t.Errorf("%s still in the collection after the trade", offers[0].CardName)
+	}
+	if p.BonusDuelLife != OasisLifeBonus {
+		t.Errorf("BonusDuelLife = %d, want %d", p.BonusDuelLife, OasisLifeBonus)
+	}
+	if _, err := lair.TradeAtOasis(offers[0]); err == nil {
+		t.Error("traded a card the player no longer has")
+	}
+}
+
+func TestRuinedTowerRevealsDungeonClues(t *testing.T) {
+	lair := newTestLair(LairRuinedTower, domain.DifficultyEasy, 1)
+	dungeon := &domain.Dungeon{Name: "Test Dungeon", MapTile: image.Pt(8, 2)}
+	dungeon.Clues[1].Text = "Beware the dragon."
+	lair.level.Dungeons = []*domain.Dungeon{
+		{Name: "Cleared", Cleared: true},
+		dungeon,
+	}
+
+	lines := lair.SearchTower()
+	if !dungeon.Clues[0].Revealed || !strings.Contains(dungeon.Clues[0].Text, "east") {
+		t.Errorf("first clue = %+v, want a revealed location clue", dungeon.Clues[0])
+	}
+	if !strings.Contains(strings.Join(lines, " "), "Test Dungeon") {
+		t.Errorf("tower message %q doesn't name the dungeon", lines)
+	}
+
+	lair.SearchTower()
+	if !dungeon.Clues[1].Revealed || dungeon.Clues[1].Text != "Beware the dragon." {
+		t.Errorf("second clue = %+v, want the written clue revealed", dungeon.Clues[1])
+	}
+
+	lair.SearchTower()
+	if lines := lair.SearchTower(); !strings.Contains(lines[0], "picked clean") {
+		t.Errorf("tower with every clue revealed said %q", lines)
+	}
+}
+
+func TestGemCutterGuildSellsAmulets(t *testing.T) {
+	lair := newTestLair(LairGemCutterGuild, domain.DifficultyEasy, 1)
+	p := lair.level.Player
+	p.Gold = GemCutterAmuletCost + 50
+
+	if _, err := lair.BuyAmulet(domain.ColorBlue); err != nil {
+		t.Fatal(err)
+	}
+	if p.Gold != 50 || p.Amulets[domain.ColorBlue] != 1 {
+		t.Errorf("after buying: %d gold and %v, want 50 gold and a blue amulet", p.Gold, p.Amulets)
+	}
+	if _, err := lair.BuyAmulet(domain.ColorBlue); err == nil {
+		t.Error("bought an amulet without enough gold")
+	}
+	if p.Gold != 50 {
+		t.Errorf("failed purchase changed gold to %d", p.Gold)
+	}
+}
+
+func TestDiamondMineTradesAnAmuletForACard(t *testing.T) {
+	lair := newTestLair(LairDiamondMine, domain.DifficultyEasy, 1)
+	p := lair.level.Player
+
+	if _, card := lair.MineDiamonds(); card != nil {
+		t.Fatalf("mine traded %s without an amulet", card.CardName)
+	}
+
+	p.AddAmulet(domain.NewAmulet(domain.ColorGreen))
+	_, card := lair.MineDiamonds()
+	if card == nil {
+		t.Fatal("mine gave no card for an amulet")
+	}
+	if p.Amulets[domain.ColorGreen] != 0 {
+		t.Errorf("amulet kept after the trade: %v", p.Amulets)
+	}
+	if p.CardCollection.GetTotalCount(card) != 1 {
+		t.Errorf("%s not added to the collection", card.CardName)
+	}
+}
+
+func TestGuardianGhostNeedsAnAmulet(t *testing.T) {
+	lair := newTestLair(LairGuardianGhost, domain.DifficultyEasy, 1)
+	var rogue string
+	for name := range domain.Rogues {
+		rogue = name
+		break
+	}
+	lair.level.Castles = []*domain.Castle{{RogueName: rogue, Color: domain.ColorRed}}
+	p := lair.level.Player
+
+	lines := lair.ConsultGhost()
+	if !strings.Contains(lines[0], "wants an amulet") {
+		t.Errorf("ghost without an amulet said %q", lines)
+	}
+
+	p.AddAmulet(domain.NewAmulet(domain.ColorWhite))
+	lines = lair.ConsultGhost()
+	if p.Amulets[domain.ColorWhite] != 0 {
+		t.Error("ghost didn't take the amulet")
+	}
+	if !strings.Contains(strings.Join(lines, " "), rogue) {
+		t.Errorf("ghost message %q doesn't name %s", lines, rogue)
+	}
+}
+
+func TestArenaChallengerScalesWithDifficulty(t *testing.T) {
+	easy, _ := newTestLair(LairSpectralArena, domain.DifficultyEasy, 1).ArenaChallenger()
+	hard, prize := newTestLair(LairSpectralArena, domain.DifficultyExpert, 1).ArenaChallenger()
+	if easy == nil || hard == nil {
+		t.Fatal("arena has no challenger")
+	}
+	if easy.Level > 3 {
+		t.Errorf("easy challenger %s is level %d, want at most 3", easy.Name, easy.Level)
+	}
+	if hard.Level < 8 {
+		t.Errorf("expert challenger %s is level %d, want at least 8", hard.Name, hard.Level)
+	}
+	if prize == nil {
+		t.Error("arena offers no prize")
+	}
+}
+
+func TestTakeRandomEncounterReturnsTheLair(t *testing.T) {
+	level := createTestLevel(1, 1)
+	level.TileWidth = 200
+	level.TileHeight = 100
+	level.Player = &domain.Player{}
+	level.Player.SetLoc(level.TileToPixel(image.Point{}))
+	level.RandomEncounters = []RandomEncounter{{TerrainType: TerrainForest, Lair: LairNomadsBazaar}}
+	level.totalTicks = 1
+
+	level.UpdateEncounters()
+
+	re, ok := level.TakeRandomEncounter()
+	if !ok || re.Lair != LairNomadsBazaar || re.TerrainType != TerrainForest {
+		t.Errorf("TakeRandomEncounter = %+v, %v; want the bazaar", re, ok)
+	}
+	if _, ok := level.TakeRandomEncounter(); ok {
+		t.Error("encounter taken twice")
+	}
+}
+
+func TestRevealDungeonClueSkipsTheDungeonYouAreIn(t *testing.T) {
+	level := createTestLevel(10, 10)
+	here := &domain.Dungeon{Name: "Here", MapTile: image.Pt(1, 1)}
+	there := &domain.Dungeon{Name: "There", MapTile: image.Pt(8, 8)}
+	level.Dungeons = []*domain.Dungeon{here, there}
+
+	for range 3 {
+		if !level.HasDungeonClues(here.MapTile) {
+			t.Fatal("HasDungeonClues = false with clues to There left")
+		}
+		d, _, ok := level.RevealDungeonClue(here.MapTile)
+		if !ok || d != there {
+			t.Fatalf("revealed a clue to %v, want There", d)
+		}
+	}
+	if level.HasDungeonClues(here.MapTile) {
+		t.Error("HasDungeonClues = true after every clue to There was found")
+	}
+	if here.Clues[0].Revealed {
+		t.Error("revealed a clue to the dungeon the player is in")
+	}
+}
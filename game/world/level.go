@@ -11,9 +11,12 @@ import (
 	"github.com/benprew/s30/game/domain"
 	"github.com/benprew/s30/game/timing"
 	"github.com/benprew/s30/game/ui/imageutil"
+	"github.com/benprew/s30/logging"
 	"github.com/hajimehoshi/ebiten/v2"
 )
 
+var logger = logging.Logger(logging.World)
+
 const (
 	enemySpawnCheckInterval = 2 * timing.UpdatesPerSecond
 	enemySpawnGracePeriod   = 7 * timing.UpdatesPerSecond
@@ -28,12 +31,21 @@ type Level struct {
 	Difficulty        domain.Difficulty
 	PlayerColor       domain.ColorMask
 	EnemyStartingLife int `json:"-"`
+	// Seed drove world generation. Generating a level with the same seed
+	// yields the same map, so it is kept in saves and can be shared.
+	Seed int64
+	// Map names the map file the world was laid out from, and is empty for a
+	// generated world. The seed still drives whatever the map leaves open.
+	Map string `json:",omitempty"`
 
 	W, H       int
 	Tiles      [][]*Tile // (Y,X) array of tiles
 	TileWidth  int
 	TileHeight int
 
+	// Roads are the tile paths roads were drawn along, so they can be redrawn
+	// after loading. Saves from before roads were kept reconnect the cities.
+	Roads          [][]image.Point
 	roadSprites    [][]*ebiten.Image // Sprites for roads
 	roadSpriteInfo [][]string        // Maps sprite index to direction string (e.g., "N", "NE")
 
@@ -44,11 +56,10 @@ type Level struct {
 	encounterIndex   int
 	encounterPending bool
 
-	RandomEncounters        []RandomEncounter
-	encounterSprites        [][]*ebiten.Image
-	randomEncounterPending  bool
-	pendingEncounterSprite  int
-	pendingEncounterTerrain int
+	RandomEncounters       []RandomEncounter
+	encounterSprites       [][]*ebiten.Image
+	randomEncounterPending bool
+	pendingEncounter       RandomEncounter
 
 	Dungeons []*domain.Dungeon
 
@@ -58,6 +69,12 @@ type Level struct {
 	pendingCastle     *domain.Castle
 	pendingCastleTile image.Point
 
+	// paths caches the paths enemies have found; see FindPath.
+	paths map[pathKey][]image.Point
+
+	// Campaign is Arzakon's conquest of the cities; see conquest.go.
+	Campaign Campaign
+
 	ticksSinceLastInteraction int
 	totalTicks                int
 	CombatsWon                int
@@ -65,10 +82,19 @@ type Level struct {
 
 // NewLevel returns a new randomly generated Level.
 func NewLevel(c *domain.Player) (*Level, error) {
+	return NewLevelWithSeed(c, NewSeed())
+}
+
+// NewLevelWithSeed returns a Level generated from seed. Terrain, cities,
+// roads, castles, dungeons, random encounters and the initial enemies are all
+// derived from the seed, so the same seed always produces the same world.
+func NewLevelWithSeed(c *domain.Player, seed int64) (*Level, error) {
 	startTime := time.Now()
-	fmt.Println("NewLevel start")
+	logger.Debug("generating level", "seed", seed)
+	rng := rand.New(rand.NewSource(seed))
 
 	l := &Level{
+		Seed:           seed,
 		W:              47,
 		H:              63,
 		TileWidth:      206,
@@ -145,30 +171,30 @@ func NewLevel(c *domain.Player) (*Level, error) {
 		{"", "NE", "E", "SE", "N", "SW"},
 		{"W", "NW", "S", "", "", ""},
 	}
-	noise := generateTerrain(l.W, l.H)
+	noise := generateTerrain(l.W, l.H, seed)
 	// mapTerrainTypes now returns valid city locations and sets Tile.TerrainType
-	validCityLocations := l.mapTerrainTypes(noise, ss, foliage, Sfoliage, foliage2, Sfoliage2, Cstline2, citySprites)
+	validCityLocations := l.mapTerrainTypes(rng, noise, ss, foliage, Sfoliage, foliage2, Sfoliage2, Cstline2, citySprites)
 
-	l.placeCastles(time.Now().UnixNano(), castles1, castles2, ss, foliage, Sfoliage)
-	l.placeCities(validCityLocations, citySprites, 35, 6)
-	l.placeDungeons(5, 6, time.Now().UnixNano(), dungeonSprites)
+	l.placeCastles(rng.Int63(), castles1, castles2, ss, foliage, Sfoliage)
+	l.placeCities(rng, validCityLocations, citySprites, 35, 6)
+	l.placeDungeons(5, 6, rng.Int63(), dungeonSprites)
 
 	// Set initial player position at center of map
 	loc := image.Point{X: l.LevelW() / 2, Y: l.LevelH() / 2}
 	l.Player.SetLoc(loc)
-	fmt.Printf("Starting player at position: %d, %d\n", loc.X, loc.Y)
+	logger.Debug("starting player", "x", loc.X, "y", loc.Y)
 
 	// Spawn initial enemies
-	if err := l.SpawnEnemies(3); err != nil {
+	if err := l.spawnEnemies(rng, 3); err != nil {
 		return nil, fmt.Errorf("failed to spawn enemies: %s", err)
 	}
 
 	if err := l.LoadRandomEncounterSprites(); err != nil {
 		return nil, fmt.Errorf("failed to load random encounter sprites: %s", err)
 	}
-	l.SpawnEncounters(10)
+	l.spawnEncounters(rng, 10)
 
-	fmt.Printf("NewLevel execution time: %s\n", time.Since(startTime))
+	logger.Info("generated level", "seed", seed, "took", time.Since(startTime))
 	return l, nil
 }
 
@@ -211,7 +237,14 @@ func (l *Level) UpdateWorld(screenW, screenH int) error {
 	oldX, oldY := l.Player.X, l.Player.Y
 	oldTimeAccumulator := l.Player.TimeAccumulator
 	oldDays := l.Player.Days
-	if err := l.Player.Update(screenW, screenH, l.LevelW(), l.LevelH()); err != nil {
+	oldFood, oldLife := l.Player.Food, l.Player.Life
+	// Terrain and world magics only change this tick's step, so the saved
+	// base speed is restored afterwards.
+	baseSpeed := l.Player.MoveSpeed
+	l.Player.MoveSpeed = baseSpeed * l.playerSpeedFactor()
+	err := l.Player.Update(screenW, screenH, l.LevelW(), l.LevelH())
+	l.Player.MoveSpeed = baseSpeed
+	if err != nil {
 		return err
 	}
 	if l.IsWaterAtPixel(l.Player.Loc()) {
@@ -219,74 +252,55 @@ func (l *Level) UpdateWorld(screenW, screenH int) error {
 		l.Player.Y = oldY
 		l.Player.TimeAccumulator = oldTimeAccumulator
 		l.Player.Days = oldDays
+		l.Player.Food = oldFood
+		l.Player.Life = oldLife
 	}
 
 	for i := range l.Enemies {
-		ex, ey := l.Enemies[i].X, l.Enemies[i].Y
-		_ = l.Enemies[i].Update(l.Player.Loc())
-		newPos := l.Enemies[i].Loc()
-
-		if !l.IsWaterAtPixel(newPos) {
-			continue
-		}
-
-		dx := newPos.X - ex
-		dy := newPos.Y - ey
-
-		// Wall-slide: when moving diagonally, try allowing movement on one axis only
-		if dx != 0 && dy != 0 {
-			if !l.IsWaterAtPixel(image.Point{X: newPos.X, Y: ey}) {
-				l.Enemies[i].Y = ey
-				continue
-			}
-			if !l.IsWaterAtPixel(image.Point{X: ex, Y: newPos.Y}) {
-				l.Enemies[i].X = ex
-				continue
-			}
-		}
+		l.moveEnemy(&l.Enemies[i])
+	}
 
-		// Blocked: restore and nudge perpendicular to get around the water
-		l.Enemies[i].X = ex
-		l.Enemies[i].Y = ey
-		speed := max(1, int(math.Round(l.Enemies[i].MoveSpeed)))
-		pLoc := l.Player.Loc()
-
-		if dx != 0 {
-			// Has horizontal movement, nudge vertically toward player
-			nudgeY := speed
-			if pLoc.Y < ey {
-				nudgeY = -speed
-			}
-			if !l.IsWaterAtPixel(image.Point{X: ex, Y: ey + nudgeY}) {
-				l.Enemies[i].Y = ey + nudgeY
-			} else if !l.IsWaterAtPixel(image.Point{X: ex, Y: ey - nudgeY}) {
-				l.Enemies[i].Y = ey - nudgeY
-			}
-		} else {
-			// Vertical movement, nudge horizontally toward player
-			nudgeX := speed
-			if pLoc.X < ex {
-				nudgeX = -speed
-			}
-			if !l.IsWaterAtPixel(image.Point{X: ex + nudgeX, Y: ey}) {
-				l.Enemies[i].X = ex + nudgeX
-			} else if !l.IsWaterAtPixel(image.Point{X: ex - nudgeX, Y: ey}) {
-				l.Enemies[i].X = ex - nudgeX
-			}
-		}
+	if l.Player.Days != l.Campaign.Day {
+		l.AdvanceCampaign()
 	}
 
 	l.UpdateEncounters()
 
 	if shouldSpawnEnemy(l.totalTicks, l.ticksSinceLastInteraction) {
 		if err := l.SpawnEnemies(1); err != nil {
-			fmt.Printf("Warning: failed to spawn enemy: %s\n", err)
+			logger.Warn("failed to spawn enemy", "err", err)
 		}
 	}
 
 	return nil
 }
 
+// moveEnemy moves an enemy for one update. One chasing the player or
+// marching on its city follows a path around the water; one wandering turns
+// elsewhere when it reaches the shore.
+func (l *Level) moveEnemy(e *domain.Enemy) {
+	prev := e.Loc()
+	target := l.enemyTarget(e)
+	following := e.Siege != nil || l.pursues(e, target)
+	if following {
+		e.MoveToward(l.stepToward(prev, target))
+	} else {
+		_ = e.Update(target)
+	}
+	if !l.IsWaterAtPixel(e.Loc()) || l.IsWaterAtPixel(prev) {
+		return
+	}
+
+	e.SetLoc(prev)
+	if following {
+		// The step clipped the shore turning a corner. From the middle of
+		// the tile every step along the path stays on land.
+		e.MoveToward(l.pixelInTile(l.PixelToTile(prev)))
+	} else {
+		e.Redirect()
+	}
+}
+
 func shouldSpawnEnemy(totalTicks, ticksSinceLastInteraction int) bool {
 	return totalTicks%enemySpawnCheckInterval == 0 && ticksSinceLastInteraction >= enemySpawnGracePeriod
 }
@@ -409,8 +423,11 @@ func (l *Level) screenOffset(x, y, screenW, screenH int) (int, int) {
 }
 
 func (l *Level) SpawnEnemies(count int) error {
+	return l.spawnEnemies(rand.New(rand.NewSource(time.Now().UnixNano())), count)
+}
+
+func (l *Level) spawnEnemies(rng *rand.Rand, count int) error {
 	pLoc := l.Player.Loc()
-	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
 	spawnTiles := l.enemySpawnTiles(pLoc)
 	if len(spawnTiles) == 0 {
 		return fmt.Errorf("no valid enemy spawn position available")
@@ -0,0 +1,520 @@
+package world
+
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+	"math/rand"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/benprew/s30/assets"
+	"github.com/benprew/s30/game/domain"
+	"github.com/benprew/s30/game/ui/imageutil"
+)
+
+// A world can be laid out from a map file instead of being generated, so
+// maps can be drawn by hand, and any world can be written back out as one.
+
+// ShandalarMapName is the name of the bundled map of the Shandalar continent.
+const ShandalarMapName = "Shandalar"
+
+// terrainSymbols are the characters a map file draws each terrain with.
+var terrainSymbols = map[int]byte{
+	TerrainWater:     '~',
+	TerrainSand:      ':',
+	TerrainMarsh:     '%',
+	TerrainPlains:    '.',
+	TerrainForest:    'T',
+	TerrainMountains: '^',
+	TerrainSnow:      '*',
+}
+
+// MapFile is a world map: the terrain, and the cities, castles, dungeons and
+// roads laid on it. Tiles are addressed by column and row as in Level.Tiles,
+// so odd rows sit half a tile to the right.
+type MapFile struct {
+	Name string `json:"name"`
+	// Terrain holds one string per row of tiles, one character per tile:
+	// ~ water, : sand, % marsh, . plains, T forest, ^ mountains, * snow.
+	Terrain []string `json:"terrain"`
+	// Start is where the player sets out, the middle of the map if unset.
+	Start    *MapPoint    `json:"start,omitempty"`
+	Cities   []MapCity    `json:"cities"`
+	Castles  []MapCastle  `json:"castles"`
+	Dungeons []MapDungeon `json:"dungeons"`
+	// Roads are left out to have every city joined to its nearest
+	// neighbour, as generated worlds are.
+	Roads []MapRoad `json:"roads,omitempty"`
+}
+
+// MapPoint is a tile on a map.
+type MapPoint struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+// MapCity is a city on a map. Tier is "hamlet", "town" or "capital". The
+// amulet color is dealt round the five colors in order when left out.
+type MapCity struct {
+	Name   string `json:"name"`
+	X      int    `json:"x"`
+	Y      int    `json:"y"`
+	Tier   string `json:"tier"`
+	Amulet string `json:"amulet,omitempty"`
+}
+
+// MapCastle is a castle wizard's castle. Each color has at most one.
+type MapCastle struct {
+	Name  string `json:"name,omitempty"`
+	Color string `json:"color"`
+	X     int    `json:"x"`
+	Y     int    `json:"y"`
+}
+
+// MapDungeon is a dungeon entrance. Difficulty is "easy", "medium" or "hard".
+type MapDungeon struct {
+	Name       string `json:"name"`
+	Color      string `json:"color"`
+	Difficulty string `json:"difficulty"`
+	X          int    `json:"x"`
+	Y          int    `json:"y"`
+}
+
+// MapRoad is a road as the tiles it runs through, each next to the one before.
+// It is written as "x,y x,y ...".
+type MapRoad []image.Point
+
+func (r MapRoad) MarshalText() ([]byte, error) {
+	points := make([]string, len(r))
+	for i, p := range r {
+		points[i] = fmt.Sprintf("%d,%d", p.X, p.Y)
+	}
+	return []byte(strings.Join(points, " ")), nil
+}
+
+func (r *MapRoad) UnmarshalText(text []byte) error {
+	*r = nil
+	for field := range strings.FieldsSeq(string(text)) {
+		xs, ys, ok := strings.Cut(field, ",")
+		x, errX := strconv.Atoi(xs)
+		y, errY := strconv.Atoi(ys)
+		if !ok || errX != nil || errY != nil {
+			return fmt.Errorf("bad road tile %q, want x,y", field)
+		}
+		*r = append(*r, image.Point{X: x, Y: y})
+	}
+	return nil
+}
+
+// ShandalarMap returns the bundled handcrafted map of the Shandalar continent.
+func ShandalarMap() (*MapFile, error) {
+	m, err := ParseMapFile(assets.ShandalarMap_json)
+	if err != nil {
+		return nil, fmt.Errorf("bundled %s map: %w", ShandalarMapName, err)
+	}
+	return m, nil
+}
+
+// LoadMapFile reads and checks the map file at path.
+func LoadMapFile(path string) (*MapFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read map %s: %w", path, err)
+	}
+	m, err := ParseMapFile(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return m, nil
+}
+
+// ParseMapFile decodes a map file and checks it can be played.
+func ParseMapFile(data []byte) (*MapFile, error) {
+	var m MapFile
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("failed to parse map: %w", err)
+	}
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// Marshal encodes the map as indented JSON, one terrain row per line.
+func (m *MapFile) Marshal() ([]byte, error) {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode map: %w", err)
+	}
+	return append(data, '\n'), nil
+}
+
+// Size returns the map's width and height in tiles.
+func (m *MapFile) Size() (width, height int) {
+	if len(m.Terrain) == 0 {
+		return 0, 0
+	}
+	return len(m.Terrain[0]), len(m.Terrain)
+}
+
+func (m *MapFile) terrainAt(p image.Point) (int, bool) {
+	w, h := m.Size()
+	if p.X < 0 || p.Y < 0 || p.X >= w || p.Y >= h {
+		return TerrainUndefined, false
+	}
+	return terrainForSymbol(m.Terrain[p.Y][p.X])
+}
+
+func (m *MapFile) start() image.Point {
+	if m.Start != nil {
+		return image.Point{X: m.Start.X, Y: m.Start.Y}
+	}
+	w, h := m.Size()
+	return image.Point{X: w / 2, Y: h / 2}
+}
+
+// Validate checks the map can be laid out: the terrain is a rectangle of
+// known symbols, the start and every place sit on land inside it, no two
+// places share a tile, and roads run between neighbouring land tiles. Without
+// roads, each city must share its land with a city listed before it, so the
+// two can be joined.
+func (m *MapFile) Validate() error {
+	w, _ := m.Size()
+	if w == 0 {
+		return fmt.Errorf("map %q has no terrain", m.Name)
+	}
+	for y, row := range m.Terrain {
+		if len(row) != w {
+			return fmt.Errorf("terrain row %d is %d tiles wide, want %d", y, len(row), w)
+		}
+		for x := range len(row) {
+			if _, ok := terrainForSymbol(row[x]); !ok {
+				return fmt.Errorf("unknown terrain %q at %d,%d", row[x], x, y)
+			}
+		}
+	}
+
+	taken := make(map[image.Point]string)
+	place := func(what string, p image.Point) error {
+		terrain, ok := m.terrainAt(p)
+		if !ok {
+			return fmt.Errorf("%s at %d,%d is off the map", what, p.X, p.Y)
+		}
+		if terrain == TerrainWater {
+			return fmt.Errorf("%s at %d,%d is in the water", what, p.X, p.Y)
+		}
+		if other, ok := taken[p]; ok {
+			return fmt.Errorf("%s at %d,%d is on the same tile as %s", what, p.X, p.Y, other)
+		}
+		taken[p] = what
+		return nil
+	}
+
+	start := m.start()
+	if terrain, ok := m.terrainAt(start); !ok || terrain == TerrainWater {
+		return fmt.Errorf("start %d,%d is not on land", start.X, start.Y)
+	}
+	for _, c := range m.Cities {
+		what := fmt.Sprintf("city %q", c.Name)
+		if c.Name == "" {
+			return fmt.Errorf("city at %d,%d has no name", c.X, c.Y)
+		}
+		if _, ok := parseCityTier(c.Tier); !ok {
+			return fmt.Errorf("%s has unknown tier %q", what, c.Tier)
+		}
+		if c.Amulet != "" && !isAmuletColor(domain.ColorNameToMask(c.Amulet)) {
+			return fmt.Errorf("%s has unknown amulet color %q", what, c.Amulet)
+		}
+		if err := place(what, image.Point{X: c.X, Y: c.Y}); err != nil {
+			return err
+		}
+	}
+	colors := make(map[domain.ColorMask]bool)
+	for _, c := range m.Castles {
+		color := domain.ColorNameToMask(c.Color)
+		if !isAmuletColor(color) {
+			return fmt.Errorf("castle at %d,%d has unknown color %q", c.X, c.Y, c.Color)
+		}
+		if colors[color] {
+			return fmt.Errorf("more than one %s castle", domain.ColorMaskToString(color))
+		}
+		colors[color] = true
+		if err := place(domain.ColorMaskToString(color)+" castle", image.Point{X: c.X, Y: c.Y}); err != nil {
+			return err
+		}
+	}
+	for _, d := range m.Dungeons {
+		what := fmt.Sprintf("dungeon %q", d.Name)
+		if d.Name == "" {
+			return fmt.Errorf("dungeon at %d,%d has no name", d.X, d.Y)
+		}
+		if !isAmuletColor(domain.ColorNameToMask(d.Color)) {
+			return fmt.Errorf("%s has unknown color %q", what, d.Color)
+		}
+		if _, ok := parseDungeonDifficulty(d.Difficulty); !ok {
+			return fmt.Errorf("%s has unknown difficulty %q", what, d.Difficulty)
+		}
+		if err := place(what, image.Point{X: d.X, Y: d.Y}); err != nil {
+			return err
+		}
+	}
+
+	for i, road := range m.Roads {
+		if len(road) < 2 {
+			return fmt.Errorf("road %d has fewer than two tiles", i+1)
+		}
+		for j, p := range road {
+			if terrain, ok := m.terrainAt(p); !ok || terrain == TerrainWater {
+				return fmt.Errorf("road %d crosses %d,%d, which is not land", i+1, p.X, p.Y)
+			}
+			if j > 0 && !isNeighbor(road[j-1], p) {
+				return fmt.Errorf("road %d jumps from %d,%d to %d,%d", i+1, road[j-1].X, road[j-1].Y, p.X, p.Y)
+			}
+		}
+	}
+
+	if m.Roads == nil && len(m.Cities) > 1 {
+		joined := m.landReachableFrom(image.Point{X: m.Cities[0].X, Y: m.Cities[0].Y})
+		for _, c := range m.Cities[1:] {
+			p := image.Point{X: c.X, Y: c.Y}
+			if !joined[p] {
+				return fmt.Errorf("city %q at %d,%d has no road to the other cities", c.Name, c.X, c.Y)
+			}
+		}
+	}
+	return nil
+}
+
+// landReachableFrom returns the land tiles that can be walked to from start.
+func (m *MapFile) landReachableFrom(start image.Point) map[image.Point]bool {
+	seen := map[image.Point]bool{start: true}
+	queue := []image.Point{start}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, d := range Directions[current.Y%2] {
+			next := current.Add(d)
+			if terrain, ok := m.terrainAt(next); !ok || terrain == TerrainWater || seen[next] {
+				continue
+			}
+			seen[next] = true
+			queue = append(queue, next)
+		}
+	}
+	return seen
+}
+
+func isNeighbor(from, to image.Point) bool {
+	return slices.Contains(Directions[from.Y%2][:], to.Sub(from))
+}
+
+func isAmuletColor(color domain.ColorMask) bool {
+	return slices.Contains(domain.GetAllAmuletColors(), color)
+}
+
+func terrainForSymbol(symbol byte) (int, bool) {
+	for terrain, s := range terrainSymbols {
+		if s == symbol {
+			return terrain, true
+		}
+	}
+	return TerrainUndefined, false
+}
+
+func parseCityTier(s string) (domain.CityTier, bool) {
+	for _, tier := range []domain.CityTier{domain.TierHamlet, domain.TierTown, domain.TierCapital} {
+		if strings.EqualFold(s, tier.String()) {
+			return tier, true
+		}
+	}
+	return 0, false
+}
+
+func parseDungeonDifficulty(s string) (domain.DungeonDifficulty, bool) {
+	for _, d := range []domain.DungeonDifficulty{domain.DungeonDifficultyEasy, domain.DungeonDifficultyMedium, domain.DungeonDifficultyHard} {
+		if strings.EqualFold(s, d.String()) {
+			return d, true
+		}
+	}
+	return 0, false
+}
+
+// NewLevelFromMap returns a Level laid out by m. The map fixes the terrain
+// and where the cities, castles, dungeons and roads are; seed drives what it
+// leaves open, such as foliage, shop stock, world magics, dungeon layouts,
+// random encounters and the first enemies.
+func NewLevelFromMap(c *domain.Player, m *MapFile, seed int64) (*Level, error) {
+	if err := m.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid map %q: %w", m.Name, err)
+	}
+	logger.Debug("laying out level from map", "map", m.Name, "seed", seed)
+	rng := rand.New(rand.NewSource(seed))
+	w, h := m.Size()
+
+	l := &Level{
+		Seed:           seed,
+		Map:            m.Name,
+		W:              w,
+		H:              h,
+		TileWidth:      206,
+		TileHeight:     102,
+		Enemies:        make([]domain.Enemy, 0),
+		Player:         c,
+		encounterIndex: -1,
+	}
+
+	sa, err := loadSpriteAssets(l.TileWidth, l.TileHeight)
+	if err != nil {
+		return nil, err
+	}
+	l.castles1Sprites = sa.castles1
+	l.castles2Sprites = sa.castles2
+	if err := l.loadRoadSprites(); err != nil {
+		return nil, err
+	}
+	dungeonSprites, err := imageutil.LoadSpriteSheet(6, 4, assets.Dungeons_png)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load dungeon spritesheet Locatn03.spr.png: %w", err)
+	}
+
+	l.Tiles = make([][]*Tile, h)
+	for y := range h {
+		l.Tiles[y] = make([]*Tile, w)
+		for x := range w {
+			t := &Tile{}
+			terrain, _ := terrainForSymbol(m.Terrain[y][x])
+			folIdx := rng.Intn(11)
+			paintTerrain(t, terrain, sa.ss, sa.foliage, sa.sfoliage, folIdx)
+			if terrain == TerrainWater && rng.Float64() < 0.1 {
+				t.AddFoliageSprite(sa.sfoliage2[folIdx][0])
+				t.AddFoliageSprite(sa.foliage2[folIdx][0])
+			}
+			l.Tiles[y][x] = t
+		}
+	}
+
+	for _, mc := range m.Castles {
+		color := domain.ColorNameToMask(mc.Color)
+		name := mc.Name
+		if name == "" {
+			name = defaultCastleName(color)
+		}
+		l.addCastle(image.Point{X: mc.X, Y: mc.Y}, color, name, sa.castles1, sa.castles2)
+	}
+
+	var cities []image.Point
+	for i, mc := range m.Cities {
+		loc := image.Point{X: mc.X, Y: mc.Y}
+		tile := l.Tile(loc)
+		addCitySprites(tile, sa.citySprites, rng)
+		tier, _ := parseCityTier(mc.Tier)
+		amuletColor := assignAmuletColor(i)
+		if mc.Amulet != "" {
+			amuletColor = domain.ColorNameToMask(mc.Amulet)
+		}
+		tile.City = &domain.City{
+			Tier:            tier,
+			Name:            mc.Name,
+			X:               loc.X,
+			Y:               loc.Y,
+			BackgroundImage: cityBgImage(int(tier)),
+			AmuletColor:     amuletColor,
+			CardsForSale:    domain.MarketStockWithRand(rng, tier, amuletColor),
+		}
+		cities = append(cities, loc)
+	}
+	l.assignWorldMagics(rng, cities)
+
+	if m.Roads == nil {
+		l.connectCities()
+	}
+	for _, road := range m.Roads {
+		l.addRoad(road)
+	}
+
+	dungeonSeed := rng.Int63()
+	diceCardPool := l.diceCardPool()
+	for i, md := range m.Dungeons {
+		difficulty, _ := parseDungeonDifficulty(md.Difficulty)
+		l.addDungeon(i, image.Point{X: md.X, Y: md.Y}, md.Name, domain.ColorNameToMask(md.Color), difficulty,
+			dungeonSeed, diceCardPool, dungeonSprites)
+	}
+
+	// TileToPixel's center falls on the boundary with the next row down, so
+	// the player is set a quarter tile higher to stand on the start tile.
+	l.Player.SetLoc(l.TileToPixel(m.start()).Sub(image.Point{Y: l.TileHeight / 4}))
+
+	if err := l.spawnEnemies(rng, 3); err != nil {
+		return nil, fmt.Errorf("failed to spawn enemies: %s", err)
+	}
+	if err := l.LoadRandomEncounterSprites(); err != nil {
+		return nil, fmt.Errorf("failed to load random encounter sprites: %s", err)
+	}
+	l.spawnEncounters(rng, 10)
+
+	return l, nil
+}
+
+// ExportMap returns l as a map file, so a generated world can be kept, shared
+// or edited by hand and played again with NewLevelFromMap.
+func ExportMap(l *Level) *MapFile {
+	m := &MapFile{Name: l.Map}
+	if m.Name == "" {
+		m.Name = fmt.Sprintf("Seed %d", l.Seed)
+	}
+
+	for y := range l.H {
+		row := make([]byte, l.W)
+		for x := range l.W {
+			symbol, ok := terrainSymbols[l.Tiles[y][x].TerrainType]
+			if !ok {
+				symbol = terrainSymbols[TerrainPlains]
+			}
+			row[x] = symbol
+		}
+		m.Terrain = append(m.Terrain, string(row))
+	}
+
+	if l.Player != nil {
+		if p := l.CharacterTile(); l.Tile(p) != nil {
+			m.Start = &MapPoint{X: p.X, Y: p.Y}
+		}
+	}
+
+	for _, p := range l.cityTileLocations() {
+		city := l.Tile(p).City
+		m.Cities = append(m.Cities, MapCity{
+			Name:   city.Name,
+			X:      p.X,
+			Y:      p.Y,
+			Tier:   strings.ToLower(city.Tier.String()),
+			Amulet: strings.ToLower(domain.ColorMaskToString(city.AmuletColor)),
+		})
+	}
+	for _, c := range l.Castles {
+		m.Castles = append(m.Castles, MapCastle{
+			Name:  c.Name,
+			Color: strings.ToLower(domain.ColorMaskToString(c.Color)),
+			X:     c.MapTile.X,
+			Y:     c.MapTile.Y,
+		})
+	}
+	for _, d := range l.Dungeons {
+		m.Dungeons = append(m.Dungeons, MapDungeon{
+			Name:       d.Name,
+			Color:      strings.ToLower(domain.ColorMaskToString(d.Color)),
+			Difficulty: strings.ToLower(d.Difficulty.String()),
+			X:          d.MapTile.X,
+			Y:          d.MapTile.Y,
+		})
+	}
+
+	m.Roads = make([]MapRoad, 0, len(l.Roads))
+	for _, road := range l.Roads {
+		m.Roads = append(m.Roads, slices.Clone(MapRoad(road)))
+	}
+	return m
+}
@@ -0,0 +1,145 @@
+package world
+
+import (
+	"image"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/benprew/s30/game/domain"
+)
+
+func TestShandalarMapIsPlayable(t *testing.T) {
+	m, err := ShandalarMap()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w, h := m.Size(); w != 47 || h != 63 {
+		t.Errorf("size = %dx%d, want the generated worlds' 47x63", w, h)
+	}
+	if len(m.Castles) != 5 {
+		t.Errorf("castles = %d, want one per color", len(m.Castles))
+	}
+
+	reachable := m.landReachableFrom(m.start())
+	for _, c := range m.Cities {
+		if !reachable[image.Pt(c.X, c.Y)] {
+			t.Errorf("city %s can't be walked to from the start", c.Name)
+		}
+	}
+	for _, c := range m.Castles {
+		if !reachable[image.Pt(c.X, c.Y)] {
+			t.Errorf("castle %s can't be walked to from the start", c.Name)
+		}
+	}
+	for _, d := range m.Dungeons {
+		if !reachable[image.Pt(d.X, d.Y)] {
+			t.Errorf("dungeon %s can't be walked to from the start", d.Name)
+		}
+	}
+
+	l, err := NewLevelFromMap(&domain.Player{}, m, 7)
+	if err != nil {
+		t.Fatalf("NewLevelFromMap: %v", err)
+	}
+	if l.Map != ShandalarMapName || l.Seed != 7 {
+		t.Errorf("level map, seed = %q, %d", l.Map, l.Seed)
+	}
+	if got := l.CharacterTile(); got != m.start() {
+		t.Errorf("player starts on %v, want %v", got, m.start())
+	}
+	celestine := l.Tile(image.Pt(m.Cities[0].X, m.Cities[0].Y))
+	if !celestine.IsCity() || celestine.City.Name != m.Cities[0].Name || len(celestine.City.CardsForSale) == 0 {
+		t.Errorf("first city = %+v", celestine.City)
+	}
+	if len(l.Castles) != 5 || len(l.Dungeons) != len(m.Dungeons) || len(l.Roads) != len(m.Roads) {
+		t.Errorf("level has %d castles, %d dungeons and %d roads", len(l.Castles), len(l.Dungeons), len(l.Roads))
+	}
+	for _, road := range l.Roads {
+		for _, p := range road {
+			if !l.Tile(p).IsRoad() {
+				t.Fatalf("road tile %v has no road drawn", p)
+			}
+		}
+	}
+}
+
+func TestExportMapRoundTrips(t *testing.T) {
+	generated, err := NewLevelWithSeed(&domain.Player{}, 42)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exported := ExportMap(generated)
+	if exported.Name != "Seed 42" {
+		t.Errorf("name = %q", exported.Name)
+	}
+	data, err := exported.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := ParseMapFile(data)
+	if err != nil {
+		t.Fatalf("ParseMapFile: %v", err)
+	}
+
+	loaded, err := NewLevelFromMap(&domain.Player{}, parsed, 99)
+	if err != nil {
+		t.Fatalf("NewLevelFromMap: %v", err)
+	}
+	again := ExportMap(loaded)
+	if !slices.Equal(again.Terrain, exported.Terrain) {
+		t.Error("terrain changed on the way through a map file")
+	}
+	if !slices.Equal(again.Cities, exported.Cities) || !slices.Equal(again.Castles, exported.Castles) ||
+		!slices.Equal(again.Dungeons, exported.Dungeons) {
+		t.Errorf("places changed:\n%+v\n%+v", exported, again)
+	}
+	if len(again.Roads) != len(exported.Roads) {
+		t.Fatalf("roads = %d, want %d", len(again.Roads), len(exported.Roads))
+	}
+	for i := range again.Roads {
+		if !slices.Equal(again.Roads[i], exported.Roads[i]) {
+			t.Errorf("road %d = %v, want %v", i, again.Roads[i], exported.Roads[i])
+		}
+	}
+	if *again.Start != *exported.Start {
+		t.Errorf("start = %v, want %v", *again.Start, *exported.Start)
+	}
+}
+
+func TestParseMapFileRejectsUnplayableMaps(t *testing.T) {
+	terrain := `"terrain": ["~~~~~~", "~....~", "~....~", "~~~~~~", "~~~~~~", "~~~~~~", "~~..~~", "~~~~~~"]`
+	land := terrain + `, "start": {"x": 2, "y": 1}`
+	tests := []struct {
+		name, body, wantErr string
+	}{
+		{"ragged terrain", `"terrain": ["~~~", "~~"]`, "row 1 is 2 tiles wide"},
+		{"unknown terrain", `"terrain": ["~?~"]`, "unknown terrain"},
+		{"start at sea", terrain + `, "start": {"x": 0, "y": 0}`, "start 0,0 is not on land"},
+		{"city in the water", land + `, "cities": [{"name": "Atlantis", "x": 0, "y": 1, "tier": "town"}]`, "in the water"},
+		{"unknown tier", land + `, "cities": [{"name": "Lesh", "x": 1, "y": 1, "tier": "metropolis"}]`, "unknown tier"},
+		{"two castles of a color", land + `, "castles": [{"color": "red", "x": 1, "y": 1}, {"color": "R", "x": 2, "y": 1}]`,
+			"more than one Red castle"},
+		{"shared tile", land + `, "cities": [{"name": "Lesh", "x": 1, "y": 1, "tier": "town"}],
+			"dungeons": [{"name": "Vault", "color": "blue", "difficulty": "hard", "x": 1, "y": 1}]`, "same tile"},
+		{"road jumps", land + `, "roads": ["1,1 4,1"]`, "road 1 jumps from 1,1 to 4,1"},
+		{"bad road tile", land + `, "roads": ["1,1 two"]`, "bad road tile"},
+		{"city on an island", land + `, "cities": [{"name": "Lesh", "x": 1, "y": 1, "tier": "town"},
+			{"name": "Isle", "x": 2, "y": 6, "tier": "hamlet"}]`, `city "Isle" at 2,6 has no road`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseMapFile([]byte(`{"name": "Broken", ` + tt.body + `}`))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want one containing %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	// An empty road list asks for no roads, so the cities needn't be joined.
+	if _, err := ParseMapFile([]byte(`{"name": "Isle", ` + land + `,
+		"cities": [{"name": "Lesh", "x": 1, "y": 1, "tier": "town"}, {"name": "Isle", "x": 2, "y": 6, "tier": "hamlet"}],
+		"roads": []}`)); err != nil {
+		t.Errorf("map without roads: %v", err)
+	}
+}
@@ -0,0 +1,200 @@
+package world
+
+import (
+	"container/heap"
+	"image"
+	"math"
+
+	"github.com/benprew/s30/game/domain"
+)
+
+// Enemies chasing the player or marching on a city walk the tile grid rather
+// than straight at their target, so they find their way around lakes, rivers
+// and coastlines instead of pressing against the shore.
+
+// maxCachedPaths bounds the path cache. It is emptied when full; paths are
+// cheap to find again and most lookups are for the few targets being chased.
+const maxCachedPaths = 4096
+
+// pathTerrainCosts is what crossing a tile of each terrain costs relative to
+// plains, and is never less than 1. Terrain missing from it, water among
+// them, can't be crossed.
+var pathTerrainCosts = map[int]float64{
+	TerrainSand:      1,
+	TerrainMarsh:     1,
+	TerrainPlains:    1,
+	TerrainForest:    1,
+	TerrainMountains: 1,
+	TerrainSnow:      1,
+}
+
+type pathKey struct {
+	from, to image.Point
+}
+
+// FindPath returns the cheapest walk over land from one tile to another,
+// both ends included, or nil if there is none. Each step is to a neighbour in
+// Directions; a step straight north or south squeezes between two tiles, so
+// both of them must be land too. Paths are cached, and since terrain doesn't
+// change during a game, they stay good for the life of the level.
+func (l *Level) FindPath(from, to image.Point) []image.Point {
+	key := pathKey{from, to}
+	if path, ok := l.paths[key]; ok {
+		return path
+	}
+	path := l.searchPath(from, to)
+	if l.paths == nil || len(l.paths)+len(path) > maxCachedPaths {
+		l.paths = make(map[pathKey][]image.Point)
+	}
+	l.paths[key] = path
+	// Every step along the way is the start of the cheapest path from there.
+	for i := 1; i < len(path)-1; i++ {
+		l.paths[pathKey{path[i], to}] = path[i:]
+	}
+	return path
+}
+
+// searchPath runs an A* search from one tile to another, guided by the
+// straight-line distance between them.
+func (l *Level) searchPath(from, to image.Point) []image.Point {
+	if _, ok := pathTerrainCosts[l.terrainAt(to)]; !ok || l.Tile(from) == nil {
+		return nil
+	}
+	if from == to {
+		return []image.Point{from}
+	}
+
+	cost := map[image.Point]float64{from: 0}
+	parent := map[image.Point]image.Point{}
+	open := &pathQueue{{tile: from, estimate: l.pixelDistance(from, to)}}
+	for open.Len() > 0 {
+		current := heap.Pop(open).(pathNode)
+		if current.tile == to {
+			return pathTo(parent, from, to)
+		}
+		if current.estimate > cost[current.tile]+l.pixelDistance(current.tile, to) {
+			continue // a cheaper way here was found after this one was queued
+		}
+		for _, d := range Directions[current.tile.Y%2] {
+			next := current.tile.Add(d)
+			step := l.stepCost(current.tile, next)
+			if step < 0 {
+				continue
+			}
+			nextCost := cost[current.tile] + step
+			if known, ok := cost[next]; ok && known <= nextCost {
+				continue
+			}
+			cost[next] = nextCost
+			parent[next] = current.tile
+			heap.Push(open, pathNode{tile: next, estimate: nextCost + l.pixelDistance(next, to)})
+		}
+	}
+	return nil
+}
+
+// stepCost is the cost of stepping between neighbouring tiles, or -1 if the
+// step can't be taken.
+func (l *Level) stepCost(from, to image.Point) float64 {
+	terrainCost, ok := pathTerrainCosts[l.terrainAt(to)]
+	if !ok {
+		return -1
+	}
+	if d := to.Sub(from); d.X == 0 && (d.Y == 2 || d.Y == -2) {
+		for _, n := range Directions[from.Y%2][4:] {
+			if n.Y != d.Y/2 {
+				continue
+			}
+			if _, ok := pathTerrainCosts[l.terrainAt(from.Add(n))]; !ok {
+				return -1
+			}
+		}
+	}
+	return terrainCost * l.pixelDistance(from, to)
+}
+
+// terrainAt is the terrain of the tile at p, or TerrainUndefined off the map.
+func (l *Level) terrainAt(p image.Point) int {
+	if t := l.Tile(p); t != nil {
+		return t.TerrainType
+	}
+	return TerrainUndefined
+}
+
+// pixelDistance is the distance in pixels between the middles of two tiles.
+func (l *Level) pixelDistance(a, b image.Point) float64 {
+	d := l.TileToPixel(a).Sub(l.TileToPixel(b))
+	return math.Hypot(float64(d.X), float64(d.Y))
+}
+
+func pathTo(parent map[image.Point]image.Point, from, to image.Point) []image.Point {
+	path := []image.Point{to}
+	for p := to; p != from; {
+		p = parent[p]
+		path = append(path, p)
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
+
+// pathNode is a tile waiting to be searched from, with the estimated cost of
+// the whole path through it.
+type pathNode struct {
+	tile     image.Point
+	estimate float64
+}
+
+// pathQueue is a min-heap of pathNodes by estimate.
+type pathQueue []pathNode
+
+func (q pathQueue) Len() int           { return len(q) }
+func (q pathQueue) Less(i, j int) bool { return q[i].estimate < q[j].estimate }
+func (q pathQueue) Swap(i, j int)      { q[i], q[j] = q[j], q[i] }
+func (q *pathQueue) Push(x any)        { *q = append(*q, x.(pathNode)) }
+func (q *pathQueue) Pop() any {
+	old := *q
+	n := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return n
+}
+
+// pursues reports whether e is chasing the player at pLoc. An enemy takes up
+// the chase once the player comes within EnemyChaseDistance and keeps on
+// along the path, however far round the water it leads, until the player
+// gets more than EnemyGiveUpDistance away along it.
+func (l *Level) pursues(e *domain.Enemy, pLoc image.Point) bool {
+	switch {
+	case e.Chasing(pLoc):
+		e.Pursuing = true
+	case e.Pursuing && l.pathLength(e.Loc(), pLoc) > domain.EnemyGiveUpDistance:
+		e.Pursuing = false
+	}
+	return e.Pursuing
+}
+
+// pathLength is how far, in pixels, it is to walk from one pixel to another
+// along the path between their tiles, or +Inf if there is no path.
+func (l *Level) pathLength(from, to image.Point) float64 {
+	path := l.FindPath(l.PixelToTile(from), l.PixelToTile(to))
+	if path == nil {
+		return math.Inf(1)
+	}
+	length := 0.0
+	for i := 1; i < len(path); i++ {
+		length += l.pixelDistance(path[i-1], path[i])
+	}
+	return length
+}
+
+// stepToward returns the pixel an enemy at loc makes for on its way to
+// target: the middle of the next tile along the path, or target itself once
+// they share a tile or when no path leads there.
+func (l *Level) stepToward(loc, target image.Point) image.Point {
+	path := l.FindPath(l.PixelToTile(loc), l.PixelToTile(target))
+	if len(path) < 2 {
+		return target
+	}
+	return l.pixelInTile(path[1])
+}
@@ -0,0 +1,237 @@
+package world
+
+import (
+	"image"
+	"slices"
+	"testing"
+
+	"github.com/benprew/s30/game/domain"
+)
+
+// buildPathLevel lays out a level from rows of W (water) and P (plains)
+// tiles, in the zigzag rows the world is drawn in.
+func buildPathLevel(t *testing.T, layout []string) *Level {
+	t.Helper()
+	l := createTestLevel(len(layout[0]), len(layout))
+	l.TileWidth = 206
+	l.TileHeight = 102
+	l.Player = &domain.Player{}
+	for y := range layout {
+		for x, tile := range layout[y] {
+			switch tile {
+			case 'W':
+				l.Tiles[y][x].TerrainType = TerrainWater
+			case 'P':
+			default:
+				t.Fatalf("unknown tile type: %c", tile)
+			}
+		}
+	}
+	return l
+}
+
+// checkPath fails t unless path walks over land between neighbours from one
+// tile to the other.
+func checkPath(t *testing.T, l *Level, path []image.Point, from, to image.Point) {
+	t.Helper()
+	if len(path) == 0 || path[0] != from || path[len(path)-1] != to {
+		t.Fatalf("path = %v, want one from %v to %v", path, from, to)
+	}
+	for i, p := range path {
+		if l.Tile(p).TerrainType == TerrainWater {
+			t.Errorf("path %v crosses water at %v", path, p)
+		}
+		if i > 0 && l.stepCost(path[i-1], p) < 0 {
+			t.Errorf("path %v can't step from %v to %v", path, path[i-1], p)
+		}
+	}
+}
+
+func TestFindPathGoesAroundWater(t *testing.T) {
+	l := buildPathLevel(t, []string{
+		"PPPPPP",
+		"PPPPPP",
+		"PPPPPP",
+		"WWWWWP",
+		"WWWWWP",
+		"PPPPPP",
+		"PPPPPP",
+	})
+	from, to := image.Pt(1, 1), image.Pt(1, 6)
+	path := l.FindPath(from, to)
+	checkPath(t, l, path, from, to)
+	if !slices.Contains(path, image.Pt(5, 3)) {
+		t.Errorf("path %v doesn't go through the gap at 5,3", path)
+	}
+}
+
+func TestFindPathWontSqueezeBetweenWater(t *testing.T) {
+	// Stepping straight south from 1,0 to 1,2 passes between 0,1 and 1,1.
+	l := buildPathLevel(t, []string{
+		"PPPP",
+		"WWPP",
+		"PPPP",
+	})
+	from, to := image.Pt(1, 0), image.Pt(1, 2)
+	path := l.FindPath(from, to)
+	checkPath(t, l, path, from, to)
+	if len(path) == 2 {
+		t.Errorf("path %v squeezes between two water tiles", path)
+	}
+
+	l.Tiles[1][1].TerrainType = TerrainPlains
+	if path := l.searchPath(from, to); len(path) == 2 {
+		t.Errorf("path %v squeezes past water on one side", path)
+	}
+	l.Tiles[1][0].TerrainType = TerrainPlains
+	if path := l.searchPath(from, to); len(path) != 2 {
+		t.Errorf("path = %v, want the straight step once both sides are land", path)
+	}
+}
+
+func TestFindPathFailsAcrossWater(t *testing.T) {
+	l := buildPathLevel(t, []string{
+		"PPPP",
+		"PPPP",
+		"WWWW",
+		"WWWW",
+		"PPPP",
+	})
+	if path := l.FindPath(image.Pt(1, 0), image.Pt(1, 4)); path != nil {
+		t.Errorf("path = %v, want none to another island", path)
+	}
+	if path := l.FindPath(image.Pt(1, 0), image.Pt(1, 3)); path != nil {
+		t.Errorf("path = %v, want none into the water", path)
+	}
+	if path := l.FindPath(image.Pt(1, 0), image.Pt(9, 0)); path != nil {
+		t.Errorf("path = %v, want none off the map", path)
+	}
+	if path := l.FindPath(image.Pt(2, 1), image.Pt(2, 1)); !slices.Equal(path, []image.Point{{2, 1}}) {
+		t.Errorf("path = %v, want just the tile itself", path)
+	}
+}
+
+func TestFindPathCachesEveryStep(t *testing.T) {
+	l := buildPathLevel(t, []string{
+		"PPPPPP",
+		"PPPPPP",
+		"WWWWPP",
+		"PPPPPP",
+		"PPPPPP",
+	})
+	from, to := image.Pt(0, 0), image.Pt(0, 4)
+	path := l.FindPath(from, to)
+	checkPath(t, l, path, from, to)
+	cached := len(l.paths)
+	if cached != len(path)-1 {
+		t.Errorf("cached %d paths, want one from each of the %d tiles before the end", cached, len(path)-1)
+	}
+	// The enemy moves on a tile and asks again.
+	if next := l.FindPath(path[1], to); !slices.Equal(next, path[1:]) {
+		t.Errorf("path from %v = %v, want the rest of %v", path[1], next, path)
+	}
+	l.FindPath(image.Pt(0, 4), image.Pt(0, 3))
+	if len(l.paths) != cached+1 {
+		t.Errorf("cache has %d paths, want %d", len(l.paths), cached+1)
+	}
+}
+
+// walkEnemy moves e until it stands on tile, failing t if it ever steps into
+// the water or takes too long.
+func walkEnemy(t *testing.T, l *Level, e *domain.Enemy, tile image.Point) {
+	t.Helper()
+	for range 5000 {
+		l.moveEnemy(e)
+		if l.IsWaterAtPixel(e.Loc()) {
+			t.Fatalf("enemy walked into the water at %v", l.PixelToTile(e.Loc()))
+		}
+		if l.PixelToTile(e.Loc()) == tile {
+			return
+		}
+	}
+	t.Fatalf("enemy stuck at %v on its way to %v", l.PixelToTile(e.Loc()), tile)
+}
+
+func TestChasingEnemyWalksAroundARiver(t *testing.T) {
+	l := buildPathLevel(t, []string{
+		"PPPPPPPP",
+		"PPPPPPPP",
+		"PPPPPPPP",
+		"WWWPPPPP",
+		"PPPPPPPP",
+		"PPPPPPPP",
+	})
+	player, start := image.Pt(1, 4), image.Pt(1, 2)
+	l.Player.SetLoc(l.pixelInTile(player))
+	e := &domain.Enemy{Character: &domain.Character{Name: "Chaser"}}
+	e.SetLoc(l.pixelInTile(start))
+	e.MoveSpeed = domain.MovementSpeed(120)
+	if !l.pursues(e, l.Player.Loc()) {
+		t.Fatal("the player should be in chasing range across the river")
+	}
+	walkEnemy(t, l, e, player)
+}
+
+func TestMinionMarchesOnItsCityAroundALake(t *testing.T) {
+	l := buildPathLevel(t, []string{
+		"PPPPPPPPPPPP",
+		"PPPPPPPPPPPP",
+		"PPPPWWWWPPPP",
+		"PPPWWWWWPPPP",
+		"PPPWWWWWWPPP",
+		"PPPWWWWWPPPP",
+		"PPPPWWWWPPPP",
+		"PPPPPPPPPPPP",
+		"PPPPPPPPPPPP",
+	})
+	city := image.Pt(10, 4)
+	l.Player.SetLoc(l.pixelInTile(image.Pt(0, 8)))
+	e := &domain.Enemy{
+		Character: &domain.Character{Name: "Minion"},
+		Siege:     &domain.Siege{City: city, Color: domain.ColorRed},
+	}
+	e.SetLoc(l.pixelInTile(image.Pt(2, 4)))
+	e.MoveSpeed = domain.MovementSpeed(120)
+	walkEnemy(t, l, e, city)
+}
+
+func TestChasingEnemyGivesUpOnALongWayRound(t *testing.T) {
+	l := buildPathLevel(t, []string{
+		"PPPPPPPPPPPP",
+		"PPPPPPPPPPPP",
+		"PPPPPPPPPPPP",
+		"WWWWWWWWWWPP",
+		"PPPPPPPPPPPP",
+	})
+	l.Player.SetLoc(l.pixelInTile(image.Pt(1, 4)))
+	e := &domain.Enemy{Character: &domain.Character{Name: "Chaser"}}
+	e.SetLoc(l.pixelInTile(image.Pt(1, 2)))
+	if !l.pursues(e, l.Player.Loc()) {
+		t.Fatal("expected the enemy to take up the chase across the river")
+	}
+	e.SetLoc(l.pixelInTile(image.Pt(4, 2)))
+	if l.pursues(e, l.Player.Loc()) {
+		t.Error("expected the enemy to give up once the way round is too long")
+	}
+}
+
+func TestWanderingEnemyStaysOutOfTheWater(t *testing.T) {
+	l := buildPathLevel(t, []string{
+		"PPPPPPPPPPPP",
+		"PPPPPPPPPPPP",
+		"PPPPPPPPPPPP",
+		"WWWWWWWWWWWW",
+		"WWWWWWWWWWWW",
+		"PPPPPPPPPPPP",
+	})
+	l.Player.SetLoc(l.pixelInTile(image.Pt(11, 5)))
+	e := &domain.Enemy{Character: &domain.Character{Name: "Wanderer"}}
+	e.SetLoc(l.pixelInTile(image.Pt(1, 2)))
+	e.MoveSpeed = domain.MovementSpeed(120)
+	for range 2000 {
+		l.moveEnemy(e)
+		if l.IsWaterAtPixel(e.Loc()) {
+			t.Fatalf("enemy wandered into the water at %v", l.PixelToTile(e.Loc()))
+		}
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"image"
 	"math"
 	"math/rand"
+	"time"
 
 	"github.com/benprew/s30/assets"
 	"github.com/benprew/s30/game/timing"
@@ -29,6 +30,7 @@ type RandomEncounter struct {
 	Tile        image.Point
 	SpriteIndex int
 	TerrainType int
+	Lair        LairKind
 }
 
 func (l *Level) LoadRandomEncounterSprites() error {
@@ -37,14 +39,17 @@ func (l *Level) LoadRandomEncounterSprites() error {
 	if err != nil {
 		return fmt.Errorf("failed to load random encounter sprites: %w", err)
 	}
-	fmt.Println("main encounter spr", sprites[0][0].Bounds().Size())
-	fmt.Println("shadow encounter spr", sprites[1][0].Bounds().Size())
+	logger.Debug("loaded random encounter sprites", "main", sprites[0][0].Bounds().Size(), "shadow", sprites[1][0].Bounds().Size())
 
 	l.encounterSprites = sprites
 	return nil
 }
 
 func (l *Level) SpawnEncounters(count int) {
+	l.spawnEncounters(rand.New(rand.NewSource(time.Now().UnixNano())), count)
+}
+
+func (l *Level) spawnEncounters(rng *rand.Rand, count int) {
 	pLoc := l.Player.Loc()
 
 	for range count {
@@ -56,8 +61,8 @@ func (l *Level) SpawnEncounters(count int) {
 
 		for range maxAttempts {
 			// Random tile coordinates
-			tileX = rand.Intn(l.W)
-			tileY = rand.Intn(l.H)
+			tileX = rng.Intn(l.W)
+			tileY = rng.Intn(l.H)
 
 			// Get Tile
 			t := l.Tile(image.Point{tileX, tileY})
@@ -103,7 +108,7 @@ func (l *Level) SpawnEncounters(count int) {
 		}
 
 		if !valid {
-			fmt.Println("unable to create encounter")
+			logger.Debug("no room for a random encounter")
 			continue // Could not find a valid spot
 		}
 
@@ -117,12 +122,13 @@ func (l *Level) SpawnEncounters(count int) {
 
 		pixel := l.TileToPixel(image.Point{tileX, tileY})
 
-		fmt.Println("Added encounter at Tile:", tileX, tileY, "Pixel:", pixel.X, pixel.Y, "type:", spriteIdx)
+		logger.Debug("added random encounter", "tile", image.Point{tileX, tileY}, "pixel", pixel, "type", spriteIdx)
 
 		re := RandomEncounter{
 			Tile:        image.Point{tileX, tileY},
 			SpriteIndex: spriteIdx,
 			TerrainType: t.TerrainType,
+			Lair:        randomLairKind(rng),
 		}
 		l.RandomEncounters = append(l.RandomEncounters, re)
 	}
@@ -138,8 +144,7 @@ func (l *Level) UpdateEncounters() {
 	for i, re := range l.RandomEncounters {
 		if dist(l.Player.Loc(), l.TileToPixel(re.Tile)) < EncounterTriggerDist {
 			l.randomEncounterPending = true
-			l.pendingEncounterSprite = re.SpriteIndex
-			l.pendingEncounterTerrain = re.TerrainType
+			l.pendingEncounter = re
 			t := l.Tile(re.Tile)
 			t.RemoveRandomEncounter()
 			l.RandomEncounters = append(l.RandomEncounters[:i], l.RandomEncounters[i+1:]...)
@@ -156,12 +161,14 @@ func (l *Level) RandomEncounterPending() bool {
 	return l.randomEncounterPending
 }
 
-func (l *Level) TakeRandomEncounter() (spriteIdx int, terrainType int, ok bool) {
+// TakeRandomEncounter returns the encounter the player walked onto and clears
+// the pending flag. ok is false if no encounter is pending.
+func (l *Level) TakeRandomEncounter() (re RandomEncounter, ok bool) {
 	if !l.randomEncounterPending {
-		return -1, 0, false
+		return RandomEncounter{}, false
 	}
 	l.randomEncounterPending = false
-	return l.pendingEncounterSprite, l.pendingEncounterTerrain, true
+	return l.pendingEncounter, true
 }
 
 var basicLands = []string{"Plains", "Island", "Swamp", "Mountain", "Forest"}
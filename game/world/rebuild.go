@@ -137,14 +137,8 @@ func (l *Level) RebuildSprites() error {
 	l.castles1Sprites = sa.castles1
 	l.castles2Sprites = sa.castles2
 
-	roads, err := imageutil.LoadSpriteSheet(6, 2, assets.Roads_png)
-	if err != nil {
-		return fmt.Errorf("failed to load road sprites: %w", err)
-	}
-	l.roadSprites = roads
-	l.roadSpriteInfo = [][]string{
-		{"", "NE", "E", "SE", "N", "SW"},
-		{"W", "NW", "S", "", "", ""},
+	if err := l.loadRoadSprites(); err != nil {
+		return err
 	}
 
 	// JSON unmarshalling produces a separate *Castle for each tile and for
@@ -184,7 +178,7 @@ func (l *Level) RebuildSprites() error {
 			l.Enemies[i].Character = rogue
 		}
 		if err := l.Enemies[i].Character.LoadImages(); err != nil {
-			fmt.Printf("Warning: failed to load sprites for enemy %q: %v\n", name, err)
+			logger.Warn("failed to load enemy sprites", "enemy", name, "err", err)
 		}
 	}
 
@@ -216,7 +210,32 @@ func (l *Level) rebuildDungeonEnemies() {
 	}
 }
 
+func (l *Level) loadRoadSprites() error {
+	roads, err := imageutil.LoadSpriteSheet(6, 2, assets.Roads_png)
+	if err != nil {
+		return fmt.Errorf("failed to load road sprites: %w", err)
+	}
+	l.roadSprites = roads
+	l.roadSpriteInfo = [][]string{
+		{"", "NE", "E", "SE", "N", "SW"},
+		{"W", "NW", "S", "", "", ""},
+	}
+	return nil
+}
+
 func (l *Level) rebuildRoads() {
+	if l.Roads != nil {
+		for _, path := range l.Roads {
+			l.drawRoadAlongPath(path)
+		}
+		return
+	}
+	l.connectCities()
+}
+
+// connectCities builds a road from each city after the first to the nearest
+// road or city before it.
+func (l *Level) connectCities() {
 	var cityLocations []image.Point
 	for y := 0; y < l.H; y++ {
 		for x := 0; x < l.W; x++ {
@@ -230,7 +249,7 @@ func (l *Level) rebuildRoads() {
 			continue
 		}
 		if path := l.connectCityBFS(loc); path != nil {
-			l.drawRoadAlongPath(path)
+			l.addRoad(path)
 		}
 	}
 }
@@ -0,0 +1,33 @@
+package world
+
+import (
+	"hash/fnv"
+	"math/rand"
+	"strconv"
+	"strings"
+)
+
+// maxSeed keeps generated seeds short enough to read aloud or paste into a
+// chat message.
+const maxSeed = 10_000_000_000
+
+// NewSeed returns a random world seed.
+func NewSeed() int64 {
+	return rand.Int63n(maxSeed)
+}
+
+// ParseSeed turns user input into a world seed. Numbers are used as-is and
+// any other text is hashed, so "shandalar" is as valid a seed as "42". Empty
+// input yields a fresh random seed.
+func ParseSeed(s string) int64 {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return NewSeed()
+	}
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return n
+	}
+	h := fnv.New64a()
+	h.Write([]byte(s))
+	return int64(h.Sum64() & (1<<63 - 1))
+}
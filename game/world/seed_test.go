@@ -0,0 +1,86 @@
+package world
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/benprew/s30/game/domain"
+)
+
+func TestParseSeed(t *testing.T) {
+	if got := ParseSeed(" 42 "); got != 42 {
+		t.Errorf("numeric seed: expected 42, got %d", got)
+	}
+	if ParseSeed("shandalar") != ParseSeed("shandalar") {
+		t.Error("text seed should hash to the same value every time")
+	}
+	if ParseSeed("shandalar") < 0 {
+		t.Error("text seed should hash to a non-negative value")
+	}
+	if ParseSeed("shandalar") == ParseSeed("arzakon") {
+		t.Error("different text seeds should hash differently")
+	}
+}
+
+func TestNewLevelWithSeedIsReproducible(t *testing.T) {
+	a, err := NewLevelWithSeed(&domain.Player{}, 1234)
+	if err != nil {
+		t.Fatalf("NewLevelWithSeed: %v", err)
+	}
+	b, err := NewLevelWithSeed(&domain.Player{}, 1234)
+	if err != nil {
+		t.Fatalf("NewLevelWithSeed: %v", err)
+	}
+
+	if a.Seed != 1234 {
+		t.Errorf("expected Seed 1234, got %d", a.Seed)
+	}
+	if got, want := levelFingerprint(b), levelFingerprint(a); got != want {
+		t.Errorf("same seed produced different worlds:\n%s\n---\n%s", want, got)
+	}
+
+	c, err := NewLevelWithSeed(&domain.Player{}, 4321)
+	if err != nil {
+		t.Fatalf("NewLevelWithSeed: %v", err)
+	}
+	if levelFingerprint(c) == levelFingerprint(a) {
+		t.Error("different seeds produced identical worlds")
+	}
+}
+
+// levelFingerprint summarises everything seeded generation is responsible for.
+func levelFingerprint(l *Level) string {
+	var s []byte
+	for y := range l.H {
+		for x := range l.W {
+			tile := l.Tiles[y][x]
+			s = append(s, byte('0'+tile.TerrainType))
+			if tile.IsRoad() {
+				s = append(s, 'r')
+			}
+			if tile.IsCity() {
+				s = append(s, tile.City.Name...)
+				for _, c := range tile.City.CardsForSale {
+					s = append(s, c.CardName...)
+				}
+				if m := tile.City.AssignedWorldMagic; m != nil {
+					s = append(s, m.Name...)
+				}
+			}
+		}
+		s = append(s, '\n')
+	}
+	for _, c := range l.Castles {
+		s = append(s, fmt.Sprintf("castle %s %v\n", c.RogueName, c.MapTile)...)
+	}
+	for _, d := range l.Dungeons {
+		s = append(s, fmt.Sprintf("dungeon %s %v\n", d.Name, d.MapTile)...)
+	}
+	for _, e := range l.RandomEncounters {
+		s = append(s, fmt.Sprintf("encounter %v %d %s\n", e.Tile, e.SpriteIndex, e.Lair)...)
+	}
+	for _, e := range l.Enemies {
+		s = append(s, fmt.Sprintf("enemy %s %v %v\n", e.Character.Name, e.Loc(), e.MoveSpeed)...)
+	}
+	return string(s)
+}
@@ -2,7 +2,6 @@ package world
 
 import (
 	"encoding/json"
-	"fmt"
 	"slices"
 
 	"github.com/benprew/s30/game/domain"
@@ -147,9 +146,7 @@ func (t *Tile) AddRandomEncounter(s1, s2 *ebiten.Image) {
 			OffsetY: -float64(s2.Bounds().Dy()) / yOffset,
 		},
 	}
-	fmt.Println("Added random ecounter sprite:")
-	fmt.Println(s1.Bounds())
-	fmt.Println(t.encounterSprites)
+	logger.Debug("added random encounter sprite", "bounds", s1.Bounds())
 }
 
 func (t *Tile) RemoveRandomEncounter() {
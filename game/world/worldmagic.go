@@ -0,0 +1,69 @@
+package world
+
+import (
+	"fmt"
+	"image"
+	"slices"
+
+	"github.com/benprew/s30/game/domain"
+)
+
+// roughTerrainSpeedFactor slows the player in marshes and mountains unless a
+// world magic eases the way.
+const roughTerrainSpeedFactor = 0.6
+
+// playerSpeedFactor returns the multiplier applied to the player's movement
+// speed on their current tile.
+func (l *Level) playerSpeedFactor() float64 {
+	factor := l.Player.WorldMoveSpeedFactor() * l.Player.TravelSpeedFactor()
+	tile := l.Tile(l.CharacterTile())
+	if tile == nil {
+		return factor
+	}
+	switch tile.TerrainType {
+	case TerrainMarsh:
+		if !l.Player.HasWorldMagicNamed(domain.WorldMagicAmuletOfSwampwalk) {
+			factor *= roughTerrainSpeedFactor
+		}
+	case TerrainMountains:
+		if !l.Player.HasWorldMagicNamed(domain.WorldMagicDwarvenPick) {
+			factor *= roughTerrainSpeedFactor
+		}
+	}
+	return factor
+}
+
+// VisitedCities returns the cities the player has entered, in visit order.
+func (l *Level) VisitedCities() []*domain.City {
+	var cities []*domain.City
+	for _, pt := range l.Player.VisitedCities {
+		if tile := l.Tile(pt); tile != nil && tile.IsCity() {
+			cities = append(cities, tile.City)
+		}
+	}
+	return cities
+}
+
+// LeapToCity teleports the player to a visited city using Leap of Fate.
+func (l *Level) LeapToCity(city *domain.City) error {
+	if !l.Player.HasWorldMagicNamed(domain.WorldMagicLeapOfFate) {
+		return fmt.Errorf("leap of fate not owned")
+	}
+	pt := image.Pt(city.X, city.Y)
+	if !slices.Contains(l.Player.VisitedCities, pt) {
+		return fmt.Errorf("city %q has not been visited", city.Name)
+	}
+	l.Player.SetLoc(l.pixelInTile(pt))
+	l.ticksSinceLastInteraction = 0
+	return nil
+}
+
+// pixelInTile returns a pixel that PixelToTile maps back to tile, so the
+// player lands on the tile itself rather than the diamond drawn around it.
+func (l *Level) pixelInTile(tile image.Point) image.Point {
+	x := tile.X*l.TileWidth + l.TileWidth/2
+	if tile.Y%2 != 0 {
+		x += l.TileWidth / 2
+	}
+	return image.Point{X: x, Y: tile.Y*l.TileHeight/2 + l.TileHeight/4}
+}
@@ -0,0 +1,78 @@
+package world
+
+import (
+	"image"
+	"testing"
+
+	"github.com/benprew/s30/game/domain"
+)
+
+func TestPlayerSpeedFactorRoughTerrain(t *testing.T) {
+	tests := []struct {
+		name    string
+		terrain int
+		magic   string
+		food    int
+		want    float64
+	}{
+		{"plains", TerrainPlains, "", 10, 1},
+		{"marsh", TerrainMarsh, "", 10, roughTerrainSpeedFactor},
+		{"marsh with swampwalk", TerrainMarsh, domain.WorldMagicAmuletOfSwampwalk, 10, 1},
+		{"mountains", TerrainMountains, "", 10, roughTerrainSpeedFactor},
+		{"mountains with dwarven pick", TerrainMountains, domain.WorldMagicDwarvenPick, 10, 1},
+		{"mountains with swampwalk", TerrainMountains, domain.WorldMagicAmuletOfSwampwalk, 10, roughTerrainSpeedFactor},
+		{"plains with quickening", TerrainPlains, domain.WorldMagicQuickening, 10, domain.QuickeningSpeedFactor},
+		{"marsh with quickening", TerrainMarsh, domain.WorldMagicQuickening, 10, domain.QuickeningSpeedFactor * roughTerrainSpeedFactor},
+		{"plains starving", TerrainPlains, "", 0, domain.StarvingSpeedFactor},
+		{"marsh starving", TerrainMarsh, "", 0, domain.StarvingSpeedFactor * roughTerrainSpeedFactor},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := createTestLevel(3, 3)
+			l.TileWidth, l.TileHeight = 200, 100
+			l.Tiles[1][1].TerrainType = tt.terrain
+			l.Player = &domain.Player{Food: tt.food}
+			if tt.magic != "" {
+				l.Player.AddWorldMagic(domain.FindWorldMagic(tt.magic))
+			}
+			l.Player.SetLoc(l.pixelInTile(image.Pt(1, 1)))
+
+			if got := l.playerSpeedFactor(); got != tt.want {
+				t.Errorf("playerSpeedFactor() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLeapToCity(t *testing.T) {
+	l := createTestLevel(5, 5)
+	l.TileWidth, l.TileHeight = 200, 100
+	home := &domain.City{Name: "Home", X: 1, Y: 1}
+	away := &domain.City{Name: "Away", X: 3, Y: 3}
+	l.Tiles[1][1].City = home
+	l.Tiles[3][3].City = away
+	l.Player = &domain.Player{}
+	l.Player.SetLoc(l.pixelInTile(image.Pt(1, 1)))
+	l.Player.VisitCity(away)
+	l.Player.VisitCity(home)
+
+	if err := l.LeapToCity(away); err == nil {
+		t.Fatal("LeapToCity without Leap of Fate should fail")
+	}
+
+	l.Player.AddWorldMagic(domain.FindWorldMagic(domain.WorldMagicLeapOfFate))
+	if err := l.LeapToCity(&domain.City{Name: "Unvisited", X: 2, Y: 2}); err == nil {
+		t.Fatal("LeapToCity to an unvisited city should fail")
+	}
+	if err := l.LeapToCity(away); err != nil {
+		t.Fatalf("LeapToCity: %v", err)
+	}
+	if got := l.CharacterTile(); got != image.Pt(3, 3) {
+		t.Errorf("player tile after leap = %v, want (3,3)", got)
+	}
+
+	visited := l.VisitedCities()
+	if len(visited) != 2 || visited[0] != away || visited[1] != home {
+		t.Errorf("VisitedCities() = %v, want [Away Home]", visited)
+	}
+}
@@ -0,0 +1,110 @@
+package logging
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// LogFileName is the name of the current log file in the log directory.
+// Older logs are kept beside it as s30.log.1, s30.log.2 and so on.
+const LogFileName = "s30.log"
+
+var (
+	// maxLogFileBytes is how large the log file grows before it is rotated.
+	maxLogFileBytes int64 = 1 << 20
+	keptLogFiles          = 3
+)
+
+// OpenLogFile starts writing the log to LogFileName in dir as well as the
+// console, rotating it as it grows.
+func OpenLogFile(dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("create log directory: %w", err)
+	}
+	f, err := openRotatingFile(filepath.Join(dir, LogFileName))
+	if err != nil {
+		return err
+	}
+	output.Lock()
+	defer output.Unlock()
+	if output.file != nil {
+		output.file.close()
+	}
+	output.file = f
+	return nil
+}
+
+// CloseLogFile stops writing to the log file.
+func CloseLogFile() {
+	output.Lock()
+	defer output.Unlock()
+	if output.file != nil {
+		output.file.close()
+		output.file = nil
+	}
+}
+
+// rotatingFile appends to a log file, moving it aside once it reaches
+// maxLogFileBytes.
+type rotatingFile struct {
+	path string
+	f    *os.File
+	size int64
+}
+
+func openRotatingFile(path string) (*rotatingFile, error) {
+	r := &rotatingFile{path: path}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *rotatingFile) open() error {
+	f, err := os.OpenFile(r.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("open log file: %w", err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("open log file: %w", err)
+	}
+	r.f, r.size = f, info.Size()
+	return nil
+}
+
+// write appends line, rotating first if it would take the file past its
+// limit. A log that can't be written is given up on quietly; there is
+// nowhere left to report it.
+func (r *rotatingFile) write(line string) {
+	if r.f == nil {
+		return
+	}
+	if r.size > 0 && r.size+int64(len(line)) > maxLogFileBytes {
+		if err := r.rotate(); err != nil {
+			return
+		}
+	}
+	n, _ := r.f.WriteString(line)
+	r.size += int64(n)
+}
+
+func (r *rotatingFile) rotate() error {
+	r.close()
+	for i := keptLogFiles - 1; i >= 1; i-- {
+		_ = os.Rename(fmt.Sprintf("%s.%d", r.path, i), fmt.Sprintf("%s.%d", r.path, i+1))
+	}
+	if err := os.Rename(r.path, r.path+".1"); err != nil {
+		return err
+	}
+	return r.open()
+}
+
+func (r *rotatingFile) close() {
+	if r.f != nil {
+		_ = r.f.Close()
+		r.f = nil
+	}
+}
@@ -0,0 +1,121 @@
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const timeFormat = "2006-01-02 15:04:05.000"
+
+// output is where every subsystem's records are written.
+var output = struct {
+	sync.Mutex
+	console io.Writer
+	file    *rotatingFile
+	recent  *ring
+}{console: os.Stderr, recent: newRing(RecentLines)}
+
+// SetConsole sends console output to w, or nowhere when w is nil.
+func SetConsole(w io.Writer) {
+	output.Lock()
+	defer output.Unlock()
+	output.console = w
+}
+
+func write(line string) {
+	output.Lock()
+	defer output.Unlock()
+	output.recent.add(strings.TrimSuffix(line, "\n"))
+	if output.console != nil {
+		_, _ = io.WriteString(output.console, line)
+	}
+	if output.file != nil {
+		output.file.write(line)
+	}
+}
+
+// handler formats records as one line each:
+//
+//	2026-10-17 12:00:00.000 INFO duel: game over winner=You turn=7
+type handler struct {
+	subsystem Subsystem
+	level     *slog.LevelVar
+	// attrs are the formatted attributes added with WithAttrs.
+	attrs string
+	// group prefixes the keys of later attributes.
+	group string
+}
+
+func (h *handler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level.Level()
+}
+
+func (h *handler) Handle(_ context.Context, r slog.Record) error {
+	var b strings.Builder
+	if !r.Time.IsZero() {
+		b.WriteString(r.Time.Format(timeFormat))
+		b.WriteByte(' ')
+	}
+	b.WriteString(r.Level.String())
+	b.WriteByte(' ')
+	b.WriteString(string(h.subsystem))
+	b.WriteString(": ")
+	b.WriteString(r.Message)
+	b.WriteString(h.attrs)
+	r.Attrs(func(a slog.Attr) bool {
+		appendAttr(&b, h.group, a)
+		return true
+	})
+	b.WriteByte('\n')
+	write(b.String())
+	return nil
+}
+
+func (h *handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	var b strings.Builder
+	for _, a := range attrs {
+		appendAttr(&b, h.group, a)
+	}
+	h2 := *h
+	h2.attrs += b.String()
+	return &h2
+}
+
+func (h *handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	h2 := *h
+	h2.group += name + "."
+	return &h2
+}
+
+func appendAttr(b *strings.Builder, prefix string, a slog.Attr) {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return
+	}
+	if a.Value.Kind() == slog.KindGroup {
+		if a.Key != "" {
+			prefix += a.Key + "."
+		}
+		for _, ga := range a.Value.Group() {
+			appendAttr(b, prefix, ga)
+		}
+		return
+	}
+	b.WriteByte(' ')
+	b.WriteString(prefix)
+	b.WriteString(a.Key)
+	b.WriteByte('=')
+	s := a.Value.String()
+	if s == "" || strings.ContainsAny(s, " \t\n\"=") {
+		s = strconv.Quote(s)
+	}
+	b.WriteString(s)
+}
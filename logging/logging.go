@@ -1,27 +1,107 @@
+// Package logging is the game's leveled, structured logging. Each subsystem
+// has its own slog.Logger and level, set with the -v flag or the settings
+// file. Records go to the console, to a rotating log file once one is open,
+// and to a ring buffer of recent lines that bug reports include.
 package logging
 
-import "fmt"
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+)
 
+// Subsystem names a part of the game whose logging is configured on its own.
 type Subsystem string
 
 const (
 	MTG   Subsystem = "mtg"
 	World Subsystem = "world"
 	Duel  Subsystem = "duel"
+	Game  Subsystem = "game"
+	UI    Subsystem = "ui"
+	Audio Subsystem = "audio"
 )
 
-var enabled = map[Subsystem]bool{}
+// Subsystems lists every subsystem, in the order -v help lists them.
+var Subsystems = []Subsystem{MTG, World, Duel, Game, UI, Audio}
+
+// DefaultLevel is the level a subsystem logs at until it is configured.
+const DefaultLevel = slog.LevelInfo
+
+var levels = struct {
+	sync.Mutex
+	vars map[Subsystem]*slog.LevelVar
+}{vars: map[Subsystem]*slog.LevelVar{}}
+
+func levelVar(s Subsystem) *slog.LevelVar {
+	levels.Lock()
+	defer levels.Unlock()
+	v, ok := levels.vars[s]
+	if !ok {
+		v = new(slog.LevelVar)
+		v.Set(DefaultLevel)
+		levels.vars[s] = v
+	}
+	return v
+}
+
+// Logger returns the logger for s. Its level follows later calls to SetLevel,
+// so packages can keep it in a variable.
+func Logger(s Subsystem) *slog.Logger {
+	return slog.New(&handler{subsystem: s, level: levelVar(s)})
+}
+
+// SetLevel sets the lowest level s logs at.
+func SetLevel(s Subsystem, level slog.Level) {
+	levelVar(s).Set(level)
+}
+
+// Level returns the lowest level s logs at.
+func Level(s Subsystem) slog.Level {
+	return levelVar(s).Level()
+}
 
+// Enable turns on debug logging for s.
 func Enable(s Subsystem) {
-	enabled[s] = true
+	SetLevel(s, slog.LevelDebug)
 }
 
+// Enabled reports whether s logs at debug level, for callers that gather
+// debug output before logging it.
 func Enabled(s Subsystem) bool {
-	return enabled[s]
+	return Level(s) <= slog.LevelDebug
 }
 
-func Printf(s Subsystem, format string, args ...any) {
-	if enabled[s] {
-		fmt.Printf(format, args...)
+// Configure sets subsystem levels from a comma-separated spec such as
+// "duel,world=warn". A subsystem on its own logs at debug level, and "all"
+// stands for every subsystem. Entries apply in order, so "all=warn,duel"
+// quiets everything but the duel.
+func Configure(spec string) error {
+	for entry := range strings.SplitSeq(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, levelName, hasLevel := strings.Cut(entry, "=")
+		name = strings.TrimSpace(name)
+		level := slog.LevelDebug
+		if hasLevel {
+			if err := level.UnmarshalText([]byte(strings.TrimSpace(levelName))); err != nil {
+				return fmt.Errorf("log level for %s: %w", name, err)
+			}
+		}
+		if name == "all" {
+			for _, s := range Subsystems {
+				SetLevel(s, level)
+			}
+			continue
+		}
+		if !slices.Contains(Subsystems, Subsystem(name)) {
+			return fmt.Errorf("unknown log subsystem %q", name)
+		}
+		SetLevel(Subsystem(name), level)
 	}
+	return nil
 }
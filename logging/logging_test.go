@@ -0,0 +1,130 @@
+package logging
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// captureConsole sends console output to a buffer and puts the subsystem
+// levels back afterwards.
+func captureConsole(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	SetConsole(&buf)
+	saved := map[Subsystem]slog.Level{}
+	for _, s := range Subsystems {
+		saved[s] = Level(s)
+	}
+	t.Cleanup(func() {
+		SetConsole(os.Stderr)
+		for s, level := range saved {
+			SetLevel(s, level)
+		}
+	})
+	return &buf
+}
+
+func TestConfigureSetsSubsystemLevels(t *testing.T) {
+	captureConsole(t)
+	if err := Configure("all=warn, duel ,world=error"); err != nil {
+		t.Fatal(err)
+	}
+	want := map[Subsystem]slog.Level{Duel: slog.LevelDebug, World: slog.LevelError, MTG: slog.LevelWarn, Audio: slog.LevelWarn}
+	for s, level := range want {
+		if Level(s) != level {
+			t.Errorf("%s level = %v, want %v", s, Level(s), level)
+		}
+	}
+	if !Enabled(Duel) || Enabled(World) {
+		t.Error("expected only the duel to log at debug level")
+	}
+
+	for _, spec := range []string{"combat", "duel=loud"} {
+		if err := Configure(spec); err == nil {
+			t.Errorf("Configure(%q) succeeded", spec)
+		}
+	}
+}
+
+func TestLoggerFiltersByLevelAndFormatsAttrs(t *testing.T) {
+	buf := captureConsole(t)
+	SetLevel(World, slog.LevelInfo)
+	log := Logger(World).With("seed", 42)
+
+	log.Debug("tile scan")
+	log.Info("new level", "took", "1.5s", slog.Group("player", "x", 3), "err", errors.New("no road"))
+
+	out := buf.String()
+	if strings.Contains(out, "tile scan") {
+		t.Errorf("debug line logged at info level: %q", out)
+	}
+	want := `INFO world: new level seed=42 took=1.5s player.x=3 err="no road"` + "\n"
+	if !strings.HasSuffix(out, want) {
+		t.Errorf("output = %q, want it to end %q", out, want)
+	}
+
+	recent := Recent()
+	if len(recent) == 0 || !strings.HasSuffix(recent[len(recent)-1], strings.TrimSuffix(want, "\n")) {
+		t.Errorf("recent lines end %q", recent[len(recent)-1:])
+	}
+}
+
+func TestRingKeepsTheLatestLines(t *testing.T) {
+	r := newRing(3)
+	for _, line := range []string{"a", "b"} {
+		r.add(line)
+	}
+	if got := strings.Join(r.contents(), ","); got != "a,b" {
+		t.Errorf("contents = %s, want a,b", got)
+	}
+	for _, line := range []string{"c", "d", "e"} {
+		r.add(line)
+	}
+	if got := strings.Join(r.contents(), ","); got != "c,d,e" {
+		t.Errorf("contents = %s, want c,d,e", got)
+	}
+}
+
+func TestLogFileRotates(t *testing.T) {
+	captureConsole(t)
+	SetConsole(nil)
+	saved := maxLogFileBytes
+	maxLogFileBytes = 200
+	t.Cleanup(func() { maxLogFileBytes = saved })
+
+	dir := t.TempDir()
+	if err := OpenLogFile(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(CloseLogFile)
+	log := Logger(Game)
+	for i := range 20 {
+		log.Warn("disk filling up", "line", i)
+	}
+	CloseLogFile()
+
+	current, err := os.ReadFile(filepath.Join(dir, LogFileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(current) == 0 || int64(len(current)) > maxLogFileBytes {
+		t.Errorf("current log is %d bytes, want up to %d", len(current), maxLogFileBytes)
+	}
+	if !strings.Contains(string(current), "line=19") {
+		t.Errorf("current log lacks the last line: %q", current)
+	}
+	for i := 1; i <= keptLogFiles; i++ {
+		if _, err := os.Stat(filepath.Join(dir, fmt.Sprintf("%s.%d", LogFileName, i))); err != nil {
+			t.Errorf("rotated log %d: %v", i, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, fmt.Sprintf("%s.%d", LogFileName, keptLogFiles+1))); err == nil {
+		t.Error("kept more rotated logs than keptLogFiles")
+	}
+}
@@ -0,0 +1,37 @@
+package logging
+
+// RecentLines is how many of the latest log lines are kept for bug reports.
+const RecentLines = 500
+
+// ring keeps the last few lines written, overwriting the oldest.
+type ring struct {
+	lines []string
+	next  int
+	full  bool
+}
+
+func newRing(size int) *ring {
+	return &ring{lines: make([]string, size)}
+}
+
+func (r *ring) add(line string) {
+	r.lines[r.next] = line
+	r.next = (r.next + 1) % len(r.lines)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+func (r *ring) contents() []string {
+	if !r.full {
+		return append([]string(nil), r.lines[:r.next]...)
+	}
+	return append(append([]string(nil), r.lines[r.next:]...), r.lines[:r.next]...)
+}
+
+// Recent returns the latest log lines, oldest first.
+func Recent() []string {
+	output.Lock()
+	defer output.Unlock()
+	return output.recent.contents()
+}
@@ -7,10 +7,10 @@ import (
 	"os"
 	"runtime"
 	"runtime/pprof"
-	"strings"
 
 	"github.com/benprew/s30/game"
 	"github.com/benprew/s30/game/bugreport"
+	"github.com/benprew/s30/game/save"
 	"github.com/benprew/s30/game/world"
 	"github.com/benprew/s30/internal/pprofutil"
 	"github.com/benprew/s30/logging"
@@ -38,7 +38,7 @@ func writeProfile(name, profileName string, gc bool) error {
 }
 
 func main() {
-	verbose := flag.String("v", "", "enable verbose logging for subsystems (comma-separated: mtg,world,duel)")
+	verbose := flag.String("v", "", "log levels by subsystem, e.g. duel,world=warn,all=debug (subsystems: mtg,world,duel,game,ui,audio; levels: debug,info,warn,error)")
 	pprofAddr := flag.String("pprof", "", "enable pprof HTTP server at the given listen address, e.g. 127.0.0.1:6060")
 	cpuprofile := flag.String("cpuprofile", "", "write CPU profile to file on exit")
 	memprofile := flag.String("memprofile", "", "write retained heap profile to file on exit")
@@ -46,12 +46,22 @@ func main() {
 	memProfileRate := flag.Int("memprofilerate", runtime.MemProfileRate, "bytes allocated per heap-profile sample (1 records every allocation)")
 	debug := flag.Bool("debug", false, "use the debug burn deck and start enemies at 1 life")
 	showOpponentHand := flag.Bool("show-opponent-hand", false, "reveal the opponent's hand")
+	recordDuels := flag.Bool("record-duels", false, "save a replay of every finished duel beside the saves directory")
+	replayFile := flag.String("replay", "", "play back a saved duel replay file")
+	reportFile := flag.String("load-report", "", "restore the game, and any duel in progress, from a bug report JSON file")
+	mapFile := flag.String("map", "", "lay out new games' worlds from this map file (see cmd/worldmap)")
+	cardMirror := flag.String("card-mirror", "", "fetch card images from this http(s) URL or directory instead of Scryfall")
+	cardCacheMB := flag.Int64("card-cache-mb", 0, "disk space for cached card images in MB (0 for the default, -1 to turn the cache off)")
 	flag.Parse()
 
-	if *verbose != "" {
-		for s := range strings.SplitSeq(*verbose, ",") {
-			logging.Enable(logging.Subsystem(strings.TrimSpace(s)))
+	if err := logging.Configure(*verbose); err != nil {
+		log.Fatalf("Invalid -v: %v", err)
+	}
+	if dir, err := save.LogDir(); err == nil {
+		if err := logging.OpenLogFile(dir); err != nil {
+			log.Printf("Warning: could not open log file: %v", err)
 		}
+		defer logging.CloseLogFile()
 	}
 
 	if *memprofile != "" || *allocprofile != "" {
@@ -84,8 +94,15 @@ func main() {
 	// ebiten.SetFullscreen(true)
 
 	g, err := game.NewGameWithOptions(game.Options{
-		Debug:            *debug,
-		ShowOpponentHand: *showOpponentHand,
+		Debug:               *debug,
+		Verbose:             *verbose,
+		ShowOpponentHand:    *showOpponentHand,
+		RecordDuels:         *recordDuels,
+		ReplayFile:          *replayFile,
+		ReportFile:          *reportFile,
+		MapFile:             *mapFile,
+		CardImageMirror:     *cardMirror,
+		CardImageCacheBytes: *cardCacheMB << 20,
 	})
 	if err != nil {
 		log.Fatal(err)
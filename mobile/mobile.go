@@ -1,11 +1,11 @@
 package mobile
 
 import (
-	"fmt"
 	"path/filepath"
 
 	"github.com/benprew/s30/game"
 	"github.com/benprew/s30/game/save"
+	"github.com/benprew/s30/logging"
 	"github.com/hajimehoshi/ebiten/v2/mobile"
 )
 
@@ -26,6 +26,6 @@ func SetSaveDir(appFilesDir string) {
 // SaveGame persists the active Android game when the activity is paused.
 func SaveGame() {
 	if err := currentGame.SaveGame(); err != nil {
-		fmt.Printf("Error auto-saving Android game: %v\n", err)
+		logging.Logger(logging.Game).Error("failed to auto-save Android game", "err", err)
 	}
 }